@@ -15,6 +15,22 @@ func main() {
 		&entities.ExampleEntity{},
 		&entities.EnergyPlants{},
 		&entities.EventEntity{},
+		&entities.AlertRuleEntity{},
+		&entities.AlertEntity{},
+		&entities.WebhookDeliveryEntity{},
+		&entities.WebhookAttemptEntity{},
+		&entities.WebhookSubscriptionEntity{},
+		&entities.AnomalyEntity{},
+		&entities.AnomalySettingsEntity{},
+		&entities.PlantStatusHistoryEntity{},
+		&entities.MaintenanceWindowEntity{},
+		&entities.ForecastEntity{},
+		&entities.EnergyDailyEntity{},
+		&entities.PortfolioNodeEntity{},
+		&entities.ReadingRollupEntity{},
+		&entities.RollupWatermarkEntity{},
+		&entities.EmissionFactorEntity{},
+		&entities.QuarantinedEventEntity{},
 		// Add more entities here as needed
 	)
 	if err != nil {
@@ -23,27 +23,33 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/events": {
+        "/admin/config": {
             "get": {
-                "description": "Get all events from the database ordered by creation time",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
                 ],
+                "description": "Get the effective configuration by environment variable; secrets (SECRET, PASSWORD, API_KEY) are masked (admin listener)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "admin"
                 ],
-                "summary": "List all events",
+                "summary": "Effective configuration",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entities.EventEntity"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
                     "500": {
@@ -55,50 +61,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/events/type/{type}": {
+        "/admin/loglevel": {
             "get": {
-                "description": "Get all events filtered by event type",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
                 ],
+                "description": "Get the current level of the application logger (admin listener)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "events"
-                ],
-                "summary": "Get events by type",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Event Type",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Get log level",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entities.EventEntity"
-                            }
+                            "$ref": "#/definitions/rest.LogLevelResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/events/{id}": {
-            "get": {
-                "description": "Get a single event by its UUID",
+            },
+            "put": {
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
+                ],
+                "description": "Change the level of the application logger at runtime (debug, info, warn, error) (admin listener)",
                 "consumes": [
                     "application/json"
                 ],
@@ -106,23 +105,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "admin"
                 ],
-                "summary": "Get an event by ID",
+                "summary": "Change log level",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Event ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "New log level",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.LogLevelRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.EventEntity"
+                            "$ref": "#/definitions/rest.LogLevelResponse"
                         }
                     },
                     "400": {
@@ -131,8 +132,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
@@ -140,39 +141,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/examples": {
+        "/admin/simulator": {
             "get": {
-                "description": "Get all examples from the database",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
                 ],
+                "description": "Get the state (running, paused, stopped), current settings and counters of the event simulator (admin listener)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "examples"
+                    "admin"
                 ],
-                "summary": "List all examples",
+                "summary": "Simulator status",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entities.ExampleEntity"
-                            }
+                            "$ref": "#/definitions/rest.SimulatorStatusResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/simulator/burst": {
             "post": {
-                "description": "Create a new example with the provided data",
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
+                ],
+                "description": "Send count simulated readings right away, spread in turn over the given plants (all registered plants when plant_ids is empty). Works while the simulator is stopped or paused (admin listener)",
                 "consumes": [
                     "application/json"
                 ],
@@ -180,25 +187,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "examples"
+                    "admin"
                 ],
-                "summary": "Create a new example",
+                "summary": "Send a burst of events",
                 "parameters": [
                     {
-                        "description": "Example data",
+                        "description": "Burst",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/rest.CreateExampleRequest"
+                            "$ref": "#/definitions/rest.SimulatorBurstRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.ExampleEntity"
+                            "$ref": "#/definitions/rest.SimulatorBurstResponse"
                         }
                     },
                     "400": {
@@ -207,6 +214,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -216,51 +241,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/examples/{id}": {
-            "get": {
-                "description": "Get a single example by its UUID",
-                "consumes": [
-                    "application/json"
+        "/admin/simulator/pause": {
+            "post": {
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
                 ],
+                "description": "Stop sending batches without stopping the loop; an ongoing batch is cut short (admin listener)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "examples"
-                ],
-                "summary": "Get an example by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Example ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Pause the simulator",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.ExampleEntity"
+                            "$ref": "#/definitions/rest.SimulatorStatusResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/simulator/rate": {
             "put": {
-                "description": "Update an existing example by ID",
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
+                ],
+                "description": "Change the events per second, batch size or interval without a restart. Rate and batch size apply from the next batch; a new interval restarts the ticker (admin listener)",
                 "consumes": [
                     "application/json"
                 ],
@@ -268,24 +293,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "examples"
+                    "admin"
                 ],
-                "summary": "Update an example",
+                "summary": "Change simulator rate",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Example ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Example data",
+                        "description": "Settings to change",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/rest.UpdateExampleRequest"
+                            "$ref": "#/definitions/rest.SimulatorRateRequest"
                         }
                     }
                 ],
@@ -293,7 +311,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.ExampleEntity"
+                            "$ref": "#/definitions/rest.SimulatorStatusResponse"
                         }
                     },
                     "400": {
@@ -302,141 +320,5789 @@ const docTemplate = `{
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/simulator/resume": {
+            "post": {
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
+                ],
+                "description": "Send batches again from the next interval (admin listener)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Resume the simulator",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SimulatorStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an example by ID",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/simulator/start": {
+            "post": {
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
                 ],
+                "description": "Start sending batches of simulated readings: one immediately, then one every interval (admin listener)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "examples"
+                    "admin"
                 ],
-                "summary": "Delete an example",
-                "parameters": [
+                "summary": "Start the simulator",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SimulatorStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/simulator/stop": {
+            "post": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Example ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "AdminApiKey": []
                     }
                 ],
+                "description": "Stop the simulator loop; an ongoing batch is cut short. Simulated plant state and counters are kept (admin listener)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Stop the simulator",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.SimulatorStatusResponse"
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/rest.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entities.EnergyPlants": {
-            "type": "object",
-            "properties": {
-                "capacityMW": {
-                    "type": "number"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "location": {
-                    "type": "string"
-                },
-                "plantName": {
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "type": "string"
-                }
-            }
         },
-        "entities.EventEntity": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
+        "/admin/version": {
+            "get": {
+                "security": [
+                    {
+                        "AdminApiKey": []
+                    }
+                ],
+                "description": "Get the build date, git commit, Go version and uptime of the running binary (admin listener)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Build info",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.VersionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alert-rules": {
+            "get": {
+                "description": "Get all threshold alert rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "List alert rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/rest.AlertRuleResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a threshold rule written as \"\u003cmetric\u003e \u003cop\u003e \u003cthreshold\u003e [for \u003cduration\u003e]\", scoped to all plants (scope=all), a plant type (scope=plant_type) or one plant (scope=plant)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Create an alert rule",
+                "parameters": [
+                    {
+                        "description": "Alert rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alert-rules/{id}": {
+            "get": {
+                "description": "Get a single alert rule by its UUID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get an alert rule by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace an alert rule; firing alerts are kept until they resolve, or resolved at once if the rule is disabled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Update an alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alert rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an alert rule; its firing alerts are resolved",
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Delete an alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts": {
+            "get": {
+                "description": "Get fired and resolved alerts, most recent first. kind=threshold are alert rule alerts; kind=offline are raised when a plant stops reporting (rule_id is the nil UUID)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "List alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "firing or resolved",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Plant UUID",
+                        "name": "plant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alert rule UUID",
+                        "name": "rule_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "threshold or offline",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of alerts",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.AlertEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/alerts/{id}": {
+            "get": {
+                "description": "Get a single alert by its UUID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get an alert by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.AlertEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/anomalies": {
+            "get": {
+                "description": "Get readings flagged as anomalous by the detector, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "List anomalies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant UUID",
+                        "name": "plant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric (e.g. power_generated_mw)",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Detected at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Detected before (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of anomalies",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.AnomalyEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/anomalies/{id}": {
+            "get": {
+                "description": "Get a single anomaly with its score and the baseline it was compared against",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "Get an anomaly by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Anomaly ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.AnomalyEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/connectivity": {
+            "get": {
+                "description": "Last reading received from each plant and whether it is online, offline (silent for longer than its expected interval times PLANT_OFFLINE_MISSED_INTERVALS) or never_seen",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "connectivity"
+                ],
+                "summary": "List plant connectivity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by state (online, offline, never_seen)",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.PlantConnectivity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/emission-factors": {
+            "get": {
+                "description": "Get grid emission factors ordered by version, optionally only those of a region, only the defaults (no region) or only up to a version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emissions"
+                ],
+                "summary": "List emission factors",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio region UUID",
+                        "name": "region_node_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only factors without region",
+                        "name": "default_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only factors with version \u003c= max_version",
+                        "name": "max_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.EmissionFactorEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a grid emission factor (tCO2e per MWh) for a portfolio region, or a default factor without region, valid for whole UTC days [valid_from, valid_to). The factor gets the next version; factors cannot be changed or deleted, so a correction is a new factor. Reports use the highest version that covers each day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emissions"
+                ],
+                "summary": "Create an emission factor",
+                "parameters": [
+                    {
+                        "description": "Emission factor",
+                        "name": "factor",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.EmissionFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EmissionFactorEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/emission-factors/{id}": {
+            "get": {
+                "description": "Get a single grid emission factor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emissions"
+                ],
+                "summary": "Get an emission factor by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Emission factor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EmissionFactorEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/emissions": {
+            "get": {
+                "description": "Avoided emissions (tCO2e) of all plants together per period, plus the range total of every plant",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emissions"
+                ],
+                "summary": "Get fleet avoided emissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Use only factors up to this version (default latest)",
+                        "name": "factor_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EmissionsReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/energy": {
+            "get": {
+                "description": "Energy generated, consumed and net (MWh) of all plants together per period, plus the range total of every plant. Covered hours are summed across plants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "energy"
+                ],
+                "summary": "Get fleet energy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default day)",
+                        "name": "granularity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.FleetEnergyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events": {
+            "get": {
+                "description": "Get all events from the database ordered by event_time (when the reading was taken), newest first; created_at is the ingestion time. Use quality=good to leave out readings flagged by the plausibility checks, or quality=suspect to list only those.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List all events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "good or suspect (default all)",
+                        "name": "quality",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.EventEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/type/{type}": {
+            "get": {
+                "description": "Get all events filtered by event type, optionally by quality, ordered by event_time (newest first)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get events by type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event Type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "good or suspect (default all)",
+                        "name": "quality",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.EventEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/events/{id}": {
+            "get": {
+                "description": "Get a single event by its UUID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get an event by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EventEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/examples": {
+            "get": {
+                "description": "Get all examples from the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "examples"
+                ],
+                "summary": "List all examples",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.ExampleEntity"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new example with the provided data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "examples"
+                ],
+                "summary": "Create a new example",
+                "parameters": [
+                    {
+                        "description": "Example data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.CreateExampleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ExampleEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/examples/{id}": {
+            "get": {
+                "description": "Get a single example by its UUID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "examples"
+                ],
+                "summary": "Get an example by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ExampleEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing example by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "examples"
+                ],
+                "summary": "Update an example",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Example data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.UpdateExampleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ExampleEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an example by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "examples"
+                ],
+                "summary": "Delete an example",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Example ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/kpis": {
+            "get": {
+                "description": "KPIs of all plants together per period, plus the range total of every plant. The fleet capacity factor uses the summed capacity; covered hours are summed across plants and the peak is the highest single-plant peak. telemetry combines the plants that report each type-specific metric, and round_trip_efficiency_percent covers the storage plants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kpis"
+                ],
+                "summary": "Get fleet KPIs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago or the first day still within ROLLUP_RAW_RETENTION); earlier times are rejected",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Leave maintenance windows out of every KPI and of the available capacity",
+                        "name": "exclude_maintenance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Use only readings that passed the plausibility checks (quality=good)",
+                        "name": "exclude_suspect",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.FleetKPIReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/maintenance-windows": {
+            "get": {
+                "description": "Get planned maintenance windows, optionally only those of a plant or only those active now",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "List maintenance windows",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant UUID",
+                        "name": "plant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only windows with an occurrence in progress",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/rest.MaintenanceWindowResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Plan a maintenance window for a plant. With recurrence daily, weekly or monthly, starts_at/ends_at are the first occurrence. While an occurrence is active, new alerts of the plant are suppressed (alert_policy=suppress) or fired as info (alert_policy=downgrade), and its readings are stored with maintenance_window_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Create a maintenance window",
+                "parameters": [
+                    {
+                        "description": "Maintenance window",
+                        "name": "window",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.MaintenanceWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MaintenanceWindowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/maintenance-windows/{id}": {
+            "get": {
+                "description": "Get a single maintenance window with its current or next occurrence",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Get a maintenance window by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Maintenance window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MaintenanceWindowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a maintenance window; it applies from the next reading",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Update a maintenance window",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Maintenance window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Maintenance window",
+                        "name": "window",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.MaintenanceWindowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.MaintenanceWindowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a maintenance window; readings already marked keep their maintenance_window_id",
+                "tags": [
+                    "maintenance"
+                ],
+                "summary": "Delete a maintenance window",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Maintenance window ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plant-types": {
+            "get": {
+                "description": "Plant technologies with the telemetry each one reports: the common metrics (power, efficiency, temperature) plus irradiance for solar, wind speed for wind, reservoir level for hydro and state of charge for storage. A type-specific metric sent by a plant of another type is stored with quality=suspect (unexpected_metric); values outside the listed min/max are flagged out_of_range unless QUALITY_RANGES overrides them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plants"
+                ],
+                "summary": "List plant types",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.PlantTypeDefinition"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants": {
+            "get": {
+                "description": "Get all energy plants ordered by name, with their current status and whether they are in an active maintenance window",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plants"
+                ],
+                "summary": "List plants",
+                "parameters": [
+                    {
+                        "enum": [
+                            "solar",
+                            "wind",
+                            "hydro",
+                            "storage",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Plant type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/rest.PlantResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}": {
+            "get": {
+                "description": "Get a single energy plant with its current status and active maintenance window",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plants"
+                ],
+                "summary": "Get a plant by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.PlantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/anomaly-settings": {
+            "get": {
+                "description": "Get the effective detector settings of a plant: its own (source=plant) or the ANOMALY_* defaults (source=default)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "Get the anomaly detector settings of a plant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AnomalySettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Set the plant's own detector settings; omitted fields keep their current value. The plant baseline is rebuilt from recent history with the next reading.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "Tune the anomaly detector for a plant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Detector settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.AnomalySettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.AnomalySettingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the plant's own settings so the ANOMALY_* defaults apply again",
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "Reset the anomaly detector settings of a plant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/connectivity": {
+            "get": {
+                "description": "Last reading received from a plant, its expected reporting interval and whether it is online",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "connectivity"
+                ],
+                "summary": "Get plant connectivity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlantConnectivity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Set how often a plant is expected to report (Go duration, at least 1s), or send expected_interval null to use PLANT_EXPECTED_INTERVAL. The new threshold applies from the next check.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "connectivity"
+                ],
+                "summary": "Set plant expected interval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expected interval",
+                        "name": "interval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.PlantConnectivityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlantConnectivity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/emissions": {
+            "get": {
+                "description": "Avoided emissions (tCO2e) of a plant per day, week or month: generated energy of each day times the grid factor of the plant region valid that day (the default factor when the region has none). Energy of days without any factor is reported as uncovered_mwh. factor_version and the applied factors are returned so the report can be reproduced.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emissions"
+                ],
+                "summary": "Get plant avoided emissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Use only factors up to this version (default latest)",
+                        "name": "factor_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlantEmissionsReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/energy": {
+            "get": {
+                "description": "Energy generated, consumed and net (MWh) of a plant per day, week (ISO, Monday first) or month (UTC), integrated from power readings with the trapezoidal rule using the reading timestamp. Gaps longer than ENERGY_MAX_GAP count as no data. from and to are widened to whole days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "energy"
+                ],
+                "summary": "Get plant energy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default day)",
+                        "name": "granularity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlantEnergyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/energy/recompute": {
+            "post": {
+                "description": "Integrates again the stored readings of a plant for whole days in the range and replaces its daily totals. Use it after a backfill or to pick up readings that arrived out of order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "energy"
+                ],
+                "summary": "Recompute plant energy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago or the first day still within ROLLUP_RAW_RETENTION); earlier days are rejected",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.EnergyDailyEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/forecast": {
+            "get": {
+                "description": "Hourly power_generated_mw forecast of the latest model run (additive Holt-Winters with daily seasonality) with confidence intervals, from the current hour up to the requested horizon. Backtest metrics tell how far the model missed the last hours it did not see.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "forecasts"
+                ],
+                "summary": "Get plant production forecast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Forecast horizon, e.g. 24h or 7d (default 24h)",
+                        "name": "horizon",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ForecastEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Fits the plant model on its recent history now and stores a new run. Needs at least two days of readings and one in the last day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "forecasts"
+                ],
+                "summary": "Retrain plant forecast",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ForecastEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/forecast/runs": {
+            "get": {
+                "description": "Previous model runs of a plant (newest first) with their fitted parameters and backtest error, to track how much the forecast can be trusted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "forecasts"
+                ],
+                "summary": "List plant forecast runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/rest.ForecastRunResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/kpis": {
+            "get": {
+                "description": "Capacity factor, average efficiency, peak output, energy generated and consumed, net export and time in each status for a plant, per day, week (ISO, Monday first) or month (UTC). Energy is integrated from good power readings with the trapezoidal rule, like the energy endpoints; gaps longer than ENERGY_MAX_GAP count as no data. telemetry summarizes the metrics of the plant type (irradiance, wind speed, reservoir level, state of charge); storage plants also report round_trip_efficiency_percent (discharged / charged energy).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "kpis"
+                ],
+                "summary": "Get plant KPIs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago or the first day still within ROLLUP_RAW_RETENTION); earlier times are rejected",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Leave maintenance windows out of every KPI and of the available capacity",
+                        "name": "exclude_maintenance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Use only readings that passed the plausibility checks (quality=good)",
+                        "name": "exclude_suspect",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlantKPIReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/portfolio-node": {
+            "put": {
+                "description": "Place a plant under a site of the portfolio, or send node_id null to leave it unassigned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Assign a plant to a site",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Site",
+                        "name": "node",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.PlantPortfolioNodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.PlantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/readings": {
+            "get": {
+                "description": "Count, mean, min and max of a metric of a plant per bucket, from good readings only. The series is read from the finest tier that still keeps the range and whose resolution divides the bucket: raw events (ROLLUP_RAW_RETENTION), 1-minute rollups (ROLLUP_1M_RETENTION), 1-hour rollups (ROLLUP_1H_RETENTION) or daily rollups (kept forever); tier tells which one was used. Buckets are aligned to UTC and empty buckets are left out; the most recent minutes may be missing from a rollup tier until its next build.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plants"
+                ],
+                "summary": "Get plant reading series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metric (default power_generated_mw)",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 24 hours ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket size as a Go duration, e.g. 1m, 15m, 1h, 24h (default 1h)",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ReadingSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/status": {
+            "get": {
+                "description": "Current operational status of a plant (operational, maintenance, standby, peak_load) as reported by its latest readings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plant-status"
+                ],
+                "summary": "Get plant status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.PlantStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/status/history": {
+            "get": {
+                "description": "Status changes of a plant in chronological order. Transitions outside the allowed ones are flagged with allowed=false.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plant-status"
+                ],
+                "summary": "Get plant status timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changed at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changed before (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.PlantStatusHistoryEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/plants/{id}/status/time-in-state": {
+            "get": {
+                "description": "Hours a plant spent in each status during a period. The status at the start of the period is the last one reported before it; time before the first known status is reported as unknown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "plant-status"
+                ],
+                "summary": "Get plant time in each status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default now)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PlantTimeInState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/nodes": {
+            "get": {
+                "description": "Get organizations, regions and sites ordered by name, optionally only one level or only the direct children of a node",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "List portfolio nodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization, region or site",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parent node UUID",
+                        "name": "parent_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.PortfolioNodeEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create an organization (no parent), a region (parent is an organization) or a site (parent is a region)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Create a portfolio node",
+                "parameters": [
+                    {
+                        "description": "Portfolio node",
+                        "name": "node",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.PortfolioNodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PortfolioNodeEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/nodes/{id}": {
+            "get": {
+                "description": "Get a single organization, region or site",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get a portfolio node by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PortfolioNodeEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Rename a node, change its description or move it under another parent of the level above. The level cannot change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Update a portfolio node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Portfolio node",
+                        "name": "node",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.PortfolioNodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PortfolioNodeEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a node that has no child nodes and no plants assigned",
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Delete a portfolio node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/nodes/{id}/emissions": {
+            "get": {
+                "description": "Avoided emissions (tCO2e) of all plants under an organization, region or site per period, plus the range total of every plant. Each plant uses the factor of its own region.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emissions"
+                ],
+                "summary": "Get portfolio node avoided emissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default day)",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Use only factors up to this version (default latest)",
+                        "name": "factor_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.EmissionsReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portfolio/nodes/{id}/summary": {
+            "get": {
+                "description": "Roll-up of every plant below the node: plant count, capacity, current output (latest good reading within PORTFOLIO_OUTPUT_MAX_AGE), energy of the range and firing alerts by severity. Also returned for each direct child node and for the plants assigned directly to the node. from and to are widened to whole days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Get portfolio node summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Portfolio node ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Energy range start (RFC3339 or YYYY-MM-DD, default 30 days ago)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Energy range end, exclusive (RFC3339 or YYYY-MM-DD, default end of today)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.PortfolioReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/quarantine": {
+            "get": {
+                "description": "Messages from plants that are not registered, grouped by plant_source_id (most recent activity first). Only filled with UNKNOWN_PLANT_MODE=quarantine.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quarantine"
+                ],
+                "summary": "List quarantined plants",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.QuarantinedPlant"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/quarantine/{plant_id}": {
+            "get": {
+                "description": "Number of quarantined messages of a plant, their time range and whether the plant is already registered",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quarantine"
+                ],
+                "summary": "Get a quarantined plant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant source ID",
+                        "name": "plant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.QuarantinedPlant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the quarantined messages of a plant without storing them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quarantine"
+                ],
+                "summary": "Discard quarantined events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant source ID",
+                        "name": "plant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.QuarantineDiscardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/quarantine/{plant_id}/events": {
+            "get": {
+                "description": "Quarantined messages of a plant ordered by event time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quarantine"
+                ],
+                "summary": "List quarantined events of a plant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant source ID",
+                        "name": "plant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.QuarantinedEventEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/quarantine/{plant_id}/register": {
+            "post": {
+                "description": "Create the plant with the quarantined plant_source_id and release its messages into events. Released messages go through the intake again (quality checks, maintenance, alerts, aggregates) in event time order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quarantine"
+                ],
+                "summary": "Register a quarantined plant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant source ID",
+                        "name": "plant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Plant",
+                        "name": "plant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.RegisterQuarantinedPlantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/rest.QuarantineReleaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/quarantine/{plant_id}/release": {
+            "post": {
+                "description": "Release the quarantined messages of a plant that is already registered into events. Stops at the first error; the remaining messages stay quarantined and can be released again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quarantine"
+                ],
+                "summary": "Release quarantined events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plant source ID",
+                        "name": "plant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rest.QuarantineReleaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhook-deliveries": {
+            "get": {
+                "description": "Get the webhook delivery log, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type (e.g. power_reading, alert.firing)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.WebhookDeliveryEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhook-deliveries/{id}": {
+            "get": {
+                "description": "Get a webhook delivery with every attempt and its response code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook delivery by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.WebhookDeliveryEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhook-deliveries/{id}/redeliver": {
+            "post": {
+                "description": "Queue a failed or delivered webhook delivery again with the same payload and delivery ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entities.WebhookDeliveryEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "Get all webhook subscriptions with their filters, state and consecutive failures",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.WebhookSubscriptionEntity"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a webhook endpoint with its own HMAC secret and event type / plant filters (empty filters = everything). If no secret is sent one is generated; the secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create a webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Webhook subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/rest.WebhookSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "get": {
+                "description": "Get a single webhook subscription by its UUID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook subscription by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.WebhookSubscriptionEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the URL, filters and state (active or paused) of a subscription. Setting an auto-disabled subscription back to active resets its failure counter. An empty secret keeps the current one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.WebhookSubscriptionEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook subscription; its delivery history is kept in /api/v1/webhook-deliveries",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Get the delivery history of a subscription, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List deliveries of a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.WebhookDeliveryEntity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/test": {
+            "post": {
+                "description": "Queue a signed webhook.test notification to the subscription, regardless of its filters and state",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Send a test event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/entities.WebhookDeliveryEntity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Run the readiness checks (database, migrations, Kafka connectivity, consumer loop, event generator) and report the status of each one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entities.AlertEntity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "description": "Evento que disparó la alerta",
+                    "type": "string"
+                },
+                "fired_at": {
+                    "description": "Momento en que se cumplió el \"for\"",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "threshold"
+                },
+                "maintenance_window_id": {
+                    "description": "Ventana de mantenimiento activa al dispararse (la severidad se bajó a info)",
+                    "type": "string"
+                },
+                "metric": {
+                    "type": "string",
+                    "example": "temperature_celsius"
+                },
+                "operator": {
+                    "type": "string",
+                    "example": "\u003e"
+                },
+                "pending_since": {
+                    "description": "Primera lectura que cumplió la condición",
+                    "type": "string"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "description": "Momento de resolución",
+                    "type": "string"
+                },
+                "resolved_value": {
+                    "description": "Valor que resolvió la alerta",
+                    "type": "number",
+                    "example": 42.1
+                },
+                "rule_id": {
+                    "type": "string"
+                },
+                "rule_name": {
+                    "type": "string",
+                    "example": "High temperature"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "warning"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "firing"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 45
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Valor que disparó la alerta",
+                    "type": "number",
+                    "example": 47.3
+                }
+            }
+        },
+        "entities.AnomalyEntity": {
+            "type": "object",
+            "properties": {
+                "baseline_mean": {
+                    "type": "number",
+                    "example": 402.7
+                },
+                "baseline_std": {
+                    "type": "number",
+                    "example": 35.1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "detected_at": {
+                    "description": "Timestamp de la lectura",
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string",
+                    "example": "low"
+                },
+                "event_id": {
+                    "description": "Evento de la lectura anómala",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metric": {
+                    "type": "string",
+                    "example": "power_generated_mw"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "z-score: (value - mean) / std",
+                    "type": "number",
+                    "example": -11.12
+                },
+                "threshold": {
+                    "description": "|score| mínimo vigente para la planta",
+                    "type": "number",
+                    "example": 4
+                },
+                "value": {
+                    "type": "number",
+                    "example": 12.4
+                }
+            }
+        },
+        "entities.EmissionFactorEntity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "region_node_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "REE 2025 grid mix"
+                },
+                "tco2e_per_mwh": {
+                    "type": "number",
+                    "example": 0.21
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2026-01-01T00:00:00Z"
+                },
+                "valid_to": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "entities.EmissionsPeriod": {
+            "type": "object",
+            "properties": {
+                "avoided_tco2e": {
+                    "type": "number",
+                    "example": 382.3
+                },
+                "end": {
+                    "type": "string"
+                },
+                "generated_mwh": {
+                    "type": "number",
+                    "example": 1820.5
+                },
+                "start": {
+                    "type": "string"
+                },
+                "uncovered_mwh": {
+                    "type": "number",
+                    "example": 0
+                }
+            }
+        },
+        "entities.EmissionsReport": {
+            "type": "object",
+            "properties": {
+                "factor_version": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "factors": {
+                    "description": "Factores aplicados",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.EmissionFactorEntity"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "node": {
+                    "$ref": "#/definitions/entities.PortfolioNodeRef"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.EmissionsPeriod"
+                    }
+                },
+                "plants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PlantEmissionsReport"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.EmissionsPeriod"
+                }
+            }
+        },
+        "entities.EnergyDailyEntity": {
+            "type": "object",
+            "properties": {
+                "consumed_mwh": {
+                    "type": "number",
+                    "example": 96.2
+                },
+                "covered_hours": {
+                    "type": "number",
+                    "example": 23.5
+                },
+                "day": {
+                    "type": "string"
+                },
+                "generated_mwh": {
+                    "type": "number",
+                    "example": 1820.5
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.EnergyPeriod": {
+            "type": "object",
+            "properties": {
+                "consumed_mwh": {
+                    "type": "number",
+                    "example": 96.2
+                },
+                "covered_hours": {
+                    "type": "number",
+                    "example": 23.5
+                },
+                "end": {
+                    "type": "string"
+                },
+                "generated_mwh": {
+                    "type": "number",
+                    "example": 1820.5
+                },
+                "net_mwh": {
+                    "description": "Generada - consumida",
+                    "type": "number",
+                    "example": 1724.3
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.EnergyPlants": {
+            "type": "object",
+            "properties": {
+                "capacityMW": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "intervalSeconds": {
+                    "description": "Intervalo de reporte esperado en segundos (nil = PLANT_EXPECTED_INTERVAL)",
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "plantName": {
+                    "type": "string"
+                },
+                "plantType": {
+                    "type": "string"
+                },
+                "portfolioNodeID": {
+                    "description": "Sitio del portfolio al que pertenece (nil = sin asignar)",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Último estado reportado (vacío hasta el primer evento con status)",
+                    "type": "string"
+                },
+                "statusChangedAt": {
+                    "description": "Momento del último cambio de estado",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.EventEntity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "Cambiado de jsonb a text para compatibilidad con GORM string type",
+                    "type": "string"
+                },
+                "event_time": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "maintenance_window_id": {
+                    "description": "CAMBIO: Ventana de mantenimiento activa cuando se tomó la lectura\nRAZÓN: Las lecturas tomadas durante un mantenimiento planificado quedan marcadas",
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "string"
+                },
+                "plant_source": {
+                    "description": "Relaciones",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.EnergyPlants"
+                        }
+                    ]
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "quality": {
+                    "description": "CAMBIO: Calidad de la lectura según los chequeos de plausibilidad del intake\nRAZÓN: Las lecturas sospechosas se guardan marcadas en lugar de aceptarse sin más",
+                    "type": "string",
+                    "example": "suspect"
+                },
+                "quality_flags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "above_capacity:power_generated_mw"
+                    ]
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ExampleEntity": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.FleetEnergyReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.EnergyPeriod"
+                    }
+                },
+                "plants": {
+                    "description": "Solo el total de cada planta",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PlantEnergyReport"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.EnergyPeriod"
+                }
+            }
+        },
+        "entities.FleetKPIReport": {
+            "type": "object",
+            "properties": {
+                "capacity_mw": {
+                    "type": "number",
+                    "example": 1250
+                },
+                "exclude_maintenance": {
+                    "type": "boolean"
+                },
+                "exclude_suspect": {
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.KPIPeriod"
+                    }
+                },
+                "plants": {
+                    "description": "Solo el total de cada planta",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PlantKPIReport"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.KPIPeriod"
+                }
+            }
+        },
+        "entities.ForecastEntity": {
+            "type": "object",
+            "properties": {
+                "alpha": {
+                    "type": "number",
+                    "example": 0.2
+                },
+                "backtest_hours": {
+                    "description": "0 si no hubo historial suficiente",
+                    "type": "integer",
+                    "example": 24
+                },
+                "backtest_mae": {
+                    "type": "number",
+                    "example": 6.1
+                },
+                "backtest_nmae_percent": {
+                    "type": "number",
+                    "example": 4.1
+                },
+                "backtest_rmse": {
+                    "type": "number",
+                    "example": 9.4
+                },
+                "beta": {
+                    "type": "number",
+                    "example": 0.01
+                },
+                "confidence": {
+                    "type": "number",
+                    "example": 0.95
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gamma": {
+                    "type": "number",
+                    "example": 0.3
+                },
+                "horizon_hours": {
+                    "type": "integer",
+                    "example": 168
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "holt_winters_additive"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ForecastPoint"
+                    }
+                },
+                "residual_std": {
+                    "description": "Desvío del error a un paso (MW)",
+                    "type": "number",
+                    "example": 8.3
+                },
+                "season_length": {
+                    "description": "Puntos por temporada (horas por día)",
+                    "type": "integer",
+                    "example": 24
+                },
+                "trained_from": {
+                    "type": "string"
+                },
+                "trained_to": {
+                    "description": "Última hora con datos usada para entrenar",
+                    "type": "string"
+                },
+                "training_points": {
+                    "type": "integer",
+                    "example": 672
+                }
+            }
+        },
+        "entities.ForecastPoint": {
+            "type": "object",
+            "properties": {
+                "lower": {
+                    "type": "number",
+                    "example": 70.2
+                },
+                "time": {
+                    "type": "string"
+                },
+                "upper": {
+                    "type": "number",
+                    "example": 104.8
+                },
+                "value": {
+                    "type": "number",
+                    "example": 87.5
+                }
+            }
+        },
+        "entities.KPIPeriod": {
+            "type": "object",
+            "properties": {
+                "avg_efficiency_percent": {
+                    "type": "number",
+                    "example": 86.4
+                },
+                "capacity_factor": {
+                    "description": "nil si la capacidad es 0",
+                    "type": "number",
+                    "example": 0.3792
+                },
+                "covered_hours": {
+                    "type": "number",
+                    "example": 23.5
+                },
+                "end": {
+                    "type": "string"
+                },
+                "energy_consumed_mwh": {
+                    "type": "number",
+                    "example": 96.2
+                },
+                "energy_generated_mwh": {
+                    "type": "number",
+                    "example": 1820.5
+                },
+                "hours_in_period": {
+                    "type": "number",
+                    "example": 24
+                },
+                "maintenance_hours": {
+                    "type": "number",
+                    "example": 4
+                },
+                "net_export_mwh": {
+                    "type": "number",
+                    "example": 1724.3
+                },
+                "peak_output_at": {
+                    "type": "string"
+                },
+                "peak_output_mw": {
+                    "type": "number",
+                    "example": 410.7
+                },
+                "readings": {
+                    "type": "integer",
+                    "example": 288
+                },
+                "round_trip_efficiency_percent": {
+                    "type": "number",
+                    "example": 88.5
+                },
+                "start": {
+                    "type": "string"
+                },
+                "telemetry": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/entities.TelemetryStats"
+                    }
+                },
+                "time_in_status_hours": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "entities.MetricDefinition": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Wind speed at hub height"
+                },
+                "max": {
+                    "type": "number",
+                    "example": 75
+                },
+                "min": {
+                    "type": "number",
+                    "example": 0
+                },
+                "name": {
+                    "type": "string",
+                    "example": "wind_speed_m_s"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "m/s"
+                }
+            }
+        },
+        "entities.PlantConnectivity": {
+            "type": "object",
+            "properties": {
+                "alert_id": {
+                    "type": "string"
+                },
+                "custom_interval": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "expected_interval_seconds": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "last_event_time": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "offline_after_seconds": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "plant_name": {
+                    "type": "string",
+                    "example": "Solar Plant Alpha"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "plant_type": {
+                    "type": "string",
+                    "example": "solar"
+                },
+                "silent_seconds": {
+                    "type": "number",
+                    "example": 42
+                },
+                "state": {
+                    "type": "string",
+                    "example": "online"
+                }
+            }
+        },
+        "entities.PlantEmissionsReport": {
+            "type": "object",
+            "properties": {
+                "factor_version": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "factors": {
+                    "description": "Factores aplicados",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.EmissionFactorEntity"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "example": "month"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.EmissionsPeriod"
+                    }
+                },
+                "plant_name": {
+                    "type": "string",
+                    "example": "Solar Plant Alpha"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "region_node_id": {
+                    "description": "Región del portfolio cuyo factor se aplica (nil = factor por defecto)",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.EmissionsPeriod"
+                }
+            }
+        },
+        "entities.PlantEnergyReport": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "example": "day"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.EnergyPeriod"
+                    }
+                },
+                "plant_name": {
+                    "type": "string",
+                    "example": "Solar Plant Alpha"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.EnergyPeriod"
+                }
+            }
+        },
+        "entities.PlantKPIReport": {
+            "type": "object",
+            "properties": {
+                "capacity_mw": {
+                    "type": "number",
+                    "example": 200
+                },
+                "exclude_maintenance": {
+                    "type": "boolean"
+                },
+                "exclude_suspect": {
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string",
+                    "example": "day"
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.KPIPeriod"
+                    }
+                },
+                "plant_name": {
+                    "type": "string",
+                    "example": "Solar Plant Alpha"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "plant_type": {
+                    "type": "string",
+                    "example": "solar"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/entities.KPIPeriod"
+                }
+            }
+        },
+        "entities.PlantStatusHistoryEntity": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "description": "Evento que reportó el nuevo estado",
+                    "type": "string"
+                },
+                "from_status": {
+                    "description": "Vacío en el primer estado conocido",
+                    "type": "string",
+                    "example": "operational"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "to_status": {
+                    "type": "string",
+                    "example": "maintenance"
+                }
+            }
+        },
+        "entities.PlantTimeInState": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "hours": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Recortado a la hora actual si el período no terminó",
+                    "type": "string"
+                },
+                "transitions": {
+                    "description": "Cambios de estado dentro del período",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "entities.PlantTypeDefinition": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Wind farm"
+                },
+                "metrics": {
+                    "description": "Comunes y propias del tipo",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.MetricDefinition"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "wind"
+                }
+            }
+        },
+        "entities.PortfolioNodeEntity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "site"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Andalusia South"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.PortfolioNodeRef": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "region"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Spain"
+                }
+            }
+        },
+        "entities.PortfolioNodeSummary": {
+            "type": "object",
+            "properties": {
+                "alerts_by_severity": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "capacity_mw": {
+                    "type": "number",
+                    "example": 640
+                },
+                "consumed_mwh": {
+                    "type": "number",
+                    "example": 1520.7
+                },
+                "current_output_mw": {
+                    "type": "number",
+                    "example": 412.5
+                },
+                "firing_alerts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "generated_mwh": {
+                    "type": "number",
+                    "example": 98120.4
+                },
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "region"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Spain"
+                },
+                "net_mwh": {
+                    "type": "number",
+                    "example": 96599.7
+                },
+                "plant_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "reporting_plants": {
+                    "type": "integer",
+                    "example": 11
+                }
+            }
+        },
+        "entities.PortfolioPlantSummary": {
+            "type": "object",
+            "properties": {
+                "capacity_mw": {
+                    "type": "number",
+                    "example": 200
+                },
+                "consumed_mwh": {
+                    "type": "number",
+                    "example": 410.2
+                },
+                "current_output_mw": {
+                    "description": "nil si no reportó dentro de PORTFOLIO_OUTPUT_MAX_AGE",
+                    "type": "number",
+                    "example": 143.2
+                },
+                "firing_alerts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "generated_mwh": {
+                    "type": "number",
+                    "example": 30112.9
+                },
+                "output_at": {
+                    "type": "string"
+                },
+                "plant_name": {
+                    "type": "string",
+                    "example": "Solar Plant Alpha"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "plant_type": {
+                    "type": "string",
+                    "example": "solar"
+                }
+            }
+        },
+        "entities.PortfolioReport": {
+            "type": "object",
+            "properties": {
+                "alerts_by_severity": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "capacity_mw": {
+                    "type": "number",
+                    "example": 640
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PortfolioNodeSummary"
+                    }
+                },
+                "consumed_mwh": {
+                    "type": "number",
+                    "example": 1520.7
+                },
+                "current_output_mw": {
+                    "type": "number",
+                    "example": 412.5
+                },
+                "firing_alerts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "from": {
+                    "type": "string"
+                },
+                "generated_mwh": {
+                    "type": "number",
+                    "example": 98120.4
+                },
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "region"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Spain"
+                },
+                "net_mwh": {
+                    "type": "number",
+                    "example": 96599.7
+                },
+                "path": {
+                    "description": "Desde la organización hasta el nodo (incluido)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PortfolioNodeRef"
+                    }
+                },
+                "plant_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "plants": {
+                    "description": "Solo las asignadas directamente (sitios)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.PortfolioPlantSummary"
+                    }
+                },
+                "reporting_plants": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.QuarantinedEventEntity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "event_time": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "power_reading"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "string"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "Solar Plant Delta"
+                }
+            }
+        },
+        "entities.QuarantinedPlant": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "first_event_time": {
+                    "type": "string"
+                },
+                "first_received_at": {
+                    "type": "string"
+                },
+                "last_event_time": {
+                    "type": "string"
+                },
+                "last_received_at": {
+                    "type": "string"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "registered": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "source": {
+                    "type": "string",
+                    "example": "Solar Plant Delta"
+                }
+            }
+        },
+        "entities.ReadingSeries": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "1h0m0s"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "metric": {
+                    "type": "string",
+                    "example": "power_generated_mw"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.ReadingSeriesPoint"
+                    }
+                },
+                "tier": {
+                    "type": "string",
+                    "example": "1m"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.ReadingSeriesPoint": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "max": {
+                    "type": "number",
+                    "example": 131.8
+                },
+                "mean": {
+                    "type": "number",
+                    "example": 120.5
+                },
+                "min": {
+                    "type": "number",
+                    "example": 110.2
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.TelemetryStats": {
+            "type": "object",
+            "properties": {
+                "max": {
+                    "type": "number",
+                    "example": 18.2
+                },
+                "mean": {
+                    "type": "number",
+                    "example": 7.4
+                },
+                "min": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "readings": {
+                    "type": "integer",
+                    "example": 288
+                }
+            }
+        },
+        "entities.TimeRange": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.WebhookAttemptEntity": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "error": {
+                    "type": "string",
+                    "example": "webhook returned error status: 503"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "description": "nil si no hubo respuesta HTTP",
+                    "type": "integer",
+                    "example": 503
+                }
+            }
+        },
+        "entities.WebhookDeliveryEntity": {
+            "type": "object",
+            "properties": {
+                "attempt_count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.WebhookAttemptEntity"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "alert.firing"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "payload": {
+                    "description": "Cuerpo JSON firmado y enviado",
+                    "type": "string"
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "description": "Evento o alerta notificada",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "delivered"
+                },
+                "subscription_id": {
+                    "description": "nil = WEBHOOK_URL global",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/energy"
+                }
+            }
+        },
+        "entities.WebhookSubscriptionEntity": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "alert.firing",
+                        "alert.resolved"
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ops pager"
+                },
+                "plant_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://pager.example.com/hooks/energy"
+                }
+            }
+        },
+        "health.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "context deadline exceeded"
+                },
+                "latency_ms": {
+                    "type": "number",
+                    "example": 1.25
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckResult"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "rest.ActiveMaintenanceWindow": {
+            "type": "object",
+            "properties": {
+                "alert_policy": {
+                    "type": "string",
+                    "example": "suppress"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Inverter replacement"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.AlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "expression",
+                "name"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "expression": {
+                    "type": "string",
+                    "example": "temperature_celsius \u003e 45 for 10m"
+                },
+                "hysteresis": {
+                    "type": "number",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "High temperature"
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "plant_type"
+                },
+                "scope_value": {
+                    "type": "string",
+                    "example": "solar"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "warning"
+                }
+            }
+        },
+        "rest.AlertRuleResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "expression": {
+                    "type": "string",
+                    "example": "temperature_celsius \u003e 45 for 10m0s"
+                },
+                "for_seconds": {
+                    "type": "integer",
+                    "example": 600
+                },
+                "hysteresis": {
+                    "type": "number",
+                    "example": 2
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metric": {
+                    "type": "string",
+                    "example": "temperature_celsius"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "High temperature"
+                },
+                "operator": {
+                    "type": "string",
+                    "example": "\u003e"
+                },
+                "scope_type": {
+                    "type": "string",
+                    "example": "all"
+                },
+                "scope_value": {
+                    "type": "string",
+                    "example": "solar"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "warning"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 45
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.AnomalySettingsRequest": {
+            "type": "object",
+            "properties": {
+                "alpha": {
+                    "type": "number",
+                    "example": 0.05
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "power_generated_mw",
+                        "efficiency_percent"
+                    ]
+                },
+                "warm_up": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "z_threshold": {
+                    "type": "number",
+                    "example": 4
+                }
+            }
+        },
+        "rest.AnomalySettingsResponse": {
+            "type": "object",
+            "properties": {
+                "alpha": {
+                    "type": "number",
+                    "example": 0.05
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "power_generated_mw",
+                        "efficiency_percent"
+                    ]
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "plant or default",
+                    "type": "string",
+                    "example": "plant"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warm_up": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "z_threshold": {
+                    "type": "number",
+                    "example": 4
+                }
+            }
+        },
+        "rest.CreateExampleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "This is an example description"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "My Example"
+                }
+            }
+        },
+        "rest.EmissionFactorRequest": {
+            "type": "object",
+            "required": [
+                "tco2e_per_mwh",
+                "valid_from"
+            ],
+            "properties": {
+                "region_node_id": {
+                    "description": "Portfolio region, or null for the default factor",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Publication the factor comes from",
+                    "type": "string",
+                    "example": "REE 2025 grid mix"
+                },
+                "tco2e_per_mwh": {
+                    "description": "Tonnes of CO2e per MWh of grid electricity",
+                    "type": "number",
+                    "example": 0.21
+                },
+                "valid_from": {
+                    "description": "YYYY-MM-DD (UTC)",
+                    "type": "string",
+                    "example": "2026-01-01"
+                },
+                "valid_to": {
+                    "description": "YYYY-MM-DD (UTC, exclusive); empty = open ended",
+                    "type": "string",
+                    "example": "2027-01-01"
+                }
+            }
+        },
+        "rest.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "error message"
+                }
+            }
+        },
+        "rest.ForecastRunResponse": {
+            "type": "object",
+            "properties": {
+                "alpha": {
+                    "type": "number",
+                    "example": 0.2
+                },
+                "backtest_hours": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "backtest_mae": {
+                    "type": "number",
+                    "example": 6.1
+                },
+                "backtest_nmae_percent": {
+                    "description": "MAE as % of plant capacity",
+                    "type": "number",
+                    "example": 4.1
+                },
+                "backtest_rmse": {
+                    "type": "number",
+                    "example": 9.4
+                },
+                "beta": {
+                    "type": "number",
+                    "example": 0.01
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gamma": {
+                    "type": "number",
+                    "example": 0.3
+                },
+                "id": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string",
+                    "example": "holt_winters_additive"
+                },
+                "residual_std": {
+                    "type": "number",
+                    "example": 8.3
                 },
-                "data": {
-                    "description": "Cambiado de jsonb a text para compatibilidad con GORM string type",
+                "trained_from": {
                     "type": "string"
                 },
-                "event_type": {
+                "trained_to": {
                     "type": "string"
                 },
-                "id": {
+                "training_points": {
+                    "type": "integer",
+                    "example": 672
+                }
+            }
+        },
+        "rest.LogLevelRequest": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "debug"
+                }
+            }
+        },
+        "rest.LogLevelResponse": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "INFO"
+                }
+            }
+        },
+        "rest.MaintenanceWindowRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "name",
+                "plant_source_id",
+                "starts_at"
+            ],
+            "properties": {
+                "alert_policy": {
+                    "description": "suppress (default) or downgrade",
+                    "type": "string",
+                    "example": "suppress"
+                },
+                "ends_at": {
                     "type": "string"
                 },
-                "metadata": {
+                "name": {
+                    "type": "string",
+                    "example": "Inverter replacement"
+                },
+                "plant_source_id": {
                     "type": "string"
                 },
-                "plant_source": {
-                    "description": "Relaciones",
+                "recurrence": {
+                    "description": "none (default), daily, weekly or monthly",
+                    "type": "string",
+                    "example": "weekly"
+                },
+                "recurrence_until": {
+                    "description": "Last occurrence starts before this time",
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.MaintenanceWindowResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "alert_policy": {
+                    "type": "string",
+                    "example": "suppress"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Inverter replacement"
+                },
+                "next_occurrence": {
+                    "description": "Current occurrence when active",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/entities.EnergyPlants"
+                            "$ref": "#/definitions/entities.TimeRange"
                         }
                     ]
                 },
                 "plant_source_id": {
                     "type": "string"
                 },
-                "source": {
+                "recurrence": {
+                    "type": "string",
+                    "example": "weekly"
+                },
+                "recurrence_until": {
+                    "description": "Última ocurrencia empieza antes de este instante",
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "entities.ExampleEntity": {
+        "rest.PlantConnectivityRequest": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "expected_interval": {
+                    "description": "Go duration, or null to use PLANT_EXPECTED_INTERVAL",
+                    "type": "string",
+                    "example": "5m"
+                }
+            }
+        },
+        "rest.PlantPortfolioNodeRequest": {
+            "type": "object",
+            "properties": {
+                "node_id": {
+                    "description": "Site ID, or null to unassign",
                     "type": "string"
+                }
+            }
+        },
+        "rest.PlantResponse": {
+            "type": "object",
+            "properties": {
+                "capacity_mw": {
+                    "type": "number",
+                    "example": 200
                 },
-                "description": {
-                    "type": "string"
+                "expected_interval_seconds": {
+                    "description": "Own reporting interval (default PLANT_EXPECTED_INTERVAL)",
+                    "type": "integer",
+                    "example": 300
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "in_maintenance": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Seville"
+                },
+                "maintenance_window": {
+                    "$ref": "#/definitions/rest.ActiveMaintenanceWindow"
+                },
+                "plant_name": {
+                    "type": "string",
+                    "example": "Solar Plant Alpha"
+                },
+                "plant_type": {
+                    "type": "string",
+                    "example": "solar"
+                },
+                "portfolio_node_id": {
+                    "description": "Site the plant belongs to",
                     "type": "string"
                 },
-                "updatedAt": {
+                "status": {
+                    "type": "string",
+                    "example": "operational"
+                },
+                "status_changed_at": {
                     "type": "string"
                 }
             }
         },
-        "rest.CreateExampleRequest": {
+        "rest.PlantStatusResponse": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "description": "Time in the current status",
+                    "type": "number",
+                    "example": 5400
+                },
+                "in_maintenance": {
+                    "description": "Inside an active maintenance window",
+                    "type": "boolean",
+                    "example": false
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Empty until the plant reports a status",
+                    "type": "string",
+                    "example": "operational"
+                }
+            }
+        },
+        "rest.PortfolioNodeRequest": {
             "type": "object",
             "required": [
                 "name"
@@ -444,20 +6110,194 @@ const docTemplate = `{
             "properties": {
                 "description": {
                     "type": "string",
-                    "example": "This is an example description"
+                    "example": "Solar parks south of Seville"
+                },
+                "level": {
+                    "description": "organization, region or site (required on create, cannot change)",
+                    "type": "string",
+                    "example": "site"
                 },
                 "name": {
                     "type": "string",
-                    "example": "My Example"
+                    "example": "Andalusia South"
+                },
+                "parent_id": {
+                    "description": "Organization for a region, region for a site",
+                    "type": "string"
                 }
             }
         },
-        "rest.ErrorResponse": {
+        "rest.QuarantineDiscardResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "discarded": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "plant_source_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.QuarantineReleaseResponse": {
+            "type": "object",
+            "properties": {
+                "plant": {
+                    "description": "Registered plant (register only)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/rest.PlantResponse"
+                        }
+                    ]
+                },
+                "plant_source_id": {
+                    "type": "string"
+                },
+                "released": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "rest.RegisterQuarantinedPlantRequest": {
+            "type": "object",
+            "properties": {
+                "capacity_mw": {
+                    "type": "number",
+                    "example": 50
+                },
+                "location": {
                     "type": "string",
-                    "example": "error message"
+                    "example": "Almería"
+                },
+                "plant_name": {
+                    "description": "Default: plant_name of the latest quarantined message",
+                    "type": "string",
+                    "example": "Solar Plant Delta"
+                },
+                "plant_type": {
+                    "description": "Default: other",
+                    "type": "string",
+                    "example": "solar"
+                }
+            }
+        },
+        "rest.SimulatorBurstRequest": {
+            "type": "object",
+            "required": [
+                "count"
+            ],
+            "properties": {
+                "count": {
+                    "description": "Number of events (1-10000)",
+                    "type": "integer",
+                    "example": 100
+                },
+                "plant_ids": {
+                    "description": "Plants to simulate (empty = all registered plants)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "rest.SimulatorBurstResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "plant_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sent": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "rest.SimulatorRateRequest": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "description": "Events per batch (1-10000)",
+                    "type": "integer",
+                    "example": 60
+                },
+                "interval": {
+                    "description": "Go duration between batches (\u003e= 1s)",
+                    "type": "string",
+                    "example": "1m"
+                },
+                "rate": {
+                    "description": "Events per second within a batch (0 = no pause)",
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "rest.SimulatorStatusResponse": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "batches": {
+                    "description": "Batches and bursts with events",
+                    "type": "integer",
+                    "example": 40
+                },
+                "failed": {
+                    "description": "Events rejected by Kafka",
+                    "type": "integer",
+                    "example": 0
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "5m0s"
+                },
+                "last_batch_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "plants": {
+                    "description": "Plants with simulated state",
+                    "type": "integer",
+                    "example": 4
+                },
+                "rate": {
+                    "description": "Events per second within a batch (0 = no pause)",
+                    "type": "number",
+                    "example": 10
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "sent": {
+                    "description": "Since the service started, bursts included",
+                    "type": "integer",
+                    "example": 1200
+                },
+                "state": {
+                    "description": "running, paused or stopped",
+                    "type": "string",
+                    "example": "running"
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "events.default"
                 }
             }
         },
@@ -473,6 +6313,134 @@ const docTemplate = `{
                     "example": "Updated Example"
                 }
             }
+        },
+        "rest.VersionResponse": {
+            "type": "object",
+            "properties": {
+                "build_date": {
+                    "type": "string",
+                    "example": "2026-01-10T17:11:00Z"
+                },
+                "git_commit": {
+                    "type": "string",
+                    "example": "c8bd6e2"
+                },
+                "go_version": {
+                    "type": "string",
+                    "example": "go1.24.0"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "uptime_seconds": {
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
+        "rest.WebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "alert.firing",
+                        "alert.resolved"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ops pager"
+                },
+                "plant_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Empty on create = generated; empty on update = unchanged",
+                    "type": "string",
+                    "example": "s3cr3t"
+                },
+                "status": {
+                    "description": "active or paused (default active)",
+                    "type": "string",
+                    "example": "active"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://pager.example.com/hooks/energy"
+                }
+            }
+        },
+        "rest.WebhookSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "alert.firing",
+                        "alert.resolved"
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ops pager"
+                },
+                "plant_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://pager.example.com/hooks/energy"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminApiKey": {
+            "description": "Admin endpoints are served on ADMIN_PORT, not on the public port",
+            "type": "apiKey",
+            "name": "X-Admin-Api-Key",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,308 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// AlertEngine evalúa las reglas de umbral contra cada lectura aceptada por el intake
+//
+// PROPÓSITO:
+// Mantiene por cada par (regla, planta) el estado de la condición:
+// - inactiva: la condición no se cumple
+// - pendiente: se cumple desde pendingSince pero aún no pasó el "for" de la regla
+// - disparada: existe una alerta "firing" en la tabla alerts
+//
+// FUNCIONAMIENTO:
+// 1. La primera lectura carga las reglas activas y las alertas "firing" (estado tras un reinicio)
+// 2. Por cada regla que aplica a la planta y cuya métrica viene en la lectura:
+//   - Si hay alerta disparada y el valor cruza el umbral de resolución (hysteresis) → resolved
+//   - Si la condición se cumple durante el "for" (según el timestamp de las lecturas) → firing
+//   - Si la condición deja de cumplirse antes del "for" → vuelve a inactiva
+//
+// 3. Reload se llama al crear, editar o borrar reglas desde la API REST
+// 4. Si la lectura se tomó en una ventana de mantenimiento, las alertas nuevas se suprimen
+// (la condición vuelve a contar desde cero) o se disparan con severidad info según la ventana.
+// Las alertas ya disparadas se pueden resolver igual
+//
+// El estado pendiente vive solo en memoria: tras un reinicio el "for" vuelve a contar.
+// Los listeners se notifican después de liberar el lock, con una copia de cada alerta que cambió.
+type AlertEngine struct {
+	ruleRepository  output.AlertRuleRepositoryInterface
+	alertRepository output.AlertRepositoryInterface
+	metrics         output.MetricsRecorderInterface
+	logger          *slog.Logger
+
+	listeners []input.AlertListener // Notificados al disparar o resolver (ej: WebhookDispatcher)
+
+	mu     sync.Mutex
+	loaded bool
+	rules  []*entities.AlertRuleEntity
+	states map[alertKey]*alertState
+}
+
+// alertKey identifica el estado de una regla para una planta
+type alertKey struct {
+	ruleID  uuid.UUID
+	plantID uuid.UUID
+}
+
+// alertState es el estado de una regla para una planta (pendiente o disparada)
+type alertState struct {
+	pendingSince time.Time
+	alert        *entities.AlertEntity // nil mientras está pendiente
+}
+
+var _ input.ReadingProcessor = &AlertEngine{}
+
+// NewAlertEngine crea el motor de reglas; las reglas se cargan con la primera lectura
+func NewAlertEngine(
+	ruleRepository output.AlertRuleRepositoryInterface,
+	alertRepository output.AlertRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+) *AlertEngine {
+	return &AlertEngine{
+		ruleRepository:  ruleRepository,
+		alertRepository: alertRepository,
+		metrics:         metrics,
+		logger:          logger,
+		states:          make(map[alertKey]*alertState),
+	}
+}
+
+// RegisterListener agrega un listener notificado cada vez que una alerta se dispara o se resuelve
+func (e *AlertEngine) RegisterListener(listener input.AlertListener) {
+	e.listeners = append(e.listeners, listener)
+}
+
+// Reload vuelve a cargar las reglas activas y reconstruye el estado de las alertas disparadas
+// Las alertas "firing" de reglas borradas o deshabilitadas se resuelven
+func (e *AlertEngine) Reload(ctx context.Context) error {
+	e.mu.Lock()
+	resolved, err := e.reload(ctx)
+	e.mu.Unlock()
+
+	e.notify(ctx, resolved)
+	return err
+}
+
+// reload devuelve las alertas resueltas por reglas borradas o deshabilitadas para notificarlas sin el lock
+func (e *AlertEngine) reload(ctx context.Context) ([]entities.AlertEntity, error) {
+	rules, err := e.ruleRepository.FindEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading alert rules: %w", err)
+	}
+	firing, err := e.alertRepository.FindFiring(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading firing alerts: %w", err)
+	}
+
+	active := make(map[uuid.UUID]bool, len(rules))
+	for _, rule := range rules {
+		active[rule.ID] = true
+	}
+
+	var resolved []entities.AlertEntity
+	states := make(map[alertKey]*alertState, len(firing))
+	for _, alert := range firing {
+		if alert.Kind != entities.AlertKindThreshold {
+			continue // Las alertas offline las administra el ConnectivityWatcher
+		}
+		if !active[alert.RuleID] {
+			// El error ya se registró en resolve; la alerta se reintentará en el próximo Reload
+			if err := e.resolve(ctx, alert, time.Now(), nil); err == nil {
+				resolved = append(resolved, *alert)
+			}
+			continue
+		}
+		states[alertKey{ruleID: alert.RuleID, plantID: alert.PlantSourceId}] = &alertState{
+			pendingSince: alert.PendingSince,
+			alert:        alert,
+		}
+	}
+
+	// Conserva las condiciones pendientes de reglas que siguen activas
+	for key, state := range e.states {
+		if state.alert == nil && active[key.ruleID] {
+			states[key] = state
+		}
+	}
+
+	e.rules = rules
+	e.states = states
+	e.loaded = true
+
+	e.logger.InfoContext(ctx, "Alert rules loaded", slog.Int("rules", len(rules)), slog.Int("firing", len(firing)))
+	return resolved, nil
+}
+
+// ProcessReading evalúa todas las reglas que aplican a la planta de la lectura
+// Las lecturas suspect no se evalúan: no disparan, no resuelven ni avanzan la cuenta de for
+//
+// CAMBIO: Los listeners se notifican después de liberar el lock
+// RAZÓN: OnAlert del WebhookDispatcher guarda entregas en la base; con el lock tomado
+// cada notificación frenaba la evaluación de las lecturas de todas las plantas
+func (e *AlertEngine) ProcessReading(ctx context.Context, reading entities.PlantReading) error {
+	if reading.Suspect() {
+		return nil
+	}
+
+	e.mu.Lock()
+	changed, err := e.process(ctx, reading)
+	e.mu.Unlock()
+
+	e.notify(ctx, changed)
+	return err
+}
+
+// process evalúa las reglas con el lock tomado y devuelve las alertas disparadas o resueltas
+// (también las anteriores a un error, que ya quedaron guardadas)
+func (e *AlertEngine) process(ctx context.Context, reading entities.PlantReading) ([]entities.AlertEntity, error) {
+	var changed []entities.AlertEntity
+	if !e.loaded {
+		resolved, err := e.reload(ctx)
+		if err != nil {
+			return nil, err
+		}
+		changed = resolved
+	}
+
+	for _, rule := range e.rules {
+		if !rule.AppliesTo(reading.PlantSourceId, reading.PlantType) {
+			continue
+		}
+		value, ok := reading.Metric(rule.Metric)
+		if !ok {
+			continue
+		}
+		alert, err := e.evaluate(ctx, rule, reading, value)
+		if err != nil {
+			return changed, err
+		}
+		if alert != nil {
+			changed = append(changed, *alert)
+		}
+	}
+	return changed, nil
+}
+
+// evaluate aplica la máquina de estados de una regla para la planta de la lectura
+// Devuelve la alerta si se disparó o se resolvió (nil = sin cambios)
+func (e *AlertEngine) evaluate(ctx context.Context, rule *entities.AlertRuleEntity, reading entities.PlantReading, value float64) (*entities.AlertEntity, error) {
+	key := alertKey{ruleID: rule.ID, plantID: reading.PlantSourceId}
+	state := e.states[key]
+
+	// Disparada: solo se resuelve al cruzar el umbral de resolución
+	if state != nil && state.alert != nil {
+		if !rule.Cleared(value) {
+			return nil, nil
+		}
+		alert := state.alert
+		if err := e.resolve(ctx, alert, reading.Timestamp, &value); err != nil {
+			return nil, err
+		}
+		delete(e.states, key)
+		return alert, nil
+	}
+
+	if !rule.Matches(value) {
+		delete(e.states, key)
+		return nil, nil
+	}
+
+	window := reading.MaintenanceWindow
+	if window != nil && window.AlertPolicy == entities.MaintenanceAlertSuppress {
+		delete(e.states, key)
+		e.logger.DebugContext(ctx, "Alert suppressed by maintenance window",
+			slog.String("rule", rule.Name),
+			slog.String("plant_source_id", reading.PlantSourceId.String()),
+			slog.String("maintenance_window_id", window.ID.String()))
+		return nil, nil
+	}
+
+	if state == nil {
+		state = &alertState{pendingSince: reading.Timestamp}
+		e.states[key] = state
+	}
+
+	if reading.Timestamp.Sub(state.pendingSince) < rule.ForDuration() {
+		return nil, nil
+	}
+
+	alert := &entities.AlertEntity{
+		Kind:          entities.AlertKindThreshold,
+		RuleID:        rule.ID,
+		RuleName:      rule.Name,
+		PlantSourceId: reading.PlantSourceId,
+		Metric:        rule.Metric,
+		Operator:      rule.Operator,
+		Threshold:     rule.Threshold,
+		Severity:      rule.Severity,
+		Status:        entities.AlertStatusFiring,
+		Value:         value,
+		EventID:       reading.EventID,
+		PendingSince:  state.pendingSince,
+		FiredAt:       reading.Timestamp,
+	}
+	if window != nil {
+		alert.Severity = entities.SeverityInfo
+		alert.MaintenanceWindowID = &window.ID
+	}
+	if _, err := e.alertRepository.Create(ctx, alert); err != nil {
+		return nil, fmt.Errorf("error saving alert for rule %s: %w", rule.ID, err)
+	}
+	state.alert = alert
+
+	e.metrics.AlertTransition(alert.Severity, alert.Status)
+	e.logger.WarnContext(ctx, "Alert firing",
+		slog.String("alert_id", alert.ID.String()),
+		slog.String("rule", rule.Name),
+		slog.String("expression", rule.Expression()),
+		slog.String("plant_source_id", reading.PlantSourceId.String()),
+		slog.Float64("value", value))
+	return alert, nil
+}
+
+// resolve marca la alerta como resuelta; value es nil cuando se resuelve por borrado de la regla
+// El llamador notifica la alerta después de liberar el lock
+func (e *AlertEngine) resolve(ctx context.Context, alert *entities.AlertEntity, at time.Time, value *float64) error {
+	alert.Status = entities.AlertStatusResolved
+	alert.ResolvedAt = &at
+	alert.ResolvedValue = value
+	if _, err := e.alertRepository.Update(ctx, alert); err != nil {
+		e.logger.ErrorContext(ctx, "Error resolving alert", slog.String("alert_id", alert.ID.String()), slog.Any("error", err))
+		return fmt.Errorf("error resolving alert %s: %w", alert.ID, err)
+	}
+
+	e.metrics.AlertTransition(alert.Severity, alert.Status)
+	e.logger.InfoContext(ctx, "Alert resolved",
+		slog.String("alert_id", alert.ID.String()),
+		slog.String("rule", alert.RuleName),
+		slog.String("plant_source_id", alert.PlantSourceId.String()))
+	return nil
+}
+
+// notify entrega las alertas a los listeners sin el lock tomado; sus errores se registran
+// pero no afectan el estado de las alertas
+func (e *AlertEngine) notify(ctx context.Context, alerts []entities.AlertEntity) {
+	for _, alert := range alerts {
+		for _, listener := range e.listeners {
+			if err := listener.OnAlert(ctx, alert); err != nil {
+				e.logger.ErrorContext(ctx, "Error notifying alert",
+					slog.String("alert_id", alert.ID.String()),
+					slog.String("listener", fmt.Sprintf("%T", listener)),
+					slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// alertRulesFake devuelve las reglas activas fijas
+type alertRulesFake struct {
+	output.AlertRuleRepositoryInterface
+	rules []*entities.AlertRuleEntity
+}
+
+func (f *alertRulesFake) FindEnabled(context.Context) ([]*entities.AlertRuleEntity, error) {
+	return f.rules, nil
+}
+
+// alertsFake guarda las alertas en memoria
+type alertsFake struct {
+	output.AlertRepositoryInterface
+	firing []*entities.AlertEntity
+}
+
+func (f *alertsFake) FindFiring(context.Context) ([]*entities.AlertEntity, error) {
+	return f.firing, nil
+}
+
+func (f *alertsFake) Create(_ context.Context, alert *entities.AlertEntity) (*entities.AlertEntity, error) {
+	alert.ID = uuid.New()
+	return alert, nil
+}
+
+func (f *alertsFake) Update(_ context.Context, alert *entities.AlertEntity) (*entities.AlertEntity, error) {
+	return alert, nil
+}
+
+// alertRecorder registra las notificaciones como "<status>@<lectura>" y verifica que lleguen sin el lock del motor
+type alertRecorder struct {
+	t       *testing.T
+	engine  *AlertEngine
+	reading int
+	got     []string
+	alerts  []entities.AlertEntity
+}
+
+func (r *alertRecorder) OnAlert(_ context.Context, alert entities.AlertEntity) error {
+	if !r.engine.mu.TryLock() {
+		r.t.Errorf("alert %s notified while the engine lock is held", alert.Status)
+	} else {
+		r.engine.mu.Unlock()
+	}
+	r.got = append(r.got, fmt.Sprintf("%s@%d", alert.Status, r.reading))
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+// alertStep es una lectura de la secuencia: minutos desde el inicio y valor de temperatura
+type alertStep struct {
+	minute int
+	value  float64
+	window string // Política de la ventana de mantenimiento activa ("" = sin ventana)
+}
+
+func TestAlertEngineSequences(t *testing.T) {
+	plantID := uuid.New()
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		forSeconds int64
+		hysteresis float64
+		scope      string // plant_source_id del alcance RuleScopePlant ("" = todas)
+		steps      []alertStep
+		want       []string
+	}{
+		{
+			name:       "fires after the for duration and resolves below the hysteresis band",
+			forSeconds: 600, hysteresis: 2,
+			steps: []alertStep{{0, 46, ""}, {5, 47, ""}, {10, 46, ""}, {11, 44, ""}, {12, 43, ""}, {13, 42, ""}},
+			want:  []string{"firing@2", "resolved@5"},
+		},
+		{
+			name:       "a dip before the for duration restarts the countdown",
+			forSeconds: 600,
+			steps:      []alertStep{{0, 46, ""}, {5, 44, ""}, {10, 46, ""}, {15, 46, ""}, {20, 46, ""}},
+			want:       []string{"firing@4"},
+		},
+		{
+			name:       "zero for duration fires on the first matching reading",
+			forSeconds: 0,
+			steps:      []alertStep{{0, 40, ""}, {1, 45.5, ""}, {2, 46, ""}, {3, 45, ""}},
+			want:       []string{"firing@1", "resolved@3"},
+		},
+		{
+			name:       "without hysteresis resolves as soon as the condition stops",
+			forSeconds: 300,
+			steps:      []alertStep{{0, 50, ""}, {5, 50, ""}, {6, 45, ""}, {7, 46, ""}, {12, 46, ""}},
+			want:       []string{"firing@1", "resolved@2", "firing@4"},
+		},
+		{
+			name:       "readings inside the band keep the alert firing",
+			forSeconds: 0, hysteresis: 5,
+			steps: []alertStep{{0, 46, ""}, {1, 44, ""}, {2, 41, ""}, {3, 40, ""}, {4, 39.9, ""}},
+			want:  []string{"firing@0", "resolved@4"},
+		},
+		{
+			name:       "suppressing maintenance window restarts the countdown",
+			forSeconds: 600,
+			steps: []alertStep{
+				{0, 46, ""}, {10, 46, entities.MaintenanceAlertSuppress}, {15, 46, ""}, {20, 46, ""}, {25, 46, ""},
+			},
+			want: []string{"firing@4"},
+		},
+		{
+			name:       "firing alert resolves during a suppressing window",
+			forSeconds: 0,
+			steps:      []alertStep{{0, 46, ""}, {1, 40, entities.MaintenanceAlertSuppress}},
+			want:       []string{"firing@0", "resolved@1"},
+		},
+		{
+			name:       "rule of another plant is not evaluated",
+			forSeconds: 0,
+			scope:      uuid.NewString(),
+			steps:      []alertStep{{0, 46, ""}, {1, 50, ""}},
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &entities.AlertRuleEntity{
+				ID:         uuid.New(),
+				Name:       "High temperature",
+				Metric:     entities.MetricTemperature,
+				Operator:   entities.OperatorGreater,
+				Threshold:  45,
+				ForSeconds: tt.forSeconds,
+				Hysteresis: tt.hysteresis,
+				Severity:   entities.SeverityWarning,
+				ScopeType:  entities.RuleScopeAll,
+				Enabled:    true,
+			}
+			if tt.scope != "" {
+				rule.ScopeType, rule.ScopeValue = entities.RuleScopePlant, tt.scope
+			}
+			engine := NewAlertEngine(&alertRulesFake{rules: []*entities.AlertRuleEntity{rule}}, &alertsFake{}, nopMetrics{}, discardLogger())
+			recorder := &alertRecorder{t: t, engine: engine}
+			engine.RegisterListener(recorder)
+
+			for i, step := range tt.steps {
+				recorder.reading = i
+				reading := entities.PlantReading{
+					EventID:       uuid.New(),
+					PlantSourceId: plantID,
+					PlantType:     entities.PlantTypeSolar,
+					Metrics:       map[string]float64{entities.MetricTemperature: step.value},
+					Timestamp:     start.Add(time.Duration(step.minute) * time.Minute),
+				}
+				if step.window != "" {
+					reading.MaintenanceWindow = &entities.MaintenanceWindowEntity{ID: uuid.New(), AlertPolicy: step.window}
+				}
+				if err := engine.ProcessReading(context.Background(), reading); err != nil {
+					t.Fatalf("ProcessReading(#%d) error = %v", i, err)
+				}
+			}
+
+			if fmt.Sprint(recorder.got) != fmt.Sprint(tt.want) {
+				t.Fatalf("notifications = %v, want %v", recorder.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertEngineSkipsSuspectReadings(t *testing.T) {
+	plantID := uuid.New()
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	rule := &entities.AlertRuleEntity{
+		ID: uuid.New(), Name: "High temperature", Metric: entities.MetricTemperature,
+		Operator: entities.OperatorGreater, Threshold: 45, ForSeconds: 600,
+		Severity: entities.SeverityWarning, ScopeType: entities.RuleScopeAll, Enabled: true,
+	}
+	engine := NewAlertEngine(&alertRulesFake{rules: []*entities.AlertRuleEntity{rule}}, &alertsFake{}, nopMetrics{}, discardLogger())
+	recorder := &alertRecorder{t: t, engine: engine}
+	engine.RegisterListener(recorder)
+
+	steps := []struct {
+		minute  int
+		value   float64
+		suspect bool
+	}{
+		{0, 46, false},
+		{5, 20, true}, // Una lectura suspect bajo el umbral no reinicia la cuenta
+		{10, 46, false},
+		{11, 90, true}, // Ni una suspect sobre el umbral resuelve o dispara otra vez
+		{12, 20, true},
+		{13, 40, false},
+	}
+	for i, step := range steps {
+		recorder.reading = i
+		reading := entities.PlantReading{
+			EventID:       uuid.New(),
+			PlantSourceId: plantID,
+			Metrics:       map[string]float64{entities.MetricTemperature: step.value},
+			Timestamp:     start.Add(time.Duration(step.minute) * time.Minute),
+		}
+		if step.suspect {
+			reading.QualityFlags = []string{entities.QualityFlag(entities.QualityCheckRange, entities.MetricTemperature)}
+		}
+		if err := engine.ProcessReading(context.Background(), reading); err != nil {
+			t.Fatalf("ProcessReading(#%d) error = %v", i, err)
+		}
+	}
+
+	if want := []string{"firing@2", "resolved@5"}; fmt.Sprint(recorder.got) != fmt.Sprint(want) {
+		t.Fatalf("notifications = %v, want %v", recorder.got, want)
+	}
+}
+
+func TestAlertEngineFiredAlertFields(t *testing.T) {
+	plantID := uuid.New()
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	rule := &entities.AlertRuleEntity{
+		ID: uuid.New(), Name: "Low efficiency", Metric: entities.MetricEfficiency,
+		Operator: entities.OperatorLess, Threshold: 78, ForSeconds: 300, Hysteresis: 1,
+		Severity: entities.SeverityCritical, ScopeType: entities.RuleScopeAll, Enabled: true,
+	}
+	engine := NewAlertEngine(&alertRulesFake{rules: []*entities.AlertRuleEntity{rule}}, &alertsFake{}, nopMetrics{}, discardLogger())
+	recorder := &alertRecorder{t: t, engine: engine}
+	engine.RegisterListener(recorder)
+
+	steps := []struct {
+		minute int
+		value  float64
+		window *entities.MaintenanceWindowEntity
+	}{
+		{0, 77, nil},
+		{5, 75, &entities.MaintenanceWindowEntity{ID: uuid.New(), AlertPolicy: entities.MaintenanceAlertDowngrade}},
+		{6, 78.5, nil}, // Dentro de la banda: sigue firing
+		{7, 79.5, nil},
+	}
+	for i, step := range steps {
+		recorder.reading = i
+		reading := entities.PlantReading{
+			EventID:           uuid.New(),
+			PlantSourceId:     plantID,
+			Metrics:           map[string]float64{entities.MetricEfficiency: step.value},
+			Timestamp:         start.Add(time.Duration(step.minute) * time.Minute),
+			MaintenanceWindow: step.window,
+		}
+		if err := engine.ProcessReading(context.Background(), reading); err != nil {
+			t.Fatalf("ProcessReading(#%d) error = %v", i, err)
+		}
+	}
+
+	if len(recorder.alerts) != 2 {
+		t.Fatalf("notifications = %v, want firing and resolved", recorder.got)
+	}
+	fired, resolved := recorder.alerts[0], recorder.alerts[1]
+	if fired.Status != entities.AlertStatusFiring || fired.Severity != entities.SeverityInfo || fired.MaintenanceWindowID == nil {
+		t.Errorf("fired alert = status %s, severity %s, window %v; want firing, info (downgraded), window set",
+			fired.Status, fired.Severity, fired.MaintenanceWindowID)
+	}
+	if !fired.PendingSince.Equal(start) || !fired.FiredAt.Equal(start.Add(5*time.Minute)) || fired.Value != 75 {
+		t.Errorf("fired alert = pending since %v, fired at %v, value %v", fired.PendingSince, fired.FiredAt, fired.Value)
+	}
+	if resolved.Status != entities.AlertStatusResolved || resolved.ResolvedAt == nil || !resolved.ResolvedAt.Equal(start.Add(7*time.Minute)) ||
+		resolved.ResolvedValue == nil || *resolved.ResolvedValue != 79.5 {
+		t.Errorf("resolved alert = status %s, resolved at %v, value %v", resolved.Status, resolved.ResolvedAt, resolved.ResolvedValue)
+	}
+	if resolved.ID != fired.ID {
+		t.Errorf("resolved alert %s, want the fired alert %s", resolved.ID, fired.ID)
+	}
+}
@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// Piso del desvío de la línea base, relativo a la escala de cada métrica
+// CAMBIO: El piso depende de la capacidad de la planta o del rango de la métrica
+// RAZÓN: Con un piso fijo (1e-3) los ceros nocturnos de una planta solar dejaban el desvío
+// en el piso y cada amanecer y atardecer daba z-scores enormes
+const (
+	capacityStdFraction = 0.02 // Potencia: fracción de CapacityMW de la planta
+	rangeStdFraction    = 0.01 // Métricas con rango definido (eficiencia, temperatura...): fracción del rango
+	minBaselineStd      = 1e-3 // Sin capacidad ni rango: solo evita dividir por cero
+)
+
+// AnomalyDetectorConfig valores por defecto del detector (variables ANOMALY_*)
+// Cada planta puede reemplazarlos en /api/v1/plants/:id/anomaly-settings
+type AnomalyDetectorConfig struct {
+	Enabled       bool          // ANOMALY_ENABLED
+	ZThreshold    float64       // ANOMALY_Z_THRESHOLD: |z-score| a partir del cual la lectura es anómala
+	Alpha         float64       // ANOMALY_ALPHA: peso de cada lectura en la media y varianza EWMA
+	WarmUp        int           // ANOMALY_WARM_UP: lecturas antes de empezar a detectar
+	Metrics       []string      // ANOMALY_METRICS: métricas evaluadas
+	HistoryWindow time.Duration // ANOMALY_HISTORY_WINDOW: historial usado para reconstruir la línea base
+}
+
+// AnomalyDetector detecta lecturas que se desvían de la línea base de cada planta y métrica
+//
+// PROPÓSITO:
+// Los umbrales fijos del AlertEngine no ven derivas graduales y se disparan con picos normales.
+// El detector compara cada lectura con la media y el desvío EWMA de su propia planta.
+//
+// FUNCIONAMIENTO:
+// 1. La primera lectura de una planta reconstruye su línea base con los eventos de los
+// últimos HistoryWindow (así el estado sobrevive a un reinicio sin persistirlo)
+// 2. Con al menos WarmUp lecturas, z = (valor - media) / desvío; si |z| >= ZThreshold
+// se guarda una anomalía con el score y la línea base vigente
+// 3. La lectura actualiza la media y la varianza EWMA (durante el warm-up el peso es 1/n,
+// es decir, un promedio simple). Tras el warm-up el valor se recorta a media ± ZThreshold·desvío,
+// así un pico aislado no infla el desvío y un cambio de nivel sostenido se absorbe de a poco
+// 4. Reload se llama al cambiar la configuración de una planta: su línea base se reconstruye
+//
+// Las lecturas suspect (chequeos de plausibilidad) no se evalúan ni entran en la línea base.
+// El desvío nunca baja de un piso relativo a la escala de la métrica (ver stdFloor).
+type AnomalyDetector struct {
+	eventRepository    output.EventRepositoryInterface
+	anomalyRepository  output.AnomalyRepositoryInterface
+	settingsRepository output.AnomalySettingsRepositoryInterface
+	metrics            output.MetricsRecorderInterface
+	logger             *slog.Logger
+	cfg                AnomalyDetectorConfig
+
+	mu        sync.Mutex
+	loaded    bool
+	settings  map[uuid.UUID]*entities.AnomalySettingsEntity // Solo plantas con configuración propia
+	baselines map[uuid.UUID]map[string]*ewmaBaseline        // nil hasta reconstruir la planta
+}
+
+// ewmaBaseline es la media y varianza exponencial de una métrica de una planta
+type ewmaBaseline struct {
+	count    int
+	mean     float64
+	variance float64
+}
+
+var _ input.ReadingProcessor = &AnomalyDetector{}
+
+// NewAnomalyDetector crea el detector; la configuración por planta se carga con la primera lectura
+func NewAnomalyDetector(
+	eventRepository output.EventRepositoryInterface,
+	anomalyRepository output.AnomalyRepositoryInterface,
+	settingsRepository output.AnomalySettingsRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg AnomalyDetectorConfig,
+) *AnomalyDetector {
+	return &AnomalyDetector{
+		eventRepository:    eventRepository,
+		anomalyRepository:  anomalyRepository,
+		settingsRepository: settingsRepository,
+		metrics:            metrics,
+		logger:             logger,
+		cfg:                cfg,
+		settings:           make(map[uuid.UUID]*entities.AnomalySettingsEntity),
+		baselines:          make(map[uuid.UUID]map[string]*ewmaBaseline),
+	}
+}
+
+// Settings devuelve la configuración efectiva de la planta y si es propia (false = valores por defecto)
+func (d *AnomalyDetector) Settings(ctx context.Context, plantID uuid.UUID) (*entities.AnomalySettingsEntity, bool, error) {
+	settings, err := d.settingsRepository.FindByPlant(ctx, plantID)
+	if err == nil {
+		return settings, true, nil
+	}
+	if !errors.Is(err, domainerrors.ErrNotFound) {
+		return nil, false, err
+	}
+	return d.defaults(plantID), false, nil
+}
+
+// Reload vuelve a leer la configuración de la planta y descarta su línea base
+// (se reconstruye desde el historial con la próxima lectura)
+func (d *AnomalyDetector) Reload(ctx context.Context, plantID uuid.UUID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	settings, custom, err := d.Settings(ctx, plantID)
+	if err != nil {
+		return fmt.Errorf("error loading anomaly settings of plant %s: %w", plantID, err)
+	}
+	if custom {
+		d.settings[plantID] = settings
+	} else {
+		delete(d.settings, plantID)
+	}
+	delete(d.baselines, plantID)
+	return nil
+}
+
+// ProcessReading compara la lectura con la línea base de su planta y la actualiza
+func (d *AnomalyDetector) ProcessReading(ctx context.Context, reading entities.PlantReading) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.loaded {
+		if err := d.loadSettings(ctx); err != nil {
+			return err
+		}
+	}
+
+	settings := d.effective(reading.PlantSourceId)
+	if !settings.Enabled || reading.Suspect() {
+		return nil
+	}
+
+	baselines, ok := d.baselines[reading.PlantSourceId]
+	if !ok {
+		baselines = d.rebuild(ctx, reading, settings)
+		d.baselines[reading.PlantSourceId] = baselines
+	}
+
+	for _, metric := range settings.Metrics {
+		value, ok := reading.Metric(metric)
+		if !ok {
+			continue
+		}
+		baseline := baselines[metric]
+		if baseline == nil {
+			baseline = &ewmaBaseline{}
+			baselines[metric] = baseline
+		}
+
+		floor := stdFloor(metric, reading.PlantCapacityMW)
+		if baseline.count >= settings.WarmUp {
+			if err := d.evaluate(ctx, reading, metric, value, baseline, floor, settings); err != nil {
+				return err
+			}
+			value = baseline.clamp(value, settings.ZThreshold, floor)
+		}
+		baseline.update(value, settings.Alpha)
+	}
+	return nil
+}
+
+// evaluate guarda la anomalía si el z-score de la lectura supera el umbral de la planta
+func (d *AnomalyDetector) evaluate(ctx context.Context, reading entities.PlantReading, metric string, value float64, baseline *ewmaBaseline, floor float64, settings *entities.AnomalySettingsEntity) error {
+	std := baseline.std(floor)
+	score := (value - baseline.mean) / std
+	if math.Abs(score) < settings.ZThreshold {
+		return nil
+	}
+
+	anomaly := &entities.AnomalyEntity{
+		PlantSourceId: reading.PlantSourceId,
+		Metric:        metric,
+		EventID:       reading.EventID,
+		Value:         value,
+		BaselineMean:  baseline.mean,
+		BaselineStd:   std,
+		Score:         score,
+		Threshold:     settings.ZThreshold,
+		Direction:     entities.AnomalyDirectionHigh,
+		DetectedAt:    reading.Timestamp,
+	}
+	if score < 0 {
+		anomaly.Direction = entities.AnomalyDirectionLow
+	}
+	if _, err := d.anomalyRepository.Create(ctx, anomaly); err != nil {
+		return fmt.Errorf("error saving anomaly for plant %s: %w", reading.PlantSourceId, err)
+	}
+
+	d.metrics.AnomalyDetected(metric, anomaly.Direction)
+	d.logger.WarnContext(ctx, "Anomaly detected",
+		slog.String("anomaly_id", anomaly.ID.String()),
+		slog.String("plant_source_id", reading.PlantSourceId.String()),
+		slog.String("metric", metric),
+		slog.Float64("value", value),
+		slog.Float64("baseline_mean", baseline.mean),
+		slog.Float64("score", score))
+	return nil
+}
+
+// rebuild reconstruye la línea base de la planta con el historial reciente (sin la lectura actual
+// ni las lecturas suspect). Si el historial no se puede leer la planta arranca con el warm-up vacío
+func (d *AnomalyDetector) rebuild(ctx context.Context, reading entities.PlantReading, settings *entities.AnomalySettingsEntity) map[string]*ewmaBaseline {
+	baselines := make(map[string]*ewmaBaseline)
+	if d.cfg.HistoryWindow <= 0 {
+		return baselines
+	}
+
+	events, err := d.eventRepository.FindByFilter(ctx, entities.EventFilter{
+		PlantSourceId: &reading.PlantSourceId,
+		From:          time.Now().Add(-d.cfg.HistoryWindow),
+		Quality:       entities.EventQualityGood,
+	})
+	if err != nil {
+		d.logger.ErrorContext(ctx, "Error loading history for anomaly baseline",
+			slog.String("plant_source_id", reading.PlantSourceId.String()), slog.Any("error", err))
+		return baselines
+	}
+
+	history := make([]entities.PlantReading, 0, len(events))
+	for _, event := range events {
+		if event.ID == reading.EventID {
+			continue
+		}
+		if past, err := entities.PlantReadingFromEvent(event, reading.PlantType); err == nil {
+			history = append(history, past)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Timestamp.Before(history[j].Timestamp) })
+
+	for _, past := range history {
+		for _, metric := range settings.Metrics {
+			if value, ok := past.Metric(metric); ok {
+				baseline := baselines[metric]
+				if baseline == nil {
+					baseline = &ewmaBaseline{}
+					baselines[metric] = baseline
+				}
+				if baseline.count >= settings.WarmUp {
+					value = baseline.clamp(value, settings.ZThreshold, stdFloor(metric, reading.PlantCapacityMW))
+				}
+				baseline.update(value, settings.Alpha)
+			}
+		}
+	}
+
+	d.logger.InfoContext(ctx, "Anomaly baseline rebuilt",
+		slog.String("plant_source_id", reading.PlantSourceId.String()), slog.Int("readings", len(history)))
+	return baselines
+}
+
+func (d *AnomalyDetector) loadSettings(ctx context.Context) error {
+	all, err := d.settingsRepository.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading anomaly settings: %w", err)
+	}
+	for _, settings := range all {
+		d.settings[settings.PlantSourceId] = settings
+	}
+	d.loaded = true
+	return nil
+}
+
+// effective devuelve la configuración propia de la planta o los valores por defecto
+func (d *AnomalyDetector) effective(plantID uuid.UUID) *entities.AnomalySettingsEntity {
+	if settings, ok := d.settings[plantID]; ok {
+		return settings
+	}
+	return d.defaults(plantID)
+}
+
+func (d *AnomalyDetector) defaults(plantID uuid.UUID) *entities.AnomalySettingsEntity {
+	return &entities.AnomalySettingsEntity{
+		PlantSourceId: plantID,
+		Enabled:       d.cfg.Enabled,
+		ZThreshold:    d.cfg.ZThreshold,
+		Alpha:         d.cfg.Alpha,
+		WarmUp:        d.cfg.WarmUp,
+		Metrics:       slices.Clone(d.cfg.Metrics),
+	}
+}
+
+// update agrega una lectura a la media y varianza EWMA
+// Mientras 1/n sea mayor que alpha se usa 1/n, así el warm-up es un promedio simple
+func (b *ewmaBaseline) update(value, alpha float64) {
+	b.count++
+	if b.count == 1 {
+		b.mean, b.variance = value, 0
+		return
+	}
+	weight := math.Max(alpha, 1/float64(b.count))
+	diff := value - b.mean
+	incr := weight * diff
+	b.mean += incr
+	b.variance = (1 - weight) * (b.variance + diff*incr)
+}
+
+// clamp recorta el valor a media ± threshold·desvío antes de actualizar la línea base
+func (b *ewmaBaseline) clamp(value, threshold, floor float64) float64 {
+	limit := threshold * b.std(floor)
+	return math.Min(math.Max(value, b.mean-limit), b.mean+limit)
+}
+
+// std devuelve el desvío EWMA, nunca menor que floor
+func (b *ewmaBaseline) std(floor float64) float64 {
+	return math.Max(math.Sqrt(b.variance), floor)
+}
+
+// stdFloor devuelve el desvío mínimo de la métrica según su escala:
+// - Potencia: capacityStdFraction de la capacidad de la planta
+// - Métricas con mínimo y máximo definidos: rangeStdFraction del rango
+// - Resto (o planta sin capacidad): minBaselineStd
+func stdFloor(metric string, capacityMW float64) float64 {
+	if (metric == entities.MetricPowerGenerated || metric == entities.MetricPowerConsumed) && capacityMW > 0 {
+		return math.Max(capacityStdFraction*capacityMW, minBaselineStd)
+	}
+	if definition, ok := entities.FindMetricDefinition(metric); ok && definition.Min != nil && definition.Max != nil {
+		return math.Max(rangeStdFraction*(*definition.Max-*definition.Min), minBaselineStd)
+	}
+	return minBaselineStd
+}
@@ -0,0 +1,494 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// ConnectivityWatcherConfig configuración de la detección de plantas offline (variables PLANT_*)
+type ConnectivityWatcherConfig struct {
+	Enabled          bool          // PLANT_OFFLINE_ENABLED: dispara y resuelve alertas offline
+	ExpectedInterval time.Duration // PLANT_EXPECTED_INTERVAL: intervalo de reporte de las plantas sin intervalo propio
+	MissedIntervals  int           // PLANT_OFFLINE_MISSED_INTERVALS: intervalos sin lecturas antes de considerar la planta offline
+	CheckInterval    time.Duration // PLANT_OFFLINE_CHECK_INTERVAL: cada cuánto se revisa el silencio de las plantas
+	Severity         string        // PLANT_OFFLINE_SEVERITY: severidad de las alertas offline
+}
+
+// ConnectivityWatcher detecta las plantas que dejaron de enviar lecturas
+//
+// PROPÓSITO:
+// El intake solo reacciona a los mensajes que llegan: una planta que deja de reportar no
+// genera ningún evento. El watcher guarda la última lectura recibida de cada planta y revisa
+// periódicamente cuánto lleva en silencio.
+//
+// FUNCIONAMIENTO:
+// 1. Al arrancar carga las plantas, su último evento guardado y las alertas offline firing
+// 2. Cada lectura aceptada por el intake (good o suspect) actualiza la última vez vista de la
+// planta; si tenía una alerta offline, se resuelve
+// 3. Cada CheckInterval, una planta en silencio más de intervalo × MissedIntervals pasa a
+// offline y se dispara una alerta kind=offline (en la tabla alerts, notificada a los
+// listeners como las de umbral). El intervalo es el de la planta o ExpectedInterval
+// 4. Las ventanas de mantenimiento suprimen la alerta o la bajan a info según su política
+// 5. Las plantas que nunca enviaron lecturas figuran como never_seen y no disparan alertas
+//
+// El silencio se mide con la hora de ingesta, no con el event_time: una planta que envía
+// lecturas atrasadas sigue conectada.
+type ConnectivityWatcher struct {
+	plantRepository output.EnergyPlantRepositoryInterface
+	eventRepository output.EventRepositoryInterface
+	alertRepository output.AlertRepositoryInterface
+	calendar        input.MaintenanceCalendar
+	metrics         output.MetricsRecorderInterface
+	logger          *slog.Logger
+	cfg             ConnectivityWatcherConfig
+
+	listeners []input.AlertListener // Notificados al disparar o resolver (ej: WebhookDispatcher)
+
+	mu     sync.Mutex
+	loaded bool
+	plants map[uuid.UUID]*connectivityState
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// connectivityState es la última lectura recibida de una planta y su alerta offline
+type connectivityState struct {
+	plant         *entities.EnergyPlants
+	lastSeen      time.Time // Cero = nunca envió lecturas
+	lastEventTime time.Time
+	lastEventID   uuid.UUID
+	alert         *entities.AlertEntity // Alerta offline firing (nil si no hay)
+}
+
+var _ input.ReadingProcessor = &ConnectivityWatcher{}
+
+// NewConnectivityWatcher crea el watcher; las revisiones periódicas se inician con Start
+func NewConnectivityWatcher(
+	plantRepository output.EnergyPlantRepositoryInterface,
+	eventRepository output.EventRepositoryInterface,
+	alertRepository output.AlertRepositoryInterface,
+	calendar input.MaintenanceCalendar,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg ConnectivityWatcherConfig,
+) *ConnectivityWatcher {
+	if cfg.ExpectedInterval <= 0 {
+		cfg.ExpectedInterval = time.Hour
+	}
+	if cfg.MissedIntervals <= 0 {
+		cfg.MissedIntervals = 3
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = 30 * time.Second
+	}
+	if !slices.Contains([]string{entities.SeverityInfo, entities.SeverityWarning, entities.SeverityCritical}, cfg.Severity) {
+		if cfg.Severity != "" {
+			logger.Warn("PLANT_OFFLINE_SEVERITY is not info, warning or critical, using critical", slog.String("severity", cfg.Severity))
+		}
+		cfg.Severity = entities.SeverityCritical
+	}
+	return &ConnectivityWatcher{
+		plantRepository: plantRepository,
+		eventRepository: eventRepository,
+		alertRepository: alertRepository,
+		calendar:        calendar,
+		metrics:         metrics,
+		logger:          logger,
+		cfg:             cfg,
+		plants:          make(map[uuid.UUID]*connectivityState),
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// RegisterListener agrega un listener notificado cada vez que una alerta offline se dispara o se resuelve
+func (w *ConnectivityWatcher) RegisterListener(listener input.AlertListener) {
+	w.listeners = append(w.listeners, listener)
+}
+
+// ProcessReading registra la lectura como la última vista de la planta y resuelve su alerta offline
+func (w *ConnectivityWatcher) ProcessReading(ctx context.Context, reading entities.PlantReading) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.ensureLoaded(ctx); err != nil {
+		return err
+	}
+	state, ok := w.plants[reading.PlantSourceId]
+	if !ok {
+		// Planta registrada después de la última revisión
+		plant, err := w.plantRepository.FindByID(ctx, reading.PlantSourceId)
+		if err != nil {
+			return fmt.Errorf("error loading plant %s: %w", reading.PlantSourceId, err)
+		}
+		state = &connectivityState{plant: plant}
+		w.plants[plant.ID] = state
+	}
+
+	seenAt := reading.ReceivedAt
+	if seenAt.IsZero() {
+		seenAt = time.Now().UTC()
+	}
+	if seenAt.After(state.lastSeen) {
+		silence := seenAt.Sub(state.lastSeen)
+		state.lastSeen = seenAt
+		state.lastEventID = reading.EventID
+		if state.alert != nil {
+			if err := w.resolve(ctx, state, seenAt, &silence); err != nil {
+				return err
+			}
+		}
+	}
+	if reading.Timestamp.After(state.lastEventTime) {
+		state.lastEventTime = reading.Timestamp
+	}
+	return nil
+}
+
+// Start revisa el silencio de las plantas cada CheckInterval en un goroutine
+func (w *ConnectivityWatcher) Start(ctx context.Context) {
+	if !w.cfg.Enabled {
+		w.logger.InfoContext(ctx, "Plant offline detection disabled")
+		return
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := w.Check(ctx, time.Now().UTC()); err != nil {
+					w.logger.ErrorContext(ctx, "Error checking plant connectivity", slog.Any("error", err))
+				}
+			case <-w.stopChan:
+				return
+			}
+		}
+	}()
+	w.logger.InfoContext(ctx, "Plant offline detection started",
+		slog.Duration("check_interval", w.cfg.CheckInterval),
+		slog.Duration("expected_interval", w.cfg.ExpectedInterval),
+		slog.Int("missed_intervals", w.cfg.MissedIntervals))
+}
+
+// Stop detiene las revisiones periódicas
+func (w *ConnectivityWatcher) Stop() {
+	close(w.stopChan)
+	w.wg.Wait()
+}
+
+// Check dispara una alerta offline para cada planta en silencio más allá de su umbral
+func (w *ConnectivityWatcher) Check(ctx context.Context, now time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.sync(ctx); err != nil {
+		return err
+	}
+
+	offline := 0
+	for _, state := range w.plants {
+		if w.connectivityState(state, now) != entities.ConnectivityOffline {
+			continue
+		}
+		offline++
+		if state.alert != nil {
+			continue
+		}
+		if err := w.fire(ctx, state, now); err != nil {
+			w.logger.ErrorContext(ctx, "Error firing plant offline alert",
+				slog.String("plant_source_id", state.plant.ID.String()),
+				slog.Any("error", err))
+		}
+	}
+	w.metrics.PlantsOffline(offline)
+	return nil
+}
+
+// Connectivity devuelve la última lectura recibida y el estado de cada planta, ordenadas por nombre
+// state filtra por estado (vacío = todas)
+func (w *ConnectivityWatcher) Connectivity(ctx context.Context, state string, now time.Time) ([]entities.PlantConnectivity, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.sync(ctx); err != nil {
+		return nil, err
+	}
+	result := make([]entities.PlantConnectivity, 0, len(w.plants))
+	for _, plant := range w.plants {
+		connectivity := w.connectivity(plant, now)
+		if state == "" || connectivity.State == state {
+			result = append(result, connectivity)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PlantName < result[j].PlantName })
+	return result, nil
+}
+
+// PlantConnectivity devuelve la conectividad de una planta
+func (w *ConnectivityWatcher) PlantConnectivity(ctx context.Context, plantID uuid.UUID, now time.Time) (*entities.PlantConnectivity, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.sync(ctx); err != nil {
+		return nil, err
+	}
+	state, ok := w.plants[plantID]
+	if !ok {
+		return nil, domainerrors.ErrNotFound
+	}
+	connectivity := w.connectivity(state, now)
+	return &connectivity, nil
+}
+
+// SetExpectedInterval cambia el intervalo de reporte esperado de la planta (nil = PLANT_EXPECTED_INTERVAL)
+// El nuevo umbral se aplica desde la próxima revisión
+func (w *ConnectivityWatcher) SetExpectedInterval(ctx context.Context, plantID uuid.UUID, interval *time.Duration) (*entities.PlantConnectivity, error) {
+	var seconds *int64
+	if interval != nil {
+		if *interval < time.Second {
+			return nil, fmt.Errorf("%w: expected_interval must be at least 1s", domainerrors.ErrInvalidInput)
+		}
+		s := int64(interval.Seconds())
+		seconds = &s
+	}
+	if err := w.plantRepository.UpdateExpectedInterval(ctx, plantID, seconds); err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	if state, ok := w.plants[plantID]; ok {
+		state.plant.IntervalSeconds = seconds
+	}
+	w.mu.Unlock()
+
+	w.logger.InfoContext(ctx, "Plant expected interval updated",
+		slog.String("plant_source_id", plantID.String()),
+		slog.Any("expected_interval_seconds", seconds))
+	return w.PlantConnectivity(ctx, plantID, time.Now().UTC())
+}
+
+// ensureLoaded carga el estado inicial la primera vez que se necesita
+func (w *ConnectivityWatcher) ensureLoaded(ctx context.Context) error {
+	if w.loaded {
+		return nil
+	}
+	if err := w.refresh(ctx); err != nil {
+		return err
+	}
+
+	firing, err := w.alertRepository.FindAll(ctx, entities.AlertFilter{Status: entities.AlertStatusFiring, Kind: entities.AlertKindOffline})
+	if err != nil {
+		return fmt.Errorf("error loading offline alerts: %w", err)
+	}
+	for _, alert := range firing {
+		if state, ok := w.plants[alert.PlantSourceId]; ok && state.alert == nil {
+			state.alert = alert
+		}
+	}
+	w.loaded = true
+	return nil
+}
+
+// sync carga el estado inicial o, si ya está cargado, refresca las plantas
+func (w *ConnectivityWatcher) sync(ctx context.Context) error {
+	if !w.loaded {
+		return w.ensureLoaded(ctx)
+	}
+	return w.refresh(ctx)
+}
+
+// refresh vuelve a cargar las plantas (intervalos, altas y bajas) y el último evento de las nuevas
+// Las alertas offline de plantas borradas se resuelven
+func (w *ConnectivityWatcher) refresh(ctx context.Context) error {
+	plants, err := w.plantRepository.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading plants: %w", err)
+	}
+
+	current := make(map[uuid.UUID]bool, len(plants))
+	var added []uuid.UUID
+	for _, plant := range plants {
+		current[plant.ID] = true
+		if state, ok := w.plants[plant.ID]; ok {
+			state.plant = plant
+			continue
+		}
+		w.plants[plant.ID] = &connectivityState{plant: plant}
+		added = append(added, plant.ID)
+	}
+	for id, state := range w.plants {
+		if current[id] {
+			continue
+		}
+		if state.alert != nil {
+			// El error ya se registró en resolve; la planta deja de vigilarse igual
+			_ = w.resolve(ctx, state, time.Now().UTC(), nil)
+		}
+		delete(w.plants, id)
+	}
+
+	if len(added) > 0 {
+		events, err := w.eventRepository.FindLatestByPlants(ctx, added, time.Time{})
+		if err != nil {
+			return fmt.Errorf("error loading last events: %w", err)
+		}
+		for _, event := range events {
+			state := w.plants[event.PlantSourceId]
+			if event.CreatedAt.After(state.lastSeen) {
+				state.lastSeen = event.CreatedAt
+				state.lastEventTime = event.EventTime
+				state.lastEventID = event.ID
+			}
+		}
+	}
+	return nil
+}
+
+// expectedInterval devuelve el intervalo de la planta o el valor por defecto
+func (w *ConnectivityWatcher) expectedInterval(plant *entities.EnergyPlants) (time.Duration, bool) {
+	if plant.IntervalSeconds != nil && *plant.IntervalSeconds > 0 {
+		return time.Duration(*plant.IntervalSeconds) * time.Second, true
+	}
+	return w.cfg.ExpectedInterval, false
+}
+
+// offlineAfter devuelve el silencio a partir del cual la planta está offline
+func (w *ConnectivityWatcher) offlineAfter(plant *entities.EnergyPlants) time.Duration {
+	interval, _ := w.expectedInterval(plant)
+	return interval * time.Duration(w.cfg.MissedIntervals)
+}
+
+// connectivityState calcula el estado de la planta a la hora indicada
+func (w *ConnectivityWatcher) connectivityState(state *connectivityState, now time.Time) string {
+	switch {
+	case state.lastSeen.IsZero():
+		return entities.ConnectivityNeverSeen
+	case now.Sub(state.lastSeen) > w.offlineAfter(state.plant):
+		return entities.ConnectivityOffline
+	default:
+		return entities.ConnectivityOnline
+	}
+}
+
+// connectivity arma la respuesta de una planta
+func (w *ConnectivityWatcher) connectivity(state *connectivityState, now time.Time) entities.PlantConnectivity {
+	interval, custom := w.expectedInterval(state.plant)
+	connectivity := entities.PlantConnectivity{
+		PlantSourceId:           state.plant.ID,
+		PlantName:               state.plant.PlantName,
+		PlantType:               state.plant.PlantType,
+		State:                   w.connectivityState(state, now),
+		ExpectedIntervalSeconds: int64(interval.Seconds()),
+		CustomInterval:          custom,
+		OfflineAfterSeconds:     int64(w.offlineAfter(state.plant).Seconds()),
+	}
+	if !state.lastSeen.IsZero() {
+		lastSeen, lastEventTime := state.lastSeen, state.lastEventTime
+		silent := max(now.Sub(lastSeen), 0).Seconds()
+		connectivity.LastSeenAt = &lastSeen
+		connectivity.LastEventTime = &lastEventTime
+		connectivity.SilentSeconds = &silent
+	}
+	if state.alert != nil {
+		connectivity.AlertID = &state.alert.ID
+	}
+	return connectivity
+}
+
+// fire guarda y notifica la alerta offline de la planta (salvo que una ventana de mantenimiento la suprima)
+func (w *ConnectivityWatcher) fire(ctx context.Context, state *connectivityState, now time.Time) error {
+	window, err := w.calendar.ActiveWindow(ctx, state.plant.ID, now)
+	if err != nil {
+		return fmt.Errorf("error loading maintenance windows: %w", err)
+	}
+	if window != nil && window.AlertPolicy == entities.MaintenanceAlertSuppress {
+		return nil
+	}
+
+	alert := &entities.AlertEntity{
+		Kind:          entities.AlertKindOffline,
+		RuleID:        uuid.Nil,
+		RuleName:      entities.OfflineAlertRuleName,
+		PlantSourceId: state.plant.ID,
+		Metric:        entities.OfflineAlertMetric,
+		Operator:      entities.OperatorGreater,
+		Threshold:     w.offlineAfter(state.plant).Seconds(),
+		Severity:      w.cfg.Severity,
+		Status:        entities.AlertStatusFiring,
+		Value:         now.Sub(state.lastSeen).Seconds(),
+		EventID:       state.lastEventID,
+		PendingSince:  state.lastSeen,
+		FiredAt:       now,
+	}
+	if window != nil {
+		alert.Severity = entities.SeverityInfo
+		alert.MaintenanceWindowID = &window.ID
+	}
+	if _, err := w.alertRepository.Create(ctx, alert); err != nil {
+		return fmt.Errorf("error saving offline alert: %w", err)
+	}
+	state.alert = alert
+
+	w.metrics.AlertTransition(alert.Severity, alert.Status)
+	w.notify(ctx, alert)
+	w.logger.WarnContext(ctx, "Plant offline",
+		slog.String("alert_id", alert.ID.String()),
+		slog.String("plant_source_id", state.plant.ID.String()),
+		slog.Time("last_seen_at", state.lastSeen),
+		slog.Duration("offline_after", w.offlineAfter(state.plant)))
+	return nil
+}
+
+// resolve marca la alerta offline como resuelta; silence es el silencio que terminó (nil si la planta se borró)
+func (w *ConnectivityWatcher) resolve(ctx context.Context, state *connectivityState, at time.Time, silence *time.Duration) error {
+	alert := state.alert
+	alert.Status = entities.AlertStatusResolved
+	alert.ResolvedAt = &at
+	if silence != nil {
+		seconds := silence.Seconds()
+		alert.ResolvedValue = &seconds
+	}
+	if _, err := w.alertRepository.Update(ctx, alert); err != nil {
+		w.logger.ErrorContext(ctx, "Error resolving offline alert", slog.String("alert_id", alert.ID.String()), slog.Any("error", err))
+		return fmt.Errorf("error resolving alert %s: %w", alert.ID, err)
+	}
+	state.alert = nil
+
+	w.metrics.AlertTransition(alert.Severity, alert.Status)
+	w.notify(ctx, alert)
+	w.logger.InfoContext(ctx, "Plant back online",
+		slog.String("alert_id", alert.ID.String()),
+		slog.String("plant_source_id", alert.PlantSourceId.String()))
+	return nil
+}
+
+// notify entrega una copia de la alerta a los listeners; sus errores se registran pero no
+// afectan el estado de la alerta
+func (w *ConnectivityWatcher) notify(ctx context.Context, alert *entities.AlertEntity) {
+	for _, listener := range w.listeners {
+		if err := listener.OnAlert(ctx, *alert); err != nil {
+			w.logger.ErrorContext(ctx, "Error notifying alert",
+				slog.String("alert_id", alert.ID.String()),
+				slog.String("listener", fmt.Sprintf("%T", listener)),
+				slog.Any("error", err))
+		}
+	}
+}
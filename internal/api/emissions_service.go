@@ -0,0 +1,364 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// EmissionsService administra los factores de emisión de la red y calcula las emisiones evitadas
+//
+// PROPÓSITO:
+// Las emisiones evitadas (tCO2e) de una planta son la energía generada de cada día (tabla
+// energy_daily) por el factor de la red vigente ese día en su región. Los reportes indican la
+// versión de factores usada y los factores aplicados para poder reproducirlos.
+//
+// FUNCIONAMIENTO DE LOS REPORTES:
+// 1. La versión es la pedida o, por defecto, la más alta; solo se usan factores con version <= versión
+// 2. La región de una planta es el nodo región que contiene su sitio en el portfolio
+// 3. Para cada día se elige el factor vigente de la región de la planta o, si no hay, el factor
+// por defecto (sin región); entre varios vigentes gana el de versión más alta
+// 4. La energía de días sin factor se informa como uncovered_mwh y no suma emisiones evitadas
+//
+// Los factores son pocos: se cargan todos los de la versión y se eligen en memoria.
+type EmissionsService struct {
+	factorRepository output.EmissionFactorRepositoryInterface
+	plantRepository  output.EnergyPlantRepositoryInterface
+	nodeRepository   output.PortfolioNodeRepositoryInterface
+	energyRepository output.EnergyDailyRepositoryInterface
+	logger           *slog.Logger
+}
+
+// NewEmissionsService crea el servicio de emisiones evitadas
+func NewEmissionsService(
+	factorRepository output.EmissionFactorRepositoryInterface,
+	plantRepository output.EnergyPlantRepositoryInterface,
+	nodeRepository output.PortfolioNodeRepositoryInterface,
+	energyRepository output.EnergyDailyRepositoryInterface,
+	logger *slog.Logger,
+) *EmissionsService {
+	return &EmissionsService{
+		factorRepository: factorRepository,
+		plantRepository:  plantRepository,
+		nodeRepository:   nodeRepository,
+		energyRepository: energyRepository,
+		logger:           logger,
+	}
+}
+
+// CreateFactor valida el factor y su región y lo guarda con la versión siguiente
+func (s *EmissionsService) CreateFactor(ctx context.Context, factor *entities.EmissionFactorEntity) (*entities.EmissionFactorEntity, error) {
+	if err := factor.Validate(); err != nil {
+		return nil, err
+	}
+	if factor.RegionNodeID != nil {
+		node, err := s.nodeRepository.FindByID(ctx, *factor.RegionNodeID)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				return nil, fmt.Errorf("%w: region %s does not exist", domainerrors.ErrInvalidInput, *factor.RegionNodeID)
+			}
+			return nil, err
+		}
+		if node.Level != entities.PortfolioLevelRegion {
+			return nil, fmt.Errorf("%w: region_node_id must be a region, got a %s", domainerrors.ErrInvalidInput, node.Level)
+		}
+	}
+
+	created, err := s.factorRepository.Create(ctx, factor)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.InfoContext(ctx, "Emission factor created",
+		slog.String("id", created.ID.String()),
+		slog.Int64("version", created.Version),
+		slog.Float64("tco2e_per_mwh", created.TCO2ePerMWh))
+	return created, nil
+}
+
+// PlantReport calcula las emisiones evitadas de la planta por período con la versión de factores indicada (0 = la más alta)
+func (s *EmissionsService) PlantReport(ctx context.Context, plantID uuid.UUID, query entities.EnergyQuery, factorVersion int64) (*entities.PlantEmissionsReport, error) {
+	query.From, query.To = alignDays(query.From, query.To)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	plant, err := s.plantRepository.FindByID(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := s.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	calc, err := s.newCalculation(ctx, query, factorVersion, nodes, []*entities.EnergyPlants{plant})
+	if err != nil {
+		return nil, err
+	}
+	if err := calc.add(ctx, s.energyRepository, &plant.ID); err != nil {
+		return nil, err
+	}
+	report := calc.plants[0]
+	report.Periods = calc.periods
+	report.Factors = calc.usedFactors()
+	return &report, nil
+}
+
+// NodeReport calcula las emisiones evitadas de las plantas del subárbol de un nodo del portfolio
+func (s *EmissionsService) NodeReport(ctx context.Context, nodeID uuid.UUID, query entities.EnergyQuery, factorVersion int64) (*entities.EmissionsReport, error) {
+	query.From, query.To = alignDays(query.From, query.To)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	nodes, err := s.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := nodes[nodeID]
+	if !ok {
+		return nil, domainerrors.ErrNotFound
+	}
+
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, node := range nodes {
+		if node.ParentID != nil {
+			children[*node.ParentID] = append(children[*node.ParentID], node.ID)
+		}
+	}
+	subtree := []uuid.UUID{root.ID}
+	for i := 0; i < len(subtree); i++ {
+		subtree = append(subtree, children[subtree[i]]...)
+	}
+	plants, err := s.plantRepository.FindByPortfolioNodes(ctx, subtree)
+	if err != nil {
+		return nil, fmt.Errorf("error loading plants of node %s: %w", root.ID, err)
+	}
+
+	report, err := s.report(ctx, query, factorVersion, nodes, plants)
+	if err != nil {
+		return nil, err
+	}
+	report.Node = &entities.PortfolioNodeRef{ID: root.ID, Level: root.Level, Name: root.Name}
+	return report, nil
+}
+
+// FleetReport calcula las emisiones evitadas de todas las plantas
+func (s *EmissionsService) FleetReport(ctx context.Context, query entities.EnergyQuery, factorVersion int64) (*entities.EmissionsReport, error) {
+	query.From, query.To = alignDays(query.From, query.To)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	nodes, err := s.loadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plants, err := s.plantRepository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading plants: %w", err)
+	}
+	return s.report(ctx, query, factorVersion, nodes, plants)
+}
+
+// report suma las emisiones evitadas de un conjunto de plantas por período y el total de cada una
+func (s *EmissionsService) report(ctx context.Context, query entities.EnergyQuery, factorVersion int64, nodes map[uuid.UUID]*entities.PortfolioNodeEntity, plants []*entities.EnergyPlants) (*entities.EmissionsReport, error) {
+	calc, err := s.newCalculation(ctx, query, factorVersion, nodes, plants)
+	if err != nil {
+		return nil, err
+	}
+	if err := calc.add(ctx, s.energyRepository, nil); err != nil {
+		return nil, err
+	}
+
+	report := &entities.EmissionsReport{
+		From:          query.From,
+		To:            query.To,
+		Granularity:   query.Granularity,
+		FactorVersion: calc.version,
+		Periods:       calc.periods,
+		Total:         entities.EmissionsPeriod{Start: query.From, End: query.To},
+		Plants:        calc.plants,
+		Factors:       calc.usedFactors(),
+	}
+	for _, period := range calc.periods {
+		addEmissions(&report.Total, period)
+	}
+	return report, nil
+}
+
+// loadNodes carga todos los nodos del portfolio por ID (son pocos)
+func (s *EmissionsService) loadNodes(ctx context.Context) (map[uuid.UUID]*entities.PortfolioNodeEntity, error) {
+	nodes, err := s.nodeRepository.FindAll(ctx, entities.PortfolioNodeFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("error loading portfolio nodes: %w", err)
+	}
+	byID := make(map[uuid.UUID]*entities.PortfolioNodeEntity, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+	}
+	return byID, nil
+}
+
+// newCalculation resuelve la versión de factores, carga sus factores y prepara los períodos y plantas del reporte
+func (s *EmissionsService) newCalculation(ctx context.Context, query entities.EnergyQuery, factorVersion int64, nodes map[uuid.UUID]*entities.PortfolioNodeEntity, plants []*entities.EnergyPlants) (*emissionsCalculation, error) {
+	latest, err := s.factorRepository.LatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading latest emission factor version: %w", err)
+	}
+	if factorVersion < 0 || factorVersion > latest {
+		return nil, fmt.Errorf("%w: factor_version %d does not exist (latest is %d)", domainerrors.ErrInvalidInput, factorVersion, latest)
+	}
+	if factorVersion == 0 {
+		factorVersion = latest
+	}
+
+	var factors []*entities.EmissionFactorEntity
+	if factorVersion > 0 {
+		if factors, err = s.factorRepository.FindAll(ctx, entities.EmissionFactorFilter{MaxVersion: factorVersion}); err != nil {
+			return nil, fmt.Errorf("error loading emission factors: %w", err)
+		}
+	}
+
+	calc := &emissionsCalculation{
+		query:   query,
+		version: factorVersion,
+		factors: factors,
+		used:    make(map[uuid.UUID]*entities.EmissionFactorEntity),
+		plants:  make([]entities.PlantEmissionsReport, 0, len(plants)),
+		index:   make(map[uuid.UUID]int, len(plants)),
+	}
+	for _, period := range newEnergyPeriods(query) {
+		calc.periods = append(calc.periods, entities.EmissionsPeriod{Start: period.Start, End: period.End})
+	}
+	for i, plant := range plants {
+		calc.index[plant.ID] = i
+		calc.plants = append(calc.plants, entities.PlantEmissionsReport{
+			PlantSourceId: plant.ID,
+			PlantName:     plant.PlantName,
+			RegionNodeID:  plantRegion(nodes, plant),
+			From:          query.From,
+			To:            query.To,
+			Granularity:   query.Granularity,
+			FactorVersion: factorVersion,
+			Total:         entities.EmissionsPeriod{Start: query.From, End: query.To},
+		})
+	}
+	return calc, nil
+}
+
+// emissionsCalculation acumula las emisiones evitadas de un reporte
+type emissionsCalculation struct {
+	query   entities.EnergyQuery
+	version int64
+	factors []*entities.EmissionFactorEntity             // Factores con version <= version, por versión ascendente
+	used    map[uuid.UUID]*entities.EmissionFactorEntity // Factores aplicados al menos una vez
+	periods []entities.EmissionsPeriod
+	plants  []entities.PlantEmissionsReport
+	index   map[uuid.UUID]int // Posición de cada planta en plants
+}
+
+// add suma la energía diaria del rango (de una planta o de todas) a los períodos y a los totales de cada planta
+func (c *emissionsCalculation) add(ctx context.Context, energyRepository output.EnergyDailyRepositoryInterface, plantID *uuid.UUID) error {
+	days, err := energyRepository.FindDaily(ctx, entities.EnergyDailyFilter{PlantSourceId: plantID, From: c.query.From, To: c.query.To})
+	if err != nil {
+		return fmt.Errorf("error loading energy: %w", err)
+	}
+
+	for _, day := range days {
+		i, ok := c.index[day.PlantSourceId]
+		if !ok {
+			continue // Planta fuera del reporte o borrada
+		}
+		plant := &c.plants[i]
+		factor := c.factorFor(plant.RegionNodeID, day.Day)
+		if factor != nil {
+			c.used[factor.ID] = factor
+		}
+		addEmissionsDay(&plant.Total, day, factor)
+		addEmissionsDay(emissionsPeriodAt(c.periods, day.Day), day, factor)
+	}
+	return nil
+}
+
+// factorFor elige el factor vigente el día: el de la región con versión más alta o, si no hay, el por defecto
+func (c *emissionsCalculation) factorFor(regionID *uuid.UUID, day time.Time) *entities.EmissionFactorEntity {
+	var regional, fallback *entities.EmissionFactorEntity
+	for _, factor := range c.factors {
+		if !factor.Covers(day) {
+			continue
+		}
+		switch {
+		case factor.RegionNodeID == nil:
+			fallback = factor
+		case regionID != nil && *factor.RegionNodeID == *regionID:
+			regional = factor
+		}
+	}
+	if regional != nil {
+		return regional
+	}
+	return fallback
+}
+
+// usedFactors devuelve los factores aplicados ordenados por versión
+func (c *emissionsCalculation) usedFactors() []*entities.EmissionFactorEntity {
+	factors := make([]*entities.EmissionFactorEntity, 0, len(c.used))
+	for _, factor := range c.used {
+		factors = append(factors, factor)
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i].Version < factors[j].Version })
+	return factors
+}
+
+// plantRegion devuelve la región del portfolio que contiene el sitio de la planta (nil sin región)
+func plantRegion(nodes map[uuid.UUID]*entities.PortfolioNodeEntity, plant *entities.EnergyPlants) *uuid.UUID {
+	if plant.PortfolioNodeID == nil {
+		return nil
+	}
+	for node := nodes[*plant.PortfolioNodeID]; node != nil; {
+		if node.Level == entities.PortfolioLevelRegion {
+			return &node.ID
+		}
+		if node.ParentID == nil {
+			break
+		}
+		node = nodes[*node.ParentID]
+	}
+	return nil
+}
+
+// emissionsPeriodAt devuelve el período que contiene t (nil fuera del rango)
+func emissionsPeriodAt(periods []entities.EmissionsPeriod, t time.Time) *entities.EmissionsPeriod {
+	i := sort.Search(len(periods), func(i int) bool { return periods[i].End.After(t) })
+	if i == len(periods) || t.Before(periods[i].Start) {
+		return nil
+	}
+	return &periods[i]
+}
+
+// addEmissionsDay suma la energía generada del día y sus emisiones evitadas (ignora días fuera del rango)
+func addEmissionsDay(period *entities.EmissionsPeriod, day *entities.EnergyDailyEntity, factor *entities.EmissionFactorEntity) {
+	if period == nil {
+		return
+	}
+	period.GeneratedMWh += day.GeneratedMWh
+	if factor == nil {
+		period.UncoveredMWh += day.GeneratedMWh
+		return
+	}
+	period.AvoidedTCO2e += day.GeneratedMWh * factor.TCO2ePerMWh
+}
+
+// addEmissions suma un período a un total
+func addEmissions(total *entities.EmissionsPeriod, period entities.EmissionsPeriod) {
+	total.GeneratedMWh += period.GeneratedMWh
+	total.AvoidedTCO2e += period.AvoidedTCO2e
+	total.UncoveredMWh += period.UncoveredMWh
+}
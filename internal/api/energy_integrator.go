@@ -0,0 +1,494 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// energyRecomputeMaxRange rango máximo que se puede recalcular de una vez
+const energyRecomputeMaxRange = 366 * 24 * time.Hour
+
+// energyMetrics métricas de potencia que se integran como energía
+var energyMetrics = []string{entities.MetricPowerGenerated, entities.MetricPowerConsumed}
+
+// EnergyIntegratorConfig configuración de la integración de energía (variables ENERGY_*)
+type EnergyIntegratorConfig struct {
+	MaxGap       time.Duration // ENERGY_MAX_GAP: intervalo máximo entre lecturas que se integra
+	RawRetention time.Duration // ROLLUP_RAW_RETENTION con los rollups activos: eventos que sobreviven a la purga (0 = todos)
+}
+
+// EnergyIntegrator integra la potencia de las lecturas en energía (MWh) diaria por planta
+//
+// PROPÓSITO:
+// Las lecturas traen MW instantáneos; facturación y reportes necesitan MWh. Los totales diarios
+// se mantienen en energy_daily a medida que llegan las lecturas, así los reportes por día, semana
+// o mes y el de la flota solo suman días.
+//
+// FUNCIONAMIENTO:
+// 1. Guarda en memoria la última muestra de cada planta y métrica (power_generated_mw y
+// power_consumed_mw); la primera lectura de una planta la busca en los eventos recientes
+// 2. Cada lectura nueva integra el intervalo desde la muestra anterior con la regla del trapecio
+// si dura como mucho MaxGap (un hueco mayor no suma energía ni horas cubiertas)
+// 3. Un intervalo que cruza la medianoche UTC se reparte entre los dos días interpolando la potencia
+// 4. La base de tiempo es el event_time de la lectura. Una lectura anterior a la última muestra
+// no se integra: Recompute vuelve a calcular los días afectados desde los eventos guardados
+// (el LateDataHandler lo dispara solo para las lecturas dentro de EVENT_ALLOWED_LATENESS)
+//
+// Las lecturas y los recálculos de una misma planta se serializan: un incremento que llega
+// mientras se recalcula no puede quedar pisado por ReplaceRange.
+type EnergyIntegrator struct {
+	eventRepository  output.EventRepositoryInterface
+	plantRepository  output.EnergyPlantRepositoryInterface
+	energyRepository output.EnergyDailyRepositoryInterface
+	logger           *slog.Logger
+	cfg              EnergyIntegratorConfig
+
+	mu      sync.Mutex                            // Protege los mapas
+	locks   map[uuid.UUID]*sync.Mutex             // Serializa lecturas y recálculos de cada planta
+	samples map[uuid.UUID]map[string]energySample // nil hasta cargar la planta
+}
+
+// energySample última potencia conocida de una métrica
+type energySample struct {
+	at    time.Time
+	value float64
+}
+
+var _ input.ReadingProcessor = &EnergyIntegrator{}
+var _ input.AggregateRecomputer = &EnergyIntegrator{}
+
+// NewEnergyIntegrator crea el integrador; la última muestra de cada planta se carga con su primera lectura
+func NewEnergyIntegrator(
+	eventRepository output.EventRepositoryInterface,
+	plantRepository output.EnergyPlantRepositoryInterface,
+	energyRepository output.EnergyDailyRepositoryInterface,
+	logger *slog.Logger,
+	cfg EnergyIntegratorConfig,
+) *EnergyIntegrator {
+	if cfg.MaxGap <= 0 {
+		cfg.MaxGap = 15 * time.Minute
+	}
+	return &EnergyIntegrator{
+		eventRepository:  eventRepository,
+		plantRepository:  plantRepository,
+		energyRepository: energyRepository,
+		logger:           logger,
+		cfg:              cfg,
+		locks:            make(map[uuid.UUID]*sync.Mutex),
+		samples:          make(map[uuid.UUID]map[string]energySample),
+	}
+}
+
+// plantLock devuelve el lock de la planta, creándolo en su primer uso
+func (e *EnergyIntegrator) plantLock(plantID uuid.UUID) *sync.Mutex {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	lock, ok := e.locks[plantID]
+	if !ok {
+		lock = &sync.Mutex{}
+		e.locks[plantID] = lock
+	}
+	return lock
+}
+
+// ProcessReading integra el intervalo entre la muestra anterior de la planta y la lectura
+// Las lecturas suspect no se integran: el intervalo queda entre las lecturas good vecinas
+func (e *EnergyIntegrator) ProcessReading(ctx context.Context, reading entities.PlantReading) error {
+	if reading.Suspect() {
+		return nil
+	}
+
+	lock := e.plantLock(reading.PlantSourceId)
+	lock.Lock()
+	defer lock.Unlock()
+
+	e.mu.Lock()
+	samples, ok := e.samples[reading.PlantSourceId]
+	e.mu.Unlock()
+	if !ok {
+		samples = e.loadSamples(ctx, reading)
+		e.mu.Lock()
+		e.samples[reading.PlantSourceId] = samples
+		e.mu.Unlock()
+	}
+
+	days := make(map[time.Time]*entities.EnergyDailyEntity)
+	next := make(map[string]energySample)
+	for _, metric := range energyMetrics {
+		value, ok := reading.Metric(metric)
+		if !ok {
+			continue
+		}
+		prev, ok := samples[metric]
+		if ok && !reading.Timestamp.After(prev.at) {
+			e.logger.DebugContext(ctx, "Out-of-order reading not integrated",
+				slog.String("plant_source_id", reading.PlantSourceId.String()),
+				slog.String("metric", metric),
+				slog.Time("timestamp", reading.Timestamp),
+				slog.Time("last_sample", prev.at))
+			continue
+		}
+		if ok {
+			addEnergy(days, reading.PlantSourceId, metric, prev.at, reading.Timestamp, prev.value, value, e.cfg.MaxGap, time.Time{}, time.Time{})
+		}
+		next[metric] = energySample{at: reading.Timestamp, value: value}
+	}
+
+	if err := e.energyRepository.AddIncrements(ctx, sortedDays(days)); err != nil {
+		return fmt.Errorf("error saving energy of plant %s: %w", reading.PlantSourceId, err)
+	}
+	// Las muestras avanzan solo si la energía se guardó: la próxima lectura integra el intervalo completo
+	for metric, sample := range next {
+		samples[metric] = sample
+	}
+	return nil
+}
+
+// Recompute vuelve a integrar los días [from, to) de la planta desde los eventos guardados
+// (from se alinea al inicio de su día y to al final del suyo). Devuelve los días recalculados.
+// Bloquea las lecturas de la planta desde la carga de eventos hasta ReplaceRange y descarta sus
+// muestras en memoria: la próxima lectura vuelve a cargar la anterior desde los eventos.
+//
+// CAMBIO: Rechaza los rangos que empiezan antes de now - RawRetention
+// RAZÓN: ReplaceRange borraba los días ya calculados cuyos eventos se purgaron y no los reconstruía
+func (e *EnergyIntegrator) Recompute(ctx context.Context, plantID uuid.UUID, from, to time.Time) ([]*entities.EnergyDailyEntity, error) {
+	from, to = alignDays(from, to)
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", domainerrors.ErrInvalidInput)
+	}
+	if to.Sub(from) > energyRecomputeMaxRange {
+		return nil, fmt.Errorf("%w: range cannot exceed %s", domainerrors.ErrInvalidInput, energyRecomputeMaxRange)
+	}
+	if earliest := e.RecomputeStart(); from.Before(earliest) {
+		return nil, fmt.Errorf("%w: from cannot be before %s, older events are purged (ROLLUP_RAW_RETENTION)",
+			domainerrors.ErrInvalidInput, earliest.Format(time.DateOnly))
+	}
+	plant, err := e.plantRepository.FindByID(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := e.plantLock(plant.ID)
+	lock.Lock()
+	defer lock.Unlock()
+	defer func() {
+		e.mu.Lock()
+		delete(e.samples, plant.ID)
+		e.mu.Unlock()
+	}()
+
+	events, err := e.eventRepository.FindByFilter(ctx, entities.EventFilter{
+		PlantSourceId: &plant.ID,
+		From:          from.Add(-e.cfg.MaxGap),
+		To:            to.Add(e.cfg.MaxGap),
+		Quality:       entities.EventQualityGood,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading events of plant %s: %w", plant.ID, err)
+	}
+	readings := make([]entities.PlantReading, 0, len(events))
+	for _, event := range events {
+		if reading, err := entities.PlantReadingFromEvent(event, plant.PlantType); err == nil {
+			readings = append(readings, reading)
+		}
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].Timestamp.Before(readings[j].Timestamp) })
+
+	days := make(map[time.Time]*entities.EnergyDailyEntity)
+	for _, metric := range energyMetrics {
+		energyIntervals(readings, metric, e.cfg.MaxGap, func(a, b time.Time, va, vb float64) {
+			addEnergy(days, plant.ID, metric, a, b, va, vb, e.cfg.MaxGap, from, to)
+		})
+	}
+
+	result := sortedDays(days)
+	if err := e.energyRepository.ReplaceRange(ctx, plant.ID, from, to, result); err != nil {
+		return nil, fmt.Errorf("error saving energy of plant %s: %w", plant.ID, err)
+	}
+	e.logger.InfoContext(ctx, "Energy recomputed",
+		slog.String("plant_source_id", plant.ID.String()),
+		slog.Time("from", from), slog.Time("to", to),
+		slog.Int("readings", len(readings)), slog.Int("days", len(result)))
+	return result, nil
+}
+
+// RecomputeStart devuelve el primer día UTC completo cuyos eventos no se purgaron, el from más
+// antiguo que acepta Recompute (cero si los eventos no se purgan)
+func (e *EnergyIntegrator) RecomputeStart() time.Time {
+	return rawRetentionStart(e.cfg.RawRetention)
+}
+
+// RecomputeRange recalcula los días [from, to) de la planta (lecturas tardías del LateDataHandler)
+func (e *EnergyIntegrator) RecomputeRange(ctx context.Context, plantID uuid.UUID, from, to time.Time) error {
+	_, err := e.Recompute(ctx, plantID, from, to)
+	return err
+}
+
+// PlantReport suma la energía diaria de la planta en los períodos de la consulta
+func (e *EnergyIntegrator) PlantReport(ctx context.Context, plantID uuid.UUID, query entities.EnergyQuery) (*entities.PlantEnergyReport, error) {
+	query.From, query.To = alignDays(query.From, query.To)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	plant, err := e.plantRepository.FindByID(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := e.energyRepository.FindDaily(ctx, entities.EnergyDailyFilter{PlantSourceId: &plant.ID, From: query.From, To: query.To})
+	if err != nil {
+		return nil, fmt.Errorf("error loading energy of plant %s: %w", plant.ID, err)
+	}
+
+	periods := newEnergyPeriods(query)
+	for _, day := range days {
+		addDay(energyPeriodAt(periods, day.Day), day)
+	}
+	report := &entities.PlantEnergyReport{
+		PlantSourceId: plant.ID,
+		PlantName:     plant.PlantName,
+		From:          query.From,
+		To:            query.To,
+		Granularity:   query.Granularity,
+		Periods:       periods,
+		Total:         entities.EnergyPeriod{Start: query.From, End: query.To},
+	}
+	for _, period := range periods {
+		addPeriod(&report.Total, period)
+	}
+	return report, nil
+}
+
+// FleetReport suma la energía diaria de todas las plantas por período y el total de cada planta
+func (e *EnergyIntegrator) FleetReport(ctx context.Context, query entities.EnergyQuery) (*entities.FleetEnergyReport, error) {
+	query.From, query.To = alignDays(query.From, query.To)
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	plants, err := e.plantRepository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading plants: %w", err)
+	}
+	days, err := e.energyRepository.FindDaily(ctx, entities.EnergyDailyFilter{From: query.From, To: query.To})
+	if err != nil {
+		return nil, fmt.Errorf("error loading fleet energy: %w", err)
+	}
+
+	report := &entities.FleetEnergyReport{
+		From:        query.From,
+		To:          query.To,
+		Granularity: query.Granularity,
+		Periods:     newEnergyPeriods(query),
+		Total:       entities.EnergyPeriod{Start: query.From, End: query.To},
+		Plants:      make([]entities.PlantEnergyReport, 0, len(plants)),
+	}
+	totals := make(map[uuid.UUID]*entities.EnergyPeriod, len(plants))
+	for _, plant := range plants {
+		report.Plants = append(report.Plants, entities.PlantEnergyReport{
+			PlantSourceId: plant.ID,
+			PlantName:     plant.PlantName,
+			From:          query.From,
+			To:            query.To,
+			Granularity:   query.Granularity,
+			Total:         entities.EnergyPeriod{Start: query.From, End: query.To},
+		})
+	}
+	for i := range report.Plants {
+		totals[report.Plants[i].PlantSourceId] = &report.Plants[i].Total
+	}
+
+	for _, day := range days {
+		total, ok := totals[day.PlantSourceId]
+		if !ok {
+			continue // Planta borrada
+		}
+		addDay(total, day)
+		addDay(energyPeriodAt(report.Periods, day.Day), day)
+	}
+	for i := range report.Periods {
+		addPeriod(&report.Total, report.Periods[i])
+	}
+	return report, nil
+}
+
+// loadSamples busca la última muestra de cada métrica anterior a la lectura en los eventos recientes
+// Si el historial no se puede leer la planta arranca sin muestras (se pierde solo el primer intervalo)
+func (e *EnergyIntegrator) loadSamples(ctx context.Context, reading entities.PlantReading) map[string]energySample {
+	samples := make(map[string]energySample)
+	events, err := e.eventRepository.FindByFilter(ctx, entities.EventFilter{
+		PlantSourceId: &reading.PlantSourceId,
+		From:          reading.Timestamp.Add(-e.cfg.MaxGap),
+		Quality:       entities.EventQualityGood,
+	})
+	if err != nil {
+		e.logger.ErrorContext(ctx, "Error loading last energy samples",
+			slog.String("plant_source_id", reading.PlantSourceId.String()), slog.Any("error", err))
+		return samples
+	}
+
+	for _, event := range events {
+		if event.ID == reading.EventID {
+			continue
+		}
+		past, err := entities.PlantReadingFromEvent(event, reading.PlantType)
+		if err != nil || !past.Timestamp.Before(reading.Timestamp) {
+			continue
+		}
+		for _, metric := range energyMetrics {
+			if value, ok := past.Metric(metric); ok && past.Timestamp.After(samples[metric].at) {
+				samples[metric] = energySample{at: past.Timestamp, value: value}
+			}
+		}
+	}
+	return samples
+}
+
+// addEnergy integra con la regla del trapecio el intervalo [a, b) (si dura como mucho maxGap),
+// recortado a [from, to) cuando no son cero, y lo reparte entre los días UTC que cruza
+// Las horas cubiertas se cuentan con la potencia generada
+func addEnergy(days map[time.Time]*entities.EnergyDailyEntity, plantID uuid.UUID, metric string, a, b time.Time, va, vb float64, maxGap time.Duration, from, to time.Time) {
+	if !integrable(a, b, maxGap) {
+		return
+	}
+
+	x := a
+	if !from.IsZero() && x.Before(from) {
+		x = from
+	}
+	end := b
+	if !to.IsZero() && end.After(to) {
+		end = to
+	}
+	for x.Before(end) {
+		day := entities.PeriodStart(x, entities.GranularityDay)
+		y := day.AddDate(0, 0, 1)
+		if y.After(end) {
+			y = end
+		}
+
+		total := days[day]
+		if total == nil {
+			total = &entities.EnergyDailyEntity{PlantSourceId: plantID, Day: day}
+			days[day] = total
+		}
+		mwh := trapezoidMWh(a, b, va, vb, x, y)
+		switch metric {
+		case entities.MetricPowerGenerated:
+			total.GeneratedMWh += mwh
+			total.CoveredHours += y.Sub(x).Hours()
+		case entities.MetricPowerConsumed:
+			total.ConsumedMWh += mwh
+		}
+		x = y
+	}
+}
+
+// energyIntervals recorre las lecturas ordenadas que traen la métrica y llama a fn con cada par
+// consecutivo que se integra. Es el único camino de integración de energy_daily y de los KPIs:
+// las lecturas suspect nunca se integran (el intervalo queda entre las good vecinas)
+func energyIntervals(readings []entities.PlantReading, metric string, maxGap time.Duration, fn func(a, b time.Time, va, vb float64)) {
+	var prev *energySample
+	for _, reading := range readings {
+		if reading.Suspect() {
+			continue
+		}
+		value, ok := reading.Metric(metric)
+		if !ok {
+			continue
+		}
+		if prev != nil && integrable(prev.at, reading.Timestamp, maxGap) {
+			fn(prev.at, reading.Timestamp, prev.value, value)
+		}
+		prev = &energySample{at: reading.Timestamp, value: value}
+	}
+}
+
+// integrable indica si el intervalo [a, b) suma energía: ordenado y de como mucho maxGap
+// (un hueco mayor cuenta como sin datos)
+func integrable(a, b time.Time, maxGap time.Duration) bool {
+	span := b.Sub(a)
+	return span > 0 && span <= maxGap
+}
+
+// trapezoidMWh devuelve la energía del tramo [x, y) del intervalo [a, b), con potencia va en a y
+// vb en b, interpolando la potencia en los bordes del tramo
+func trapezoidMWh(a, b time.Time, va, vb float64, x, y time.Time) float64 {
+	span := float64(b.Sub(a))
+	vx := va + (vb-va)*float64(x.Sub(a))/span
+	vy := va + (vb-va)*float64(y.Sub(a))/span
+	return (vx + vy) / 2 * y.Sub(x).Hours()
+}
+
+// sortedDays devuelve los días acumulados en orden cronológico
+func sortedDays(days map[time.Time]*entities.EnergyDailyEntity) []*entities.EnergyDailyEntity {
+	result := make([]*entities.EnergyDailyEntity, 0, len(days))
+	for _, day := range days {
+		result = append(result, day)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Day.Before(result[j].Day) })
+	return result
+}
+
+// alignDays lleva from al inicio de su día UTC y to al final del suyo (si no es ya medianoche)
+func alignDays(from, to time.Time) (time.Time, time.Time) {
+	from = entities.PeriodStart(from, entities.GranularityDay)
+	if day := entities.PeriodStart(to, entities.GranularityDay); !day.Equal(to) {
+		to = day.AddDate(0, 0, 1)
+	}
+	return from, to.UTC()
+}
+
+// newEnergyPeriods divide el rango en períodos de la granularidad (el primero y el último recortados)
+func newEnergyPeriods(query entities.EnergyQuery) []entities.EnergyPeriod {
+	var periods []entities.EnergyPeriod
+	for start := entities.PeriodStart(query.From, query.Granularity); start.Before(query.To); start = entities.NextPeriod(start, query.Granularity) {
+		period := entities.EnergyPeriod{Start: start, End: entities.NextPeriod(start, query.Granularity)}
+		if period.Start.Before(query.From) {
+			period.Start = query.From
+		}
+		if period.End.After(query.To) {
+			period.End = query.To
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// energyPeriodAt devuelve el período que contiene t (nil fuera del rango)
+func energyPeriodAt(periods []entities.EnergyPeriod, t time.Time) *entities.EnergyPeriod {
+	i := sort.Search(len(periods), func(i int) bool { return periods[i].End.After(t) })
+	if i == len(periods) || t.Before(periods[i].Start) {
+		return nil
+	}
+	return &periods[i]
+}
+
+// addDay suma un día al período (ignora días fuera del rango)
+func addDay(period *entities.EnergyPeriod, day *entities.EnergyDailyEntity) {
+	if period == nil {
+		return
+	}
+	period.GeneratedMWh += day.GeneratedMWh
+	period.ConsumedMWh += day.ConsumedMWh
+	period.NetMWh = period.GeneratedMWh - period.ConsumedMWh
+	period.CoveredHours += day.CoveredHours
+}
+
+// addPeriod suma un período a un total
+func addPeriod(total *entities.EnergyPeriod, period entities.EnergyPeriod) {
+	total.GeneratedMWh += period.GeneratedMWh
+	total.ConsumedMWh += period.ConsumedMWh
+	total.NetMWh = total.GeneratedMWh - total.ConsumedMWh
+	total.CoveredHours += period.CoveredHours
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// energyTotals totales esperados de un día
+type energyTotals struct {
+	generated, consumed, covered float64
+}
+
+func TestAddEnergy(t *testing.T) {
+	plantID := uuid.New()
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	at := func(d, hour, minute int) time.Time { return time.Date(2026, 10, d, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		metric   string
+		a, b     time.Time
+		va, vb   float64
+		maxGap   time.Duration
+		from, to time.Time
+		want     map[time.Time]energyTotals
+	}{
+		{
+			name:   "constant power for one hour",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 10, 0), b: at(18, 11, 0), va: 10, vb: 10,
+			want: map[time.Time]energyTotals{day(18): {generated: 10, covered: 1}},
+		},
+		{
+			name:   "ramp uses the trapezoid",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 10, 0), b: at(18, 10, 30), va: 0, vb: 20,
+			want: map[time.Time]energyTotals{day(18): {generated: 5, covered: 0.5}},
+		},
+		{
+			name:   "interval across midnight is split at the interpolated value",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 23, 30), b: at(19, 0, 30), va: 0, vb: 20,
+			want: map[time.Time]energyTotals{
+				day(18): {generated: 2.5, covered: 0.5}, // 0 → 10 MW en media hora
+				day(19): {generated: 7.5, covered: 0.5}, // 10 → 20 MW en media hora
+			},
+		},
+		{
+			name:   "interval spanning a whole day",
+			metric: entities.MetricPowerGenerated,
+			a:      at(17, 12, 0), b: at(19, 12, 0), va: 5, vb: 5, maxGap: 72 * time.Hour,
+			want: map[time.Time]energyTotals{
+				day(17): {generated: 60, covered: 12},
+				day(18): {generated: 120, covered: 24},
+				day(19): {generated: 60, covered: 12},
+			},
+		},
+		{
+			name:   "gap longer than max gap counts as no data",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 10, 0), b: at(18, 12, 1), va: 10, vb: 10,
+			want: map[time.Time]energyTotals{},
+		},
+		{
+			name:   "gap of exactly max gap is integrated",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 10, 0), b: at(18, 12, 0), va: 10, vb: 10,
+			want: map[time.Time]energyTotals{day(18): {generated: 20, covered: 2}},
+		},
+		{
+			name:   "out of order readings add nothing",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 11, 0), b: at(18, 10, 0), va: 10, vb: 10,
+			want: map[time.Time]energyTotals{},
+		},
+		{
+			name:   "same timestamp adds nothing",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 11, 0), b: at(18, 11, 0), va: 10, vb: 10,
+			want: map[time.Time]energyTotals{},
+		},
+		{
+			name:   "consumed power does not count covered hours",
+			metric: entities.MetricPowerConsumed,
+			a:      at(18, 10, 0), b: at(18, 11, 0), va: 2, vb: 4,
+			want: map[time.Time]energyTotals{day(18): {consumed: 3}},
+		},
+		{
+			name:   "clipped to from",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 10, 0), b: at(18, 12, 0), va: 0, vb: 20, from: at(18, 11, 0),
+			want: map[time.Time]energyTotals{day(18): {generated: 15, covered: 1}}, // 10 → 20 MW
+		},
+		{
+			name:   "clipped to a day boundary",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 23, 0), b: at(19, 1, 0), va: 4, vb: 4, to: day(19),
+			want: map[time.Time]energyTotals{day(18): {generated: 4, covered: 1}},
+		},
+		{
+			name:   "interval outside the range adds nothing",
+			metric: entities.MetricPowerGenerated,
+			a:      at(18, 10, 0), b: at(18, 11, 0), va: 4, vb: 4, from: day(19), to: day(20),
+			want: map[time.Time]energyTotals{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxGap := tt.maxGap
+			if maxGap == 0 {
+				maxGap = 2 * time.Hour
+			}
+			days := make(map[time.Time]*entities.EnergyDailyEntity)
+
+			addEnergy(days, plantID, tt.metric, tt.a, tt.b, tt.va, tt.vb, maxGap, tt.from, tt.to)
+
+			if len(days) != len(tt.want) {
+				t.Fatalf("got %d days, want %d", len(days), len(tt.want))
+			}
+			for d, want := range tt.want {
+				got := days[d]
+				if got == nil {
+					t.Fatalf("missing day %s", d.Format(time.DateOnly))
+				}
+				if got.PlantSourceId != plantID || !got.Day.Equal(d) {
+					t.Errorf("day %s has plant %s and day %s", d.Format(time.DateOnly), got.PlantSourceId, got.Day)
+				}
+				if !closeTo(got.GeneratedMWh, want.generated) || !closeTo(got.ConsumedMWh, want.consumed) || !closeTo(got.CoveredHours, want.covered) {
+					t.Errorf("day %s = generated %.4f, consumed %.4f, covered %.4f; want %.4f, %.4f, %.4f",
+						d.Format(time.DateOnly), got.GeneratedMWh, got.ConsumedMWh, got.CoveredHours,
+						want.generated, want.consumed, want.covered)
+				}
+			}
+		})
+	}
+}
+
+func TestAddEnergyAccumulatesConsecutiveReadings(t *testing.T) {
+	plantID := uuid.New()
+	readings := []struct {
+		at    time.Time
+		value float64
+	}{
+		{time.Date(2026, 10, 18, 22, 0, 0, 0, time.UTC), 0},
+		{time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC), 10},
+		{time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC), 10},
+		{time.Date(2026, 10, 19, 5, 0, 0, 0, time.UTC), 10}, // Hueco de 4 h: sin datos
+		{time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC), 30},
+	}
+
+	days := make(map[time.Time]*entities.EnergyDailyEntity)
+	for i := 1; i < len(readings); i++ {
+		prev, cur := readings[i-1], readings[i]
+		addEnergy(days, plantID, entities.MetricPowerGenerated, prev.at, cur.at, prev.value, cur.value, 2*time.Hour, time.Time{}, time.Time{})
+	}
+
+	sorted := sortedDays(days)
+	want := []energyTotals{
+		{generated: 5 + 10, covered: 2},  // 22-23 rampa 0→10, 23-24 constante 10
+		{generated: 10 + 20, covered: 2}, // 0-1 constante 10, 5-6 rampa 10→30
+	}
+	if len(sorted) != len(want) {
+		t.Fatalf("got %d days, want %d", len(sorted), len(want))
+	}
+	for i, got := range sorted {
+		if !closeTo(got.GeneratedMWh, want[i].generated) || !closeTo(got.CoveredHours, want[i].covered) {
+			t.Errorf("day %s = generated %.4f, covered %.4f; want %.4f, %.4f",
+				got.Day.Format(time.DateOnly), got.GeneratedMWh, got.CoveredHours, want[i].generated, want[i].covered)
+		}
+	}
+}
+
+func TestEnergyIntegratorRecomputeRejectsPurgedDays(t *testing.T) {
+	integrator := NewEnergyIntegrator(nil, nil, nil, discardLogger(), EnergyIntegratorConfig{RawRetention: 72 * time.Hour})
+	earliest := integrator.RecomputeStart()
+	if oldest := time.Now().UTC().Add(-72 * time.Hour); earliest.Before(oldest) || earliest.Sub(oldest) > 24*time.Hour {
+		t.Fatalf("RecomputeStart() = %v, want the first day start after %v", earliest, oldest)
+	}
+
+	// El rechazo llega antes de tocar los repositorios (nil en el test)
+	_, err := integrator.Recompute(context.Background(), uuid.New(), earliest.Add(-time.Hour), earliest.AddDate(0, 0, 1))
+	if !errors.Is(err, domainerrors.ErrInvalidInput) {
+		t.Fatalf("Recompute() error = %v, want ErrInvalidInput", err)
+	}
+
+	if start := NewEnergyIntegrator(nil, nil, nil, discardLogger(), EnergyIntegratorConfig{}).RecomputeStart(); !start.IsZero() {
+		t.Errorf("RecomputeStart() without retention = %v, want zero", start)
+	}
+}
+
+func closeTo(got, want float64) bool {
+	return math.Abs(got-want) < 1e-9
+}
@@ -1,14 +1,35 @@
 package api
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
 	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Estados del simulador (SimulatorStatus.State)
+const (
+	SimulatorRunning = "running"
+	SimulatorPaused  = "paused"
+	SimulatorStopped = "stopped"
+)
+
+// Límites de los cambios del simulador en caliente
+const (
+	maxSimulatorEvents      = 10000       // Máximo de eventos por lote o ráfaga
+	minSimulatorInterval    = time.Second // Intervalo mínimo entre lotes
+	simulatorStallIntervals = 2           // Intervalos sin lote tras los que el check de readiness falla
 )
 
 // EventGenerator genera automáticamente eventos de monitoreo de energía
@@ -19,10 +40,85 @@ import (
 //
 // CAMBIO REALIZADO: Archivo creado desde cero
 // RAZÓN: Necesitábamos generar eventos automáticamente cada 5 minutos según requisitos
+// CAMBIO: Lote, intervalo, ritmo, topic y semilla vienen de la configuración (SIMULATOR_*)
+// RAZÓN: El ticker real era de 60 minutos, el topic "intake" no coincidía con CONSUMER_TOPIC
+// y las corridas no se podían reproducir
+// CAMBIO: Las plantas se cargan de energy_plants en cada lote
+// RAZÓN: Simula cualquier planta registrada en lugar de tres UUIDs fijos
+// CAMBIO: Los valores salen del perfil físico del tipo de planta (simulatedPlant)
+// RAZÓN: Los valores uniformes (0-1000 MW para una planta de 150 MW, estados al azar)
+// disparaban los chequeos de calidad y las alertas sin sentido en demos y pruebas
+// CAMBIO: Se controla en caliente (Start, Stop, Pause, Resume, SetRate, Burst, Status)
+// RAZÓN: Solo se podía arrancar con el servicio y detener matando el proceso (/admin/simulator)
 type EventGenerator struct {
-	kafkaService input.KafkaServiceInterface // Servicio para enviar mensajes a Kafka
-	topic        string                      // Tópico de Kafka donde se envían los eventos ("intake")
-	stopChan     chan struct{}               // Canal para detener el generador de forma segura
+	kafkaService    input.KafkaServiceInterface           // Servicio para enviar mensajes a Kafka
+	plantRepository output.EnergyPlantRepositoryInterface // Plantas simuladas
+	metrics         output.MetricsRecorderInterface       // Para contar lotes y eventos enviados
+	logger          *slog.Logger                          // Logger inyectado desde el Container
+
+	control sync.Mutex    // Serializa Start, Stop, Pause y Resume
+	reset   chan struct{} // Avisa al loop que cambió el intervalo
+
+	mu          sync.Mutex                    // Protege los campos siguientes
+	cfg         EventGeneratorConfig          // Configuración del simulador (rate, lote e intervalo cambian en caliente)
+	rng         *rand.Rand                    // Fuente aleatoria (sembrada con cfg.Seed)
+	simulations map[uuid.UUID]*simulatedPlant // Estado simulado de cada planta entre lotes
+	stopChan    chan struct{}                 // Canal para detener el loop actual (se crea en cada Start)
+	done        chan struct{}                 // Se cierra cuando el loop actual termina
+	sent        int64                         // Eventos enviados desde el arranque del servicio
+	failed      int64                         // Eventos que Kafka rechazó
+	batches     int64                         // Lotes y ráfagas completados
+	lastBatchAt time.Time                     // Fin del último lote o ráfaga con eventos
+	lastError   string                        // Último error (carga de plantas o envío)
+	lastErrorAt time.Time
+	heartbeat   time.Time // Último lote intentado, arranque o reanudación (check de readiness)
+
+	running atomic.Bool // true mientras el loop está activo
+	paused  atomic.Bool // El loop sigue activo pero no envía lotes
+}
+
+// EventGeneratorConfig configuración del simulador (variables SIMULATOR_*)
+// SIMULATOR_ENABLED no forma parte: solo decide si main llama a Start al arrancar
+type EventGeneratorConfig struct {
+	Topic     string        // SIMULATOR_TOPIC: tópico de destino (el Container usa CONSUMER_TOPIC si está vacío)
+	BatchSize int           // SIMULATOR_BATCH_SIZE: eventos por lote
+	Interval  time.Duration // SIMULATOR_INTERVAL: cada cuánto se envía un lote
+	Rate      float64       // SIMULATOR_RATE: eventos por segundo dentro de un lote (0 = sin pausa)
+	Seed      int64         // SIMULATOR_SEED: semilla de la fuente aleatoria (0 = una distinta en cada arranque)
+}
+
+// SimulatorRate cambios del ritmo del simulador (nil = sin cambio)
+type SimulatorRate struct {
+	Rate      *float64       // Eventos por segundo dentro de un lote (0 = sin pausa)
+	BatchSize *int           // Eventos por lote (1-10000)
+	Interval  *time.Duration // Tiempo entre lotes (>= 1s)
+}
+
+// SimulatorStatus estado del simulador
+//
+// CAMPOS:
+// - State: running, paused o stopped
+// - Sent / Failed / Batches: Acumulados desde el arranque del servicio (incluye las ráfagas)
+// - LastBatchAt: Fin del último lote o ráfaga con eventos (cero = ninguno)
+// - LastError / LastErrorAt: Último error al cargar plantas o enviar a Kafka
+// - Plants: Plantas con estado simulado
+type SimulatorStatus struct {
+	State       string
+	Config      EventGeneratorConfig
+	Sent        int64
+	Failed      int64
+	Batches     int64
+	LastBatchAt time.Time
+	LastError   string
+	LastErrorAt time.Time
+	Plants      int
+}
+
+// SimulatorBurst resultado de una ráfaga
+type SimulatorBurst struct {
+	Sent   int
+	Failed int
+	Plants []uuid.UUID // Plantas simuladas en la ráfaga
 }
 
 // EnergyMonitoringEvent estructura de datos para eventos de monitoreo de energía
@@ -31,146 +127,482 @@ type EventGenerator struct {
 // RAZÓN: Define el formato de los eventos que se envían a Kafka con datos realistas
 // de plantas de energía (potencia generada, eficiencia, temperatura, etc.)
 type EnergyMonitoringEvent struct {
-	PlantID        string    `json:"plant_id"`            // Identificador de la planta (plant-1, plant-2, etc.)
-	PlantSourceId  uuid.UUID `json:"plant_source_id"`     // UUID real de la planta en la base de datos
+	PlantID        string    `json:"plant_id"`            // Identificador de la planta (plant-1, plant-2, etc. según el orden por nombre)
+	PlantSourceId  uuid.UUID `json:"plant_source_id"`     // UUID de la planta en energy_plants
 	PlantName      string    `json:"plant_name"`          // Nombre descriptivo de la planta
 	EventType      string    `json:"event_type"`          // Tipo: power_reading, status_update, efficiency_report, alert
-	PowerGenerated float64   `json:"power_generated_mw"`  // Potencia generada en megavatios (0-1000 MW)
-	PowerConsumed  float64   `json:"power_consumed_mw"`   // Potencia consumida en megavatios (0-50 MW)
-	Efficiency     float64   `json:"efficiency_percent"`  // Eficiencia de la planta (75-95%)
-	Temperature    float64   `json:"temperature_celsius"` // Temperatura de operación (20-50°C)
+	PowerGenerated float64   `json:"power_generated_mw"`  // Potencia generada en megavatios (0-CapacityMW)
+	PowerConsumed  float64   `json:"power_consumed_mw"`   // Consumo propio en megavatios (+ carga en almacenamiento)
+	Efficiency     float64   `json:"efficiency_percent"`  // Eficiencia de conversión (0 sin producción)
+	Temperature    float64   `json:"temperature_celsius"` // Temperatura de los equipos (ambiente + carga)
 	Status         string    `json:"status"`              // Estado: operational, maintenance, standby, peak_load
 	Timestamp      time.Time `json:"timestamp"`           // Timestamp del evento
+
+	// CAMBIO: Métricas propias del tipo de planta (solo se envía la del tipo de la planta)
+	// RAZÓN: El intake las valida contra el catálogo de tipos y los KPIs las resumen
+	Irradiance     *float64 `json:"irradiance_w_m2,omitempty"`         // Solar: irradiancia (0-1000 W/m²)
+	WindSpeed      *float64 `json:"wind_speed_m_s,omitempty"`          // Eólica: velocidad del viento (Weibull, λ=8 m/s)
+	ReservoirLevel *float64 `json:"reservoir_level_percent,omitempty"` // Hidro: nivel del embalse (35-98%)
+	StateOfCharge  *float64 `json:"state_of_charge_percent,omitempty"` // Almacenamiento: estado de carga (10-95%)
 }
 
-// NewEventGenerator crea una nueva instancia del generador de eventos
+// NewEventGenerator crea una nueva instancia del generador de eventos (detenido)
 // PARÁMETROS:
 // - kafkaService: Servicio de Kafka para enviar mensajes
-// - topic: Nombre del tópico de Kafka (normalmente "intake")
-func NewEventGenerator(kafkaService input.KafkaServiceInterface, topic string) *EventGenerator {
+// - plantRepository: Plantas registradas que se simulan
+// - metrics: Registro de métricas de lotes enviados
+// - logger: Logger de la aplicación
+// - cfg: Configuración del simulador (SIMULATOR_*)
+func NewEventGenerator(
+	kafkaService input.KafkaServiceInterface,
+	plantRepository output.EnergyPlantRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg EventGeneratorConfig,
+) *EventGenerator {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 30
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	if cfg.Rate < 0 {
+		cfg.Rate = 0
+	}
+	// Sin semilla se toma una del reloj; se registra al arrancar para poder repetir la corrida
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
 	return &EventGenerator{
-		kafkaService: kafkaService,
-		topic:        topic,
-		stopChan:     make(chan struct{}),
+		kafkaService:    kafkaService,
+		plantRepository: plantRepository,
+		metrics:         metrics,
+		logger:          logger,
+		reset:           make(chan struct{}, 1),
+		cfg:             cfg,
+		rng:             rand.New(rand.NewSource(cfg.Seed)),
+		simulations:     make(map[uuid.UUID]*simulatedPlant),
 	}
 }
 
-// Start inicia el generador de eventos en modo continuo
+// Start inicia el generador de eventos en modo continuo (ErrConflict si ya está activo)
 //
 // FUNCIONAMIENTO:
-// 1. Envía el primer lote de 30 eventos inmediatamente al iniciar
-// 2. Luego envía 30 eventos cada 5 minutos de forma automática
-// 3. Se ejecuta en un goroutine separado (llamado con 'go')
+// 1. Envía el primer lote de BatchSize eventos inmediatamente al iniciar
+// 2. Luego envía un lote cada Interval de forma automática
+// 3. El loop corre en su propio goroutine; Start retorna enseguida
+// 4. main lo llama al arrancar con SIMULATOR_ENABLED=true; /admin/simulator/start en cualquier momento
 //
-// CAMBIO: Método nuevo
-// RAZÓN: Implementa el requisito de enviar 30 eventos cada 5 minutos automáticamente
-func (eg *EventGenerator) Start() {
-	log.Println("Starting Event Generator - will send 30 messages every 5 minutes")
+// CAMBIO: Lanza el loop y retorna; se puede volver a llamar después de Stop
+// RAZÓN: El simulador se arranca y detiene en caliente desde la API de administración
+func (eg *EventGenerator) Start() error {
+	eg.control.Lock()
+	defer eg.control.Unlock()
+
+	if eg.running.Load() {
+		return fmt.Errorf("%w: simulator is already running", domainerrors.ErrConflict)
+	}
+
+	eg.mu.Lock()
+	cfg := eg.cfg
+	eg.stopChan = make(chan struct{})
+	eg.done = make(chan struct{})
+	eg.heartbeat = time.Now()
+	stop, done := eg.stopChan, eg.done
+	eg.mu.Unlock()
+
+	eg.logger.Info("Starting Event Generator",
+		slog.String("topic", cfg.Topic),
+		slog.Int("batch_size", cfg.BatchSize),
+		slog.Duration("interval", cfg.Interval),
+		slog.Float64("rate", cfg.Rate),
+		slog.Int64("seed", cfg.Seed))
+
+	eg.paused.Store(false)
+	eg.running.Store(true)
+	go eg.run(stop, done)
+	return nil
+}
+
+// run es el loop del generador; termina al cerrarse stop
+func (eg *EventGenerator) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	defer eg.running.Store(false)
 
 	// CAMBIO: Envía el primer lote inmediatamente
-	// RAZÓN: Permite ver eventos de inmediato sin esperar 60 minutos
-	eg.generateAndSendEvents()
+	// RAZÓN: Permite ver eventos de inmediato sin esperar el primer intervalo
+	eg.generateAndSendEvents(stop)
 
-	// CAMBIO: Configura ticker para enviar cada 60 minutos
+	// CAMBIO: El ticker usa SIMULATOR_INTERVAL (SetRate lo reinicia con el nuevo valor)
 	// RAZÓN: Cumple con el requisito de enviar eventos periódicamente
-	ticker := time.NewTicker(60 * time.Minute)
+	ticker := time.NewTicker(eg.interval())
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			// Cada 60 minutos, genera y envía 30 eventos
-			eg.generateAndSendEvents()
-		case <-eg.stopChan:
+			if !eg.paused.Load() {
+				eg.generateAndSendEvents(stop)
+			}
+		case <-eg.reset:
+			ticker.Reset(eg.interval())
+		case <-stop:
 			// Permite detener el generador de forma limpia
-			log.Println("Stopping Event Generator")
+			eg.logger.Info("Stopping Event Generator")
 			return
 		}
 	}
 }
 
-// generateAndSendEvents genera y envía exactamente 30 eventos a Kafka
+// generateAndSendEvents genera y envía un lote de BatchSize eventos a Kafka
 //
 // FUNCIONAMIENTO:
-// 1. Define arrays de nombres de plantas, estados y tipos de eventos
-// 2. En un loop genera 30 eventos con datos aleatorios pero realistas
-// 3. Envía cada evento a Kafka usando KafkaService
-// 4. Espera 100ms entre eventos para no saturar Kafka
-//
-// CAMBIO: Método nuevo
-// RAZÓN: Implementa la lógica de generación de 30 eventos con datos simulados realistas
-func (eg *EventGenerator) generateAndSendEvents() {
-	log.Println("Generating and sending 30 events to Kafka...")
-
-	// CAMBIO: Define plantas con sus UUIDs reales de la base de datos
-	// RAZÓN: Usa los UUIDs exactos del archivo de seed para relacionar eventos con plantas reales
-	type PlantInfo struct {
-		ID   uuid.UUID
-		Name string
-	}
-
-	plants := []PlantInfo{
-		{
-			ID:   uuid.MustParse("1e2d3c4b-5a6f-7e8d-9c0b-1a2b3c4d5e6f"),
-			Name: "Solar Plant Alpha",
-		},
-		{
-			ID:   uuid.MustParse("2f3e4d5c-6b7a-8c9d-0e1f-2b3c4d5e6f7a"),
-			Name: "Wind Farm Beta",
-		},
-		{
-			ID:   uuid.MustParse("c2e78b94-76f4-49a4-b6e8-d62c8d1d23ea"),
-			Name: "Hydro Plant Gamma",
-		},
-	}
-
-	statuses := []string{"operational", "maintenance", "standby", "peak_load"}
-	eventTypes := []string{"power_reading", "status_update", "efficiency_report", "alert"}
-
-	// CAMBIO: Loop que genera exactamente 30 eventos
-	// RAZÓN: Cumple con el requisito específico de "30 mensajes cada 60 minutos"
-	for i := 0; i < 30; i++ {
-		// CAMBIO: Selecciona una planta aleatoria de las reales
-		// RAZÓN: Usa plantas reales de la base de datos con sus UUIDs
-		selectedPlant := plants[rand.Intn(len(plants))]
-
-		// CAMBIO: Genera datos aleatorios pero dentro de rangos realistas
-		// RAZÓN: Simula datos reales de plantas de energía para testing
-		event := EnergyMonitoringEvent{
-			PlantID:        fmt.Sprintf("plant-%d", rand.Intn(len(plants))+1),
-			PlantSourceId:  selectedPlant.ID,
-			PlantName:      selectedPlant.Name,
-			EventType:      eventTypes[rand.Intn(len(eventTypes))],
-			PowerGenerated: rand.Float64() * 1000,  // 0-1000 MW
-			PowerConsumed:  rand.Float64() * 50,    // 0-50 MW
-			Efficiency:     75 + rand.Float64()*20, // 75-95%
-			Temperature:    20 + rand.Float64()*30, // 20-50°C
-			Status:         statuses[rand.Intn(len(statuses))],
-			Timestamp:      time.Now(),
+// 1. Carga las plantas registradas (las nuevas entran en el siguiente lote)
+// 2. En un loop elige BatchSize veces una planta al azar y avanza su simulación hasta ahora
+// 3. Envía cada evento a Kafka usando KafkaService, con el plant_source_id como key
+// 4. Espera 1/Rate segundos entre eventos para no saturar Kafka (Stop y Pause cortan el lote)
+func (eg *EventGenerator) generateAndSendEvents(stop <-chan struct{}) {
+	// CAMBIO: Cada lote es la raíz de una traza; cada evento enviado es un span hijo
+	// RAZÓN: Permite seguir una lectura desde su generación hasta PostgreSQL
+	ctx, span := tracer.Start(context.Background(), "generator.batch")
+	defer span.End()
+
+	eg.mu.Lock()
+	cfg := eg.cfg
+	eg.heartbeat = time.Now()
+	eg.mu.Unlock()
+
+	// CAMBIO: Las plantas vienen de energy_plants
+	// RAZÓN: Los UUIDs fijos del seed dejaban fuera cualquier planta registrada después
+	plants, err := eg.plantRepository.FindAll(ctx)
+	if err != nil {
+		eg.logger.ErrorContext(ctx, "Error loading plants for the event generator", slog.Any("error", err))
+		eg.recordError(err)
+		return
+	}
+	if len(plants) == 0 {
+		eg.logger.WarnContext(ctx, "No plants registered, skipping event generator batch")
+		return
+	}
+	eg.logger.InfoContext(ctx, "Generating and sending events to Kafka",
+		slog.Int("events", cfg.BatchSize), slog.Int("plants", len(plants)))
+
+	var pause time.Duration
+	if cfg.Rate > 0 {
+		pause = time.Duration(float64(time.Second) / cfg.Rate)
+	}
+
+	sent, failed := 0, 0
+
+batch:
+	for i := 0; i < cfg.BatchSize; i++ {
+		// Stop y Pause cortan el lote también sin pausa entre eventos (SIMULATOR_RATE=0)
+		select {
+		case <-stop:
+			break batch
+		default:
 		}
+		if eg.paused.Load() {
+			break
+		}
+
+		eg.mu.Lock()
+		index := eg.rng.Intn(len(plants))
+		eg.mu.Unlock()
 
-		// CAMBIO: Crea una key única para el mensaje de Kafka
-		// RAZÓN: Kafka usa keys para particionar mensajes y garantizar orden
-		key := fmt.Sprintf("%s-%d", event.PlantID, time.Now().Unix())
-		err := eg.kafkaService.SendEvent(eg.topic, key, event)
-		if err != nil {
-			log.Printf("Error sending event %d to Kafka: %v", i+1, err)
+		if eg.send(ctx, cfg.Topic, plants, index, i+1) {
+			sent++
 		} else {
-			log.Printf("Event %d sent: PlantID=%s, Type=%s, Power=%.2fMW",
-				i+1, event.PlantID, event.EventType, event.PowerGenerated)
+			failed++
 		}
 
-		// CAMBIO: Pausa de 100ms entre eventos
+		// CAMBIO: Pausa entre eventos según SIMULATOR_RATE
 		// RAZÓN: Evita saturar Kafka y permite ver el flujo de eventos en los logs
-		time.Sleep(100 * time.Millisecond)
+		if pause > 0 && i < cfg.BatchSize-1 {
+			select {
+			case <-time.After(pause):
+			case <-stop:
+				break batch
+			}
+		}
 	}
 
-	log.Println("Finished sending 30 events to Kafka")
+	eg.recordBatch(sent, failed)
+	span.SetAttributes(attribute.Int("generator.sent", sent), attribute.Int("generator.failed", failed))
+	eg.logger.InfoContext(ctx, "Finished sending events to Kafka", slog.Int("sent", sent), slog.Int("failed", failed))
+}
+
+// send avanza la simulación de plants[index] y envía la lectura; devuelve si Kafka la aceptó
+func (eg *EventGenerator) send(ctx context.Context, topic string, plants []*entities.EnergyPlants, index, number int) bool {
+	// CAMBIO: La lectura sale del perfil del tipo de planta, continuo desde su lectura anterior
+	// RAZÓN: Potencia acotada por CapacityMW, temperatura según la carga y estados plausibles
+	eg.mu.Lock()
+	event := eg.simulation(plants[index]).step(time.Now(), eg.rng)
+	eg.mu.Unlock()
+	event.PlantID = fmt.Sprintf("plant-%d", index+1)
+
+	// CAMBIO: La key del mensaje es el plant_source_id
+	// RAZÓN: Kafka particiona por key; las lecturas de una planta llegan en orden
+	if err := eg.kafkaService.SendEvent(ctx, topic, event.PlantSourceId.String(), event); err != nil {
+		eg.logger.ErrorContext(ctx, "Error sending event to Kafka", slog.Int("event", number), slog.Any("error", err))
+		eg.recordError(err)
+		return false
+	}
+	eg.logger.DebugContext(ctx, "Event sent",
+		slog.Int("event", number),
+		slog.String("plant_source_id", event.PlantSourceId.String()),
+		slog.String("event_type", event.EventType),
+		slog.Float64("power_mw", event.PowerGenerated))
+	return true
 }
 
-// Stop detiene el generador de eventos de forma segura
+// Burst envía una ráfaga de count eventos sin pausa, repartidos en orden entre las plantas indicadas
+// (todas las registradas si plantIDs está vacío). Funciona aunque el simulador esté detenido o en pausa.
+// ErrInvalidInput si count está fuera de 1-10000; ErrNotFound si alguna planta no existe.
+func (eg *EventGenerator) Burst(ctx context.Context, count int, plantIDs []uuid.UUID) (*SimulatorBurst, error) {
+	if count < 1 || count > maxSimulatorEvents {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", domainerrors.ErrInvalidInput, maxSimulatorEvents)
+	}
+
+	// La ráfaga continúa aunque el cliente HTTP se desconecte
+	ctx, span := tracer.Start(context.WithoutCancel(ctx), "generator.burst")
+	defer span.End()
+
+	plants, err := eg.plantRepository.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading plants: %w", err)
+	}
+
+	// Índices en la lista completa para que plant_id coincida con el de los lotes
+	indexes := make([]int, 0, len(plants))
+	if len(plantIDs) == 0 {
+		for i := range plants {
+			indexes = append(indexes, i)
+		}
+	} else {
+		byID := make(map[uuid.UUID]int, len(plants))
+		for i, plant := range plants {
+			byID[plant.ID] = i
+		}
+		for _, id := range plantIDs {
+			i, ok := byID[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: plant %s", domainerrors.ErrNotFound, id)
+			}
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("%w: no plants registered", domainerrors.ErrConflict)
+	}
+
+	eg.mu.Lock()
+	topic := eg.cfg.Topic
+	eg.mu.Unlock()
+
+	result := &SimulatorBurst{Plants: make([]uuid.UUID, 0, len(indexes))}
+	for _, i := range indexes {
+		result.Plants = append(result.Plants, plants[i].ID)
+	}
+	for n := 0; n < count; n++ {
+		if eg.send(ctx, topic, plants, indexes[n%len(indexes)], n+1) {
+			result.Sent++
+		} else {
+			result.Failed++
+		}
+	}
+
+	eg.recordBatch(result.Sent, result.Failed)
+	span.SetAttributes(attribute.Int("generator.sent", result.Sent), attribute.Int("generator.failed", result.Failed))
+	eg.logger.InfoContext(ctx, "Event generator burst sent",
+		slog.Int("sent", result.Sent), slog.Int("failed", result.Failed), slog.Int("plants", len(indexes)))
+	return result, nil
+}
+
+// Stop detiene el generador de eventos de forma segura: corta el lote en curso y espera a que
+// el loop termine (ErrConflict si no está activo)
 // CAMBIO: Método nuevo
 // RAZÓN: Permite apagar el generador sin memory leaks cerrando el canal stopChan
-func (eg *EventGenerator) Stop() {
-	close(eg.stopChan)
+func (eg *EventGenerator) Stop() error {
+	eg.control.Lock()
+	defer eg.control.Unlock()
+
+	if !eg.running.Load() {
+		return fmt.Errorf("%w: simulator is not running", domainerrors.ErrConflict)
+	}
+
+	eg.mu.Lock()
+	stop, done := eg.stopChan, eg.done
+	eg.mu.Unlock()
+
+	close(stop)
+	<-done
+	eg.paused.Store(false)
+	return nil
+}
+
+// Pause deja de enviar lotes sin detener el loop; corta el lote en curso (ErrConflict si no está corriendo o ya está en pausa)
+func (eg *EventGenerator) Pause() error {
+	eg.control.Lock()
+	defer eg.control.Unlock()
+
+	if !eg.running.Load() {
+		return fmt.Errorf("%w: simulator is not running", domainerrors.ErrConflict)
+	}
+	if eg.paused.Load() {
+		return fmt.Errorf("%w: simulator is already paused", domainerrors.ErrConflict)
+	}
+	eg.paused.Store(true)
+	eg.logger.Info("Event Generator paused")
+	return nil
+}
+
+// Resume vuelve a enviar lotes desde el próximo intervalo (ErrConflict si no está en pausa)
+func (eg *EventGenerator) Resume() error {
+	eg.control.Lock()
+	defer eg.control.Unlock()
+
+	if !eg.running.Load() || !eg.paused.Load() {
+		return fmt.Errorf("%w: simulator is not paused", domainerrors.ErrConflict)
+	}
+	eg.mu.Lock()
+	eg.heartbeat = time.Now()
+	eg.mu.Unlock()
+	eg.paused.Store(false)
+	eg.logger.Info("Event Generator resumed")
+	return nil
+}
+
+// SetRate cambia el ritmo, el tamaño de lote o el intervalo sin reiniciar
+// Rate y BatchSize aplican desde el próximo lote; un intervalo nuevo reinicia el ticker
+func (eg *EventGenerator) SetRate(update SimulatorRate) (EventGeneratorConfig, error) {
+	if update.Rate != nil && *update.Rate < 0 {
+		return EventGeneratorConfig{}, fmt.Errorf("%w: rate must be >= 0", domainerrors.ErrInvalidInput)
+	}
+	if update.BatchSize != nil && (*update.BatchSize < 1 || *update.BatchSize > maxSimulatorEvents) {
+		return EventGeneratorConfig{}, fmt.Errorf("%w: batch_size must be between 1 and %d", domainerrors.ErrInvalidInput, maxSimulatorEvents)
+	}
+	if update.Interval != nil && *update.Interval < minSimulatorInterval {
+		return EventGeneratorConfig{}, fmt.Errorf("%w: interval must be at least %s", domainerrors.ErrInvalidInput, minSimulatorInterval)
+	}
+
+	eg.mu.Lock()
+	if update.Rate != nil {
+		eg.cfg.Rate = *update.Rate
+	}
+	if update.BatchSize != nil {
+		eg.cfg.BatchSize = *update.BatchSize
+	}
+	if update.Interval != nil {
+		eg.cfg.Interval = *update.Interval
+		eg.heartbeat = time.Now()
+	}
+	cfg := eg.cfg
+	eg.mu.Unlock()
+
+	if update.Interval != nil {
+		select {
+		case eg.reset <- struct{}{}:
+		default: // Ya hay un reinicio pendiente; leerá el intervalo nuevo
+		}
+	}
+
+	eg.logger.Info("Event Generator rate changed",
+		slog.Float64("rate", cfg.Rate),
+		slog.Int("batch_size", cfg.BatchSize),
+		slog.Duration("interval", cfg.Interval))
+	return cfg, nil
+}
+
+// Status devuelve el estado, la configuración actual y los contadores del simulador
+func (eg *EventGenerator) Status() SimulatorStatus {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+
+	state := SimulatorStopped
+	if eg.running.Load() {
+		state = SimulatorRunning
+		if eg.paused.Load() {
+			state = SimulatorPaused
+		}
+	}
+	return SimulatorStatus{
+		State:       state,
+		Config:      eg.cfg,
+		Sent:        eg.sent,
+		Failed:      eg.failed,
+		Batches:     eg.batches,
+		LastBatchAt: eg.lastBatchAt,
+		LastError:   eg.lastError,
+		LastErrorAt: eg.lastErrorAt,
+		Plants:      len(eg.simulations),
+	}
+}
+
+// Running indica si el goroutine del generador sigue activo
+func (eg *EventGenerator) Running() bool {
+	return eg.running.Load()
+}
+
+// Check es el check de readiness del simulador
+// Falla solo si está corriendo (sin pausa) y no intentó un lote en simulatorStallIntervals
+// intervalos más la duración de un lote; detenido o en pausa no es un error
+func (eg *EventGenerator) Check() error {
+	if !eg.running.Load() || eg.paused.Load() {
+		return nil
+	}
+
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+
+	limit := simulatorStallIntervals * eg.cfg.Interval
+	if eg.cfg.Rate > 0 {
+		limit += time.Duration(float64(eg.cfg.BatchSize) / eg.cfg.Rate * float64(time.Second))
+	}
+	if age := time.Since(eg.heartbeat); age > limit {
+		return fmt.Errorf("event generator has not run a batch for %s", age.Round(time.Second))
+	}
+	return nil
+}
+
+// interval devuelve el intervalo actual entre lotes
+func (eg *EventGenerator) interval() time.Duration {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	return eg.cfg.Interval
+}
+
+// recordBatch suma un lote o ráfaga a los contadores y a las métricas
+func (eg *EventGenerator) recordBatch(sent, failed int) {
+	eg.metrics.GeneratorBatch(sent, failed)
+
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	eg.sent += int64(sent)
+	eg.failed += int64(failed)
+	if sent+failed > 0 {
+		eg.batches++
+		eg.lastBatchAt = time.Now()
+	}
+}
+
+// recordError guarda el último error para Status
+func (eg *EventGenerator) recordError(err error) {
+	eg.mu.Lock()
+	defer eg.mu.Unlock()
+	eg.lastError = err.Error()
+	eg.lastErrorAt = time.Now()
+}
+
+// simulation devuelve el estado simulado de la planta, creándolo en su primera lectura
+// Los datos de la planta (nombre, tipo, capacidad) se actualizan con los de cada lote
+// Se llama con eg.mu tomado
+func (eg *EventGenerator) simulation(plant *entities.EnergyPlants) *simulatedPlant {
+	sim, ok := eg.simulations[plant.ID]
+	if !ok {
+		sim = newSimulatedPlant(plant, eg.rng)
+		eg.simulations[plant.ID] = sim
+	}
+	sim.plant = plant
+	return sim
 }
@@ -0,0 +1,400 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// forecastSeason puntos por temporada: la serie es horaria y la estacionalidad diaria
+const forecastSeason = 24
+
+// Resultado de cada reentrenamiento (métrica monitoring_energy_forecast_runs_total)
+const (
+	forecastOutcomeTrained = "trained"
+	forecastOutcomeSkipped = "skipped" // Historial insuficiente
+	forecastOutcomeFailed  = "failed"
+)
+
+// ForecastServiceConfig configuración del pronóstico (variables FORECAST_*)
+type ForecastServiceConfig struct {
+	Enabled         bool          // FORECAST_ENABLED: reentrenamiento periódico
+	RetrainInterval time.Duration // FORECAST_RETRAIN_INTERVAL
+	TrainingWindow  time.Duration // FORECAST_TRAINING_WINDOW: historial usado para entrenar
+	Horizon         time.Duration // FORECAST_HORIZON: horas pronosticadas en cada corrida
+	BacktestHorizon time.Duration // FORECAST_BACKTEST_HORIZON: tramo final reservado para medir el error
+	Confidence      float64       // FORECAST_CONFIDENCE: nivel de los intervalos (0.95 = 95%)
+	Retention       time.Duration // FORECAST_RETENTION: antigüedad máxima de las corridas guardadas
+}
+
+// ForecastService pronostica la generación (power_generated_mw) de cada planta
+//
+// PROPÓSITO:
+// Ajusta por planta un Holt-Winters aditivo con estacionalidad diaria sobre el historial
+// horario y guarda el pronóstico de las próximas Horizon horas con intervalos de confianza.
+//
+// FUNCIONAMIENTO:
+// 1. Las lecturas de los últimos TrainingWindow se promedian por hora; las horas sin lecturas
+// entre dos con datos se interpolan linealmente
+// 2. Se necesitan al menos dos días de serie y una lectura en el último día
+// 3. Backtest: se entrena sin las últimas BacktestHorizon horas y se compara el pronóstico
+// con las horas reales (solo las que tenían lecturas) → MAE, RMSE y MAE en % de la capacidad
+// 4. Se entrena con la serie completa y se guarda la corrida en la tabla forecasts;
+// los valores se recortan a [0, CapacityMW]
+// 5. Start reentrena todas las plantas al arrancar y cada RetrainInterval, y purga las
+// corridas más viejas que Retention
+type ForecastService struct {
+	eventRepository    output.EventRepositoryInterface
+	plantRepository    output.EnergyPlantRepositoryInterface
+	forecastRepository output.ForecastRepositoryInterface
+	metrics            output.MetricsRecorderInterface
+	logger             *slog.Logger
+	cfg                ForecastServiceConfig
+
+	mu       sync.Mutex // Un entrenamiento a la vez (el programado y los pedidos por REST)
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewForecastService crea el servicio; el reentrenamiento periódico se inicia con Start
+func NewForecastService(
+	eventRepository output.EventRepositoryInterface,
+	plantRepository output.EnergyPlantRepositoryInterface,
+	forecastRepository output.ForecastRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg ForecastServiceConfig,
+) *ForecastService {
+	if cfg.TrainingWindow <= 0 {
+		cfg.TrainingWindow = 28 * 24 * time.Hour
+	}
+	if cfg.Horizon < time.Hour {
+		cfg.Horizon = 7 * 24 * time.Hour
+	}
+	if cfg.Confidence <= 0 || cfg.Confidence >= 1 {
+		cfg.Confidence = 0.95
+	}
+
+	return &ForecastService{
+		eventRepository:    eventRepository,
+		plantRepository:    plantRepository,
+		forecastRepository: forecastRepository,
+		metrics:            metrics,
+		logger:             logger,
+		cfg:                cfg,
+		stopChan:           make(chan struct{}),
+	}
+}
+
+// Start inicia el reentrenamiento periódico (no hace nada si FORECAST_ENABLED es false)
+func (s *ForecastService) Start(ctx context.Context) {
+	if !s.cfg.Enabled || s.cfg.RetrainInterval <= 0 {
+		s.logger.InfoContext(ctx, "Forecast retraining disabled")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		s.TrainAll(ctx)
+		ticker := time.NewTicker(s.cfg.RetrainInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.TrainAll(ctx)
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	s.logger.InfoContext(ctx, "Forecast retraining started", slog.Duration("interval", s.cfg.RetrainInterval))
+}
+
+// Stop detiene el reentrenamiento periódico y espera a que termine la corrida en curso
+func (s *ForecastService) Stop() {
+	close(s.stopChan)
+	s.wg.Wait()
+}
+
+// TrainAll reentrena todas las plantas y purga las corridas vencidas
+// Una planta sin historial suficiente se saltea sin cortar el resto
+func (s *ForecastService) TrainAll(ctx context.Context) {
+	plants, err := s.plantRepository.FindAll(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error loading plants for forecasting", slog.Any("error", err))
+		return
+	}
+
+	trained := 0
+	for _, plant := range plants {
+		if _, err := s.train(ctx, plant); err != nil {
+			if errors.Is(err, domainerrors.ErrInvalidInput) {
+				s.logger.DebugContext(ctx, "Forecast skipped",
+					slog.String("plant_source_id", plant.ID.String()), slog.Any("reason", err))
+				continue
+			}
+			s.logger.ErrorContext(ctx, "Error training forecast",
+				slog.String("plant_source_id", plant.ID.String()), slog.Any("error", err))
+			continue
+		}
+		trained++
+	}
+
+	if s.cfg.Retention > 0 {
+		deleted, err := s.forecastRepository.DeleteCreatedBefore(ctx, time.Now().Add(-s.cfg.Retention))
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Error purging old forecasts", slog.Any("error", err))
+		} else if deleted > 0 {
+			s.logger.InfoContext(ctx, "Old forecasts purged", slog.Int64("deleted", deleted))
+		}
+	}
+
+	s.logger.InfoContext(ctx, "Forecasts retrained", slog.Int("plants", len(plants)), slog.Int("trained", trained))
+}
+
+// Train reentrena el modelo de una planta y guarda la corrida
+// Devuelve ErrNotFound si la planta no existe y ErrInvalidInput si el historial no alcanza
+func (s *ForecastService) Train(ctx context.Context, plantID uuid.UUID) (*entities.ForecastEntity, error) {
+	plant, err := s.plantRepository.FindByID(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.train(ctx, plant)
+}
+
+// Latest devuelve la última corrida de la planta con los puntos desde la hora actual hasta now+horizon
+// (horizon <= 0 devuelve todos los puntos futuros)
+func (s *ForecastService) Latest(ctx context.Context, plantID uuid.UUID, horizon time.Duration) (*entities.ForecastEntity, error) {
+	forecast, err := s.forecastRepository.FindLatest(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now().UTC().Truncate(time.Hour)
+	points := make([]entities.ForecastPoint, 0, len(forecast.Points))
+	for _, point := range forecast.Points {
+		if point.Time.Before(from) {
+			continue
+		}
+		if horizon > 0 && !point.Time.Before(from.Add(horizon)) {
+			break
+		}
+		points = append(points, point)
+	}
+	forecast.Points = points
+	return forecast, nil
+}
+
+func (s *ForecastService) train(ctx context.Context, plant *entities.EnergyPlants) (*entities.ForecastEntity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	forecast, err := s.fit(ctx, plant)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrInvalidInput) {
+			s.metrics.ForecastRun(forecastOutcomeSkipped)
+		} else {
+			s.metrics.ForecastRun(forecastOutcomeFailed)
+		}
+		return nil, err
+	}
+
+	if _, err := s.forecastRepository.Create(ctx, forecast); err != nil {
+		s.metrics.ForecastRun(forecastOutcomeFailed)
+		return nil, fmt.Errorf("error saving forecast for plant %s: %w", plant.ID, err)
+	}
+	s.metrics.ForecastRun(forecastOutcomeTrained)
+
+	attrs := []any{
+		slog.String("plant_source_id", plant.ID.String()),
+		slog.Int("training_points", forecast.TrainingPoints),
+	}
+	if forecast.BacktestMAE != nil {
+		attrs = append(attrs, slog.Float64("backtest_mae", *forecast.BacktestMAE))
+	}
+	s.logger.InfoContext(ctx, "Forecast trained", attrs...)
+	return forecast, nil
+}
+
+// fit arma la serie horaria de la planta, corre el backtest y entrena el modelo final
+func (s *ForecastService) fit(ctx context.Context, plant *entities.EnergyPlants) (*entities.ForecastEntity, error) {
+	now := time.Now().UTC()
+	end := now.Truncate(time.Hour) // La hora en curso está incompleta
+	start := end.Add(-s.cfg.TrainingWindow)
+
+	events, err := s.eventRepository.FindByFilter(ctx, entities.EventFilter{
+		PlantSourceId: &plant.ID,
+		From:          start,
+		Quality:       entities.EventQualityGood, // Las lecturas suspect no entrenan el modelo
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading history of plant %s: %w", plant.ID, err)
+	}
+
+	series, known, first := hourlySeries(events, plant.PlantType, start, end)
+	if len(series) < 2*forecastSeason {
+		return nil, fmt.Errorf("%w: at least %d hours of power_generated_mw history are needed, got %d",
+			domainerrors.ErrInvalidInput, 2*forecastSeason, len(series))
+	}
+	last := first.Add(time.Duration(len(series)-1) * time.Hour)
+	if end.Sub(last) > forecastSeason*time.Hour {
+		return nil, fmt.Errorf("%w: no readings in the last %d hours", domainerrors.ErrInvalidInput, forecastSeason)
+	}
+
+	model, ok := fitHoltWinters(series, forecastSeason)
+	if !ok {
+		return nil, fmt.Errorf("%w: not enough history to fit the model", domainerrors.ErrInvalidInput)
+	}
+
+	horizon := int(s.cfg.Horizon / time.Hour)
+	z := math.Sqrt2 * math.Erfinv(s.cfg.Confidence)
+	forecast := &entities.ForecastEntity{
+		PlantSourceId:  plant.ID,
+		Model:          entities.ForecastModelHoltWinters,
+		Alpha:          model.alpha,
+		Beta:           model.beta,
+		Gamma:          model.gamma,
+		SeasonLength:   forecastSeason,
+		TrainedFrom:    first,
+		TrainedTo:      last,
+		TrainingPoints: len(series),
+		HorizonHours:   horizon,
+		Confidence:     s.cfg.Confidence,
+		ResidualStd:    model.residualStd,
+		Points:         make([]entities.ForecastPoint, 0, horizon),
+	}
+	for h := 1; h <= horizon; h++ {
+		value, std := model.forecast(h)
+		forecast.Points = append(forecast.Points, entities.ForecastPoint{
+			Time:  last.Add(time.Duration(h) * time.Hour),
+			Value: clampPower(value, plant.CapacityMW),
+			Lower: clampPower(value-z*std, plant.CapacityMW),
+			Upper: clampPower(value+z*std, plant.CapacityMW),
+		})
+	}
+
+	s.backtest(forecast, series, known, plant.CapacityMW)
+	return forecast, nil
+}
+
+// backtest entrena sin las últimas BacktestHorizon horas y mide el error contra las horas reales
+// Sin historial suficiente para reservar ese tramo, la corrida queda sin métricas de backtest
+func (s *ForecastService) backtest(forecast *entities.ForecastEntity, series []float64, known []bool, capacity float64) {
+	hours := int(s.cfg.BacktestHorizon / time.Hour)
+	train := len(series) - hours
+	if hours <= 0 || train < 2*forecastSeason {
+		return
+	}
+	model, ok := fitHoltWinters(series[:train], forecastSeason)
+	if !ok {
+		return
+	}
+
+	var sumAbs, sumSq float64
+	var count int
+	for h := 1; h <= hours; h++ {
+		if !known[train+h-1] {
+			continue
+		}
+		value, _ := model.forecast(h)
+		err := series[train+h-1] - clampPower(value, capacity)
+		sumAbs += math.Abs(err)
+		sumSq += err * err
+		count++
+	}
+	if count == 0 {
+		return
+	}
+
+	mae := sumAbs / float64(count)
+	rmse := math.Sqrt(sumSq / float64(count))
+	forecast.BacktestHours = hours
+	forecast.BacktestMAE = &mae
+	forecast.BacktestRMSE = &rmse
+	if capacity > 0 {
+		nmae := mae / capacity * 100
+		forecast.BacktestNMAE = &nmae
+	}
+}
+
+// hourlySeries promedia power_generated_mw por hora en [start, end)
+// La serie va de la primera a la última hora con lecturas; los huecos intermedios se
+// interpolan linealmente y known indica qué horas tenían lecturas reales
+func hourlySeries(events []*entities.EventEntity, plantType string, start, end time.Time) ([]float64, []bool, time.Time) {
+	slots := int(end.Sub(start) / time.Hour)
+	if slots <= 0 {
+		return nil, nil, start
+	}
+	sums := make([]float64, slots)
+	counts := make([]int, slots)
+	for _, event := range events {
+		reading, err := entities.PlantReadingFromEvent(event, plantType)
+		if err != nil {
+			continue
+		}
+		value, ok := reading.Metric(entities.MetricPowerGenerated)
+		if !ok || reading.Timestamp.Before(start) || !reading.Timestamp.Before(end) {
+			continue
+		}
+		slot := int(reading.Timestamp.Sub(start) / time.Hour)
+		sums[slot] += value
+		counts[slot]++
+	}
+
+	firstSlot, lastSlot := -1, -1
+	for i, count := range counts {
+		if count > 0 {
+			if firstSlot < 0 {
+				firstSlot = i
+			}
+			lastSlot = i
+		}
+	}
+	if firstSlot < 0 {
+		return nil, nil, start
+	}
+
+	series := make([]float64, 0, lastSlot-firstSlot+1)
+	known := make([]bool, 0, lastSlot-firstSlot+1)
+	prev := firstSlot
+	for i := firstSlot; i <= lastSlot; i++ {
+		if counts[i] == 0 {
+			continue
+		}
+		value := sums[i] / float64(counts[i])
+		// Interpola los huecos entre la hora anterior con datos y esta
+		if gap := i - prev; gap > 1 {
+			from := series[len(series)-1]
+			for j := 1; j < gap; j++ {
+				series = append(series, from+(value-from)*float64(j)/float64(gap))
+				known = append(known, false)
+			}
+		}
+		series = append(series, value)
+		known = append(known, true)
+		prev = i
+	}
+	return series, known, start.Add(time.Duration(firstSlot) * time.Hour)
+}
+
+// clampPower recorta un valor de potencia a [0, capacidad] (sin techo si la capacidad es 0)
+func clampPower(value, capacity float64) float64 {
+	value = math.Max(value, 0)
+	if capacity > 0 {
+		value = math.Min(value, capacity)
+	}
+	return value
+}
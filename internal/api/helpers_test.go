@@ -0,0 +1,26 @@
+package api
+
+import (
+	"io"
+	"log/slog"
+
+	"monitoring-energy-service/internal/domain/ports/output"
+)
+
+// discardLogger descarta los logs de los servicios bajo prueba
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// nopMetrics ignora las métricas que registran los servicios bajo prueba
+// Un método no sobrescrito entra en pánico (la interfaz embebida es nil)
+type nopMetrics struct {
+	output.MetricsRecorderInterface
+}
+
+func (nopMetrics) RollupBuckets(string, int)         {}
+func (nopMetrics) RetentionPurged(string, int64)     {}
+func (nopMetrics) ReadingQualityFlag(string, string) {}
+func (nopMetrics) AlertTransition(string, string)    {}
+func (nopMetrics) WebhookDelivery(string)            {}
+func (nopMetrics) IntakeOutcome(string)              {}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"math"
+)
+
+// holtWintersDamping amortigua la tendencia: a 7 días una tendencia lineal se dispara
+const holtWintersDamping = 0.98
+
+// Grilla de parámetros que se prueba al ajustar el modelo (menor error a un paso)
+var (
+	holtWintersAlphas = []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.7}
+	holtWintersBetas  = []float64{0, 0.01, 0.05, 0.1}
+	holtWintersGammas = []float64{0.05, 0.1, 0.2, 0.3, 0.5}
+)
+
+// holtWinters es un modelo Holt-Winters aditivo con tendencia amortiguada ya ajustado a una serie
+//
+// FUNCIONAMIENTO:
+// - Nivel, tendencia y componente estacional (season valores, uno por hora del día) se
+// actualizan con cada punto de la serie según alpha, beta y gamma
+// - El pronóstico a h pasos es nivel + tendencia amortiguada + estacionalidad de esa hora
+// - residualStd es el desvío de los errores a un paso; de él salen los intervalos de confianza
+type holtWinters struct {
+	alpha, beta, gamma float64
+	season             int
+
+	level, trend float64
+	seasonal     []float64
+	residualStd  float64
+	n            int // Puntos de la serie ajustada
+}
+
+// fitHoltWinters prueba la grilla de parámetros y devuelve el modelo con menor error cuadrático
+// a un paso. La serie necesita al menos dos temporadas completas.
+func fitHoltWinters(series []float64, season int) (*holtWinters, bool) {
+	if season <= 0 || len(series) < 2*season {
+		return nil, false
+	}
+
+	var best *holtWinters
+	bestSSE := math.Inf(1)
+	for _, alpha := range holtWintersAlphas {
+		for _, beta := range holtWintersBetas {
+			for _, gamma := range holtWintersGammas {
+				model := &holtWinters{alpha: alpha, beta: beta, gamma: gamma, season: season}
+				if sse := model.fit(series); sse < bestSSE {
+					best, bestSSE = model, sse
+				}
+			}
+		}
+	}
+	return best, best != nil
+}
+
+// fit recorre la serie actualizando el estado y devuelve la suma de errores cuadráticos a un paso
+// (la primera temporada solo inicializa la estacionalidad)
+func (m *holtWinters) fit(series []float64) float64 {
+	s := m.season
+	first, second := mean(series[:s]), mean(series[s:2*s])
+	m.level = first
+	m.trend = (second - first) / float64(s)
+	m.seasonal = make([]float64, s)
+	for i := 0; i < s; i++ {
+		m.seasonal[i] = series[i] - first
+	}
+
+	var sse float64
+	var count int
+	for t := s; t < len(series); t++ {
+		y := series[t]
+		idx := t % s
+		predicted := m.level + holtWintersDamping*m.trend + m.seasonal[idx]
+		err := y - predicted
+		sse += err * err
+		count++
+
+		level := m.alpha*(y-m.seasonal[idx]) + (1-m.alpha)*(m.level+holtWintersDamping*m.trend)
+		m.trend = m.beta*(level-m.level) + (1-m.beta)*holtWintersDamping*m.trend
+		m.level = level
+		m.seasonal[idx] = m.gamma*(y-level) + (1-m.gamma)*m.seasonal[idx]
+	}
+
+	m.n = len(series)
+	if count > 0 {
+		m.residualStd = math.Sqrt(sse / float64(count))
+	}
+	return sse
+}
+
+// forecast devuelve el pronóstico a h pasos (h >= 1) y el desvío estimado de su error
+// El desvío crece con h según la aproximación clásica del modelo aditivo:
+// σ²·(1 + Σ_{j<h} (alpha·(1 + j·beta) + gamma·[j múltiplo de la temporada])²)
+func (m *holtWinters) forecast(h int) (value, std float64) {
+	damped := 0.0
+	phi := 1.0
+	for i := 1; i <= h; i++ {
+		phi *= holtWintersDamping
+		damped += phi
+	}
+	value = m.level + damped*m.trend + m.seasonal[(m.n+h-1)%m.season]
+
+	variance := 1.0
+	for j := 1; j < h; j++ {
+		c := m.alpha * (1 + float64(j)*m.beta)
+		if j%m.season == 0 {
+			c += m.gamma
+		}
+		variance += c * c
+	}
+	return value, m.residualStd * math.Sqrt(variance)
+}
+
+// mean devuelve el promedio de los valores (0 si no hay)
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"math"
+	"testing"
+)
+
+// seasonalSeries repite pattern seasons veces sumando slope por punto y un ruido determinístico de amplitud noise
+func seasonalSeries(pattern []float64, seasons int, slope, noise float64) []float64 {
+	series := make([]float64, 0, len(pattern)*seasons)
+	for t := 0; t < len(pattern)*seasons; t++ {
+		jitter := noise * math.Sin(float64(t)*12.9898)
+		series = append(series, pattern[t%len(pattern)]+slope*float64(t)+jitter)
+	}
+	return series
+}
+
+// dailyPattern es una curva tipo solar: 0 de noche y una campana de 6 a 18
+func dailyPattern() []float64 {
+	pattern := make([]float64, 24)
+	for hour := 6; hour <= 18; hour++ {
+		pattern[hour] = 50 * math.Sin(math.Pi*float64(hour-6)/12)
+	}
+	return pattern
+}
+
+func TestFitHoltWintersRequiresTwoSeasons(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+		season int
+		want   bool
+	}{
+		{name: "no season", length: 48, season: 0, want: false},
+		{name: "less than two seasons", length: 47, season: 24, want: false},
+		{name: "exactly two seasons", length: 48, season: 24, want: true},
+		{name: "more than two seasons", length: 100, season: 24, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := make([]float64, tt.length)
+			for i := range series {
+				series[i] = float64(i % 7)
+			}
+			model, ok := fitHoltWinters(series, tt.season)
+			if ok != tt.want || (model != nil) != tt.want {
+				t.Fatalf("fitHoltWinters() = %v, %t; want ok = %t", model, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoltWintersForecast(t *testing.T) {
+	pattern := dailyPattern()
+
+	tests := []struct {
+		name      string
+		series    []float64
+		horizons  []int
+		want      func(h int) float64 // Valor esperado a h pasos
+		tolerance float64
+	}{
+		{
+			name:      "constant series",
+			series:    seasonalSeries(make([]float64, 24), 3, 0, 0),
+			horizons:  []int{1, 12, 24, 48},
+			want:      func(int) float64 { return 0 },
+			tolerance: 1e-9,
+		},
+		{
+			name:      "pure seasonal series repeats the pattern",
+			series:    seasonalSeries(pattern, 3, 0, 0),
+			horizons:  []int{1, 7, 12, 24, 31},
+			want:      func(h int) float64 { return pattern[(72+h-1)%24] },
+			tolerance: 1e-9,
+		},
+		{
+			name:      "noisy seasonal series stays close to the pattern",
+			series:    seasonalSeries(pattern, 7, 0, 1),
+			horizons:  []int{1, 6, 12, 18, 24},
+			want:      func(h int) float64 { return pattern[(168+h-1)%24] },
+			tolerance: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, ok := fitHoltWinters(tt.series, 24)
+			if !ok {
+				t.Fatal("fitHoltWinters() did not fit")
+			}
+			for _, h := range tt.horizons {
+				value, _ := model.forecast(h)
+				if want := tt.want(h); math.Abs(value-want) > tt.tolerance {
+					t.Errorf("forecast(%d) = %.4f, want %.4f ± %g", h, value, want, tt.tolerance)
+				}
+			}
+		})
+	}
+}
+
+func TestHoltWintersTrendIsDamped(t *testing.T) {
+	const slope = 0.5
+	series := seasonalSeries(make([]float64, 24), 4, slope, 0)
+	model, ok := fitHoltWinters(series, 24)
+	if !ok {
+		t.Fatal("fitHoltWinters() did not fit")
+	}
+
+	// A la misma hora del día (h múltiplos de 24) el pronóstico sigue subiendo, cada día menos
+	previousStep := slope * 24
+	previous, _ := model.forecast(24)
+	for h := 48; h <= 168; h += 24 {
+		value, _ := model.forecast(h)
+		step := value - previous
+		if step <= 0 {
+			t.Errorf("forecast(%d) - forecast(%d) = %.4f, want > 0 (rising trend)", h, h-24, step)
+		}
+		if step >= previousStep {
+			t.Errorf("forecast(%d) - forecast(%d) = %.4f, want below %.4f (damped trend)", h, h-24, step, previousStep)
+		}
+		previous, previousStep = value, step
+	}
+}
+
+func TestHoltWintersForecastStdGrowsWithHorizon(t *testing.T) {
+	model, ok := fitHoltWinters(seasonalSeries(dailyPattern(), 7, 0.01, 2), 24)
+	if !ok {
+		t.Fatal("fitHoltWinters() did not fit")
+	}
+	if model.residualStd <= 0 {
+		t.Fatalf("residualStd = %v, want > 0 for a noisy series", model.residualStd)
+	}
+
+	_, first := model.forecast(1)
+	if math.Abs(first-model.residualStd) > 1e-12 {
+		t.Errorf("forecast(1) std = %v, want residualStd %v", first, model.residualStd)
+	}
+	previous := first
+	for h := 2; h <= 48; h++ {
+		_, std := model.forecast(h)
+		if std < previous {
+			t.Fatalf("forecast(%d) std = %v, below forecast(%d) std %v", h, std, h-1, previous)
+		}
+		previous = std
+	}
+}
+
+func TestMean(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{name: "empty", values: nil, want: 0},
+		{name: "single", values: []float64{4}, want: 4},
+		{name: "several", values: []float64{1, 2, 3, 6}, want: 3},
+		{name: "negative", values: []float64{-2, 2, -4}, want: -4.0 / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mean(tt.values); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("mean(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,17 +1,22 @@
 package api
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
 	"monitoring-energy-service/internal/domain/ports/input"
 	"monitoring-energy-service/internal/domain/ports/output"
+	"monitoring-energy-service/internal/infrastructure/logging"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // IntakeHandler procesa mensajes consumidos desde Kafka
@@ -23,25 +28,82 @@ import (
 // CAMBIO REALIZADO: Se agregó EventRepository y EnergyPlantRepository como dependencias
 // RAZÓN: Necesitábamos persistir los eventos y validar que las plantas existen
 type IntakeHandler struct {
-	eventRepository      output.EventRepositoryInterface      // Para guardar eventos en DB
+	eventRepository       output.EventRepositoryInterface       // Para guardar eventos en DB
 	energyPlantRepository output.EnergyPlantRepositoryInterface // Para validar que las plantas existen
+	metrics               output.MetricsRecorderInterface       // Para contar resultados del intake
+	logger                *slog.Logger                          // Logger con correlation_id por mensaje
+	redactor              *logging.Redactor                     // Política de redacción del payload en logs
+	processors            []input.ReadingProcessor              // Se ejecutan con cada lectura guardada (ej: AlertEngine)
+	maintenance           input.MaintenanceCalendar             // Para marcar las lecturas tomadas durante un mantenimiento
+	quality               input.ReadingQualityChecker           // Chequeos de plausibilidad antes de guardar
+	quarantine            input.EventQuarantine                 // Guarda los mensajes de plantas desconocidas (UNKNOWN_PLANT_MODE)
+	cfg                   IntakeHandlerConfig
 }
 
-var _ input.MessageHandler = &IntakeHandler{}
+// IntakeHandlerConfig configuración del intake
+type IntakeHandlerConfig struct {
+	MaxClockSkew time.Duration // EVENT_MAX_CLOCK_SKEW: adelanto máximo de timestamp sobre la recepción (0 = sin límite)
+}
+
+// Resultados del intake que se registran como métrica (label "outcome")
+const (
+	IntakeOutcomeSaved          = "saved"
+	IntakeOutcomeUnknownPlant   = "unknown_plant"
+	IntakeOutcomeQuarantined    = "quarantined"
+	IntakeOutcomeBadJSON        = "bad_json"
+	IntakeOutcomeInvalidPlantID = "invalid_plant_id"
+	IntakeOutcomeError          = "error"
+)
+
+var (
+	_ input.MessageHandler  = &IntakeHandler{}
+	_ input.MessageReplayer = &IntakeHandler{}
+)
 
 // NewIntakeHandler crea una nueva instancia del handler de Kafka
 // CAMBIO: Ahora recibe eventRepository y energyPlantRepository como parámetros
 // RAZÓN: Necesita validar plantas antes de guardar eventos
+// CAMBIO: Recibe metrics para registrar el resultado de cada mensaje
+// CAMBIO: Recibe logger y redactor inyectados desde el Container
+// CAMBIO: Recibe el calendario de mantenimiento
+// RAZÓN: Los eventos tomados durante una ventana de mantenimiento se guardan marcados
+// CAMBIO: Recibe el checker de calidad de lecturas
+// RAZÓN: Las lecturas implausibles se guardan con quality=suspect en lugar de aceptarse sin marca
+// CAMBIO: Recibe la cuarentena de plantas desconocidas
+// RAZÓN: Los mensajes de plantas todavía no registradas pueden guardarse en lugar de perderse
+// CAMBIO: Recibe la configuración del intake (EVENT_MAX_CLOCK_SKEW)
+// RAZÓN: Un timestamp muy adelantado movía hacia el futuro el estado de calidad, energía y lecturas tardías
 func NewIntakeHandler(
 	eventRepository output.EventRepositoryInterface,
 	energyPlantRepository output.EnergyPlantRepositoryInterface,
+	maintenance input.MaintenanceCalendar,
+	quality input.ReadingQualityChecker,
+	quarantine input.EventQuarantine,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	redactor *logging.Redactor,
+	cfg IntakeHandlerConfig,
 ) *IntakeHandler {
 	return &IntakeHandler{
 		eventRepository:       eventRepository,
 		energyPlantRepository: energyPlantRepository,
+		maintenance:           maintenance,
+		quality:               quality,
+		quarantine:            quarantine,
+		metrics:               metrics,
+		logger:                logger,
+		redactor:              redactor,
+		cfg:                   cfg,
 	}
 }
 
+// RegisterProcessor agrega un procesador que recibe cada lectura después de guardarse
+// CAMBIO: Método nuevo
+// RAZÓN: Las reglas de alerta se evalúan en el path de intake sin acoplar el handler al AlertEngine
+func (h *IntakeHandler) RegisterProcessor(processor input.ReadingProcessor) {
+	h.processors = append(h.processors, processor)
+}
+
 // HandleMessage procesa cada mensaje recibido desde Kafka
 //
 // FLUJO:
@@ -54,50 +116,57 @@ func NewIntakeHandler(
 //
 // CAMBIO REALIZADO: Completamente reescrito desde el TODO inicial
 // RAZÓN: Implementar la persistencia de eventos en PostgreSQL
-func (h *IntakeHandler) HandleMessage(message []byte) error {
-	// CAMBIO: Log safe metadata instead of raw message to avoid exposing PII
-	// RAZÓN: Evita exponer datos sensibles en logs, usa hash y tamaño del mensaje
-	hash := sha256.Sum256(message)
-	hashHex := hex.EncodeToString(hash[:])
+// CAMBIO: Recibe el contexto con la traza extraída de los headers de Kafka
+// RAZÓN: El trace_id se guarda en Metadata y las queries quedan dentro de la misma traza
+func (h *IntakeHandler) HandleMessage(ctx context.Context, message []byte) error {
+	return h.handle(ctx, message, uuid.Nil)
+}
 
-	// Preview: primeros 50 bytes (o menos si el mensaje es más corto)
-	previewLen := 50
-	if len(message) < previewLen {
-		previewLen = len(message)
+// ReplayMessage procesa un mensaje guardado (ej: liberado de la cuarentena) igual que uno consumido
+// Si la planta sigue sin existir el mensaje se rechaza en lugar de volver a la cuarentena
+//
+// CAMBIO: El evento se guarda con el ID del mensaje guardado y se omite si ese ID ya existe
+// RAZÓN: Si el proceso se cae entre el replay y el borrado de la cuarentena, la siguiente
+// liberación duplicaba el evento (y sus alertas, energía y webhooks)
+func (h *IntakeHandler) ReplayMessage(ctx context.Context, id uuid.UUID, message []byte) error {
+	_, err := h.eventRepository.FindByID(ctx, id)
+	if err == nil {
+		h.logger.InfoContext(ctx, "Replayed message already saved, skipped", slog.String("event_id", id.String()))
+		return nil
 	}
-	preview := string(message[:previewLen])
-	if len(message) > previewLen {
-		preview += "..."
+	if !errors.Is(err, domainerrors.ErrNotFound) {
+		return fmt.Errorf("error checking replayed event %s: %w", id, err)
 	}
+	return h.handle(ctx, message, id)
+}
 
-	log.Printf("Received message on intake topic - Size: %d bytes, SHA256: %s, Preview: %s",
-		len(message), hashHex, preview)
+// readingTime devuelve el event_time de la lectura en UTC y si se recortó a la recepción
+// por venir más adelantado que MaxClockSkew
+func (h *IntakeHandler) readingTime(data map[string]any) (time.Time, bool) {
+	receivedAt := time.Now().UTC()
+	readingTime := entities.ReadingTimestamp(data, receivedAt)
+	if h.cfg.MaxClockSkew > 0 && readingTime.After(receivedAt.Add(h.cfg.MaxClockSkew)) {
+		return receivedAt, true
+	}
+	return readingTime, false
+}
+
+// handle procesa el mensaje; replayID es el ID con que se guarda un mensaje liberado
+// (uuid.Nil = mensaje consumido, que puede ir a la cuarentena)
+func (h *IntakeHandler) handle(ctx context.Context, message []byte, replayID uuid.UUID) error {
+	// CAMBIO: El payload se registra a través de la política de redacción (LOG_PAYLOAD_MODE)
+	// RAZÓN: Evita exponer datos sensibles en logs; por defecto solo tamaño, hash y campos permitidos
+	h.logger.InfoContext(ctx, "Received message on intake topic", h.redactor.Attr("payload", message))
 
 	// CAMBIO: Parse del mensaje JSON
 	// RAZÓN: Necesitamos extraer campos específicos (event_type, plant_name)
 	var data map[string]interface{}
 	if err := json.Unmarshal(message, &data); err != nil {
-		log.Printf("Error unmarshaling message: %v", err)
+		h.logger.ErrorContext(ctx, "Error unmarshaling message", slog.Any("error", err))
+		h.metrics.IntakeOutcome(IntakeOutcomeBadJSON)
 		return err
 	}
 
-	// CAMBIO: Log solo campos seguros después del parsing
-	// RAZÓN: Permite debugging sin exponer payload completo con posible PII
-	safeEventType := "unknown"
-	if et, ok := data["event_type"].(string); ok {
-		safeEventType = et
-	}
-	safePlantName := "not_specified"
-	if pn, ok := data["plant_name"].(string); ok {
-		safePlantName = pn
-	}
-	safePlantSourceId := "not_specified"
-	if psid, ok := data["plant_source_id"].(string); ok {
-		safePlantSourceId = psid
-	}
-	log.Printf("Parsed safe fields - EventType: %s, PlantName: %s, PlantSourceId: %s",
-		safeEventType, safePlantName, safePlantSourceId)
-
 	// CAMBIO: Extrae event_type del mensaje
 	// RAZÓN: Indexamos por event_type para filtrado rápido en queries
 	eventType := "unknown"
@@ -118,56 +187,164 @@ func (h *IntakeHandler) HandleMessage(message []byte) error {
 	if plantSourceIdStr, ok := data["plant_source_id"].(string); ok {
 		parsedUUID, err := uuid.Parse(plantSourceIdStr)
 		if err != nil {
-			log.Printf("ERROR: Invalid plant_source_id format: %v - Message will be retried or sent to DLQ", err)
+			h.logger.ErrorContext(ctx, "Invalid plant_source_id format", slog.Any("error", err))
+			h.metrics.IntakeOutcome(IntakeOutcomeInvalidPlantID)
 			return fmt.Errorf("invalid plant_source_id format: %v", err)
 		}
 		plantSourceId = parsedUUID
 	} else {
-		log.Printf("ERROR: plant_source_id not found in message - Message will be retried or sent to DLQ")
+		h.logger.ErrorContext(ctx, "plant_source_id not found in message")
+		h.metrics.IntakeOutcome(IntakeOutcomeInvalidPlantID)
 		return fmt.Errorf("missing plant_source_id field in message")
 	}
 
+	// CAMBIO: El timestamp de la lectura se acota a la recepción + EVENT_MAX_CLOCK_SKEW
+	// RAZÓN: Un reloj adelantado dejaba fuera de orden todas las lecturas siguientes de la planta
+	readingTime, clamped := h.readingTime(data)
+	if clamped {
+		h.logger.WarnContext(ctx, "Reading timestamp is too far in the future, clamped to the receive time",
+			slog.String("plant_source_id", plantSourceId.String()),
+			slog.Any("timestamp", data["timestamp"]),
+			slog.Duration("max_clock_skew", h.cfg.MaxClockSkew))
+	}
+
 	// CAMBIO: Validar que la planta existe en la base de datos
 	// RAZÓN: Solo guardamos eventos de plantas válidas para mantener integridad referencial
-	exists, err := h.energyPlantRepository.Exists(plantSourceId)
-	if err != nil {
-		log.Printf("ERROR: Failed to validate plant existence for plant_source_id=%s: %v", plantSourceId, err)
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("energy.plant_source_id", plantSourceId.String()),
+		attribute.String("energy.event_type", eventType),
+	)
+
+	// CAMBIO: Usa FindByID en lugar de Exists
+	// RAZÓN: Las reglas de alerta necesitan el tipo de planta (plant_type) de la lectura
+	plant, err := h.energyPlantRepository.FindByID(ctx, plantSourceId)
+	if err != nil && !errors.Is(err, domainerrors.ErrNotFound) {
+		h.logger.ErrorContext(ctx, "Failed to validate plant existence",
+			slog.String("plant_source_id", plantSourceId.String()), slog.Any("error", err))
+		h.metrics.IntakeOutcome(IntakeOutcomeError)
 		return err
 	}
-	if !exists {
-		log.Printf("ERROR: Event rejected - plant_source_id=%s does not exist in database. EventType=%s, Source=%s - Message will be retried or sent to DLQ",
-			plantSourceId, eventType, source)
+	if plant == nil {
+		// CAMBIO: Con UNKNOWN_PLANT_MODE=quarantine el mensaje se guarda en cuarentena
+		// RAZÓN: Las lecturas de una planta recién puesta en marcha se liberan al registrarla
+		if replayID == uuid.Nil {
+			held, err := h.quarantine.Hold(ctx, &entities.QuarantinedEventEntity{
+				PlantSourceId: plantSourceId,
+				EventType:     eventType,
+				Source:        source,
+				Data:          string(message),
+				Metadata:      marshalMetadata(eventMetadata(ctx)),
+				EventTime:     readingTime,
+			})
+			if err != nil {
+				h.logger.ErrorContext(ctx, "Error quarantining event",
+					slog.String("plant_source_id", plantSourceId.String()), slog.Any("error", err))
+				h.metrics.IntakeOutcome(IntakeOutcomeError)
+				return err
+			}
+			if held {
+				h.logger.WarnContext(ctx, "Event quarantined - plant does not exist in database",
+					slog.String("plant_source_id", plantSourceId.String()),
+					slog.String("event_type", eventType),
+					slog.String("source", source))
+				h.metrics.IntakeOutcome(IntakeOutcomeQuarantined)
+				return nil
+			}
+		}
+		h.logger.WarnContext(ctx, "Event rejected - plant does not exist in database",
+			slog.String("plant_source_id", plantSourceId.String()),
+			slog.String("event_type", eventType),
+			slog.String("source", source))
+		h.metrics.IntakeOutcome(IntakeOutcomeUnknownPlant)
 		return fmt.Errorf("plant_source_id=%s does not exist in database (eventType=%s, source=%s)",
 			plantSourceId, eventType, source)
 	}
 
-	log.Printf("✓ Plant validated successfully: plant_source_id=%s", plantSourceId)
+	h.logger.DebugContext(ctx, "Plant validated successfully", slog.String("plant_source_id", plantSourceId.String()))
 
 	// CAMBIO: Convierte data completo a JSON string
 	// RAZÓN: PostgreSQL almacena el JSON completo como texto para consultas posteriores
 	dataJSON, err := json.Marshal(data)
 	if err != nil {
-		log.Printf("Error marshaling data: %v", err)
+		h.logger.ErrorContext(ctx, "Error marshaling data", slog.Any("error", err))
+		h.metrics.IntakeOutcome(IntakeOutcomeError)
+		return err
+	}
+
+	// CAMBIO: Busca la ventana de mantenimiento activa al momento de la lectura
+	// RAZÓN: Las lecturas tomadas durante un mantenimiento se marcan y sus alertas se suprimen o bajan a info
+	window, err := h.maintenance.ActiveWindow(ctx, plantSourceId, readingTime)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "Error checking maintenance windows", slog.Any("error", err))
+		h.metrics.IntakeOutcome(IntakeOutcomeError)
 		return err
 	}
 
 	// CAMBIO: Crea entidad de evento
 	// RAZÓN: Mapea el mensaje de Kafka a nuestra estructura de base de datos
+	// CAMBIO: Metadata guarda trace_id, span_id y correlation_id del mensaje
+	// RAZÓN: Permite saltar desde un evento en la API REST a su traza completa
 	event := &entities.EventEntity{
+		ID:            replayID,
 		EventType:     eventType,
 		PlantSourceId: plantSourceId,
 		Source:        source,
 		Data:          string(dataJSON),
+		Metadata:      marshalMetadata(eventMetadata(ctx)),
+	}
+	if window != nil {
+		event.MaintenanceWindowID = &window.ID
+	}
+	// CAMBIO: El timestamp de la lectura se guarda en su propia columna
+	// RAZÓN: Consultas y agregados usan event_time; created_at queda como tiempo de ingesta
+	event.EventTime = readingTime
+
+	// CAMBIO: Chequeos de plausibilidad antes de guardar
+	// RAZÓN: La lectura se guarda igual, pero marcada como suspect con los chequeos que falló
+	preview := entities.NewPlantReading(event, plant.PlantType, data)
+	event.Quality = entities.EventQualityGood
+	flags := h.quality.Check(ctx, plant, preview)
+	if clamped {
+		flags = append(flags, entities.QualityFlag(entities.QualityCheckFuture, entities.QualityMetricTimestamp))
+		h.metrics.ReadingQualityFlag(entities.QualityCheckFuture, entities.QualityMetricTimestamp)
+	}
+	if len(flags) > 0 {
+		event.Quality = entities.EventQualitySuspect
+		event.QualityFlags = flags
+		h.logger.WarnContext(ctx, "Reading failed plausibility checks",
+			slog.String("plant_source_id", plantSourceId.String()),
+			slog.Any("quality_flags", flags))
 	}
 
 	// CAMBIO: Guarda en PostgreSQL
 	// RAZÓN: Persiste el evento para consultas posteriores via API REST o DBeaver
-	savedEvent, err := h.eventRepository.Create(event)
+	savedEvent, err := h.eventRepository.Create(ctx, event)
 	if err != nil {
-		log.Printf("Error saving event to database: %v", err)
+		h.logger.ErrorContext(ctx, "Error saving event to database", slog.Any("error", err))
+		h.metrics.IntakeOutcome(IntakeOutcomeError)
 		return err
 	}
+	h.quality.Commit(ctx, plant, preview)
+
+	h.logger.InfoContext(ctx, "Event saved to database",
+		slog.String("event_id", savedEvent.ID.String()),
+		slog.String("event_type", savedEvent.EventType))
+	h.metrics.IntakeOutcome(IntakeOutcomeSaved)
+	h.metrics.IngestionDelay(savedEvent.CreatedAt.Sub(savedEvent.EventTime))
 
-	log.Printf("Event saved to database with ID: %s, Type: %s", savedEvent.ID, savedEvent.EventType)
+	// CAMBIO: Entrega la lectura tipada a los procesadores registrados
+	// RAZÓN: El evento ya está guardado; un fallo de un procesador se registra pero no rechaza el mensaje
+	reading := entities.NewPlantReading(savedEvent, plant.PlantType, data)
+	reading.PlantCapacityMW = plant.CapacityMW
+	reading.MaintenanceWindow = window
+	for _, processor := range h.processors {
+		if err := processor.ProcessReading(ctx, reading); err != nil {
+			h.logger.ErrorContext(ctx, "Error processing reading",
+				slog.String("processor", fmt.Sprintf("%T", processor)),
+				slog.String("event_id", savedEvent.ID.String()),
+				slog.Any("error", err))
+		}
+	}
 	return nil
 }
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+	"monitoring-energy-service/internal/infrastructure/logging"
+
+	"github.com/google/uuid"
+)
+
+// replayEventsFake responde FindByID con el error configurado (nil = el evento ya existe)
+type replayEventsFake struct {
+	output.EventRepositoryInterface
+	findErr error
+}
+
+func (f *replayEventsFake) FindByID(_ context.Context, id uuid.UUID) (*entities.EventEntity, error) {
+	if f.findErr != nil {
+		return nil, f.findErr
+	}
+	return &entities.EventEntity{ID: id}, nil
+}
+
+// replayPlantsFake cuenta las búsquedas de plantas; ninguna planta existe
+type replayPlantsFake struct {
+	output.EnergyPlantRepositoryInterface
+	lookups int
+}
+
+func (f *replayPlantsFake) FindByID(context.Context, uuid.UUID) (*entities.EnergyPlants, error) {
+	f.lookups++
+	return nil, domainerrors.ErrNotFound
+}
+
+// replayQuarantineFake falla si un mensaje liberado vuelve a la cuarentena
+type replayQuarantineFake struct {
+	input.EventQuarantine
+	t *testing.T
+}
+
+func (f replayQuarantineFake) Hold(context.Context, *entities.QuarantinedEventEntity) (bool, error) {
+	f.t.Error("replayed message was quarantined again")
+	return true, nil
+}
+
+func TestIntakeHandlerReplayMessage(t *testing.T) {
+	lookupErr := errors.New("connection reset")
+
+	tests := []struct {
+		name        string
+		findErr     error
+		wantErr     bool
+		wantLookups int
+	}{
+		{name: "already saved is skipped", findErr: nil, wantErr: false, wantLookups: 0},
+		{name: "error checking the event", findErr: lookupErr, wantErr: true, wantLookups: 0},
+		{name: "unknown plant is rejected, not quarantined", findErr: domainerrors.ErrNotFound, wantErr: true, wantLookups: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plants := &replayPlantsFake{}
+			handler := NewIntakeHandler(&replayEventsFake{findErr: tt.findErr}, plants, nil, nil, replayQuarantineFake{t: t},
+				nopMetrics{}, discardLogger(), logging.NewRedactor(logging.PayloadModeNone, ""), IntakeHandlerConfig{})
+			message := []byte(`{"event_type":"status_update","plant_source_id":"` + uuid.NewString() + `"}`)
+
+			err := handler.ReplayMessage(context.Background(), uuid.New(), message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReplayMessage() error = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.findErr == lookupErr && !errors.Is(err, lookupErr) {
+				t.Errorf("ReplayMessage() error = %v, want it to wrap %v", err, lookupErr)
+			}
+			if plants.lookups != tt.wantLookups {
+				t.Errorf("plant lookups = %d, want %d", plants.lookups, tt.wantLookups)
+			}
+		})
+	}
+}
@@ -1,37 +1,99 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
+	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"monitoring-energy-service/internal/domain/ports/input"
 	"monitoring-energy-service/internal/domain/ports/output"
+	"monitoring-energy-service/internal/infrastructure/logging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type KafkaService struct {
 	kafkaAdapter  output.KafkaAdapterInterface
+	metrics       output.MetricsRecorderInterface
+	logger        *slog.Logger
+	redactor      *logging.Redactor
 	topicHandlers map[string]input.MessageHandler
 	stopChan      chan struct{}
+	// running y lastPoll los consulta el check de readiness del consumidor
+	running  atomic.Bool
+	lastPoll atomic.Int64
 }
 
 var _ input.KafkaServiceInterface = &KafkaService{}
 
-func NewKafkaService(adapter output.KafkaAdapterInterface) *KafkaService {
+func NewKafkaService(
+	adapter output.KafkaAdapterInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	redactor *logging.Redactor,
+) *KafkaService {
 	return &KafkaService{
 		kafkaAdapter:  adapter,
+		metrics:       metrics,
+		logger:        logger,
+		redactor:      redactor,
 		topicHandlers: make(map[string]input.MessageHandler),
 		stopChan:      make(chan struct{}),
 	}
 }
 
-func (ks *KafkaService) SendEvent(topic string, key string, event any) error {
+// SendEvent serializa el evento y lo publica en Kafka
+// CAMBIO: Crea un span de productor e inyecta el contexto W3C (traceparent) en los headers
+// RAZÓN: El consumidor continúa la misma traza al procesar el mensaje
+func (ks *KafkaService) SendEvent(ctx context.Context, topic string, key string, event any) error {
+	ctx, span := tracer.Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationName("publish"),
+		),
+	)
+	defer span.End()
+
 	value, err := json.Marshal(event)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	// CAMBIO: Cada mensaje lleva su correlation ID en los headers (se genera si el contexto no trae uno)
+	// RAZÓN: Los logs del productor y del consumidor de un mismo mensaje comparten correlation_id
+	ctx, correlationID := logging.EnsureCorrelationID(ctx)
+
+	headers := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, headers)
+	headers[logging.CorrelationKafkaHeader] = correlationID
+
+	// CAMBIO: El payload se registra a través de la política de redacción, nunca completo por defecto
+	ks.logger.DebugContext(ctx, "sending message to kafka",
+		slog.String("topic", topic),
+		ks.redactor.Attr("payload", value),
+	)
+	if err := ks.kafkaAdapter.SendMessage(topic, key, value, headers); err != nil {
+		ks.metrics.KafkaProduceError(topic)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
-	log.Printf("sending to kafka topic %s message %s", topic, value)
-	return ks.kafkaAdapter.SendMessage(topic, key, value)
+	ks.metrics.KafkaMessageProduced(topic)
+	return nil
 }
 
 func (ks *KafkaService) RegisterHandler(topic string, handler input.MessageHandler) {
@@ -39,35 +101,46 @@ func (ks *KafkaService) RegisterHandler(topic string, handler input.MessageHandl
 }
 
 func (ks *KafkaService) ConsumeEvents() {
-	log.Printf("Starting to consume events from Kafka")
+	ks.logger.Info("Starting to consume events from Kafka")
 
 	topics := make([]string, 0, len(ks.topicHandlers))
 	for topic := range ks.topicHandlers {
 		topics = append(topics, topic)
 	}
+	sort.Strings(topics)
+	// Los errores del cliente (broker caído, rebalanceo) no traen topic: se cuentan en los suscritos
+	subscribed := strings.Join(topics, ",")
 
 	if len(topics) == 0 {
-		log.Printf("No topics registered, skipping Kafka consumer")
+		ks.logger.Warn("No topics registered, skipping Kafka consumer")
 		return
 	}
 
 	if err := ks.kafkaAdapter.SubscribeTopics(topics); err != nil {
-		log.Fatalf("Error subscribing to topics: %s", err)
-		return
+		ks.logger.Error("Error subscribing to topics", slog.Any("topics", topics), slog.Any("error", err))
+		os.Exit(1)
 	}
 
+	ks.running.Store(true)
+	defer ks.running.Store(false)
+
 	disconnectedCount := 0
 	connectionRefusedCount := 0
 
 	for {
 		select {
 		case <-ks.stopChan:
-			log.Println("Stopping Kafka event consumption.")
+			ks.logger.Info("Stopping Kafka event consumption")
 			return
 		default:
-			message, topic, err := ks.kafkaAdapter.ReadMessage()
+			message, topic, headers, err := ks.kafkaAdapter.ReadMessage()
+			ks.lastPoll.Store(time.Now().UnixNano())
 			if err != nil {
-				log.Printf("Error reading message: %s", err)
+				if topic == "" {
+					topic = subscribed
+				}
+				ks.logger.Error("Error reading message", slog.String("topic", topic), slog.Any("error", err))
+				ks.metrics.KafkaConsumeError(topic)
 
 				if strings.Contains(err.Error(), "Disconnected") {
 					disconnectedCount++
@@ -86,19 +159,86 @@ func (ks *KafkaService) ConsumeEvents() {
 				continue
 			}
 
+			// Sin mensajes dentro del poll timeout
+			if message == nil {
+				continue
+			}
+
+			ks.metrics.KafkaMessageConsumed(topic)
+
 			if handler, ok := ks.topicHandlers[topic]; ok {
-				log.Printf("Handling message for topic %s", topic)
-				err = handler.HandleMessage(message)
-				if err != nil {
-					log.Printf("Error handling message for topic %s: %s", topic, err)
-				}
+				ks.handleMessage(handler, topic, message, headers)
 			} else {
-				log.Printf("No handler registered for topic %s", topic)
+				ks.logger.Warn("No handler registered for topic", slog.String("topic", topic))
 			}
 		}
 	}
 }
 
+// handleMessage ejecuta el handler dentro de un span de consumidor
+// El span continúa la traza del productor extrayendo el traceparent de los headers
+// El correlation ID se toma del header correlation_id o se genera uno nuevo por mensaje
+func (ks *KafkaService) handleMessage(handler input.MessageHandler, topic string, message []byte, headers map[string]string) {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(headers))
+	if id := headers[logging.CorrelationKafkaHeader]; id != "" {
+		ctx = logging.WithCorrelationID(ctx, id)
+	} else {
+		ctx, _ = logging.EnsureCorrelationID(ctx)
+	}
+
+	ctx, span := tracer.Start(ctx, topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationName("process"),
+		),
+	)
+	defer span.End()
+
+	ks.logger.DebugContext(ctx, "Handling message", slog.String("topic", topic))
+
+	start := time.Now()
+	err := handler.HandleMessage(ctx, message)
+	ks.metrics.ObserveHandlerDuration(handlerName(handler), time.Since(start), err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		ks.logger.ErrorContext(ctx, "Error handling message", slog.String("topic", topic), slog.Any("error", err))
+	}
+}
+
 func (ks *KafkaService) StopConsuming() {
 	close(ks.stopChan)
 }
+
+// ConsumerStatus indica si el loop de consumo está corriendo y cuándo consultó Kafka por última vez
+func (ks *KafkaService) ConsumerStatus() (bool, time.Time) {
+	var lastPoll time.Time
+	if ns := ks.lastPoll.Load(); ns > 0 {
+		lastPoll = time.Unix(0, ns)
+	}
+	return ks.running.Load(), lastPoll
+}
+
+// CheckConnectivity verifica que productor y consumidor alcanzan a los brokers
+// El timeout se toma del deadline del contexto (por defecto 2s)
+func (ks *KafkaService) CheckConnectivity(ctx context.Context) error {
+	timeout := 2 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if err := ks.kafkaAdapter.CheckProducer(timeout); err != nil {
+		return err
+	}
+	return ks.kafkaAdapter.CheckConsumer(timeout)
+}
+
+// handlerName devuelve el nombre del tipo del handler (ej: "IntakeHandler") para usarlo como label
+func handlerName(handler input.MessageHandler) string {
+	t := reflect.TypeOf(handler)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
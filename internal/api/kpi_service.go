@@ -0,0 +1,507 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// KPIServiceConfig configuración del KPIService (variables KPI_*)
+type KPIServiceConfig struct {
+	MaxGap       time.Duration // ENERGY_MAX_GAP: intervalo máximo entre lecturas que se integra (el mismo que energy_daily)
+	MaxRange     time.Duration // KPI_MAX_RANGE: rango máximo de un reporte
+	RawRetention time.Duration // ROLLUP_RAW_RETENTION con los rollups activos: eventos que sobreviven a la purga (0 = todos)
+}
+
+// KPIService calcula los KPIs de las plantas a partir de los eventos guardados
+//
+// PROPÓSITO:
+// Factor de capacidad, eficiencia promedio, pico de generación, energía consumida,
+// exportación neta y tiempo en cada status por día, semana o mes, por planta y de la flota.
+//
+// FUNCIONAMIENTO:
+// 1. Carga los eventos de la planta del rango (ampliado en MaxGap para los bordes) y los
+// convierte en lecturas; la base de tiempo es el timestamp de cada lectura
+// 2. La energía se integra con la regla del trapecio entre lecturas consecutivas good, igual que
+// energy_daily; los intervalos mayores a MaxGap se consideran sin datos y no suman energía
+// 3. Un intervalo que cruza el límite de un período se reparte interpolando la potencia
+// 4. El tiempo en cada status se asigna al status de la lectura que abre el intervalo
+// 5. El total del rango y la flota se obtienen sumando los períodos de cada planta
+// 6. Con ExcludeMaintenance las ocurrencias de las ventanas de mantenimiento de la planta se
+// recortan de todos los intervalos y sus horas se descuentan de la capacidad disponible
+// 7. Con ExcludeSuspect solo se usan las lecturas con quality=good (la energía nunca integra las suspect)
+// 8. Las métricas propias del tipo de planta se resumen en Telemetry; en almacenamiento la
+// generación es descarga y el consumo carga, y se informa la eficiencia de ida y vuelta
+// 9. Los KPIs salen de los eventos crudos: un rango que empieza antes de now - RawRetention
+// se rechaza en lugar de devolver ceros para los días ya purgados
+type KPIService struct {
+	eventRepository       output.EventRepositoryInterface
+	energyPlantRepository output.EnergyPlantRepositoryInterface
+	maintenanceRepository output.MaintenanceWindowRepositoryInterface
+	logger                *slog.Logger
+	cfg                   KPIServiceConfig
+}
+
+// NewKPIService crea el servicio de KPIs
+func NewKPIService(
+	eventRepository output.EventRepositoryInterface,
+	energyPlantRepository output.EnergyPlantRepositoryInterface,
+	maintenanceRepository output.MaintenanceWindowRepositoryInterface,
+	logger *slog.Logger,
+	cfg KPIServiceConfig,
+) *KPIService {
+	if cfg.MaxGap <= 0 {
+		cfg.MaxGap = 15 * time.Minute
+	}
+	return &KPIService{
+		eventRepository:       eventRepository,
+		energyPlantRepository: energyPlantRepository,
+		maintenanceRepository: maintenanceRepository,
+		logger:                logger,
+		cfg:                   cfg,
+	}
+}
+
+// EarliestFrom devuelve el primer día UTC completo cuyos eventos no se purgaron (cero si no se purgan)
+func (s *KPIService) EarliestFrom() time.Time {
+	return rawRetentionStart(s.cfg.RawRetention)
+}
+
+// validate valida la consulta y que el rango no empiece en eventos ya purgados
+func (s *KPIService) validate(query entities.KPIQuery) error {
+	if err := query.Validate(s.cfg.MaxRange); err != nil {
+		return err
+	}
+	if s.cfg.RawRetention > 0 {
+		if oldest := time.Now().UTC().Add(-s.cfg.RawRetention); query.From.Before(oldest) {
+			return fmt.Errorf("%w: from cannot be before %s, older events are purged (ROLLUP_RAW_RETENTION)",
+				domainerrors.ErrInvalidInput, oldest.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// PlantReport calcula los KPIs de una planta por período
+func (s *KPIService) PlantReport(ctx context.Context, plantID uuid.UUID, query entities.KPIQuery) (*entities.PlantKPIReport, error) {
+	if err := s.validate(query); err != nil {
+		return nil, err
+	}
+	plant, err := s.energyPlantRepository.FindByID(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.plantPeriods(ctx, plant, query)
+	if err != nil {
+		return nil, err
+	}
+	return newPlantKPIReport(plant, query, periods, true), nil
+}
+
+// FleetReport calcula los KPIs de la flota por período y el total de cada planta
+// En la flota CoveredHours suma las horas de todas las plantas y el pico es el mayor de una planta
+func (s *KPIService) FleetReport(ctx context.Context, query entities.KPIQuery) (*entities.FleetKPIReport, error) {
+	if err := s.validate(query); err != nil {
+		return nil, err
+	}
+	plants, err := s.energyPlantRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet := newKPIAccumulators(query)
+	report := &entities.FleetKPIReport{
+		From:               query.From,
+		To:                 query.To,
+		Granularity:        query.Granularity,
+		ExcludeMaintenance: query.ExcludeMaintenance,
+		ExcludeSuspect:     query.ExcludeSuspect,
+		Plants:             make([]entities.PlantKPIReport, 0, len(plants)),
+	}
+	for _, plant := range plants {
+		periods, err := s.plantPeriods(ctx, plant, query)
+		if err != nil {
+			return nil, err
+		}
+		for i, period := range periods {
+			fleet[i].merge(period)
+		}
+		report.CapacityMW += plant.CapacityMW
+		report.Plants = append(report.Plants, *newPlantKPIReport(plant, query, periods, false))
+	}
+
+	total := &kpiAccumulator{start: query.From, end: query.To}
+	report.Periods = make([]entities.KPIPeriod, 0, len(fleet))
+	for _, period := range fleet {
+		total.merge(period)
+		report.Periods = append(report.Periods, period.kpiPeriod())
+	}
+	report.Total = total.kpiPeriod()
+	return report, nil
+}
+
+// plantPeriods integra las lecturas de la planta en los períodos del rango
+func (s *KPIService) plantPeriods(ctx context.Context, plant *entities.EnergyPlants, query entities.KPIQuery) ([]*kpiAccumulator, error) {
+	filter := entities.EventFilter{
+		PlantSourceId: &plant.ID,
+		From:          query.From.Add(-s.cfg.MaxGap),
+		To:            query.To.Add(s.cfg.MaxGap),
+	}
+	if query.ExcludeSuspect {
+		filter.Quality = entities.EventQualityGood
+	}
+	events, err := s.eventRepository.FindByFilter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error loading events of plant %s: %w", plant.ID, err)
+	}
+
+	readings := make([]entities.PlantReading, 0, len(events))
+	for _, event := range events {
+		reading, err := entities.PlantReadingFromEvent(event, plant.PlantType)
+		if err != nil {
+			s.logger.DebugContext(ctx, "Skipping event with invalid data", slog.String("event_id", event.ID.String()), slog.Any("error", err))
+			continue
+		}
+		readings = append(readings, reading)
+	}
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].Timestamp.Before(readings[j].Timestamp) })
+
+	var excluded []entities.TimeRange
+	if query.ExcludeMaintenance {
+		if excluded, err = s.maintenanceRanges(ctx, plant.ID, query); err != nil {
+			return nil, err
+		}
+	}
+
+	periods := newKPIAccumulators(query)
+	for _, acc := range periods {
+		for _, r := range excluded {
+			acc.maintenance += overlap(acc.start, acc.end, r)
+		}
+		acc.capacityMWh = plant.CapacityMW * (acc.end.Sub(acc.start) - acc.maintenance).Hours()
+	}
+
+	s.accumulateReadings(periods, readings, excluded)
+	s.integrate(periods, query, excluded, readings, entities.MetricPowerGenerated, func(acc *kpiAccumulator, mwh float64, d time.Duration) {
+		acc.generatedMWh += mwh
+		acc.covered += d
+	})
+	s.integrate(periods, query, excluded, readings, entities.MetricPowerConsumed, func(acc *kpiAccumulator, mwh float64, _ time.Duration) {
+		acc.consumedMWh += mwh
+	})
+	s.accumulateStatus(periods, query, excluded, readings)
+	if plant.PlantType == entities.PlantTypeStorage {
+		for _, acc := range periods {
+			acc.dischargedMWh, acc.chargedMWh = acc.generatedMWh, acc.consumedMWh
+		}
+	}
+	return periods, nil
+}
+
+// maintenanceRanges devuelve las ocurrencias de mantenimiento de la planta dentro del rango, unidas y ordenadas
+func (s *KPIService) maintenanceRanges(ctx context.Context, plantID uuid.UUID, query entities.KPIQuery) ([]entities.TimeRange, error) {
+	windows, err := s.maintenanceRepository.FindAll(ctx, entities.MaintenanceWindowFilter{PlantSourceId: &plantID})
+	if err != nil {
+		return nil, fmt.Errorf("error loading maintenance windows of plant %s: %w", plantID, err)
+	}
+
+	var ranges []entities.TimeRange
+	for _, window := range windows {
+		ranges = append(ranges, window.Occurrences(query.From, query.To)...)
+	}
+	return entities.MergeTimeRanges(ranges), nil
+}
+
+// accumulateReadings suma lecturas, eficiencia, pico de generación y métricas del tipo de cada período
+func (s *KPIService) accumulateReadings(periods []*kpiAccumulator, readings []entities.PlantReading, excluded []entities.TimeRange) {
+	for _, reading := range readings {
+		acc := periodAt(periods, reading.Timestamp)
+		if acc == nil || inRanges(excluded, reading.Timestamp) {
+			continue
+		}
+		acc.readings++
+		if v, ok := reading.Metric(entities.MetricEfficiency); ok {
+			acc.efficiencySum += v
+			acc.efficiencyCount++
+		}
+		if v, ok := reading.Metric(entities.MetricPowerGenerated); ok && (acc.peakAt == nil || v > acc.peakMW) {
+			at := reading.Timestamp
+			acc.peakMW = v
+			acc.peakAt = &at
+		}
+		for _, definition := range entities.TypeMetrics(reading.PlantType) {
+			if v, ok := reading.Metric(definition.Name); ok {
+				acc.addTelemetry(definition.Name, telemetryAccumulator{sum: v, min: v, max: v, count: 1})
+			}
+		}
+	}
+}
+
+// integrate reparte entre los períodos la energía de la métrica con la misma integración que
+// energy_daily (energyIntervals y trapezoidMWh)
+//
+// CAMBIO: Usa energyIntervals/trapezoidMWh del EnergyIntegrator y ENERGY_MAX_GAP
+// RAZÓN: Con su propio trapecio, KPI_MAX_GAP y las lecturas suspect incluidas sin exclude_suspect,
+// la energía de los KPIs no coincidía con la de /energy para el mismo rango
+func (s *KPIService) integrate(periods []*kpiAccumulator, query entities.KPIQuery, excluded []entities.TimeRange, readings []entities.PlantReading, metric string, add func(acc *kpiAccumulator, mwh float64, d time.Duration)) {
+	energyIntervals(readings, metric, s.cfg.MaxGap, func(a, b time.Time, va, vb float64) {
+		eachSegment(periods, query, excluded, a, b, func(acc *kpiAccumulator, x, y time.Time) {
+			add(acc, trapezoidMWh(a, b, va, vb, x, y), y.Sub(x))
+		})
+	})
+}
+
+// accumulateStatus asigna cada intervalo entre lecturas con status al status de la primera
+func (s *KPIService) accumulateStatus(periods []*kpiAccumulator, query entities.KPIQuery, excluded []entities.TimeRange, readings []entities.PlantReading) {
+	var prev *entities.PlantReading
+	for i := range readings {
+		if readings[i].Status == "" {
+			continue
+		}
+		if prev != nil {
+			status := prev.Status
+			if span := readings[i].Timestamp.Sub(prev.Timestamp); span > 0 && span <= s.cfg.MaxGap {
+				eachSegment(periods, query, excluded, prev.Timestamp, readings[i].Timestamp, func(acc *kpiAccumulator, x, y time.Time) {
+					acc.status[status] += y.Sub(x)
+				})
+			}
+		}
+		prev = &readings[i]
+	}
+}
+
+// kpiAccumulator acumula los valores de un período antes de calcular los KPIs
+type kpiAccumulator struct {
+	start, end      time.Time
+	readings        int
+	covered         time.Duration
+	generatedMWh    float64
+	consumedMWh     float64
+	maintenance     time.Duration // Horas excluidas por mantenimiento
+	capacityMWh     float64       // Capacidad por horas disponibles (denominador del factor de capacidad)
+	efficiencySum   float64
+	efficiencyCount int
+	peakMW          float64
+	peakAt          *time.Time
+	status          map[string]time.Duration
+	telemetry       map[string]telemetryAccumulator // Métricas propias del tipo de planta
+	chargedMWh      float64                         // Solo almacenamiento (consumo)
+	dischargedMWh   float64                         // Solo almacenamiento (generación)
+}
+
+// telemetryAccumulator acumula una métrica del tipo de planta
+type telemetryAccumulator struct {
+	sum, min, max float64
+	count         int
+}
+
+func (a *kpiAccumulator) addTelemetry(metric string, o telemetryAccumulator) {
+	if a.telemetry == nil {
+		a.telemetry = make(map[string]telemetryAccumulator)
+	}
+	t, ok := a.telemetry[metric]
+	if !ok {
+		a.telemetry[metric] = o
+		return
+	}
+	t.sum += o.sum
+	t.min = min(t.min, o.min)
+	t.max = max(t.max, o.max)
+	t.count += o.count
+	a.telemetry[metric] = t
+}
+
+// newKPIAccumulators divide el rango en períodos de la granularidad (el primero y el último recortados)
+func newKPIAccumulators(query entities.KPIQuery) []*kpiAccumulator {
+	var periods []*kpiAccumulator
+	for start := entities.PeriodStart(query.From, query.Granularity); start.Before(query.To); start = entities.NextPeriod(start, query.Granularity) {
+		acc := &kpiAccumulator{
+			start:  start,
+			end:    entities.NextPeriod(start, query.Granularity),
+			status: make(map[string]time.Duration),
+		}
+		if acc.start.Before(query.From) {
+			acc.start = query.From
+		}
+		if acc.end.After(query.To) {
+			acc.end = query.To
+		}
+		periods = append(periods, acc)
+	}
+	return periods
+}
+
+// periodAt devuelve el período que contiene t (nil fuera del rango)
+func periodAt(periods []*kpiAccumulator, t time.Time) *kpiAccumulator {
+	i := sort.Search(len(periods), func(i int) bool { return periods[i].end.After(t) })
+	if i == len(periods) || t.Before(periods[i].start) {
+		return nil
+	}
+	return periods[i]
+}
+
+// eachSegment recorta el intervalo [a, b) al rango, le quita los tramos excluidos (ordenados
+// y sin solaparse) y reparte lo que queda entre los períodos que cruza
+func eachSegment(periods []*kpiAccumulator, query entities.KPIQuery, excluded []entities.TimeRange, a, b time.Time, fn func(acc *kpiAccumulator, x, y time.Time)) {
+	if a.Before(query.From) {
+		a = query.From
+	}
+	if b.After(query.To) {
+		b = query.To
+	}
+	for _, r := range excluded {
+		if !a.Before(b) || !r.Start.Before(b) {
+			break
+		}
+		if !r.End.After(a) {
+			continue
+		}
+		if a.Before(r.Start) {
+			splitSegment(periods, a, r.Start, fn)
+		}
+		a = r.End
+	}
+	if a.Before(b) {
+		splitSegment(periods, a, b, fn)
+	}
+}
+
+// splitSegment reparte [a, b) entre los períodos que cruza
+func splitSegment(periods []*kpiAccumulator, a, b time.Time, fn func(acc *kpiAccumulator, x, y time.Time)) {
+	i := sort.Search(len(periods), func(i int) bool { return periods[i].end.After(a) })
+	for ; i < len(periods) && a.Before(b); i++ {
+		end := periods[i].end
+		if b.Before(end) {
+			end = b
+		}
+		fn(periods[i], a, end)
+		a = end
+	}
+}
+
+// overlap devuelve cuánto de [a, b) cae dentro de r
+func overlap(a, b time.Time, r entities.TimeRange) time.Duration {
+	if r.Start.After(a) {
+		a = r.Start
+	}
+	if r.End.Before(b) {
+		b = r.End
+	}
+	if !a.Before(b) {
+		return 0
+	}
+	return b.Sub(a)
+}
+
+// inRanges indica si t cae dentro de alguno de los intervalos
+func inRanges(ranges []entities.TimeRange, t time.Time) bool {
+	for _, r := range ranges {
+		if !t.Before(r.Start) && t.Before(r.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// merge suma otro período (de la misma planta o de otra planta de la flota)
+func (a *kpiAccumulator) merge(o *kpiAccumulator) {
+	a.readings += o.readings
+	a.covered += o.covered
+	a.generatedMWh += o.generatedMWh
+	a.consumedMWh += o.consumedMWh
+	a.maintenance += o.maintenance
+	a.capacityMWh += o.capacityMWh
+	a.efficiencySum += o.efficiencySum
+	a.efficiencyCount += o.efficiencyCount
+	if o.peakAt != nil && (a.peakAt == nil || o.peakMW > a.peakMW) {
+		a.peakMW, a.peakAt = o.peakMW, o.peakAt
+	}
+	if a.status == nil {
+		a.status = make(map[string]time.Duration)
+	}
+	for status, d := range o.status {
+		a.status[status] += d
+	}
+	for metric, t := range o.telemetry {
+		a.addTelemetry(metric, t)
+	}
+	a.chargedMWh += o.chargedMWh
+	a.dischargedMWh += o.dischargedMWh
+}
+
+// kpiPeriod calcula los KPIs del período
+func (a *kpiAccumulator) kpiPeriod() entities.KPIPeriod {
+	hours := a.end.Sub(a.start).Hours()
+	period := entities.KPIPeriod{
+		Start:              a.start,
+		End:                a.end,
+		HoursInPeriod:      hours,
+		CoveredHours:       a.covered.Hours(),
+		Readings:           a.readings,
+		EnergyGeneratedMWh: a.generatedMWh,
+		EnergyConsumedMWh:  a.consumedMWh,
+		NetExportMWh:       a.generatedMWh - a.consumedMWh,
+		MaintenanceHours:   a.maintenance.Hours(),
+		TimeInStatusHours:  make(map[string]float64, len(a.status)),
+	}
+	if a.capacityMWh > 0 {
+		cf := a.generatedMWh / a.capacityMWh
+		period.CapacityFactor = &cf
+	}
+	if a.efficiencyCount > 0 {
+		avg := a.efficiencySum / float64(a.efficiencyCount)
+		period.AvgEfficiencyPercent = &avg
+	}
+	if a.peakAt != nil {
+		peak := a.peakMW
+		period.PeakOutputMW = &peak
+		period.PeakOutputAt = a.peakAt
+	}
+	for status, d := range a.status {
+		period.TimeInStatusHours[status] = d.Hours()
+	}
+	if len(a.telemetry) > 0 {
+		period.Telemetry = make(map[string]entities.TelemetryStats, len(a.telemetry))
+		for metric, t := range a.telemetry {
+			period.Telemetry[metric] = entities.TelemetryStats{Mean: t.sum / float64(t.count), Min: t.min, Max: t.max, Readings: t.count}
+		}
+	}
+	if a.chargedMWh > 0 {
+		rte := a.dischargedMWh / a.chargedMWh * 100
+		period.RoundTripEfficiencyPercent = &rte
+	}
+	return period
+}
+
+// newPlantKPIReport arma el reporte de la planta; withPeriods=false deja solo el total
+func newPlantKPIReport(plant *entities.EnergyPlants, query entities.KPIQuery, periods []*kpiAccumulator, withPeriods bool) *entities.PlantKPIReport {
+	report := &entities.PlantKPIReport{
+		PlantSourceId:      plant.ID,
+		PlantName:          plant.PlantName,
+		PlantType:          plant.PlantType,
+		CapacityMW:         plant.CapacityMW,
+		From:               query.From,
+		To:                 query.To,
+		Granularity:        query.Granularity,
+		ExcludeMaintenance: query.ExcludeMaintenance,
+		ExcludeSuspect:     query.ExcludeSuspect,
+	}
+
+	total := &kpiAccumulator{start: query.From, end: query.To}
+	for _, period := range periods {
+		total.merge(period)
+		if withPeriods {
+			report.Periods = append(report.Periods, period.kpiPeriod())
+		}
+	}
+	report.Total = total.kpiPeriod()
+	return report
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+func TestKPIServiceValidateRawRetention(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		from      time.Time
+		wantErr   bool
+	}{
+		{name: "without purge any range is allowed", retention: 0, from: now.AddDate(0, 0, -300), wantErr: false},
+		{name: "inside the retention", retention: 720 * time.Hour, from: now.AddDate(0, 0, -29), wantErr: false},
+		{name: "before the retention", retention: 720 * time.Hour, from: now.AddDate(0, 0, -31), wantErr: true},
+		{name: "earliest from is accepted", retention: 720 * time.Hour, from: rawRetentionStart(720 * time.Hour), wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewKPIService(nil, nil, nil, discardLogger(), KPIServiceConfig{RawRetention: tt.retention})
+			err := service.validate(entities.KPIQuery{From: tt.from, To: now, Granularity: entities.GranularityDay})
+			if tt.wantErr != errors.Is(err, domainerrors.ErrInvalidInput) || (!tt.wantErr && err != nil) {
+				t.Fatalf("validate(from %s) error = %v, want error %t", tt.from.Format(time.RFC3339), err, tt.wantErr)
+			}
+		})
+	}
+
+	if earliest := NewKPIService(nil, nil, nil, discardLogger(), KPIServiceConfig{}).EarliestFrom(); !earliest.IsZero() {
+		t.Errorf("EarliestFrom() without retention = %v, want zero", earliest)
+	}
+}
+
+func TestKPIEnergyMatchesDailyEnergy(t *testing.T) {
+	plantID := uuid.New()
+	at := func(d, hour, minute int) time.Time { return time.Date(2026, 10, d, hour, minute, 0, 0, time.UTC) }
+	suspect := []string{entities.QualityFlag(entities.QualityCheckCapacity, entities.MetricPowerGenerated)}
+	readings := []entities.PlantReading{
+		{Timestamp: at(18, 23, 0), Metrics: map[string]float64{entities.MetricPowerGenerated: 0}},
+		{Timestamp: at(18, 23, 10), Metrics: map[string]float64{entities.MetricPowerGenerated: 500}, QualityFlags: suspect},
+		{Timestamp: at(18, 23, 15), Metrics: map[string]float64{entities.MetricPowerGenerated: 12}},
+		{Timestamp: at(19, 0, 15), Metrics: map[string]float64{entities.MetricPowerGenerated: 12}}, // Hueco de 1 h: sin datos
+		{Timestamp: at(19, 0, 25), Metrics: map[string]float64{entities.MetricPowerGenerated: 6}},
+		{Timestamp: at(19, 0, 35), Metrics: map[string]float64{entities.MetricPowerGenerated: 6}},
+	}
+	for i := range readings {
+		readings[i].PlantSourceId = plantID
+	}
+	maxGap := 15 * time.Minute
+
+	days := make(map[time.Time]*entities.EnergyDailyEntity)
+	energyIntervals(readings, entities.MetricPowerGenerated, maxGap, func(a, b time.Time, va, vb float64) {
+		addEnergy(days, plantID, entities.MetricPowerGenerated, a, b, va, vb, maxGap, time.Time{}, time.Time{})
+	})
+
+	query := entities.KPIQuery{From: at(18, 0, 0), To: at(20, 0, 0), Granularity: entities.GranularityDay}
+	periods := newKPIAccumulators(query)
+	service := NewKPIService(nil, nil, nil, discardLogger(), KPIServiceConfig{MaxGap: maxGap})
+	service.integrate(periods, query, nil, readings, entities.MetricPowerGenerated, func(acc *kpiAccumulator, mwh float64, d time.Duration) {
+		acc.generatedMWh += mwh
+		acc.covered += d
+	})
+
+	want := []energyTotals{
+		{generated: 1.5, covered: 0.25},                  // 0 → 12 MW en 15 min, sin la lectura suspect
+		{generated: 1.5 + 1, covered: 10.0/60 + 10.0/60}, // 12 → 6 y 6 → 6 MW en 10 min cada uno
+	}
+	for i, acc := range periods {
+		day := days[acc.start]
+		if day == nil {
+			t.Fatalf("missing daily energy for %s", acc.start.Format(time.DateOnly))
+		}
+		if !closeTo(acc.generatedMWh, day.GeneratedMWh) || !closeTo(acc.covered.Hours(), day.CoveredHours) {
+			t.Errorf("%s KPI = %.4f MWh, %.4f h; daily = %.4f MWh, %.4f h",
+				acc.start.Format(time.DateOnly), acc.generatedMWh, acc.covered.Hours(), day.GeneratedMWh, day.CoveredHours)
+		}
+		if !closeTo(acc.generatedMWh, want[i].generated) || !closeTo(acc.covered.Hours(), want[i].covered) {
+			t.Errorf("%s KPI = %.4f MWh, %.4f h; want %.4f, %.4f",
+				acc.start.Format(time.DateOnly), acc.generatedMWh, acc.covered.Hours(), want[i].generated, want[i].covered)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// Resultado de una lectura que llega después de otra más nueva de la misma planta (label "outcome")
+const (
+	LateReadingRecompute = "recompute" // Dentro de la demora permitida: se recalculan sus días
+	LateReadingTooLate   = "too_late"  // Fuera de la demora permitida: se guarda pero los agregados no cambian
+)
+
+// LateDataConfig configuración del manejo de lecturas tardías (variables EVENT_*)
+type LateDataConfig struct {
+	AllowedLateness   time.Duration // EVENT_ALLOWED_LATENESS: demora máxima (ingesta - event_time) que se incorpora a los agregados
+	RecomputeInterval time.Duration // EVENT_LATE_RECOMPUTE_INTERVAL: cada cuánto se recalculan los días pendientes
+	Margin            time.Duration // Intervalo alrededor de la lectura que se recalcula (ENERGY_MAX_GAP)
+}
+
+// LateDataHandler detecta las lecturas que llegan tarde o fuera de orden y recalcula los agregados afectados
+//
+// PROPÓSITO:
+// Los agregados incrementales (energy_daily) solo integran lecturas posteriores a la última de la
+// planta. Una lectura que llega tarde queda guardada en su event_time pero no se integra; este
+// procesador marca sus días y los recalcula desde los eventos guardados.
+//
+// FUNCIONAMIENTO:
+// 1. Guarda en memoria la marca de agua de cada planta (event_time más nuevo visto); la primera
+// lectura de una planta la busca en los eventos guardados
+// 2. Una lectura good anterior a la marca de agua es tardía. Si su demora (ingesta - event_time)
+// es como mucho AllowedLateness, sus días UTC (± Margin) quedan pendientes; si no, se guarda
+// igual pero no dispara recálculo (se puede recalcular a mano vía API)
+// 3. Cada RecomputeInterval los días pendientes de cada planta se recalculan con cada
+// AggregateRecomputer registrado; así una ráfaga de lecturas tardías recalcula una sola vez
+// 4. Si un recálculo falla los días vuelven a quedar pendientes para el próximo ciclo
+type LateDataHandler struct {
+	eventRepository output.EventRepositoryInterface
+	metrics         output.MetricsRecorderInterface
+	logger          *slog.Logger
+	cfg             LateDataConfig
+	recomputers     []input.AggregateRecomputer
+
+	mu         sync.Mutex
+	watermarks map[uuid.UUID]time.Time
+	pending    map[uuid.UUID]map[time.Time]struct{} // Días (medianoche UTC) por recalcular
+
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+var _ input.ReadingProcessor = &LateDataHandler{}
+
+// NewLateDataHandler crea el handler; el recálculo periódico se inicia con Start
+func NewLateDataHandler(
+	eventRepository output.EventRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg LateDataConfig,
+) *LateDataHandler {
+	if cfg.RecomputeInterval <= 0 {
+		cfg.RecomputeInterval = time.Minute
+	}
+	return &LateDataHandler{
+		eventRepository: eventRepository,
+		metrics:         metrics,
+		logger:          logger,
+		cfg:             cfg,
+		watermarks:      make(map[uuid.UUID]time.Time),
+		pending:         make(map[uuid.UUID]map[time.Time]struct{}),
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// RegisterRecomputer agrega un agregado que se recalcula con las lecturas tardías
+func (h *LateDataHandler) RegisterRecomputer(recomputer input.AggregateRecomputer) {
+	h.recomputers = append(h.recomputers, recomputer)
+}
+
+// ProcessReading actualiza la marca de agua de la planta o marca los días de una lectura tardía
+// Las lecturas suspect no se tienen en cuenta: los agregados no las usan
+func (h *LateDataHandler) ProcessReading(ctx context.Context, reading entities.PlantReading) error {
+	if reading.Suspect() {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	watermark, ok := h.watermarks[reading.PlantSourceId]
+	if !ok {
+		var err error
+		if watermark, err = h.loadWatermark(ctx, reading); err != nil {
+			return fmt.Errorf("error loading watermark of plant %s: %w", reading.PlantSourceId, err)
+		}
+	}
+	if !reading.Timestamp.Before(watermark) {
+		h.watermarks[reading.PlantSourceId] = reading.Timestamp
+		return nil
+	}
+	h.watermarks[reading.PlantSourceId] = watermark
+
+	delay := reading.ReceivedAt.Sub(reading.Timestamp)
+	if h.cfg.AllowedLateness > 0 && delay > h.cfg.AllowedLateness {
+		h.metrics.LateReading(LateReadingTooLate)
+		h.logger.WarnContext(ctx, "Reading exceeds allowed lateness, aggregates not recomputed",
+			slog.String("plant_source_id", reading.PlantSourceId.String()),
+			slog.Time("event_time", reading.Timestamp),
+			slog.Duration("delay", delay),
+			slog.Duration("allowed_lateness", h.cfg.AllowedLateness))
+		return nil
+	}
+
+	h.metrics.LateReading(LateReadingRecompute)
+	days, ok := h.pending[reading.PlantSourceId]
+	if !ok {
+		days = make(map[time.Time]struct{})
+		h.pending[reading.PlantSourceId] = days
+	}
+	first := entities.PeriodStart(reading.Timestamp.Add(-h.cfg.Margin), entities.GranularityDay)
+	last := entities.PeriodStart(reading.Timestamp.Add(h.cfg.Margin), entities.GranularityDay)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		days[day] = struct{}{}
+	}
+	h.logger.DebugContext(ctx, "Late reading scheduled for recompute",
+		slog.String("plant_source_id", reading.PlantSourceId.String()),
+		slog.Time("event_time", reading.Timestamp),
+		slog.Time("watermark", watermark),
+		slog.Duration("delay", delay))
+	return nil
+}
+
+// Start recalcula los días pendientes cada RecomputeInterval en un goroutine
+func (h *LateDataHandler) Start(ctx context.Context) {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+
+		ticker := time.NewTicker(h.cfg.RecomputeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				h.RecomputePending(ctx)
+			case <-h.stopChan:
+				h.RecomputePending(ctx)
+				return
+			}
+		}
+	}()
+	h.logger.InfoContext(ctx, "Late data recompute started",
+		slog.Duration("interval", h.cfg.RecomputeInterval),
+		slog.Duration("allowed_lateness", h.cfg.AllowedLateness))
+}
+
+// Stop detiene el recálculo periódico después de procesar los días pendientes
+func (h *LateDataHandler) Stop() {
+	close(h.stopChan)
+	h.wg.Wait()
+}
+
+// RecomputePending recalcula los días pendientes de cada planta
+// Cada planta se recalcula en un solo rango, desde su primer día pendiente hasta el último
+func (h *LateDataHandler) RecomputePending(ctx context.Context) {
+	h.mu.Lock()
+	pending := h.pending
+	h.pending = make(map[uuid.UUID]map[time.Time]struct{})
+	h.mu.Unlock()
+
+	for plantID, days := range pending {
+		sorted := make([]time.Time, 0, len(days))
+		for day := range days {
+			sorted = append(sorted, day)
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+		from, to := sorted[0], sorted[len(sorted)-1].AddDate(0, 0, 1)
+
+		for _, recomputer := range h.recomputers {
+			if err := recomputer.RecomputeRange(ctx, plantID, from, to); err != nil {
+				if errors.Is(err, domainerrors.ErrNotFound) {
+					continue // La planta se borró: no hay nada que recalcular
+				}
+				h.logger.ErrorContext(ctx, "Error recomputing aggregates for late readings",
+					slog.String("plant_source_id", plantID.String()),
+					slog.String("recomputer", fmt.Sprintf("%T", recomputer)),
+					slog.Time("from", from), slog.Time("to", to),
+					slog.Any("error", err))
+				h.requeue(plantID, sorted)
+			}
+		}
+	}
+}
+
+// requeue vuelve a marcar los días de una planta cuyo recálculo falló
+func (h *LateDataHandler) requeue(plantID uuid.UUID, days []time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pending, ok := h.pending[plantID]
+	if !ok {
+		pending = make(map[time.Time]struct{})
+		h.pending[plantID] = pending
+	}
+	for _, day := range days {
+		pending[day] = struct{}{}
+	}
+}
+
+// loadWatermark busca el event_time más nuevo guardado de la planta a partir del de la lectura
+// La lectura ya está guardada: si es la más nueva la marca de agua es su propio event_time
+func (h *LateDataHandler) loadWatermark(ctx context.Context, reading entities.PlantReading) (time.Time, error) {
+	events, err := h.eventRepository.FindLatestByPlants(ctx, []uuid.UUID{reading.PlantSourceId}, reading.Timestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	watermark := reading.Timestamp
+	for _, event := range events {
+		if event.EventTime.After(watermark) {
+			watermark = event.EventTime
+		}
+	}
+	return watermark, nil
+}
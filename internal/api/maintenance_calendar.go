@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceCalendar responde si una planta está en una ventana de mantenimiento planificada
+//
+// PROPÓSITO:
+// El intake consulta el calendario con cada lectura para marcar el evento y para que el
+// AlertEngine suprima o baje la severidad de las alertas de la planta.
+//
+// FUNCIONAMIENTO:
+// 1. La primera consulta carga todas las ventanas agrupadas por planta
+// 2. ActiveWindow recorre las ventanas de la planta y devuelve la primera con una ocurrencia activa
+// (si hay varias activas gana la que suprime alertas)
+// 3. Reload se llama al crear, editar o borrar ventanas desde la API REST
+type MaintenanceCalendar struct {
+	repository output.MaintenanceWindowRepositoryInterface
+	logger     *slog.Logger
+
+	mu      sync.RWMutex
+	loaded  bool
+	windows map[uuid.UUID][]*entities.MaintenanceWindowEntity
+}
+
+var _ input.MaintenanceCalendar = &MaintenanceCalendar{}
+
+// NewMaintenanceCalendar crea el calendario; las ventanas se cargan con la primera consulta
+func NewMaintenanceCalendar(repository output.MaintenanceWindowRepositoryInterface, logger *slog.Logger) *MaintenanceCalendar {
+	return &MaintenanceCalendar{
+		repository: repository,
+		logger:     logger,
+		windows:    make(map[uuid.UUID][]*entities.MaintenanceWindowEntity),
+	}
+}
+
+// Reload vuelve a cargar todas las ventanas de mantenimiento
+func (m *MaintenanceCalendar) Reload(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reload(ctx)
+}
+
+func (m *MaintenanceCalendar) reload(ctx context.Context) error {
+	windows, err := m.repository.FindAll(ctx, entities.MaintenanceWindowFilter{})
+	if err != nil {
+		return fmt.Errorf("error loading maintenance windows: %w", err)
+	}
+
+	byPlant := make(map[uuid.UUID][]*entities.MaintenanceWindowEntity)
+	for _, window := range windows {
+		byPlant[window.PlantSourceId] = append(byPlant[window.PlantSourceId], window)
+	}
+	m.windows = byPlant
+	m.loaded = true
+
+	m.logger.InfoContext(ctx, "Maintenance windows loaded", slog.Int("windows", len(windows)))
+	return nil
+}
+
+// ActiveWindow devuelve la ventana activa de la planta en el instante indicado (nil si no hay)
+func (m *MaintenanceCalendar) ActiveWindow(ctx context.Context, plantID uuid.UUID, at time.Time) (*entities.MaintenanceWindowEntity, error) {
+	if err := m.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var active *entities.MaintenanceWindowEntity
+	for _, window := range m.windows[plantID] {
+		if _, ok := window.ActiveAt(at); !ok {
+			continue
+		}
+		if active == nil || window.AlertPolicy == entities.MaintenanceAlertSuppress {
+			active = window
+		}
+	}
+	return active, nil
+}
+
+func (m *MaintenanceCalendar) ensureLoaded(ctx context.Context) error {
+	m.mu.RLock()
+	loaded := m.loaded
+	m.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.loaded {
+		return nil
+	}
+	return m.reload(ctx)
+}
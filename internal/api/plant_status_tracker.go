@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// Resultados de un cambio de estado (label outcome de la métrica)
+const (
+	statusOutcomeApplied    = "applied"
+	statusOutcomeNotAllowed = "not_allowed"
+	statusOutcomeRejected   = "rejected"
+)
+
+// PlantStatusTrackerConfig configuración del seguimiento de estados (variables PLANT_STATUS_*)
+type PlantStatusTrackerConfig struct {
+	StrictTransitions bool // PLANT_STATUS_STRICT_TRANSITIONS: descarta las transiciones no permitidas
+}
+
+// PlantStatusTracker mantiene el estado actual de cada planta y su historial
+//
+// PROPÓSITO:
+// Los eventos traen un status (operational, maintenance, standby, peak_load) pero las plantas
+// no tenían estado actual ni se registraban los cambios.
+//
+// FUNCIONAMIENTO:
+// 1. El estado actual de cada planta se lee de energy_plants con su primera lectura y queda en memoria
+// 2. Si la lectura trae un status distinto, se valida la transición (entities.StatusTransitionAllowed)
+// y se guarda en plant_status_history junto con el nuevo estado de la planta
+// 3. Una transición no permitida se aplica marcada con allowed=false; con StrictTransitions
+// se descarta y la planta conserva su estado
+// 4. Las lecturas con timestamp anterior al último cambio se ignoran (llegaron fuera de orden)
+type PlantStatusTracker struct {
+	plantRepository   output.EnergyPlantRepositoryInterface
+	historyRepository output.PlantStatusHistoryRepositoryInterface
+	metrics           output.MetricsRecorderInterface
+	logger            *slog.Logger
+	cfg               PlantStatusTrackerConfig
+
+	mu     sync.Mutex
+	states map[uuid.UUID]*plantStatusState
+}
+
+// plantStatusState es el último estado conocido de una planta
+type plantStatusState struct {
+	status string
+	since  time.Time
+}
+
+var _ input.ReadingProcessor = &PlantStatusTracker{}
+
+// NewPlantStatusTracker crea el tracker; el estado de cada planta se carga con su primera lectura
+func NewPlantStatusTracker(
+	plantRepository output.EnergyPlantRepositoryInterface,
+	historyRepository output.PlantStatusHistoryRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg PlantStatusTrackerConfig,
+) *PlantStatusTracker {
+	return &PlantStatusTracker{
+		plantRepository:   plantRepository,
+		historyRepository: historyRepository,
+		metrics:           metrics,
+		logger:            logger,
+		cfg:               cfg,
+		states:            make(map[uuid.UUID]*plantStatusState),
+	}
+}
+
+// ProcessReading registra el cambio de estado si la lectura trae un status distinto al actual
+func (t *PlantStatusTracker) ProcessReading(ctx context.Context, reading entities.PlantReading) error {
+	if reading.Status == "" {
+		return nil
+	}
+	if !slices.Contains(entities.PlantStatuses, reading.Status) {
+		t.logger.WarnContext(ctx, "Unknown plant status ignored",
+			slog.String("plant_source_id", reading.PlantSourceId.String()),
+			slog.String("status", reading.Status),
+		)
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, err := t.state(ctx, reading.PlantSourceId)
+	if err != nil {
+		return err
+	}
+	if state.status == reading.Status || reading.Timestamp.Before(state.since) {
+		return nil
+	}
+
+	allowed := entities.StatusTransitionAllowed(state.status, reading.Status)
+	if !allowed && t.cfg.StrictTransitions {
+		t.metrics.PlantStatusTransition(reading.Status, statusOutcomeRejected)
+		t.logger.WarnContext(ctx, "Plant status transition rejected",
+			slog.String("plant_source_id", reading.PlantSourceId.String()),
+			slog.String("from", state.status),
+			slog.String("to", reading.Status),
+		)
+		return nil
+	}
+
+	transition := &entities.PlantStatusHistoryEntity{
+		PlantSourceId: reading.PlantSourceId,
+		FromStatus:    state.status,
+		ToStatus:      reading.Status,
+		Allowed:       allowed,
+		EventID:       reading.EventID,
+		ChangedAt:     reading.Timestamp,
+	}
+	if _, err := t.historyRepository.RecordTransition(ctx, transition); err != nil {
+		return fmt.Errorf("error recording status of plant %s: %w", reading.PlantSourceId, err)
+	}
+	state.status = reading.Status
+	state.since = reading.Timestamp
+
+	outcome := statusOutcomeApplied
+	if !allowed {
+		outcome = statusOutcomeNotAllowed
+		t.logger.WarnContext(ctx, "Plant status transition not allowed",
+			slog.String("plant_source_id", reading.PlantSourceId.String()),
+			slog.String("from", transition.FromStatus),
+			slog.String("to", transition.ToStatus),
+		)
+	}
+	t.metrics.PlantStatusTransition(reading.Status, outcome)
+	return nil
+}
+
+// state devuelve el estado en memoria de la planta, cargándolo de energy_plants la primera vez
+func (t *PlantStatusTracker) state(ctx context.Context, plantID uuid.UUID) (*plantStatusState, error) {
+	if state, ok := t.states[plantID]; ok {
+		return state, nil
+	}
+
+	plant, err := t.plantRepository.FindByID(ctx, plantID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading status of plant %s: %w", plantID, err)
+	}
+	state := &plantStatusState{status: plant.Status}
+	if plant.StatusChangedAt != nil {
+		state.since = *plant.StatusChangedAt
+	}
+	t.states[plantID] = state
+	return state, nil
+}
+
+// TimeInState calcula las horas que la planta pasó en cada estado en [from, to)
+// El estado al inicio es el del último cambio anterior a from; sin cambios previos
+// el tramo hasta el primer estado conocido se cuenta como unknown.
+func (t *PlantStatusTracker) TimeInState(ctx context.Context, plantID uuid.UUID, from, to time.Time) (*entities.PlantTimeInState, error) {
+	if now := time.Now().UTC(); to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", domainerrors.ErrInvalidInput)
+	}
+
+	current := entities.PlantStatusUnknown
+	last, err := t.historyRepository.FindLastBefore(ctx, plantID, from)
+	if err != nil && !errors.Is(err, domainerrors.ErrNotFound) {
+		return nil, err
+	}
+	if last != nil {
+		current = last.ToStatus
+	}
+
+	history, err := t.historyRepository.FindByPlant(ctx, plantID, entities.PlantStatusFilter{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.PlantTimeInState{
+		PlantSourceId: plantID,
+		From:          from,
+		To:            to,
+		Hours:         make(map[string]float64),
+		Transitions:   len(history),
+	}
+	cursor := from
+	for _, transition := range history {
+		if transition.ChangedAt.After(cursor) {
+			result.Hours[current] += transition.ChangedAt.Sub(cursor).Hours()
+		}
+		current = transition.ToStatus
+		cursor = transition.ChangedAt
+	}
+	result.Hours[current] += to.Sub(cursor).Hours()
+	return result, nil
+}
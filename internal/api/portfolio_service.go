@@ -0,0 +1,343 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// PortfolioServiceConfig configuración de los reportes del portfolio (variables PORTFOLIO_*)
+type PortfolioServiceConfig struct {
+	OutputMaxAge time.Duration // PORTFOLIO_OUTPUT_MAX_AGE: antigüedad máxima de la lectura que cuenta como potencia actual
+}
+
+// PortfolioService administra la jerarquía organización → región → sitio → planta y sus agregados
+//
+// PROPÓSITO:
+// La flota se gestiona por dueño, región y sitio. El servicio valida la jerarquía (nivel del
+// padre, nodos sin hijos ni plantas al borrar, plantas solo en sitios) y calcula los
+// agregados de cualquier nodo.
+//
+// FUNCIONAMIENTO DE Report:
+// 1. Carga todos los nodos (son pocos) y recorre en memoria el subárbol del nodo pedido
+// 2. Carga las plantas de todo el subárbol en una sola query
+// 3. Tres queries agregadas para todas esas plantas: último evento good de cada una
+// (DISTINCT ON), energía del rango sumada por planta (energy_daily) y alertas firing
+// contadas por planta y severidad
+// 4. Cada planta suma al total del nodo y al del hijo directo que la contiene; las plantas
+// asignadas directamente al nodo se listan con sus cifras
+//
+// Así el costo no crece con la profundidad del árbol ni con los días del rango.
+type PortfolioService struct {
+	nodeRepository   output.PortfolioNodeRepositoryInterface
+	plantRepository  output.EnergyPlantRepositoryInterface
+	eventRepository  output.EventRepositoryInterface
+	energyRepository output.EnergyDailyRepositoryInterface
+	alertRepository  output.AlertRepositoryInterface
+	logger           *slog.Logger
+	cfg              PortfolioServiceConfig
+}
+
+// NewPortfolioService crea el servicio del portfolio
+func NewPortfolioService(
+	nodeRepository output.PortfolioNodeRepositoryInterface,
+	plantRepository output.EnergyPlantRepositoryInterface,
+	eventRepository output.EventRepositoryInterface,
+	energyRepository output.EnergyDailyRepositoryInterface,
+	alertRepository output.AlertRepositoryInterface,
+	logger *slog.Logger,
+	cfg PortfolioServiceConfig,
+) *PortfolioService {
+	return &PortfolioService{
+		nodeRepository:   nodeRepository,
+		plantRepository:  plantRepository,
+		eventRepository:  eventRepository,
+		energyRepository: energyRepository,
+		alertRepository:  alertRepository,
+		logger:           logger,
+		cfg:              cfg,
+	}
+}
+
+// CreateNode valida el nodo y su padre y lo guarda
+func (s *PortfolioService) CreateNode(ctx context.Context, node *entities.PortfolioNodeEntity) (*entities.PortfolioNodeEntity, error) {
+	if err := s.validateNode(ctx, node); err != nil {
+		return nil, err
+	}
+	return s.nodeRepository.Create(ctx, node)
+}
+
+// UpdateNode cambia nombre, descripción y padre de un nodo (el nivel no se puede cambiar)
+func (s *PortfolioService) UpdateNode(ctx context.Context, id uuid.UUID, changes entities.PortfolioNodeEntity) (*entities.PortfolioNodeEntity, error) {
+	node, err := s.nodeRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes.Level != "" && changes.Level != node.Level {
+		return nil, fmt.Errorf("%w: level cannot be changed", domainerrors.ErrInvalidInput)
+	}
+
+	node.Name = changes.Name
+	node.Description = changes.Description
+	node.ParentID = changes.ParentID
+	if err := s.validateNode(ctx, node); err != nil {
+		return nil, err
+	}
+	return s.nodeRepository.Update(ctx, node)
+}
+
+// DeleteNode borra un nodo sin hijos ni plantas asignadas
+func (s *PortfolioService) DeleteNode(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.nodeRepository.FindByID(ctx, id); err != nil {
+		return err
+	}
+	children, err := s.nodeRepository.FindAll(ctx, entities.PortfolioNodeFilter{ParentID: &id})
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("%w: node has %d child nodes", domainerrors.ErrConflict, len(children))
+	}
+	plants, err := s.plantRepository.FindByPortfolioNodes(ctx, []uuid.UUID{id})
+	if err != nil {
+		return err
+	}
+	if len(plants) > 0 {
+		return fmt.Errorf("%w: node has %d plants assigned", domainerrors.ErrConflict, len(plants))
+	}
+	return s.nodeRepository.Delete(ctx, id)
+}
+
+// AssignPlant asigna la planta a un sitio (nodeID nil la deja sin asignar) y devuelve la planta actualizada
+func (s *PortfolioService) AssignPlant(ctx context.Context, plantID uuid.UUID, nodeID *uuid.UUID) (*entities.EnergyPlants, error) {
+	if nodeID != nil {
+		node, err := s.nodeRepository.FindByID(ctx, *nodeID)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				return nil, fmt.Errorf("%w: portfolio node %s does not exist", domainerrors.ErrInvalidInput, *nodeID)
+			}
+			return nil, err
+		}
+		if node.Level != entities.PortfolioLevelSite {
+			return nil, fmt.Errorf("%w: plants can only be assigned to a site, %s is a %s", domainerrors.ErrInvalidInput, node.ID, node.Level)
+		}
+	}
+	if err := s.plantRepository.UpdatePortfolioNode(ctx, plantID, nodeID); err != nil {
+		return nil, err
+	}
+	return s.plantRepository.FindByID(ctx, plantID)
+}
+
+// validateNode verifica los campos del nodo y que el padre exista y sea del nivel inmediato superior
+func (s *PortfolioService) validateNode(ctx context.Context, node *entities.PortfolioNodeEntity) error {
+	if err := node.Validate(); err != nil {
+		return err
+	}
+	if node.ParentID == nil {
+		return nil
+	}
+	parent, err := s.nodeRepository.FindByID(ctx, *node.ParentID)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrNotFound) {
+			return fmt.Errorf("%w: parent %s does not exist", domainerrors.ErrInvalidInput, *node.ParentID)
+		}
+		return err
+	}
+	if expected := entities.PortfolioParentLevel(node.Level); parent.Level != expected {
+		return fmt.Errorf("%w: the parent of a %s must be a %s, got a %s", domainerrors.ErrInvalidInput, node.Level, expected, parent.Level)
+	}
+	return nil
+}
+
+// Report calcula los agregados del subárbol del nodo, de cada hijo directo y de las plantas del nodo
+// La energía cubre los días [from, to) (alineados a días UTC); la potencia actual y las alertas son las de ahora
+func (s *PortfolioService) Report(ctx context.Context, nodeID uuid.UUID, from, to time.Time) (*entities.PortfolioReport, error) {
+	from, to = alignDays(from, to)
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", domainerrors.ErrInvalidInput)
+	}
+
+	nodes, err := s.nodeRepository.FindAll(ctx, entities.PortfolioNodeFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("error loading portfolio nodes: %w", err)
+	}
+	byID := make(map[uuid.UUID]*entities.PortfolioNodeEntity, len(nodes))
+	children := make(map[uuid.UUID][]*entities.PortfolioNodeEntity)
+	for _, node := range nodes {
+		byID[node.ID] = node
+		if node.ParentID != nil {
+			children[*node.ParentID] = append(children[*node.ParentID], node)
+		}
+	}
+	root, ok := byID[nodeID]
+	if !ok {
+		return nil, domainerrors.ErrNotFound
+	}
+
+	report := &entities.PortfolioReport{
+		PortfolioNodeSummary: newPortfolioNodeSummary(root),
+		Path:                 portfolioPath(byID, root),
+		From:                 from,
+		To:                   to,
+		Children:             make([]entities.PortfolioNodeSummary, 0, len(children[root.ID])),
+		Plants:               make([]entities.PortfolioPlantSummary, 0),
+	}
+
+	// branch indica a qué hijo directo del nodo pertenece cada nodo del subárbol
+	branch := map[uuid.UUID]int{root.ID: -1}
+	subtree := []uuid.UUID{root.ID}
+	for i, child := range children[root.ID] {
+		report.Children = append(report.Children, newPortfolioNodeSummary(child))
+		queue := []*entities.PortfolioNodeEntity{child}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			branch[node.ID] = i
+			subtree = append(subtree, node.ID)
+			queue = append(queue, children[node.ID]...)
+		}
+	}
+
+	plants, err := s.plantRepository.FindByPortfolioNodes(ctx, subtree)
+	if err != nil {
+		return nil, fmt.Errorf("error loading plants of node %s: %w", root.ID, err)
+	}
+	summaries, err := s.plantSummaries(ctx, plants, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, plant := range plants {
+		summary := summaries[i]
+		addPortfolioPlant(&report.PortfolioTotals, summary)
+		if b := branch[*plant.PortfolioNodeID]; b >= 0 {
+			addPortfolioPlant(&report.Children[b].PortfolioTotals, summary)
+		} else {
+			report.Plants = append(report.Plants, summary.PortfolioPlantSummary)
+		}
+	}
+	return report, nil
+}
+
+// portfolioPlant cifras de una planta con el detalle de alertas por severidad
+type portfolioPlant struct {
+	entities.PortfolioPlantSummary
+	alerts map[string]int
+}
+
+// plantSummaries calcula potencia actual, energía del rango y alertas firing de las plantas (en el mismo orden)
+func (s *PortfolioService) plantSummaries(ctx context.Context, plants []*entities.EnergyPlants, from, to time.Time) ([]portfolioPlant, error) {
+	ids := make([]uuid.UUID, 0, len(plants))
+	types := make(map[uuid.UUID]string, len(plants))
+	for _, plant := range plants {
+		ids = append(ids, plant.ID)
+		types[plant.ID] = plant.PlantType
+	}
+
+	events, err := s.eventRepository.FindLatestByPlants(ctx, ids, time.Now().Add(-s.cfg.OutputMaxAge))
+	if err != nil {
+		return nil, fmt.Errorf("error loading latest readings: %w", err)
+	}
+	outputs := make(map[uuid.UUID]entities.PlantReading, len(events))
+	for _, event := range events {
+		reading, err := entities.PlantReadingFromEvent(event, types[event.PlantSourceId])
+		if err != nil {
+			s.logger.DebugContext(ctx, "Skipping event with invalid data", slog.String("event_id", event.ID.String()), slog.Any("error", err))
+			continue
+		}
+		outputs[event.PlantSourceId] = reading
+	}
+
+	energy, err := s.energyRepository.SumByPlants(ctx, ids, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("error loading energy: %w", err)
+	}
+	energyByPlant := make(map[uuid.UUID]entities.PlantEnergyTotal, len(energy))
+	for _, total := range energy {
+		energyByPlant[total.PlantSourceId] = total
+	}
+
+	counts, err := s.alertRepository.CountFiringByPlants(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error loading firing alerts: %w", err)
+	}
+	alerts := make(map[uuid.UUID]map[string]int)
+	for _, count := range counts {
+		if alerts[count.PlantSourceId] == nil {
+			alerts[count.PlantSourceId] = make(map[string]int)
+		}
+		alerts[count.PlantSourceId][count.Severity] += count.Count
+	}
+
+	summaries := make([]portfolioPlant, 0, len(plants))
+	for _, plant := range plants {
+		summary := portfolioPlant{
+			PortfolioPlantSummary: entities.PortfolioPlantSummary{
+				PlantSourceId: plant.ID,
+				PlantName:     plant.PlantName,
+				PlantType:     plant.PlantType,
+				CapacityMW:    plant.CapacityMW,
+				GeneratedMWh:  energyByPlant[plant.ID].GeneratedMWh,
+				ConsumedMWh:   energyByPlant[plant.ID].ConsumedMWh,
+			},
+			alerts: alerts[plant.ID],
+		}
+		if reading, ok := outputs[plant.ID]; ok {
+			if value, ok := reading.Metric(entities.MetricPowerGenerated); ok {
+				at := reading.Timestamp
+				summary.CurrentOutputMW = &value
+				summary.OutputAt = &at
+			}
+		}
+		for _, count := range summary.alerts {
+			summary.FiringAlerts += count
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func newPortfolioNodeSummary(node *entities.PortfolioNodeEntity) entities.PortfolioNodeSummary {
+	return entities.PortfolioNodeSummary{
+		PortfolioNodeRef: entities.PortfolioNodeRef{ID: node.ID, Level: node.Level, Name: node.Name},
+		PortfolioTotals:  entities.PortfolioTotals{AlertsBySeverity: make(map[string]int)},
+	}
+}
+
+// addPortfolioPlant suma las cifras de la planta a los totales
+func addPortfolioPlant(totals *entities.PortfolioTotals, plant portfolioPlant) {
+	totals.PlantCount++
+	totals.CapacityMW += plant.CapacityMW
+	if plant.CurrentOutputMW != nil {
+		totals.CurrentOutputMW += *plant.CurrentOutputMW
+		totals.ReportingPlants++
+	}
+	totals.GeneratedMWh += plant.GeneratedMWh
+	totals.ConsumedMWh += plant.ConsumedMWh
+	totals.NetMWh = totals.GeneratedMWh - totals.ConsumedMWh
+	totals.FiringAlerts += plant.FiringAlerts
+	for severity, count := range plant.alerts {
+		totals.AlertsBySeverity[severity] += count
+	}
+}
+
+// portfolioPath devuelve el camino desde la organización hasta el nodo (incluido)
+func portfolioPath(byID map[uuid.UUID]*entities.PortfolioNodeEntity, node *entities.PortfolioNodeEntity) []entities.PortfolioNodeRef {
+	var path []entities.PortfolioNodeRef
+	for current := node; current != nil && len(path) < len(entities.PortfolioLevels); {
+		path = append([]entities.PortfolioNodeRef{{ID: current.ID, Level: current.Level, Name: current.Name}}, path...)
+		if current.ParentID == nil {
+			break
+		}
+		current = byID[*current.ParentID]
+	}
+	return path
+}
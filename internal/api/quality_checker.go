@@ -0,0 +1,224 @@
+package api
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// QualityRange rango plausible de una métrica (nil = sin límite de ese lado)
+type QualityRange struct {
+	Min *float64
+	Max *float64
+}
+
+// QualityCheckerConfig configuración de los chequeos de plausibilidad (variables QUALITY_*)
+type QualityCheckerConfig struct {
+	Enabled           bool                    // QUALITY_ENABLED
+	Ranges            map[string]QualityRange // QUALITY_RANGES: métrica:min:max
+	CapacityTolerance float64                 // QUALITY_CAPACITY_TOLERANCE: fracción tolerada sobre CapacityMW (0.05 = 5%)
+	MaxRates          map[string]float64      // QUALITY_MAX_RATES: métrica:cambio máximo por minuto
+	MaxRampPercent    float64                 // QUALITY_MAX_RAMP_PERCENT: cambio máximo de power_generated_mw por minuto en % de CapacityMW
+	StuckReadings     int                     // QUALITY_STUCK_READINGS: lecturas seguidas con el mismo valor (0 = sin chequeo)
+	StuckMetrics      []string                // QUALITY_STUCK_METRICS
+}
+
+// QualityChecker marca las lecturas implausibles antes de que el intake las guarde
+//
+// PROPÓSITO:
+// Llegan lecturas por encima de la capacidad de la planta, potencias negativas, eficiencias
+// mayores a 100% o temperaturas imposibles. En lugar de aceptarlas sin más se guardan con
+// quality=suspect y los chequeos que fallaron, y las consultas pueden excluirlas.
+//
+// FUNCIONAMIENTO:
+// 0. Tipo: una métrica propia de otro tipo de planta se marca unexpected_metric y no se chequea más
+// 1. Rango: cada métrica debe caer dentro del rango de QUALITY_RANGES o, si no tiene, del
+// definido para la métrica en el catálogo de tipos de planta
+// 2. Capacidad: power_generated_mw no puede superar CapacityMW × (1 + CapacityTolerance)
+// 3. Cambio: el cambio por minuto respecto de la lectura anterior de la planta no puede superar
+// MaxRates (power_generated_mw usa MaxRampPercent de la capacidad si no tiene límite propio)
+// 4. Valor trabado: StuckReadings lecturas seguidas con el mismo valor distinto de 0
+// (un 0 repetido es normal, por ejemplo un parque solar de noche)
+// 5. La última lectura de cada planta se guarda en memoria (después de un reinicio los
+// chequeos 3 y 4 empiezan de nuevo); las lecturas fuera de orden no la actualizan
+//
+// Check no modifica el estado: el intake llama Commit solo después de guardar el evento,
+// así una lectura que no se pudo guardar (y Kafka reintenta) no cuenta como la anterior.
+type QualityChecker struct {
+	metrics output.MetricsRecorderInterface
+	cfg     QualityCheckerConfig
+
+	mu    sync.Mutex
+	state map[uuid.UUID]map[string]*qualityState
+}
+
+// qualityState última lectura de una métrica de una planta
+type qualityState struct {
+	at      time.Time
+	value   float64
+	repeats int // Lecturas seguidas con este valor
+}
+
+var _ input.ReadingQualityChecker = &QualityChecker{}
+
+// NewQualityChecker crea el checker con la configuración de los chequeos
+func NewQualityChecker(metrics output.MetricsRecorderInterface, cfg QualityCheckerConfig) *QualityChecker {
+	return &QualityChecker{
+		metrics: metrics,
+		cfg:     cfg,
+		state:   make(map[uuid.UUID]map[string]*qualityState),
+	}
+}
+
+// Check devuelve los chequeos que falló la lectura como flags "<chequeo>:<métrica>" (vacío = good)
+//
+// CAMBIO: Ya no actualiza la última lectura de la planta (ver Commit)
+// RAZÓN: El estado se actualizaba antes de guardar el evento; si el guardado fallaba, el reintento
+// se comparaba contra sí mismo (fuera de orden o valor trabado)
+func (q *QualityChecker) Check(ctx context.Context, plant *entities.EnergyPlants, reading entities.PlantReading) []string {
+	if !q.cfg.Enabled {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	states := q.state[plant.ID]
+
+	names := make([]string, 0, len(reading.Metrics))
+	for metric := range reading.Metrics {
+		names = append(names, metric)
+	}
+	sort.Strings(names)
+
+	var flags []string
+	flag := func(check, metric string) {
+		flags = append(flags, entities.QualityFlag(check, metric))
+		q.metrics.ReadingQualityFlag(check, metric)
+	}
+
+	for _, metric := range names {
+		value := reading.Metrics[metric]
+
+		if check := q.implausible(plant, metric, value); check != "" {
+			flag(check, metric)
+			continue
+		}
+
+		prev := states[metric]
+		if prev == nil || !reading.Timestamp.After(prev.at) {
+			continue // Primera lectura o fuera de orden: no hay con qué comparar
+		}
+		if limit := q.maxRate(metric, plant); limit > 0 {
+			if elapsed := reading.Timestamp.Sub(prev.at); elapsed >= time.Second && math.Abs(value-prev.value)/elapsed.Minutes() > limit {
+				flag(entities.QualityCheckRate, metric)
+			}
+		}
+		if q.cfg.StuckReadings > 1 && value != 0 && prev.next(value) >= q.cfg.StuckReadings && q.checksStuck(metric) {
+			flag(entities.QualityCheckStuck, metric)
+		}
+	}
+	return flags
+}
+
+// Commit guarda la lectura como la anterior de la planta para los chequeos de cambio y valor trabado
+// Se llama después de guardar el evento; los valores implausibles y las lecturas fuera de orden no la actualizan
+func (q *QualityChecker) Commit(ctx context.Context, plant *entities.EnergyPlants, reading entities.PlantReading) {
+	if !q.cfg.Enabled {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	states, ok := q.state[plant.ID]
+	if !ok {
+		states = make(map[string]*qualityState)
+		q.state[plant.ID] = states
+	}
+
+	for metric, value := range reading.Metrics {
+		if q.implausible(plant, metric, value) != "" {
+			continue // Un valor imposible no sirve de referencia para la próxima lectura
+		}
+		prev := states[metric]
+		if prev != nil && !reading.Timestamp.After(prev.at) {
+			continue
+		}
+		repeats := 1
+		if prev != nil {
+			repeats = prev.next(value)
+		}
+		states[metric] = &qualityState{at: reading.Timestamp, value: value, repeats: repeats}
+	}
+}
+
+// implausible devuelve el chequeo de tipo, rango o capacidad que falla el valor ("" = plausible)
+func (q *QualityChecker) implausible(plant *entities.EnergyPlants, metric string, value float64) string {
+	if !entities.ExpectedMetric(plant.PlantType, metric) {
+		return entities.QualityCheckUnexpected
+	}
+	if r, ok := q.metricRange(metric); ok && !r.contains(value) {
+		return entities.QualityCheckRange
+	}
+	if metric == entities.MetricPowerGenerated && plant.CapacityMW > 0 && value > plant.CapacityMW*(1+q.cfg.CapacityTolerance) {
+		return entities.QualityCheckCapacity
+	}
+	return ""
+}
+
+// metricRange devuelve el rango de QUALITY_RANGES o, si no hay, el de la definición de la métrica
+func (q *QualityChecker) metricRange(metric string) (QualityRange, bool) {
+	if r, ok := q.cfg.Ranges[metric]; ok {
+		return r, true
+	}
+	if definition, ok := entities.FindMetricDefinition(metric); ok && (definition.Min != nil || definition.Max != nil) {
+		return QualityRange{Min: definition.Min, Max: definition.Max}, true
+	}
+	return QualityRange{}, false
+}
+
+// maxRate devuelve el cambio máximo por minuto de la métrica (0 = sin chequeo)
+func (q *QualityChecker) maxRate(metric string, plant *entities.EnergyPlants) float64 {
+	if rate, ok := q.cfg.MaxRates[metric]; ok {
+		return rate
+	}
+	if metric == entities.MetricPowerGenerated && q.cfg.MaxRampPercent > 0 && plant.CapacityMW > 0 {
+		return plant.CapacityMW * q.cfg.MaxRampPercent / 100
+	}
+	return 0
+}
+
+func (q *QualityChecker) checksStuck(metric string) bool {
+	for _, m := range q.cfg.StuckMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// next devuelve las lecturas seguidas con value si la siguiente lectura trae ese valor
+func (s *qualityState) next(value float64) int {
+	if value == s.value {
+		return s.repeats + 1
+	}
+	return 1
+}
+
+func (r QualityRange) contains(value float64) bool {
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+	if r.Max != nil && value > *r.Max {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// qualityStep es una lectura de la secuencia; skipCommit simula un evento que no se pudo guardar
+type qualityStep struct {
+	second     int
+	metrics    map[string]float64
+	skipCommit bool
+	want       []string
+}
+
+func TestQualityCheckerSequences(t *testing.T) {
+	maxEfficiency := 95.0
+	base := QualityCheckerConfig{
+		Enabled:           true,
+		CapacityTolerance: 0.05,
+		MaxRampPercent:    10, // 10 MW/min en una planta de 100 MW
+		StuckReadings:     3,
+		StuckMetrics:      []string{entities.MetricTemperature},
+	}
+	power := func(v float64) map[string]float64 { return map[string]float64{entities.MetricPowerGenerated: v} }
+	temperature := func(v float64) map[string]float64 { return map[string]float64{entities.MetricTemperature: v} }
+	flag := entities.QualityFlag
+
+	tests := []struct {
+		name  string
+		cfg   func(*QualityCheckerConfig)
+		steps []qualityStep
+	}{
+		{
+			name:  "disabled checks nothing",
+			cfg:   func(cfg *QualityCheckerConfig) { cfg.Enabled = false },
+			steps: []qualityStep{{second: 0, metrics: power(-5)}, {second: 60, metrics: power(500)}},
+		},
+		{
+			name: "metric of another plant type",
+			steps: []qualityStep{{second: 0, metrics: map[string]float64{entities.MetricWindSpeed: 8, entities.MetricIrradiance: 600},
+				want: []string{flag(entities.QualityCheckUnexpected, entities.MetricWindSpeed)}}},
+		},
+		{
+			name: "catalog and configured ranges",
+			cfg: func(cfg *QualityCheckerConfig) {
+				cfg.Ranges = map[string]QualityRange{entities.MetricEfficiency: {Max: &maxEfficiency}}
+			},
+			steps: []qualityStep{
+				{second: 0, metrics: power(-1), want: []string{flag(entities.QualityCheckRange, entities.MetricPowerGenerated)}},
+				{second: 60, metrics: map[string]float64{entities.MetricEfficiency: 96}, want: []string{flag(entities.QualityCheckRange, entities.MetricEfficiency)}},
+				{second: 120, metrics: temperature(151), want: []string{flag(entities.QualityCheckRange, entities.MetricTemperature)}},
+				{second: 180, metrics: map[string]float64{entities.MetricEfficiency: 95}},
+			},
+		},
+		{
+			name: "capacity with tolerance",
+			steps: []qualityStep{
+				{second: 0, metrics: power(105)},
+				{second: 3600, metrics: power(105.1), want: []string{flag(entities.QualityCheckCapacity, entities.MetricPowerGenerated)}},
+			},
+		},
+		{
+			name: "ramp rate from the capacity",
+			steps: []qualityStep{
+				{second: 0, metrics: power(10)},
+				{second: 60, metrics: power(20)},
+				{second: 120, metrics: power(31), want: []string{flag(entities.QualityCheckRate, entities.MetricPowerGenerated)}},
+				{second: 720, metrics: power(90)}, // 59 MW en 10 minutos
+			},
+		},
+		{
+			name: "implausible value is not the previous reading",
+			steps: []qualityStep{
+				{second: 0, metrics: power(10)},
+				{second: 60, metrics: power(400), want: []string{flag(entities.QualityCheckCapacity, entities.MetricPowerGenerated)}},
+				{second: 120, metrics: power(15)}, // Se compara con 10 MW, no con 400
+			},
+		},
+		{
+			name: "stuck values but repeated zeros are normal",
+			steps: []qualityStep{
+				{second: 0, metrics: temperature(40)},
+				{second: 60, metrics: temperature(40)},
+				{second: 120, metrics: temperature(40), want: []string{flag(entities.QualityCheckStuck, entities.MetricTemperature)}},
+				{second: 180, metrics: temperature(41)},
+				{second: 240, metrics: temperature(0)},
+				{second: 300, metrics: temperature(0)},
+				{second: 360, metrics: temperature(0)},
+			},
+		},
+		{
+			name: "out of order reading is not compared",
+			steps: []qualityStep{
+				{second: 120, metrics: power(50)},
+				{second: 60, metrics: power(0)},
+				{second: 180, metrics: power(55)}, // Se compara con 50 MW de 120s
+			},
+		},
+		{
+			name: "uncommitted reading does not change the state",
+			steps: []qualityStep{
+				{second: 0, metrics: temperature(40)},
+				{second: 60, metrics: temperature(40), skipCommit: true},
+				{second: 60, metrics: temperature(40)}, // Reintento: la repetición cuenta una vez
+				{second: 120, metrics: temperature(40), want: []string{flag(entities.QualityCheckStuck, entities.MetricTemperature)}},
+			},
+		},
+		{
+			name: "uncommitted reading is not the previous reading",
+			steps: []qualityStep{
+				{second: 0, metrics: power(10)},
+				{second: 60, metrics: power(15), skipCommit: true},
+				{second: 60, metrics: power(15)}, // Reintento del mismo evento: no queda fuera de orden
+				{second: 120, metrics: power(40), want: []string{flag(entities.QualityCheckRate, entities.MetricPowerGenerated)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			checker := NewQualityChecker(nopMetrics{}, cfg)
+			plant := &entities.EnergyPlants{ID: uuid.New(), PlantType: entities.PlantTypeSolar, CapacityMW: 100}
+			start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+			for i, step := range tt.steps {
+				reading := entities.PlantReading{
+					PlantSourceId: plant.ID,
+					PlantType:     plant.PlantType,
+					Metrics:       step.metrics,
+					Timestamp:     start.Add(time.Duration(step.second) * time.Second),
+				}
+				got := checker.Check(context.Background(), plant, reading)
+				if fmt.Sprint(got) != fmt.Sprint(step.want) {
+					t.Errorf("Check(#%d) = %v, want %v", i, got, step.want)
+				}
+				if !step.skipCommit {
+					checker.Commit(context.Background(), plant, reading)
+				}
+			}
+		})
+	}
+}
+
+func TestQualityCheckerCheckIsPure(t *testing.T) {
+	checker := NewQualityChecker(nopMetrics{}, QualityCheckerConfig{Enabled: true, MaxRampPercent: 10, StuckReadings: 2, StuckMetrics: []string{entities.MetricTemperature}})
+	plant := &entities.EnergyPlants{ID: uuid.New(), PlantType: entities.PlantTypeSolar, CapacityMW: 100}
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		reading := entities.PlantReading{
+			PlantSourceId: plant.ID,
+			Metrics:       map[string]float64{entities.MetricPowerGenerated: float64(i * 20), entities.MetricTemperature: 40},
+			Timestamp:     start.Add(time.Duration(i) * time.Minute),
+		}
+		if flags := checker.Check(context.Background(), plant, reading); len(flags) > 0 {
+			t.Fatalf("Check(#%d) = %v without any committed reading, want no flags", i, flags)
+		}
+	}
+	if len(checker.state) != 0 {
+		t.Fatalf("Check stored state for %d plants, want none", len(checker.state))
+	}
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// Resultados de la cuarentena que se registran como métrica (label "outcome")
+const (
+	QuarantineOutcomeQuarantined = "quarantined"
+	QuarantineOutcomeDropped     = "dropped"
+	QuarantineOutcomeReleased    = "released"
+	QuarantineOutcomeDiscarded   = "discarded"
+)
+
+// quarantineReleaseBatch es la cantidad de mensajes que se cargan por vez al liberar
+const quarantineReleaseBatch = 500
+
+// QuarantineServiceConfig configuración de la cuarentena (UNKNOWN_PLANT_MODE, QUARANTINE_*)
+type QuarantineServiceConfig struct {
+	Mode              string // UNKNOWN_PLANT_MODE: reject o quarantine
+	MaxEventsPerPlant int    // QUARANTINE_MAX_EVENTS_PER_PLANT: mensajes guardados por planta (0 = sin límite)
+}
+
+// QuarantineService guarda y libera los mensajes de plantas que todavía no están registradas
+//
+// PROPÓSITO:
+// Sin cuarentena el intake descarta los mensajes cuyo plant_source_id no existe en
+// energy_plants, así que se pierden las primeras lecturas de una planta nueva.
+//
+// FUNCIONAMIENTO:
+// 1. Con Mode=quarantine el intake entrega esos mensajes a Hold, que los guarda en
+// quarantined_events (hasta MaxEventsPerPlant por planta; el resto se descarta)
+// 2. La API los muestra agrupados por plant_source_id
+// 3. Register da de alta la planta con ese ID y libera sus mensajes; Release libera los de
+// una planta registrada por otro medio
+// 4. Liberar vuelve a procesar cada mensaje con el intake en orden de event_time (calidad,
+// mantenimiento, alertas, agregados) y lo borra de la cuarentena
+// 5. Discard borra los mensajes de la planta sin procesarlos
+//
+// Las liberaciones se serializan para que una planta no se libere dos veces en paralelo.
+type QuarantineService struct {
+	quarantineRepository output.QuarantineRepositoryInterface
+	plantRepository      output.EnergyPlantRepositoryInterface
+	metrics              output.MetricsRecorderInterface
+	logger               *slog.Logger
+	cfg                  QuarantineServiceConfig
+
+	replayer input.MessageReplayer // Intake que procesa los mensajes liberados
+	mu       sync.Mutex
+}
+
+var _ input.EventQuarantine = &QuarantineService{}
+
+// NewQuarantineService crea el servicio de cuarentena; un modo desconocido se trata como reject
+func NewQuarantineService(
+	quarantineRepository output.QuarantineRepositoryInterface,
+	plantRepository output.EnergyPlantRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg QuarantineServiceConfig,
+) *QuarantineService {
+	if cfg.Mode != entities.UnknownPlantReject && cfg.Mode != entities.UnknownPlantQuarantine {
+		logger.Warn("UNKNOWN_PLANT_MODE is not reject or quarantine, rejecting unknown plants", slog.String("mode", cfg.Mode))
+		cfg.Mode = entities.UnknownPlantReject
+	}
+	return &QuarantineService{
+		quarantineRepository: quarantineRepository,
+		plantRepository:      plantRepository,
+		metrics:              metrics,
+		logger:               logger,
+		cfg:                  cfg,
+	}
+}
+
+// SetReplayer define el intake que procesa los mensajes liberados
+// Se inyecta después de construir el IntakeHandler, que a su vez recibe este servicio
+func (s *QuarantineService) SetReplayer(replayer input.MessageReplayer) {
+	s.replayer = replayer
+}
+
+// Hold guarda el mensaje si la cuarentena está activa y la planta no superó el límite
+//
+// CAMBIO: El límite se controla en el mismo paso que el insert (CreateWithinLimit)
+// RAZÓN: Contar y después guardar permitía que mensajes concurrentes superaran el límite
+func (s *QuarantineService) Hold(ctx context.Context, event *entities.QuarantinedEventEntity) (bool, error) {
+	if s.cfg.Mode != entities.UnknownPlantQuarantine {
+		return false, nil
+	}
+	created, err := s.quarantineRepository.CreateWithinLimit(ctx, event, s.cfg.MaxEventsPerPlant)
+	if err != nil {
+		return false, fmt.Errorf("error saving quarantined event: %w", err)
+	}
+	if !created {
+		s.logger.WarnContext(ctx, "Quarantine full for plant, event dropped",
+			slog.String("plant_source_id", event.PlantSourceId.String()),
+			slog.Int("max_events", s.cfg.MaxEventsPerPlant))
+		s.metrics.QuarantineEvents(QuarantineOutcomeDropped, 1)
+		return false, nil
+	}
+	s.metrics.QuarantineEvents(QuarantineOutcomeQuarantined, 1)
+	return true, nil
+}
+
+// List devuelve las plantas con mensajes en cuarentena, las de actividad más reciente primero
+func (s *QuarantineService) List(ctx context.Context) ([]*entities.QuarantinedPlant, error) {
+	summaries, err := s.quarantineRepository.Summaries(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	plants, err := s.plantRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[uuid.UUID]bool, len(plants))
+	for _, plant := range plants {
+		registered[plant.ID] = true
+	}
+	for _, summary := range summaries {
+		summary.Registered = registered[summary.PlantSourceId]
+	}
+	return summaries, nil
+}
+
+// Get devuelve el resumen de la cuarentena de una planta (ErrNotFound si no tiene mensajes)
+func (s *QuarantineService) Get(ctx context.Context, plantID uuid.UUID) (*entities.QuarantinedPlant, error) {
+	summaries, err := s.quarantineRepository.Summaries(ctx, &plantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return nil, domainerrors.ErrNotFound
+	}
+	summary := summaries[0]
+	exists, err := s.plantRepository.Exists(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+	summary.Registered = exists
+	return summary, nil
+}
+
+// Events devuelve los mensajes en cuarentena de la planta en orden de event_time
+func (s *QuarantineService) Events(ctx context.Context, plantID uuid.UUID, limit int) ([]*entities.QuarantinedEventEntity, error) {
+	return s.quarantineRepository.FindByPlant(ctx, plantID, limit)
+}
+
+// Register da de alta la planta con el plant_source_id de sus mensajes y los libera
+// Sin plant_name se usa el del último mensaje; sin plant_type, other
+func (s *QuarantineService) Register(ctx context.Context, plant *entities.EnergyPlants) (*entities.EnergyPlants, int, error) {
+	summary, err := s.Get(ctx, plant.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if summary.Registered {
+		return nil, 0, fmt.Errorf("%w: plant %s is already registered, release its events instead", domainerrors.ErrConflict, plant.ID)
+	}
+	if plant.PlantName == "" {
+		plant.PlantName = summary.Source
+	}
+	if plant.PlantType == "" {
+		plant.PlantType = entities.PlantTypeOther
+	}
+	if err := plant.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	created, err := s.plantRepository.Create(ctx, plant)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error registering plant: %w", err)
+	}
+	s.logger.InfoContext(ctx, "Plant registered from quarantine",
+		slog.String("plant_source_id", created.ID.String()),
+		slog.String("plant_name", created.PlantName),
+		slog.String("plant_type", created.PlantType))
+
+	released, err := s.Release(ctx, created.ID)
+	return created, released, err
+}
+
+// Release vuelve a procesar con el intake los mensajes en cuarentena de una planta registrada
+// Cada mensaje se procesa con la traza y el correlation ID con que llegó (Metadata), así el
+// evento liberado conserva los identificadores originales.
+// Se detiene en el primer error; los mensajes no liberados quedan en cuarentena para reintentar
+// Cada evento se guarda con el ID del mensaje en cuarentena: si una liberación anterior se cortó
+// entre el replay y el borrado, el mensaje se omite y solo se borra
+func (s *QuarantineService) Release(ctx context.Context, plantID uuid.UUID) (int, error) {
+	exists, err := s.plantRepository.Exists(ctx, plantID)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, fmt.Errorf("%w: plant %s is not registered", domainerrors.ErrConflict, plantID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// La liberación continúa aunque el cliente HTTP se desconecte
+	ctx = context.WithoutCancel(ctx)
+	released := 0
+	defer func() {
+		if released > 0 {
+			s.metrics.QuarantineEvents(QuarantineOutcomeReleased, released)
+		}
+	}()
+	for {
+		events, err := s.quarantineRepository.FindByPlant(ctx, plantID, quarantineReleaseBatch)
+		if err != nil {
+			return released, fmt.Errorf("error loading quarantined events: %w", err)
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, event := range events {
+			if err := s.replayer.ReplayMessage(withEventMetadata(ctx, event.Metadata), event.ID, []byte(event.Data)); err != nil {
+				return released, fmt.Errorf("error releasing event %s: %w", event.ID, err)
+			}
+			if err := s.quarantineRepository.Delete(ctx, event.ID); err != nil && !errors.Is(err, domainerrors.ErrNotFound) {
+				return released, fmt.Errorf("error removing released event %s: %w", event.ID, err)
+			}
+			released++
+		}
+	}
+
+	s.logger.InfoContext(ctx, "Quarantined events released",
+		slog.String("plant_source_id", plantID.String()),
+		slog.Int("released", released))
+	return released, nil
+}
+
+// Discard borra los mensajes en cuarentena de la planta sin procesarlos (ErrNotFound si no tiene)
+func (s *QuarantineService) Discard(ctx context.Context, plantID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	discarded, err := s.quarantineRepository.DeleteByPlant(ctx, plantID)
+	if err != nil {
+		return 0, err
+	}
+	if discarded == 0 {
+		return 0, domainerrors.ErrNotFound
+	}
+	s.metrics.QuarantineEvents(QuarantineOutcomeDiscarded, int(discarded))
+	s.logger.InfoContext(ctx, "Quarantined events discarded",
+		slog.String("plant_source_id", plantID.String()),
+		slog.Int64("discarded", discarded))
+	return discarded, nil
+}
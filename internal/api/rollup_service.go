@@ -0,0 +1,525 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// rollupPurgeBatch eventos borrados por sentencia al purgar raw
+const rollupPurgeBatch = 5000
+
+// rollupChunks rango de origen que se resume por vuelta al construir cada nivel
+var rollupChunks = map[string]time.Duration{
+	entities.RollupTierMinute: time.Hour,
+	entities.RollupTierHour:   24 * time.Hour,
+	entities.RollupTierDay:    30 * 24 * time.Hour,
+}
+
+// RollupServiceConfig configuración de los rollups y la retención (variables ROLLUP_*)
+type RollupServiceConfig struct {
+	Enabled         bool          // ROLLUP_ENABLED
+	Interval        time.Duration // ROLLUP_INTERVAL: cada cuánto se construyen los rollups y se purga
+	Delay           time.Duration // ROLLUP_DELAY: antigüedad mínima de un minuto antes de resumirlo
+	RawRetention    time.Duration // ROLLUP_RAW_RETENTION: retención de los eventos (0 = sin purga)
+	MinuteRetention time.Duration // ROLLUP_1M_RETENTION: retención de los rollups de 1 minuto (0 = sin purga)
+	HourRetention   time.Duration // ROLLUP_1H_RETENTION: retención de los rollups de 1 hora (0 = sin purga)
+	MaxBuckets      int           // ROLLUP_MAX_BUCKETS: buckets máximos de una serie
+}
+
+// RollupService resume las lecturas en rollups de 1 minuto, 1 hora y 1 día y purga cada nivel
+//
+// PROPÓSITO:
+// Los eventos crecen sin límite. Cada nivel se guarda por su propia retención (los rollups diarios
+// para siempre) y las series de lecturas se leen del nivel más fino que todavía tiene el rango.
+//
+// FUNCIONAMIENTO:
+// 1. Cada Interval construye los niveles en orden: 1m desde events (hasta now - Delay), 1h desde
+// 1m y 1d desde 1h, cada uno hasta donde llegó el anterior. rollup_watermarks guarda hasta
+// dónde está construido cada nivel, así un reinicio sigue donde quedó
+// 2. Después purga: un nivel se borra hasta su retención, pero nunca más allá de lo que ya
+// resumió el nivel siguiente (los datos no se pierden si la construcción está atrasada)
+// 3. Las lecturas tardías (LateDataHandler) reconstruyen los buckets de su rango que ya estaban
+// construidos, siempre que el nivel de origen todavía tenga ese rango
+// 4. Series elige el nivel más fino cuya resolución divide el bucket pedido y cuya retención
+// cubre el inicio del rango
+type RollupService struct {
+	eventRepository  output.EventRepositoryInterface
+	plantRepository  output.EnergyPlantRepositoryInterface
+	rollupRepository output.ReadingRollupRepositoryInterface
+	metrics          output.MetricsRecorderInterface
+	logger           *slog.Logger
+	cfg              RollupServiceConfig
+
+	mu       sync.Mutex // Una sola construcción o reconstrucción a la vez
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+var _ input.AggregateRecomputer = &RollupService{}
+
+// rollupAccumulator count, sum, min y max de un bucket
+type rollupAccumulator struct {
+	count    int64
+	sum      float64
+	min, max float64
+}
+
+func (a *rollupAccumulator) add(count int64, sum, min, max float64) {
+	if a.count == 0 || min < a.min {
+		a.min = min
+	}
+	if a.count == 0 || max > a.max {
+		a.max = max
+	}
+	a.count += count
+	a.sum += sum
+}
+
+// rollupKey identifica un bucket de una métrica de una planta
+type rollupKey struct {
+	plantID uuid.UUID
+	metric  string
+	start   time.Time
+}
+
+// NewRollupService crea el servicio; la construcción periódica se inicia con Start
+func NewRollupService(
+	eventRepository output.EventRepositoryInterface,
+	plantRepository output.EnergyPlantRepositoryInterface,
+	rollupRepository output.ReadingRollupRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg RollupServiceConfig,
+) *RollupService {
+	if cfg.Delay < 0 {
+		cfg.Delay = 0
+	}
+	return &RollupService{
+		eventRepository:  eventRepository,
+		plantRepository:  plantRepository,
+		rollupRepository: rollupRepository,
+		metrics:          metrics,
+		logger:           logger,
+		cfg:              cfg,
+		stopChan:         make(chan struct{}),
+	}
+}
+
+// Start construye los rollups y purga cada Interval en un goroutine (primera corrida inmediata)
+func (s *RollupService) Start(ctx context.Context) {
+	if !s.cfg.Enabled || s.cfg.Interval <= 0 {
+		s.logger.InfoContext(ctx, "Reading rollups and retention disabled")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		s.Run(ctx)
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.Run(ctx)
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+	s.logger.InfoContext(ctx, "Reading rollups started",
+		slog.Duration("interval", s.cfg.Interval),
+		slog.Duration("raw_retention", s.cfg.RawRetention),
+		slog.Duration("1m_retention", s.cfg.MinuteRetention),
+		slog.Duration("1h_retention", s.cfg.HourRetention))
+}
+
+// Stop detiene la construcción periódica y espera a que termine la corrida en curso
+func (s *RollupService) Stop() {
+	close(s.stopChan)
+	s.wg.Wait()
+}
+
+// Run construye los niveles pendientes y después purga; si la construcción falla no se purga
+func (s *RollupService) Run(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watermarks, err := s.build(ctx, time.Now().UTC())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error building reading rollups", slog.Any("error", err))
+		return
+	}
+	s.purge(ctx, time.Now().UTC(), watermarks)
+}
+
+// build construye cada nivel desde el anterior y devuelve hasta dónde quedó cada uno
+func (s *RollupService) build(ctx context.Context, now time.Time) (map[string]time.Time, error) {
+	watermarks, err := s.loadWatermarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := now.Add(-s.cfg.Delay)
+	for _, tier := range entities.RollupTiers {
+		resolution := entities.RollupResolution(tier)
+		until := limit.Truncate(resolution)
+
+		from, ok := watermarks[tier]
+		if !ok {
+			earliest, err := s.eventRepository.FindEarliestEventTime(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error loading earliest event: %w", err)
+			}
+			if earliest.IsZero() {
+				return watermarks, nil // Sin eventos no hay nada que resumir
+			}
+			from = earliest.UTC().Truncate(resolution)
+		}
+
+		buckets := 0
+		for from.Before(until) {
+			to := minTime(from.Add(rollupChunks[tier]), until)
+			rollups, err := s.summarize(ctx, tier, nil, from, to)
+			if err != nil {
+				return nil, fmt.Errorf("error building %s rollups from %s: %w", tier, from.Format(time.RFC3339), err)
+			}
+			if err := s.rollupRepository.Upsert(ctx, rollups); err != nil {
+				return nil, fmt.Errorf("error saving %s rollups: %w", tier, err)
+			}
+			if err := s.rollupRepository.SaveWatermark(ctx, tier, to); err != nil {
+				return nil, fmt.Errorf("error saving %s watermark: %w", tier, err)
+			}
+			buckets += len(rollups)
+			from = to
+		}
+		watermarks[tier] = maxTime(from, watermarks[tier])
+		if buckets > 0 {
+			s.metrics.RollupBuckets(tier, buckets)
+			s.logger.DebugContext(ctx, "Reading rollups built",
+				slog.String("tier", tier), slog.Int("buckets", buckets), slog.Time("built_until", watermarks[tier]))
+		}
+
+		// El nivel siguiente solo resume lo que este ya tiene completo
+		limit = watermarks[tier]
+	}
+	return watermarks, nil
+}
+
+// purge borra cada nivel hasta su retención sin superar lo que ya resumió el nivel siguiente
+func (s *RollupService) purge(ctx context.Context, now time.Time, watermarks map[string]time.Time) {
+	tiers := append([]string{entities.RollupTierRaw}, entities.RollupTiers...)
+	for i, tier := range tiers[:len(tiers)-1] { // Los rollups diarios se guardan para siempre
+		retention := s.retention(tier)
+		built, ok := watermarks[tiers[i+1]]
+		if retention <= 0 || !ok {
+			continue
+		}
+		before := minTime(now.Add(-retention), built)
+
+		var deleted int64
+		var err error
+		if tier == entities.RollupTierRaw {
+			deleted, err = s.eventRepository.DeleteBefore(ctx, before, rollupPurgeBatch)
+		} else {
+			deleted, err = s.rollupRepository.DeleteBefore(ctx, tier, before)
+		}
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Error purging readings", slog.String("tier", tier), slog.Any("error", err))
+			continue
+		}
+		if deleted > 0 {
+			s.metrics.RetentionPurged(tier, deleted)
+			s.logger.InfoContext(ctx, "Readings purged",
+				slog.String("tier", tier), slog.Int64("rows", deleted), slog.Time("before", before))
+		}
+	}
+}
+
+// RecomputeRange reconstruye los buckets [from, to) ya construidos de la planta en cada nivel
+// Un nivel solo se reconstruye en la parte del rango que su origen todavía guarda
+func (s *RollupService) RecomputeRange(ctx context.Context, plantID uuid.UUID, from, to time.Time) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watermarks, err := s.loadWatermarks(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	sourceTier := entities.RollupTierRaw
+	for _, tier := range entities.RollupTiers {
+		resolution := entities.RollupResolution(tier)
+		start := from.UTC().Truncate(resolution)
+		end := ceilTime(to.UTC(), resolution)
+		if retention := s.retention(sourceTier); retention > 0 {
+			start = maxTime(start, ceilTime(now.Add(-retention), resolution))
+		}
+		if built, ok := watermarks[tier]; !ok || end.After(built) {
+			end = built // Lo que falta construir lo hace la próxima corrida
+		}
+		sourceTier = tier
+		if !end.After(start) {
+			continue
+		}
+
+		rollups, err := s.summarize(ctx, tier, &plantID, start, end)
+		if err != nil {
+			return fmt.Errorf("error rebuilding %s rollups: %w", tier, err)
+		}
+		if err := s.rollupRepository.ReplaceRange(ctx, plantID, tier, start, end, rollups); err != nil {
+			return fmt.Errorf("error saving %s rollups: %w", tier, err)
+		}
+	}
+	return nil
+}
+
+// Series devuelve la métrica de la planta agregada por bucket desde el nivel más fino disponible
+// from se alinea al inicio de su bucket y to al final del suyo
+func (s *RollupService) Series(ctx context.Context, plantID uuid.UUID, query entities.ReadingSeriesQuery) (*entities.ReadingSeries, error) {
+	if err := query.Validate(s.cfg.MaxBuckets); err != nil {
+		return nil, err
+	}
+	plant, err := s.plantRepository.FindByID(ctx, plantID)
+	if err != nil {
+		return nil, err
+	}
+	query.From = query.From.UTC().Truncate(query.Bucket)
+	query.To = ceilTime(query.To.UTC(), query.Bucket)
+
+	tier, err := s.selectTier(query, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[time.Time]*rollupAccumulator)
+	add := func(at time.Time, count int64, sum, min, max float64) {
+		start := at.UTC().Truncate(query.Bucket)
+		acc, ok := buckets[start]
+		if !ok {
+			acc = &rollupAccumulator{}
+			buckets[start] = acc
+		}
+		acc.add(count, sum, min, max)
+	}
+
+	if tier == entities.RollupTierRaw {
+		events, err := s.eventRepository.FindByFilter(ctx, entities.EventFilter{
+			PlantSourceId: &plant.ID,
+			From:          query.From,
+			To:            query.To,
+			Quality:       entities.EventQualityGood,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error loading events of plant %s: %w", plant.ID, err)
+		}
+		for _, event := range events {
+			reading, err := entities.PlantReadingFromEvent(event, plant.PlantType)
+			if err != nil {
+				continue
+			}
+			if value, ok := reading.Metric(query.Metric); ok {
+				add(reading.Timestamp, 1, value, value, value)
+			}
+		}
+	} else {
+		rollups, err := s.rollupRepository.Find(ctx, entities.ReadingRollupFilter{
+			Resolution:    tier,
+			PlantSourceId: &plant.ID,
+			Metric:        query.Metric,
+			From:          query.From,
+			To:            query.To,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s rollups of plant %s: %w", tier, plant.ID, err)
+		}
+		for _, rollup := range rollups {
+			add(rollup.BucketStart, rollup.Count, rollup.Sum, rollup.Min, rollup.Max)
+		}
+	}
+
+	series := &entities.ReadingSeries{
+		PlantSourceId: plant.ID,
+		Metric:        query.Metric,
+		From:          query.From,
+		To:            query.To,
+		Bucket:        query.Bucket.String(),
+		Tier:          tier,
+		Points:        make([]entities.ReadingSeriesPoint, 0, len(buckets)),
+	}
+	for start, acc := range buckets {
+		series.Points = append(series.Points, entities.ReadingSeriesPoint{
+			Start: start,
+			Count: acc.count,
+			Mean:  acc.sum / float64(acc.count),
+			Min:   acc.min,
+			Max:   acc.max,
+		})
+	}
+	slices.SortFunc(series.Points, func(a, b entities.ReadingSeriesPoint) int { return a.Start.Compare(b.Start) })
+	return series, nil
+}
+
+// selectTier elige el nivel más fino cuya resolución divide el bucket y cuya retención cubre from
+// Con los rollups deshabilitados siempre se lee raw
+func (s *RollupService) selectTier(query entities.ReadingSeriesQuery, now time.Time) (string, error) {
+	if !s.cfg.Enabled {
+		return entities.RollupTierRaw, nil
+	}
+	for _, tier := range append([]string{entities.RollupTierRaw}, entities.RollupTiers...) {
+		if resolution := entities.RollupResolution(tier); resolution > 0 && query.Bucket%resolution != 0 {
+			continue
+		}
+		if retention := s.retention(tier); retention > 0 && query.From.Before(now.Add(-retention)) {
+			continue
+		}
+		return tier, nil
+	}
+	return "", fmt.Errorf("%w: only daily rollups are kept for this range, bucket must be a multiple of 24h", domainerrors.ErrInvalidInput)
+}
+
+// summarize resume [from, to) del nivel de origen del tier (events para 1m) en buckets del tier
+func (s *RollupService) summarize(ctx context.Context, tier string, plantID *uuid.UUID, from, to time.Time) ([]*entities.ReadingRollupEntity, error) {
+	resolution := entities.RollupResolution(tier)
+	buckets := make(map[rollupKey]*rollupAccumulator)
+	add := func(plantID uuid.UUID, metric string, at time.Time, count int64, sum, min, max float64) {
+		key := rollupKey{plantID: plantID, metric: metric, start: at.UTC().Truncate(resolution)}
+		acc, ok := buckets[key]
+		if !ok {
+			acc = &rollupAccumulator{}
+			buckets[key] = acc
+		}
+		acc.add(count, sum, min, max)
+	}
+
+	if tier == entities.RollupTierMinute {
+		events, err := s.eventRepository.FindByFilter(ctx, entities.EventFilter{
+			PlantSourceId: plantID,
+			From:          from,
+			To:            to,
+			Quality:       entities.EventQualityGood,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			reading, err := entities.PlantReadingFromEvent(event, "")
+			if err != nil {
+				continue
+			}
+			for _, metric := range entities.ReadingMetrics {
+				if value, ok := reading.Metric(metric); ok {
+					add(event.PlantSourceId, metric, reading.Timestamp, 1, value, value, value)
+				}
+			}
+		}
+	} else {
+		source := entities.RollupTiers[slices.Index(entities.RollupTiers, tier)-1]
+		rollups, err := s.rollupRepository.Find(ctx, entities.ReadingRollupFilter{
+			Resolution:    source,
+			PlantSourceId: plantID,
+			From:          from,
+			To:            to,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, rollup := range rollups {
+			add(rollup.PlantSourceId, rollup.Metric, rollup.BucketStart, rollup.Count, rollup.Sum, rollup.Min, rollup.Max)
+		}
+	}
+
+	rollups := make([]*entities.ReadingRollupEntity, 0, len(buckets))
+	for key, acc := range buckets {
+		rollups = append(rollups, &entities.ReadingRollupEntity{
+			PlantSourceId: key.plantID,
+			Resolution:    tier,
+			BucketStart:   key.start,
+			Metric:        key.metric,
+			Count:         acc.count,
+			Sum:           acc.sum,
+			Min:           acc.min,
+			Max:           acc.max,
+		})
+	}
+	return rollups, nil
+}
+
+// loadWatermarks devuelve hasta dónde está construido cada nivel
+func (s *RollupService) loadWatermarks(ctx context.Context) (map[string]time.Time, error) {
+	saved, err := s.rollupRepository.FindWatermarks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading rollup watermarks: %w", err)
+	}
+	watermarks := make(map[string]time.Time, len(saved))
+	for _, watermark := range saved {
+		watermarks[watermark.Resolution] = watermark.BuiltUntil.UTC()
+	}
+	return watermarks, nil
+}
+
+// retention devuelve la retención de un nivel (0 = para siempre)
+func (s *RollupService) retention(tier string) time.Duration {
+	switch tier {
+	case entities.RollupTierRaw:
+		return s.cfg.RawRetention
+	case entities.RollupTierMinute:
+		return s.cfg.MinuteRetention
+	case entities.RollupTierHour:
+		return s.cfg.HourRetention
+	default:
+		return 0
+	}
+}
+
+// rawRetentionStart devuelve el primer día UTC completo cuyos eventos sobreviven a la purga
+// con la retención dada (cero si los eventos no se purgan)
+func rawRetentionStart(retention time.Duration) time.Time {
+	if retention <= 0 {
+		return time.Time{}
+	}
+	oldest := time.Now().UTC().Add(-retention)
+	start := entities.PeriodStart(oldest, entities.GranularityDay)
+	if start.Before(oldest) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start
+}
+
+// ceilTime redondea t hacia arriba al múltiplo de d
+func ceilTime(t time.Time, d time.Duration) time.Time {
+	if floor := t.Truncate(d); !floor.Equal(t) {
+		return floor.Add(d)
+	}
+	return t
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+)
+
+// rollupEventsFake registra las lecturas y purgas de events
+type rollupEventsFake struct {
+	output.EventRepositoryInterface
+	earliest      time.Time
+	earliestCalls int
+	filters       []entities.EventFilter
+	purgedBefore  *time.Time
+}
+
+func (f *rollupEventsFake) FindEarliestEventTime(context.Context) (time.Time, error) {
+	f.earliestCalls++
+	return f.earliest, nil
+}
+
+func (f *rollupEventsFake) FindByFilter(_ context.Context, filter entities.EventFilter) ([]*entities.EventEntity, error) {
+	f.filters = append(f.filters, filter)
+	return nil, nil
+}
+
+func (f *rollupEventsFake) DeleteBefore(_ context.Context, before time.Time, _ int) (int64, error) {
+	f.purgedBefore = &before
+	return 0, nil
+}
+
+// rollupRepoFake guarda watermarks en memoria y registra las purgas por nivel
+type rollupRepoFake struct {
+	output.ReadingRollupRepositoryInterface
+	watermarks map[string]time.Time
+	saved      []string
+	purged     map[string]time.Time
+}
+
+func (f *rollupRepoFake) FindWatermarks(context.Context) ([]*entities.RollupWatermarkEntity, error) {
+	var watermarks []*entities.RollupWatermarkEntity
+	for tier, builtUntil := range f.watermarks {
+		watermarks = append(watermarks, &entities.RollupWatermarkEntity{Resolution: tier, BuiltUntil: builtUntil})
+	}
+	return watermarks, nil
+}
+
+func (f *rollupRepoFake) SaveWatermark(_ context.Context, resolution string, builtUntil time.Time) error {
+	f.saved = append(f.saved, resolution+"@"+builtUntil.Format("15:04"))
+	return nil
+}
+
+func (f *rollupRepoFake) Find(context.Context, entities.ReadingRollupFilter) ([]*entities.ReadingRollupEntity, error) {
+	return nil, nil
+}
+
+func (f *rollupRepoFake) Upsert(context.Context, []*entities.ReadingRollupEntity) error {
+	return nil
+}
+
+func (f *rollupRepoFake) DeleteBefore(_ context.Context, resolution string, before time.Time) (int64, error) {
+	if f.purged == nil {
+		f.purged = make(map[string]time.Time)
+	}
+	f.purged[resolution] = before
+	return 0, nil
+}
+
+var rollupTestConfig = RollupServiceConfig{
+	Enabled:         true,
+	RawRetention:    48 * time.Hour,
+	MinuteRetention: 7 * 24 * time.Hour,
+	HourRetention:   90 * 24 * time.Hour,
+	MaxBuckets:      1000,
+}
+
+func TestRollupServicePurge(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	tests := []struct {
+		name       string
+		cfg        func(*RollupServiceConfig)
+		watermarks map[string]time.Time
+		want       map[string]time.Time // Nivel → límite de la purga (ausente = no se purga)
+	}{
+		{
+			name:       "next tiers up to date purge to retention",
+			watermarks: map[string]time.Time{entities.RollupTierMinute: now, entities.RollupTierHour: now, entities.RollupTierDay: now},
+			want: map[string]time.Time{
+				entities.RollupTierRaw:    ago(48 * time.Hour),
+				entities.RollupTierMinute: ago(7 * 24 * time.Hour),
+				entities.RollupTierHour:   ago(90 * 24 * time.Hour),
+			},
+		},
+		{
+			name: "next tiers behind retention purge only what they summarized",
+			watermarks: map[string]time.Time{
+				entities.RollupTierMinute: ago(72 * time.Hour),
+				entities.RollupTierHour:   ago(10 * 24 * time.Hour),
+				entities.RollupTierDay:    ago(100 * 24 * time.Hour),
+			},
+			want: map[string]time.Time{
+				entities.RollupTierRaw:    ago(72 * time.Hour),
+				entities.RollupTierMinute: ago(10 * 24 * time.Hour),
+				entities.RollupTierHour:   ago(100 * 24 * time.Hour),
+			},
+		},
+		{
+			name:       "next tier never built keeps the tier",
+			watermarks: map[string]time.Time{entities.RollupTierMinute: now},
+			want:       map[string]time.Time{entities.RollupTierRaw: ago(48 * time.Hour)},
+		},
+		{
+			name:       "zero retention keeps the tier",
+			cfg:        func(cfg *RollupServiceConfig) { cfg.RawRetention, cfg.HourRetention = 0, 0 },
+			watermarks: map[string]time.Time{entities.RollupTierMinute: now, entities.RollupTierHour: now, entities.RollupTierDay: now},
+			want:       map[string]time.Time{entities.RollupTierMinute: ago(7 * 24 * time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := rollupTestConfig
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			events := &rollupEventsFake{}
+			rollups := &rollupRepoFake{}
+			service := NewRollupService(events, nil, rollups, nopMetrics{}, discardLogger(), cfg)
+
+			service.purge(context.Background(), now, tt.watermarks)
+
+			got := make(map[string]time.Time, len(rollups.purged)+1)
+			for tier, before := range rollups.purged {
+				got[tier] = before
+			}
+			if events.purgedBefore != nil {
+				got[entities.RollupTierRaw] = *events.purgedBefore
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("purged tiers = %v, want %v", got, tt.want)
+			}
+			tiers := append([]string{entities.RollupTierRaw}, entities.RollupTiers...)
+			for i, tier := range tiers[:len(tiers)-1] {
+				before, ok := got[tier]
+				if want, wantOK := tt.want[tier]; ok != wantOK || !before.Equal(want) {
+					t.Errorf("%s purged before %v (%t), want %v (%t)", tier, before, ok, want, wantOK)
+				}
+				if built := tt.watermarks[tiers[i+1]]; ok && before.After(built) {
+					t.Errorf("%s purged before %v, past the %s watermark %v", tier, before, tiers[i+1], built)
+				}
+			}
+		})
+	}
+}
+
+func TestRollupServiceSelectTier(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		disabled bool
+		from     time.Time
+		bucket   time.Duration
+		want     string
+		wantErr  bool
+	}{
+		{name: "recent range any bucket reads raw", from: now.Add(-time.Hour), bucket: 30 * time.Second, want: entities.RollupTierRaw},
+		{name: "raw retention boundary is inclusive", from: now.Add(-48 * time.Hour), bucket: 30 * time.Second, want: entities.RollupTierRaw},
+		{name: "past raw retention reads minutes", from: now.Add(-48*time.Hour - time.Second), bucket: 5 * time.Minute, want: entities.RollupTierMinute},
+		{name: "past raw retention with sub-minute bucket", from: now.Add(-48*time.Hour - time.Second), bucket: 90 * time.Second, wantErr: true},
+		{name: "minute retention boundary is inclusive", from: now.Add(-7 * day), bucket: time.Minute, want: entities.RollupTierMinute},
+		{name: "past minute retention reads hours", from: now.Add(-7*day - time.Second), bucket: time.Hour, want: entities.RollupTierHour},
+		{name: "hour bucket not a multiple of the tier skips it", from: now.Add(-7*day - time.Second), bucket: 90 * time.Minute, wantErr: true},
+		{name: "past hour retention reads days", from: now.Add(-90*day - time.Second), bucket: 2 * day, want: entities.RollupTierDay},
+		{name: "past hour retention needs daily buckets", from: now.Add(-90*day - time.Second), bucket: 6 * time.Hour, wantErr: true},
+		{name: "disabled always reads raw", disabled: true, from: now.Add(-365 * day), bucket: time.Minute, want: entities.RollupTierRaw},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := rollupTestConfig
+			cfg.Enabled = !tt.disabled
+			service := NewRollupService(nil, nil, nil, nopMetrics{}, discardLogger(), cfg)
+
+			got, err := service.selectTier(entities.ReadingSeriesQuery{
+				Metric: entities.MetricPowerGenerated,
+				From:   tt.from,
+				To:     now,
+				Bucket: tt.bucket,
+			}, now)
+			if tt.wantErr {
+				if !errors.Is(err, domainerrors.ErrInvalidInput) {
+					t.Fatalf("selectTier() = %q, %v; want ErrInvalidInput", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("selectTier() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRollupServiceBuildResumesFromWatermarks(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 30, 0, time.UTC)
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 19, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name          string
+		watermarks    map[string]time.Time
+		earliest      time.Time
+		wantEarliest  bool
+		wantEventFrom []time.Time // From de cada lectura de events (1m)
+		wantSaved     []string
+		want          map[string]time.Time
+	}{
+		{
+			name:          "saved watermarks resume where the last run stopped",
+			watermarks:    map[string]time.Time{entities.RollupTierMinute: at(10, 0), entities.RollupTierHour: at(10, 0), entities.RollupTierDay: at(0, 0)},
+			wantEventFrom: []time.Time{at(10, 0), at(11, 0)},
+			wantSaved:     []string{"1m@11:00", "1m@12:00", "1h@12:00"},
+			want:          map[string]time.Time{entities.RollupTierMinute: at(12, 0), entities.RollupTierHour: at(12, 0), entities.RollupTierDay: at(0, 0)},
+		},
+		{
+			name:          "up to date watermarks build nothing",
+			watermarks:    map[string]time.Time{entities.RollupTierMinute: at(12, 0), entities.RollupTierHour: at(12, 0), entities.RollupTierDay: at(0, 0)},
+			wantEventFrom: nil,
+			wantSaved:     nil,
+			want:          map[string]time.Time{entities.RollupTierMinute: at(12, 0), entities.RollupTierHour: at(12, 0), entities.RollupTierDay: at(0, 0)},
+		},
+		{
+			name:          "first run starts at the earliest event",
+			earliest:      time.Date(2026, 10, 19, 11, 10, 20, 0, time.UTC),
+			wantEarliest:  true,
+			wantEventFrom: []time.Time{at(11, 10)},
+			wantSaved:     []string{"1m@12:00", "1h@12:00"},
+			want:          map[string]time.Time{entities.RollupTierMinute: at(12, 0), entities.RollupTierHour: at(12, 0), entities.RollupTierDay: at(0, 0)},
+		},
+		{
+			name:         "no events builds nothing",
+			wantEarliest: true,
+			want:         map[string]time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &rollupEventsFake{earliest: tt.earliest}
+			rollups := &rollupRepoFake{watermarks: tt.watermarks}
+			service := NewRollupService(events, nil, rollups, nopMetrics{}, discardLogger(), rollupTestConfig)
+
+			got, err := service.build(context.Background(), now)
+			if err != nil {
+				t.Fatalf("build() error = %v", err)
+			}
+
+			if (events.earliestCalls > 0) != tt.wantEarliest {
+				t.Errorf("earliest event loaded %d times, want loaded = %t", events.earliestCalls, tt.wantEarliest)
+			}
+			if len(events.filters) != len(tt.wantEventFrom) {
+				t.Fatalf("events read %d times, want %d", len(events.filters), len(tt.wantEventFrom))
+			}
+			for i, filter := range events.filters {
+				if !filter.From.Equal(tt.wantEventFrom[i]) {
+					t.Errorf("events read #%d from %v, want %v", i, filter.From, tt.wantEventFrom[i])
+				}
+			}
+			if len(rollups.saved) != len(tt.wantSaved) {
+				t.Fatalf("saved watermarks = %v, want %v", rollups.saved, tt.wantSaved)
+			}
+			for i := range rollups.saved {
+				if rollups.saved[i] != tt.wantSaved[i] {
+					t.Errorf("saved watermarks = %v, want %v", rollups.saved, tt.wantSaved)
+					break
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("build() = %v, want %v", got, tt.want)
+			}
+			for tier, want := range tt.want {
+				if !got[tier].Equal(want) {
+					t.Errorf("%s built until %v, want %v", tier, got[tier], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,439 @@
+package api
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+)
+
+// Parámetros físicos de la simulación
+const (
+	simulatedDefaultCapacityMW = 100.0            // Capacidad usada para plantas sin CapacityMW
+	simulatedMaxRampPerMinute  = 0.25             // Cambio máximo de potencia por minuto (fracción de la capacidad)
+	simulatedThermalLag        = 15 * time.Minute // Constante de tiempo de la temperatura de los equipos
+	simulatedReportInterval    = time.Hour        // Cada cuánto una planta envía un efficiency_report
+	simulatedAlertTemperature  = 70.0             // Temperatura (°C) a partir de la cual la lectura es un alert
+
+	// Eólica: distribución de Weibull del viento y curva de potencia del aerogenerador
+	windWeibullScale = 8.0  // λ (m/s)
+	windWeibullShape = 2.0  // k (Rayleigh)
+	windCutIn        = 3.0  // m/s
+	windRated        = 12.0 // m/s
+	windCutOut       = 25.0 // m/s
+
+	// Almacenamiento: energía útil = capacidad × horas
+	storageDurationHours = 4.0
+	storageChargeRate    = 0.5 // Fracción de la capacidad al cargar o descargar
+	storageMinCharge     = 10.0
+	storageMaxCharge     = 95.0
+)
+
+// simulatedDwell es la permanencia media en cada estado antes de un cambio espontáneo
+var simulatedDwell = map[string]time.Duration{
+	entities.PlantStatusOperational: 4 * time.Hour,
+	entities.PlantStatusPeakLoad:    2 * time.Hour,
+	entities.PlantStatusStandby:     6 * time.Hour,
+	entities.PlantStatusMaintenance: 4 * time.Hour,
+}
+
+// simulatedPlant es el estado físico simulado de una planta entre lecturas
+//
+// PROPÓSITO:
+// Cada lectura avanza el estado de la planta por el tiempo transcurrido desde la anterior,
+// así los valores son continuos y respetan los chequeos de plausibilidad del intake
+// (capacidad, rampa, velocidad de cambio de temperatura).
+//
+// FUNCIONAMIENTO DE step:
+// 1. El perfil del tipo calcula el recurso disponible (fracción de la capacidad): curva solar
+// diurna con nubes, viento Weibull por la curva de potencia, despacho hidro según el embalse,
+// horario de carga y descarga del almacenamiento
+// 2. La máquina de estados cambia el estado solo por transiciones permitidas: forzadas por el
+// recurso (sin sol o viento → standby) o espontáneas según la permanencia media de cada estado
+// 3. La potencia sigue al objetivo del estado con rampa limitada y nunca supera la capacidad
+// 4. La temperatura sigue a ambiente + calentamiento por carga con retardo térmico
+//
+// Las señales lentas (nubes, viento, despacho, caudal) son sumas de senoides con fases
+// aleatorias por planta: suaves, acotadas y reproducibles con SIMULATOR_SEED. Las horas del
+// día se toman en UTC.
+type simulatedPlant struct {
+	plant         *entities.EnergyPlants
+	status        string
+	statusChanged bool      // El último step cambió el estado
+	at            time.Time // Momento del último step (cero = sin lecturas)
+	output        float64   // MW entregados
+	charging      float64   // MW de carga (almacenamiento)
+	temperature   float64   // °C
+	reservoir     float64   // Hidro: nivel del embalse (%)
+	charge        float64   // Almacenamiento: estado de carga (%)
+	lastReport    time.Time // Último efficiency_report
+	phases        [8]float64
+}
+
+// newSimulatedPlant crea el estado inicial de la planta con fases aleatorias
+func newSimulatedPlant(plant *entities.EnergyPlants, rng *rand.Rand) *simulatedPlant {
+	sim := &simulatedPlant{
+		plant:     plant,
+		status:    entities.PlantStatusOperational,
+		reservoir: 60 + rng.Float64()*25,
+		charge:    30 + rng.Float64()*40,
+	}
+	for i := range sim.phases {
+		sim.phases[i] = rng.Float64() * 2 * math.Pi
+	}
+	return sim
+}
+
+// capacity devuelve la capacidad de la planta (simulatedDefaultCapacityMW si no tiene)
+func (s *simulatedPlant) capacity() float64 {
+	if s.plant.CapacityMW > 0 {
+		return s.plant.CapacityMW
+	}
+	return simulatedDefaultCapacityMW
+}
+
+// step avanza la simulación hasta now y devuelve la lectura de la planta
+func (s *simulatedPlant) step(now time.Time, rng *rand.Rand) EnergyMonitoringEvent {
+	first := s.at.IsZero()
+	elapsed := time.Duration(0)
+	if !first && now.After(s.at) {
+		elapsed = now.Sub(s.at)
+	}
+	s.at = now
+
+	event := EnergyMonitoringEvent{
+		PlantSourceId: s.plant.ID,
+		PlantName:     s.plant.PlantName,
+		Timestamp:     now,
+	}
+
+	resource := s.resource(now, elapsed, &event)
+	s.statusChanged = s.advanceStatus(resource, elapsed, first, rng)
+
+	capacity := s.capacity()
+	target := s.targetLoad(resource) * capacity
+	targetCharging := 0.0
+	if s.plant.PlantType == entities.PlantTypeStorage && resource < 0 && s.producing() {
+		// Almacenamiento cargando: el recurso negativo es la potencia tomada de la red
+		targetCharging = -resource * capacity
+		target = 0
+	}
+	if first {
+		s.output, s.charging = target, targetCharging
+	} else {
+		maxChange := simulatedMaxRampPerMinute * capacity * elapsed.Minutes()
+		s.output = moveToward(s.output, target, maxChange)
+		s.charging = moveToward(s.charging, targetCharging, maxChange)
+	}
+	s.output = math.Min(math.Max(s.output, 0), capacity)
+	if s.plant.PlantType == entities.PlantTypeStorage {
+		s.updateCharge(elapsed)
+	}
+
+	load := (s.output + s.charging) / capacity
+	targetTemperature := ambientTemperature(now) + heatingPerLoad(s.plant.PlantType)*load
+	if first {
+		s.temperature = targetTemperature
+	} else {
+		s.temperature += (targetTemperature - s.temperature) * (1 - math.Exp(-elapsed.Seconds()/simulatedThermalLag.Seconds()))
+	}
+
+	event.PowerGenerated = s.output
+	event.PowerConsumed = s.charging + s.auxiliary(load)*capacity
+	event.Temperature = s.temperature + 0.05*s.wave(now, 7, 3*time.Minute)
+	event.Efficiency = s.efficiency(load, now)
+	event.Status = s.status
+	event.EventType = s.eventType(event, now)
+	return event
+}
+
+// resource devuelve la fracción de la capacidad disponible según el tipo y completa la métrica del tipo
+// En almacenamiento es positiva al descargar y negativa al cargar
+func (s *simulatedPlant) resource(now time.Time, elapsed time.Duration, event *EnergyMonitoringEvent) float64 {
+	switch s.plant.PlantType {
+	case entities.PlantTypeSolar:
+		irradiance := s.irradiance(now)
+		event.Irradiance = &irradiance
+		return math.Min(irradiance/1000*0.95, 1)
+
+	case entities.PlantTypeWind:
+		speed := s.windSpeed(now)
+		event.WindSpeed = &speed
+		return windPowerCurve(speed)
+
+	case entities.PlantTypeHydro:
+		// El caudal entrante varía lentamente; la salida turbinada vacía el embalse según la carga
+		inflow := 0.45 + 0.15*s.wave(now, 0, 31*time.Hour)
+		outflow := 0.6 * s.output / s.capacity()
+		s.reservoir = math.Min(math.Max(s.reservoir+(inflow-outflow)*elapsed.Hours(), 35), 98)
+		level := s.reservoir
+		event.ReservoirLevel = &level
+
+		dispatch := 0.7 + 0.08*s.wave(now, 1, 5*time.Hour) + 0.04*s.wave(now, 2, 47*time.Minute)
+		switch {
+		case s.reservoir < 40:
+			dispatch = 0
+		case s.reservoir < 50:
+			dispatch *= (s.reservoir - 40) / 10 // Reserva el agua cuando el embalse baja
+		case s.reservoir > 94:
+			dispatch = 0.95 // Turbina al máximo antes de verter
+		}
+		return dispatch
+
+	case entities.PlantTypeStorage:
+		charge := s.charge
+		event.StateOfCharge = &charge
+		hour := hourOfDay(now)
+		switch {
+		case hour >= 10 && hour < 15 && s.charge < storageMaxCharge:
+			return -storageChargeRate // Carga con el excedente solar del mediodía
+		case hour >= 17 && hour < 22 && s.charge > storageMinCharge:
+			return storageChargeRate // Descarga en el pico de la tarde
+		}
+		return 0
+
+	default:
+		return 0.7 + 0.1*s.wave(now, 0, 3*time.Hour) + 0.05*s.wave(now, 1, 37*time.Minute)
+	}
+}
+
+// irradiance calcula la irradiancia con la curva diurna del día del año y la atenuación por nubes
+func (s *simulatedPlant) irradiance(now time.Time) float64 {
+	season := math.Sin(2 * math.Pi * float64(now.YearDay()-80) / 365)
+	daylight := 12 + 3*season
+	sunrise := 12 - daylight/2
+	hour := hourOfDay(now)
+	if hour <= sunrise || hour >= sunrise+daylight {
+		return 0
+	}
+	elevation := math.Sin(math.Pi * (hour - sunrise) / daylight)
+	clearSky := 1000 * (0.85 + 0.15*season) * math.Pow(elevation, 1.2)
+
+	cloud := 0.35 + 0.3*s.wave(now, 0, 83*time.Minute) + 0.2*s.wave(now, 1, 23*time.Minute) + 0.1*s.wave(now, 2, 9*time.Minute)
+	cloud = math.Min(math.Max(cloud, 0), 1)
+	return clearSky * (1 - 0.75*cloud)
+}
+
+// windSpeed calcula la velocidad del viento: una señal gaussiana suave pasada por la
+// inversa de la distribución de Weibull (valores Weibull con correlación en el tiempo)
+func (s *simulatedPlant) windSpeed(now time.Time) float64 {
+	periods := []time.Duration{25 * time.Minute, 40 * time.Minute, time.Hour, 90 * time.Minute, 150 * time.Minute, 4 * time.Hour}
+	z := 0.0
+	for i, period := range periods {
+		z += s.wave(now, i, period)
+	}
+	z *= math.Sqrt(2.0 / float64(len(periods))) // Varianza 1
+	u := 0.5 * (1 + math.Erf(z/math.Sqrt2))
+	u = math.Min(u, 1-1e-9)
+	return windWeibullScale * math.Pow(-math.Log(1-u), 1/windWeibullShape)
+}
+
+// windPowerCurve devuelve la fracción de la potencia nominal para la velocidad del viento
+func windPowerCurve(speed float64) float64 {
+	switch {
+	case speed < windCutIn || speed >= windCutOut:
+		return 0
+	case speed >= windRated:
+		return 1
+	}
+	return (math.Pow(speed, 3) - math.Pow(windCutIn, 3)) / (math.Pow(windRated, 3) - math.Pow(windCutIn, 3))
+}
+
+// advanceStatus aplica la máquina de estados y devuelve si el estado cambió
+//
+// REGLAS:
+// - Sin recurso (noche, viento fuera de rango, embalse bajo, almacenamiento en reposo) la
+// planta pasa a standby y vuelve a operational cuando el recurso aparece (con histéresis
+// para no oscilar en el umbral)
+// - Las plantas despachables (hidro, other) entran en peak_load por demanda; las solares y
+// eólicas solo con recurso alto (>= 0.85) y vuelven a operational cuando baja de 0.8
+// - El resto de los cambios son espontáneos según simulatedDwell; el mantenimiento es poco frecuente
+// - Solo se usan transiciones permitidas (entities.StatusTransitionAllowed)
+func (s *simulatedPlant) advanceStatus(resource float64, elapsed time.Duration, first bool, rng *rand.Rand) bool {
+	previous := s.status
+
+	switch {
+	case first:
+		if math.Abs(resource) <= 0.05 {
+			s.status = entities.PlantStatusStandby
+		}
+	case s.status == entities.PlantStatusMaintenance:
+		if s.leaves(elapsed, rng) {
+			s.status = entities.PlantStatusStandby
+		}
+	case s.producing() && math.Abs(resource) < 0.01:
+		s.status = entities.PlantStatusStandby
+	case s.status == entities.PlantStatusStandby && math.Abs(resource) > 0.05:
+		s.status = entities.PlantStatusOperational
+	case s.status == entities.PlantStatusPeakLoad && !s.dispatchable() && resource < 0.8:
+		s.status = entities.PlantStatusOperational
+	case s.leaves(elapsed, rng):
+		s.status = s.spontaneousStatus(resource, rng)
+	}
+
+	if !entities.StatusTransitionAllowed(previous, s.status) {
+		s.status = previous
+	}
+	return s.status != previous
+}
+
+// leaves indica si la planta sale de su estado en el tiempo transcurrido (proceso de Poisson)
+func (s *simulatedPlant) leaves(elapsed time.Duration, rng *rand.Rand) bool {
+	dwell := simulatedDwell[s.status]
+	if dwell <= 0 || elapsed <= 0 {
+		return false
+	}
+	return rng.Float64() < 1-math.Exp(-elapsed.Seconds()/dwell.Seconds())
+}
+
+// spontaneousStatus elige el próximo estado entre el actual y las transiciones permitidas
+// standby no se elige: depende solo del recurso
+func (s *simulatedPlant) spontaneousStatus(resource float64, rng *rand.Rand) string {
+	weights := map[string]float64{
+		entities.PlantStatusOperational: 1,
+		entities.PlantStatusMaintenance: 0.05,
+	}
+	switch {
+	case resource >= 0.85:
+		weights[entities.PlantStatusPeakLoad] = 1
+	case s.dispatchable():
+		weights[entities.PlantStatusPeakLoad] = 0.3
+	}
+
+	var candidates []string
+	total := 0.0
+	for _, status := range entities.PlantStatuses {
+		allowed := status == s.status || entities.StatusTransitionAllowed(s.status, status)
+		if weights[status] > 0 && allowed {
+			candidates = append(candidates, status)
+			total += weights[status]
+		}
+	}
+	if len(candidates) == 0 {
+		return s.status
+	}
+	pick := rng.Float64() * total
+	for _, status := range candidates {
+		pick -= weights[status]
+		if pick < 0 {
+			return status
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+// producing indica si el estado permite entregar o tomar potencia
+func (s *simulatedPlant) producing() bool {
+	return s.status == entities.PlantStatusOperational || s.status == entities.PlantStatusPeakLoad
+}
+
+// dispatchable indica si la planta regula su producción (hidro, other) en lugar de seguir al recurso
+func (s *simulatedPlant) dispatchable() bool {
+	return s.plant.PlantType == entities.PlantTypeHydro || s.plant.PlantType == entities.PlantTypeOther
+}
+
+// targetLoad devuelve la fracción de la capacidad a entregar según el estado y el recurso
+func (s *simulatedPlant) targetLoad(resource float64) float64 {
+	if !s.producing() || resource <= 0 {
+		return 0
+	}
+	if s.status == entities.PlantStatusPeakLoad && s.dispatchable() {
+		return 0.97
+	}
+	return math.Min(resource, 1)
+}
+
+// updateCharge actualiza el estado de carga con la potencia cargada y descargada
+func (s *simulatedPlant) updateCharge(elapsed time.Duration) {
+	energy := s.capacity() * storageDurationHours
+	delta := (s.charging*0.95 - s.output/0.95) * elapsed.Hours() / energy * 100
+	s.charge = math.Min(math.Max(s.charge+delta, 0), 100)
+}
+
+// auxiliary devuelve el consumo propio de la planta (fracción de la capacidad)
+func (s *simulatedPlant) auxiliary(load float64) float64 {
+	switch s.status {
+	case entities.PlantStatusMaintenance:
+		return 0.01
+	case entities.PlantStatusStandby:
+		return 0.005
+	}
+	return 0.008 + 0.012*load
+}
+
+// efficiency devuelve la eficiencia de conversión: nominal del tipo, menos la penalización
+// por carga parcial y por temperatura (0 si la planta no entrega ni toma potencia)
+func (s *simulatedPlant) efficiency(load float64, now time.Time) float64 {
+	if load <= 0.001 {
+		return 0
+	}
+	nominal := map[string]float64{
+		entities.PlantTypeSolar:   97,
+		entities.PlantTypeWind:    94,
+		entities.PlantTypeHydro:   91,
+		entities.PlantTypeStorage: 90,
+	}[s.plant.PlantType]
+	if nominal == 0 {
+		nominal = 88
+	}
+	value := nominal - 10*math.Pow(1-math.Min(load, 1), 2) - 0.2*math.Max(s.temperature-45, 0) + 0.3*s.wave(now, 6, 11*time.Minute)
+	return math.Min(math.Max(value, 0), 100)
+}
+
+// eventType clasifica la lectura: cambio de estado, condición anómala, reporte horario o lectura de potencia
+func (s *simulatedPlant) eventType(event EnergyMonitoringEvent, now time.Time) string {
+	switch {
+	case s.statusChanged:
+		return "status_update"
+	case event.Temperature >= simulatedAlertTemperature || (event.Efficiency > 0 && event.Efficiency < 75):
+		return "alert"
+	case now.Sub(s.lastReport) >= simulatedReportInterval:
+		s.lastReport = now
+		return "efficiency_report"
+	}
+	return "power_reading"
+}
+
+// wave es una senoide de amplitud 1 con la fase i de la planta
+func (s *simulatedPlant) wave(now time.Time, i int, period time.Duration) float64 {
+	t := float64(now.UnixNano()) / float64(time.Second)
+	return math.Sin(2*math.Pi*t/period.Seconds() + s.phases[i%len(s.phases)])
+}
+
+// ambientTemperature es la temperatura ambiente del día (mínima a las 3, máxima a las 15 UTC)
+func ambientTemperature(now time.Time) float64 {
+	return 17 + 7*math.Sin(2*math.Pi*(hourOfDay(now)-9)/24)
+}
+
+// heatingPerLoad es el calentamiento de los equipos a plena carga sobre el ambiente (°C)
+func heatingPerLoad(plantType string) float64 {
+	switch plantType {
+	case entities.PlantTypeSolar:
+		return 30
+	case entities.PlantTypeWind:
+		return 40
+	case entities.PlantTypeHydro:
+		return 25
+	case entities.PlantTypeStorage:
+		return 20
+	}
+	return 35
+}
+
+// hourOfDay devuelve la hora UTC con fracción (0-24)
+func hourOfDay(now time.Time) float64 {
+	now = now.UTC()
+	return float64(now.Hour()) + float64(now.Minute())/60 + float64(now.Second())/3600
+}
+
+// moveToward acerca value a target en como máximo maxChange
+func moveToward(value, target, maxChange float64) float64 {
+	if math.Abs(target-value) <= maxChange {
+		return target
+	}
+	if target > value {
+		return value + maxChange
+	}
+	return value - maxChange
+}
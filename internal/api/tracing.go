@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"monitoring-energy-service/internal/infrastructure/logging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer es el tracer de OpenTelemetry de la capa de aplicación
+// Usa el TracerProvider global configurado en conf/tracing (no-op si el tracing está deshabilitado)
+var tracer = otel.Tracer("monitoring-energy-service/internal/api")
+
+// traceMetadata devuelve el trace_id y span_id del contexto como JSON para EventEntity.Metadata
+// Permite buscar en el backend de trazas el recorrido completo de una lectura guardada
+func traceMetadata(ctx context.Context) map[string]string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return map[string]string{}
+	}
+	return map[string]string{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+	}
+}
+
+// eventMetadata combina los identificadores de traza con el correlation ID del mensaje
+func eventMetadata(ctx context.Context) map[string]string {
+	metadata := traceMetadata(ctx)
+	if id := logging.CorrelationID(ctx); id != "" {
+		metadata["correlation_id"] = id
+	}
+	return metadata
+}
+
+// withEventMetadata restaura en el contexto la traza y el correlation ID guardados en Metadata
+// (ej: al liberar un mensaje de la cuarentena); los valores faltantes o inválidos se ignoran
+func withEventMetadata(ctx context.Context, raw string) context.Context {
+	if raw == "" {
+		return ctx
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+		return ctx
+	}
+
+	if id := metadata["correlation_id"]; id != "" {
+		ctx = logging.WithCorrelationID(ctx, id)
+	}
+	traceID, traceErr := trace.TraceIDFromHex(metadata["trace_id"])
+	spanID, spanErr := trace.SpanIDFromHex(metadata["span_id"])
+	if traceErr == nil && spanErr == nil {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		}))
+	}
+	return ctx
+}
+
+// marshalMetadata serializa los metadatos de un evento; devuelve "" si no hay nada que guardar
+func marshalMetadata(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
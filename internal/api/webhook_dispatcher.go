@@ -0,0 +1,606 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/input"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+// Resultados de entrega que se registran como métrica (label "outcome")
+const (
+	WebhookOutcomeDelivered = "delivered"
+	WebhookOutcomeRetried   = "retried"
+	WebhookOutcomeFailed    = "failed"
+	WebhookOutcomeDropped   = "dropped"
+)
+
+// ErrDeliveryQueueFull se devuelve cuando la cola de entregas está llena
+var ErrDeliveryQueueFull = errors.New("webhook delivery queue is full")
+
+// WebhookDispatcherConfig configuración del WebhookDispatcher (variables WEBHOOK_*)
+type WebhookDispatcherConfig struct {
+	Enabled        bool          // WEBHOOK_ENABLED: entrega eventos y alertas a URL
+	URL            string        // WEBHOOK_URL
+	Secret         string        // WEBHOOK_SECRET: clave HMAC-SHA256 de la firma (vacía = WEBHOOK_URL deshabilitada)
+	EventTypes     []string      // WEBHOOK_EVENT_TYPES: tipos a entregar (vacío = todos)
+	QueueSize      int           // WEBHOOK_QUEUE_SIZE: entregas en memoria antes de descartar
+	Workers        int           // WEBHOOK_WORKERS: entregas concurrentes
+	MaxAttempts    int           // WEBHOOK_MAX_ATTEMPTS: intentos por entrega
+	InitialBackoff time.Duration // WEBHOOK_INITIAL_BACKOFF: espera antes del 2do intento
+	MaxBackoff     time.Duration // WEBHOOK_MAX_BACKOFF: tope de la espera exponencial
+	MaxFailures    int           // WEBHOOK_SUBSCRIPTION_MAX_FAILURES: fallos consecutivos antes de deshabilitar (0 = nunca)
+}
+
+// WebhookNotification es el cuerpo JSON que recibe el destino
+type WebhookNotification struct {
+	ID            uuid.UUID `json:"id"`   // ID de la entrega; permite deduplicar reintentos
+	Type          string    `json:"type"` // event_type del evento o alert.firing / alert.resolved
+	OccurredAt    time.Time `json:"occurred_at"`
+	PlantSourceId uuid.UUID `json:"plant_source_id"`
+	Data          any       `json:"data"`
+}
+
+// WebhookDispatcher entrega eventos aceptados y alertas al webhook global y a las suscripciones
+//
+// PROPÓSITO:
+// El intake y el AlertEngine solo arman la entrega y la encolan (sin bloquear);
+// los workers la guardan y hacen el POST firmado con reintentos, así ni un webhook
+// lento ni el insert en webhook_deliveries frenan el consumo de Kafka.
+//
+// FUNCIONAMIENTO:
+// 1. publish arma una entrega (pending) por destino y la encola; si la cola está llena se guarda failed ("dropped")
+//   - WEBHOOK_URL (si está habilitado y pasa WEBHOOK_EVENT_TYPES), firmado con WEBHOOK_SECRET
+//   - Cada suscripción activa cuyos filtros aceptan el tipo y la planta, firmado con su secreto
+//
+// 2. Cada worker guarda la entrega nueva y la envía hasta MaxAttempts veces con backoff exponencial (+-20% jitter)
+// 3. Cada intento se registra con su código de respuesta; los 4xx (salvo 408 y 429) no se reintentan
+// 4. Al arrancar se reencolan las entregas que quedaron pending, esperando lugar en la cola
+// (nunca se marcan failed por cola llena). Stop guarda como pending las entregas nuevas
+// que seguían en la cola.
+// 5. Redeliver vuelve a encolar una entrega failed o delivered
+// 6. Cada entrega fallida de una suscripción suma un fallo consecutivo; al llegar a MaxFailures
+// la suscripción queda disabled. Una entrega exitosa reinicia el contador.
+//
+// Las suscripciones se cargan en memoria con la primera notificación; Reload se llama
+// al crearlas, editarlas o borrarlas desde la API REST.
+type WebhookDispatcher struct {
+	adapter       output.WebhookAdapterInterface
+	deliveries    output.WebhookDeliveryRepositoryInterface
+	subscriptions output.WebhookSubscriptionRepositoryInterface
+	metrics       output.MetricsRecorderInterface
+	logger        *slog.Logger
+	cfg           WebhookDispatcherConfig
+
+	mu     sync.RWMutex
+	loaded bool
+	subs   map[uuid.UUID]*entities.WebhookSubscriptionEntity
+
+	queue    chan queuedDelivery
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+var (
+	_ input.ReadingProcessor = &WebhookDispatcher{}
+	_ input.AlertListener    = &WebhookDispatcher{}
+)
+
+// NewWebhookDispatcher crea el dispatcher; los workers se inician con Start
+func NewWebhookDispatcher(
+	adapter output.WebhookAdapterInterface,
+	deliveries output.WebhookDeliveryRepositoryInterface,
+	subscriptions output.WebhookSubscriptionRepositoryInterface,
+	metrics output.MetricsRecorderInterface,
+	logger *slog.Logger,
+	cfg WebhookDispatcherConfig,
+) *WebhookDispatcher {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1000
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+
+	return &WebhookDispatcher{
+		adapter:       adapter,
+		deliveries:    deliveries,
+		subscriptions: subscriptions,
+		metrics:       metrics,
+		logger:        logger,
+		cfg:           cfg,
+		subs:          make(map[uuid.UUID]*entities.WebhookSubscriptionEntity),
+		queue:         make(chan queuedDelivery, cfg.QueueSize),
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start inicia los workers y reencola las entregas pendientes de una ejecución anterior
+//
+// CAMBIO: Los workers arrancan antes de reencolar y las pendientes se envían a la cola con espera
+// RAZÓN: Con enqueue (sin bloquear) y sin workers, las pendientes que no cabían en WEBHOOK_QUEUE_SIZE
+// quedaban failed ("queue full") en cada arranque
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	if err := d.Reload(ctx); err != nil {
+		d.logger.ErrorContext(ctx, "Error loading webhook subscriptions", slog.Any("error", err))
+	}
+
+	for i := 0; i < d.cfg.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+
+	pending, err := d.deliveries.FindPending(ctx)
+	if err != nil {
+		d.logger.ErrorContext(ctx, "Error loading pending webhook deliveries", slog.Any("error", err))
+	}
+	if len(pending) > 0 {
+		d.wg.Add(1)
+		go d.requeue(ctx, pending)
+	}
+
+	d.logger.InfoContext(ctx, "Webhook dispatcher started",
+		slog.Int("workers", d.cfg.Workers), slog.Int("requeued", len(pending)))
+}
+
+// requeue envía las entregas pendientes a la cola esperando lugar; con Stop las restantes
+// siguen pending y se reencolan en el próximo arranque
+func (d *WebhookDispatcher) requeue(ctx context.Context, pending []*entities.WebhookDeliveryEntity) {
+	defer d.wg.Done()
+	for i, delivery := range pending {
+		select {
+		case d.queue <- queuedDelivery{delivery: delivery, saved: true}:
+		case <-d.stopChan:
+			d.logger.InfoContext(ctx, "Webhook dispatcher stopped while requeuing pending deliveries",
+				slog.Int("remaining", len(pending)-i))
+			return
+		}
+	}
+}
+
+// Stop detiene los workers; las entregas en curso quedan pending y se reencolan al arrancar
+// Las entregas nuevas que seguían en la cola se guardan pending para no perderlas
+func (d *WebhookDispatcher) Stop() {
+	close(d.stopChan)
+	d.wg.Wait()
+
+	ctx := context.Background()
+	for {
+		select {
+		case item := <-d.queue:
+			if !item.saved {
+				if _, err := d.deliveries.Create(ctx, item.delivery); err != nil {
+					d.logger.ErrorContext(ctx, "Error saving webhook delivery", slog.String("delivery_id", item.delivery.ID.String()), slog.Any("error", err))
+				}
+			}
+		default:
+			return
+		}
+	}
+}
+
+// Reload vuelve a cargar las suscripciones (todas, para resolver el secreto de las entregas
+// pendientes de suscripciones pausadas o deshabilitadas)
+func (d *WebhookDispatcher) Reload(ctx context.Context) error {
+	subscriptions, err := d.subscriptions.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading webhook subscriptions: %w", err)
+	}
+
+	subs := make(map[uuid.UUID]*entities.WebhookSubscriptionEntity, len(subscriptions))
+	for _, subscription := range subscriptions {
+		subs[subscription.ID] = subscription
+	}
+
+	d.mu.Lock()
+	d.subs = subs
+	d.loaded = true
+	d.mu.Unlock()
+	return nil
+}
+
+// ProcessReading entrega cada evento aceptado por el intake a los destinos cuyo filtro lo acepta
+func (d *WebhookDispatcher) ProcessReading(ctx context.Context, reading entities.PlantReading) error {
+	return d.publish(ctx, reading.EventType, reading.EventID, reading.PlantSourceId, reading.Timestamp, reading.Data)
+}
+
+// OnAlert entrega las alertas disparadas (alert.firing) y resueltas (alert.resolved)
+func (d *WebhookDispatcher) OnAlert(ctx context.Context, alert entities.AlertEntity) error {
+	eventType := entities.NotificationAlertFiring
+	occurredAt := alert.FiredAt
+	if alert.Status == entities.AlertStatusResolved {
+		eventType = entities.NotificationAlertResolved
+		if alert.ResolvedAt != nil {
+			occurredAt = *alert.ResolvedAt
+		}
+	}
+
+	return d.publish(ctx, eventType, alert.ID, alert.PlantSourceId, occurredAt, alert)
+}
+
+// Redeliver vuelve a encolar una entrega; los intentos siguen numerándose después de los anteriores
+func (d *WebhookDispatcher) Redeliver(ctx context.Context, id uuid.UUID) (*entities.WebhookDeliveryEntity, error) {
+	delivery, err := d.deliveries.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == entities.DeliveryStatusPending {
+		return nil, fmt.Errorf("%w: delivery %s is already pending", domainerrors.ErrConflict, id)
+	}
+
+	delivery.Attempts = nil
+	delivery.Status = entities.DeliveryStatusPending
+	delivery.LastError = ""
+	if _, err := d.deliveries.Update(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	if !d.enqueue(ctx, delivery) {
+		return nil, ErrDeliveryQueueFull
+	}
+	return delivery, nil
+}
+
+// SendTest encola una notificación webhook.test para la suscripción, sin importar sus filtros
+// ni su estado, para que el equipo verifique su endpoint y la firma
+func (d *WebhookDispatcher) SendTest(ctx context.Context, id uuid.UUID) (*entities.WebhookDeliveryEntity, error) {
+	subscription, err := d.subscriptions.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{
+		"subscription_id": subscription.ID,
+		"name":            subscription.Name,
+		"message":         "test event sent from monitoring-energy-service",
+	}
+	delivery, err := d.create(ctx, &subscription.ID, subscription.URL, entities.NotificationTest, subscription.ID, uuid.Nil, time.Now().UTC(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.enqueue(ctx, delivery) {
+		return nil, ErrDeliveryQueueFull
+	}
+	return delivery, nil
+}
+
+// accepts indica si el tipo de notificación pasa el filtro WEBHOOK_EVENT_TYPES
+// Sin WEBHOOK_SECRET el webhook global no recibe nada: las entregas saldrían sin firma
+func (d *WebhookDispatcher) accepts(eventType string) bool {
+	if !d.cfg.Enabled || d.cfg.URL == "" || d.cfg.Secret == "" {
+		return false
+	}
+	return len(d.cfg.EventTypes) == 0 || slices.Contains(d.cfg.EventTypes, eventType)
+}
+
+// webhookTarget es un destino de una notificación; subscriptionID nil = WEBHOOK_URL global
+type webhookTarget struct {
+	subscriptionID *uuid.UUID
+	url            string
+}
+
+// targets devuelve los destinos que aceptan la notificación
+func (d *WebhookDispatcher) targets(ctx context.Context, eventType string, plantID uuid.UUID) []webhookTarget {
+	d.mu.RLock()
+	loaded := d.loaded
+	d.mu.RUnlock()
+	if !loaded {
+		if err := d.Reload(ctx); err != nil {
+			d.logger.ErrorContext(ctx, "Error loading webhook subscriptions", slog.Any("error", err))
+		}
+	}
+
+	var targets []webhookTarget
+	if d.accepts(eventType) {
+		targets = append(targets, webhookTarget{url: d.cfg.URL})
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, subscription := range d.subs {
+		if subscription.Matches(eventType, plantID) {
+			targets = append(targets, webhookTarget{subscriptionID: &subscription.ID, url: subscription.URL})
+		}
+	}
+	return targets
+}
+
+// queuedDelivery es una entrega en la cola; saved = false si el worker todavía tiene que guardarla
+type queuedDelivery struct {
+	delivery *entities.WebhookDeliveryEntity
+	saved    bool
+}
+
+// publish arma una entrega por destino con su cuerpo final y la encola sin guardarla
+//
+// CAMBIO: El insert en webhook_deliveries lo hace el worker
+// RAZÓN: publish corre en el consumo de Kafka (intake y AlertEngine); un insert por
+// destino y por evento lo frenaba igual que un webhook lento
+func (d *WebhookDispatcher) publish(ctx context.Context, eventType string, resourceID, plantID uuid.UUID, occurredAt time.Time, data any) error {
+	for _, target := range d.targets(ctx, eventType, plantID) {
+		delivery, err := d.build(target.subscriptionID, target.url, eventType, resourceID, plantID, occurredAt, data)
+		if err != nil {
+			return err
+		}
+		d.push(ctx, queuedDelivery{delivery: delivery})
+	}
+	return nil
+}
+
+// create guarda la entrega pending con el cuerpo final que se firmará y enviará
+func (d *WebhookDispatcher) create(ctx context.Context, subscriptionID *uuid.UUID, url, eventType string, resourceID, plantID uuid.UUID, occurredAt time.Time, data any) (*entities.WebhookDeliveryEntity, error) {
+	delivery, err := d.build(subscriptionID, url, eventType, resourceID, plantID, occurredAt, data)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.deliveries.Create(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("error saving webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// build arma la entrega pending con el cuerpo final; CreatedAt es el momento de la notificación
+// aunque el worker la guarde después
+func (d *WebhookDispatcher) build(subscriptionID *uuid.UUID, url, eventType string, resourceID, plantID uuid.UUID, occurredAt time.Time, data any) (*entities.WebhookDeliveryEntity, error) {
+	delivery := &entities.WebhookDeliveryEntity{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		EventType:      eventType,
+		ResourceID:     resourceID,
+		PlantSourceId:  plantID,
+		URL:            url,
+		Status:         entities.DeliveryStatusPending,
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	payload, err := json.Marshal(WebhookNotification{
+		ID:            delivery.ID,
+		Type:          eventType,
+		OccurredAt:    occurredAt,
+		PlantSourceId: plantID,
+		Data:          data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling webhook notification: %w", err)
+	}
+	delivery.Payload = string(payload)
+	return delivery, nil
+}
+
+// enqueue agrega una entrega ya guardada a la cola sin bloquear; si está llena la marca failed
+func (d *WebhookDispatcher) enqueue(ctx context.Context, delivery *entities.WebhookDeliveryEntity) bool {
+	return d.push(ctx, queuedDelivery{delivery: delivery, saved: true})
+}
+
+// push agrega la entrega a la cola sin bloquear; si está llena la guarda failed
+func (d *WebhookDispatcher) push(ctx context.Context, item queuedDelivery) bool {
+	select {
+	case d.queue <- item:
+		return true
+	default:
+	}
+
+	delivery := item.delivery
+
+	d.metrics.WebhookDelivery(WebhookOutcomeDropped)
+	d.logger.WarnContext(ctx, "Webhook delivery queue full, delivery dropped",
+		slog.String("delivery_id", delivery.ID.String()), slog.String("event_type", delivery.EventType))
+
+	delivery.Status = entities.DeliveryStatusFailed
+	delivery.LastError = ErrDeliveryQueueFull.Error()
+	if err := d.store(ctx, item); err != nil {
+		d.logger.ErrorContext(ctx, "Error saving webhook delivery", slog.String("delivery_id", delivery.ID.String()), slog.Any("error", err))
+	}
+	return false
+}
+
+// store inserta la entrega si todavía no se guardó o actualiza su estado
+func (d *WebhookDispatcher) store(ctx context.Context, item queuedDelivery) error {
+	if item.saved {
+		_, err := d.deliveries.Update(ctx, item.delivery)
+		return err
+	}
+	_, err := d.deliveries.Create(ctx, item.delivery)
+	return err
+}
+
+func (d *WebhookDispatcher) worker() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.stopChan:
+			return
+		case item := <-d.queue:
+			d.deliver(item)
+		}
+	}
+}
+
+// deliver guarda la entrega nueva, la envía con reintentos y guarda cada intento
+func (d *WebhookDispatcher) deliver(item queuedDelivery) {
+	ctx := context.Background()
+	delivery := item.delivery
+	logger := d.logger.With(slog.String("delivery_id", delivery.ID.String()), slog.String("event_type", delivery.EventType))
+
+	if !item.saved {
+		if _, err := d.deliveries.Create(ctx, delivery); err != nil {
+			d.metrics.WebhookDelivery(WebhookOutcomeFailed)
+			logger.Error("Error saving webhook delivery, delivery dropped", slog.Any("error", err))
+			return
+		}
+	}
+
+	headers := map[string]string{
+		"X-Webhook-Id":    delivery.ID.String(),
+		"X-Webhook-Event": delivery.EventType,
+	}
+
+	secret, err := d.secret(ctx, delivery)
+	if err != nil {
+		delivery.Status = entities.DeliveryStatusFailed
+		delivery.LastError = err.Error()
+		d.save(ctx, logger, delivery)
+		d.metrics.WebhookDelivery(WebhookOutcomeFailed)
+		logger.Error("Webhook delivery failed", slog.String("error", delivery.LastError))
+		return
+	}
+
+	for attempt := 1; attempt <= d.cfg.MaxAttempts; attempt++ {
+		start := time.Now()
+		statusCode, err := d.adapter.SendPayload(ctx, delivery.URL, secret, headers, []byte(delivery.Payload))
+
+		delivery.AttemptCount++
+		record := &entities.WebhookAttemptEntity{
+			DeliveryID: delivery.ID,
+			Attempt:    delivery.AttemptCount,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		delivery.LastStatusCode = nil
+		if statusCode != 0 {
+			record.StatusCode = &statusCode
+			delivery.LastStatusCode = &statusCode
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+		if err := d.deliveries.CreateAttempt(ctx, record); err != nil {
+			logger.Error("Error saving webhook attempt", slog.Any("error", err))
+		}
+
+		if err == nil {
+			now := time.Now()
+			delivery.Status = entities.DeliveryStatusDelivered
+			delivery.DeliveredAt = &now
+			delivery.LastError = ""
+			d.save(ctx, logger, delivery)
+			d.recordResult(ctx, logger, delivery)
+			d.metrics.WebhookDelivery(WebhookOutcomeDelivered)
+			logger.Debug("Webhook delivered", slog.Int("attempt", delivery.AttemptCount), slog.Int("status_code", statusCode))
+			return
+		}
+
+		delivery.LastError = err.Error()
+		if !retryable(statusCode) || attempt == d.cfg.MaxAttempts {
+			break
+		}
+
+		d.save(ctx, logger, delivery)
+		d.metrics.WebhookDelivery(WebhookOutcomeRetried)
+		wait := d.backoff(attempt)
+		logger.Warn("Webhook delivery failed, retrying",
+			slog.Int("attempt", delivery.AttemptCount), slog.Duration("backoff", wait), slog.Any("error", err))
+
+		select {
+		case <-time.After(wait):
+		case <-d.stopChan:
+			// Queda pending y se reencola en el próximo arranque
+			return
+		}
+	}
+
+	delivery.Status = entities.DeliveryStatusFailed
+	d.save(ctx, logger, delivery)
+	d.recordResult(ctx, logger, delivery)
+	d.metrics.WebhookDelivery(WebhookOutcomeFailed)
+	logger.Error("Webhook delivery failed", slog.Int("attempts", delivery.AttemptCount), slog.String("error", delivery.LastError))
+}
+
+// secret devuelve la clave de firma: WEBHOOK_SECRET o el secreto de la suscripción
+func (d *WebhookDispatcher) secret(ctx context.Context, delivery *entities.WebhookDeliveryEntity) (string, error) {
+	if delivery.SubscriptionID == nil {
+		if d.cfg.Secret == "" {
+			return "", errors.New("WEBHOOK_SECRET is empty, refusing to send an unsigned delivery")
+		}
+		return d.cfg.Secret, nil
+	}
+
+	d.mu.RLock()
+	subscription, ok := d.subs[*delivery.SubscriptionID]
+	d.mu.RUnlock()
+	if ok {
+		return subscription.Secret, nil
+	}
+
+	// Suscripción creada después del último Reload (o ya borrada)
+	subscription, err := d.subscriptions.FindByID(ctx, *delivery.SubscriptionID)
+	if err != nil {
+		return "", fmt.Errorf("webhook subscription %s: %w", *delivery.SubscriptionID, err)
+	}
+	return subscription.Secret, nil
+}
+
+// recordResult actualiza los fallos consecutivos de la suscripción de la entrega y la
+// deshabilita al llegar a MaxFailures
+func (d *WebhookDispatcher) recordResult(ctx context.Context, logger *slog.Logger, delivery *entities.WebhookDeliveryEntity) {
+	if delivery.SubscriptionID == nil {
+		return
+	}
+	id := *delivery.SubscriptionID
+
+	if delivery.Status == entities.DeliveryStatusDelivered {
+		if err := d.subscriptions.RecordSuccess(ctx, id); err != nil {
+			logger.Error("Error updating webhook subscription", slog.String("subscription_id", id.String()), slog.Any("error", err))
+		}
+		return
+	}
+
+	reason := fmt.Sprintf("disabled after %d consecutive failed deliveries, last error: %s", d.cfg.MaxFailures, delivery.LastError)
+	subscription, err := d.subscriptions.RecordFailure(ctx, id, d.cfg.MaxFailures, reason)
+	if err != nil {
+		logger.Error("Error updating webhook subscription", slog.String("subscription_id", id.String()), slog.Any("error", err))
+		return
+	}
+
+	d.mu.Lock()
+	previous, ok := d.subs[id]
+	d.subs[id] = subscription
+	d.mu.Unlock()
+
+	if subscription.Status == entities.SubscriptionStatusDisabled && (!ok || previous.Status != entities.SubscriptionStatusDisabled) {
+		logger.Warn("Webhook subscription disabled",
+			slog.String("subscription_id", id.String()),
+			slog.String("name", subscription.Name),
+			slog.Int("consecutive_failures", subscription.ConsecutiveFailures))
+	}
+}
+
+func (d *WebhookDispatcher) save(ctx context.Context, logger *slog.Logger, delivery *entities.WebhookDeliveryEntity) {
+	if _, err := d.deliveries.Update(ctx, delivery); err != nil {
+		logger.Error("Error updating webhook delivery", slog.Any("error", err))
+	}
+}
+
+// backoff devuelve la espera antes del siguiente intento: initial * 2^(attempt-1), con tope y jitter
+func (d *WebhookDispatcher) backoff(attempt int) time.Duration {
+	wait := d.cfg.InitialBackoff << (attempt - 1)
+	if wait <= 0 || wait > d.cfg.MaxBackoff {
+		wait = d.cfg.MaxBackoff
+	}
+	jitter := 0.8 + rand.Float64()*0.4
+	return time.Duration(float64(wait) * jitter)
+}
+
+// retryable indica si vale la pena reintentar: errores de red, 5xx, 408 y 429
+func retryable(statusCode int) bool {
+	if statusCode == 0 || statusCode >= 500 {
+		return true
+	}
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+)
+
+func TestWebhookDispatcherAccepts(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       WebhookDispatcherConfig
+		eventType string
+		want      bool
+	}{
+		{name: "disabled", cfg: WebhookDispatcherConfig{URL: "http://hook", Secret: "s"}, eventType: entities.NotificationAlertFiring, want: false},
+		{name: "without url", cfg: WebhookDispatcherConfig{Enabled: true, Secret: "s"}, eventType: entities.NotificationAlertFiring, want: false},
+		{name: "without secret", cfg: WebhookDispatcherConfig{Enabled: true, URL: "http://hook"}, eventType: entities.NotificationAlertFiring, want: false},
+		{name: "all event types", cfg: WebhookDispatcherConfig{Enabled: true, URL: "http://hook", Secret: "s"}, eventType: entities.NotificationAlertFiring, want: true},
+		{
+			name:      "filtered out event type",
+			cfg:       WebhookDispatcherConfig{Enabled: true, URL: "http://hook", Secret: "s", EventTypes: []string{entities.NotificationAlertResolved}},
+			eventType: entities.NotificationAlertFiring,
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := &WebhookDispatcher{cfg: tt.cfg}
+			if got := dispatcher.accepts(tt.eventType); got != tt.want {
+				t.Errorf("accepts(%q) = %t, want %t", tt.eventType, got, tt.want)
+			}
+		})
+	}
+}
+
+// webhookDeliveriesFake registra las operaciones sobre webhook_deliveries como "<op>:<status>"
+type webhookDeliveriesFake struct {
+	output.WebhookDeliveryRepositoryInterface
+	mu  sync.Mutex
+	ops []string
+}
+
+func (f *webhookDeliveriesFake) record(op string, delivery *entities.WebhookDeliveryEntity) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ops = append(f.ops, op+":"+delivery.Status)
+}
+
+func (f *webhookDeliveriesFake) Create(_ context.Context, delivery *entities.WebhookDeliveryEntity) (*entities.WebhookDeliveryEntity, error) {
+	f.record("create", delivery)
+	return delivery, nil
+}
+
+func (f *webhookDeliveriesFake) Update(_ context.Context, delivery *entities.WebhookDeliveryEntity) (*entities.WebhookDeliveryEntity, error) {
+	f.record("update", delivery)
+	return delivery, nil
+}
+
+func (f *webhookDeliveriesFake) CreateAttempt(context.Context, *entities.WebhookAttemptEntity) error {
+	return nil
+}
+
+// webhookSubscriptionsFake no tiene suscripciones
+type webhookSubscriptionsFake struct {
+	output.WebhookSubscriptionRepositoryInterface
+}
+
+func (webhookSubscriptionsFake) FindAll(context.Context) ([]*entities.WebhookSubscriptionEntity, error) {
+	return nil, nil
+}
+
+// webhookAdapterFake responde 200 y guarda el secreto de cada envío
+type webhookAdapterFake struct {
+	secrets []string
+}
+
+func (f *webhookAdapterFake) SendPayload(_ context.Context, _ string, secret string, _ map[string]string, _ []byte) (int, error) {
+	f.secrets = append(f.secrets, secret)
+	return 200, nil
+}
+
+func TestWebhookDispatcherPublishSavesFromTheWorker(t *testing.T) {
+	deliveries := &webhookDeliveriesFake{}
+	adapter := &webhookAdapterFake{}
+	dispatcher := NewWebhookDispatcher(adapter, deliveries, webhookSubscriptionsFake{}, nopMetrics{}, discardLogger(),
+		WebhookDispatcherConfig{Enabled: true, URL: "http://hook", Secret: "s", QueueSize: 1, MaxAttempts: 1})
+	ctx := context.Background()
+	reading := entities.PlantReading{EventID: uuid.New(), PlantSourceId: uuid.New(), EventType: "status_update", Timestamp: time.Now()}
+
+	// publish no toca la base: el único insert en el consumo es el de la entrega que no entra en la cola
+	for i := 0; i < 2; i++ {
+		if err := dispatcher.ProcessReading(ctx, reading); err != nil {
+			t.Fatalf("ProcessReading(#%d) error = %v", i, err)
+		}
+	}
+	if want := []string{"create:failed"}; fmt.Sprint(deliveries.ops) != fmt.Sprint(want) {
+		t.Fatalf("after publish ops = %v, want %v", deliveries.ops, want)
+	}
+
+	// El worker guarda la entrega antes de enviarla
+	dispatcher.deliver(<-dispatcher.queue)
+	if want := []string{"create:failed", "create:pending", "update:delivered"}; fmt.Sprint(deliveries.ops) != fmt.Sprint(want) {
+		t.Fatalf("after deliver ops = %v, want %v", deliveries.ops, want)
+	}
+	if fmt.Sprint(adapter.secrets) != "[s]" {
+		t.Errorf("sent with secrets %v, want [s]", adapter.secrets)
+	}
+}
+
+func TestWebhookDispatcherStopSavesQueuedDeliveries(t *testing.T) {
+	deliveries := &webhookDeliveriesFake{}
+	dispatcher := NewWebhookDispatcher(&webhookAdapterFake{}, deliveries, webhookSubscriptionsFake{}, nopMetrics{}, discardLogger(),
+		WebhookDispatcherConfig{Enabled: true, URL: "http://hook", Secret: "s", QueueSize: 10})
+	ctx := context.Background()
+
+	saved := &entities.WebhookDeliveryEntity{ID: uuid.New(), Status: entities.DeliveryStatusPending}
+	dispatcher.enqueue(ctx, saved) // Redeliver: ya está en la base
+	reading := entities.PlantReading{EventID: uuid.New(), PlantSourceId: uuid.New(), EventType: "status_update", Timestamp: time.Now()}
+	if err := dispatcher.ProcessReading(ctx, reading); err != nil {
+		t.Fatalf("ProcessReading() error = %v", err)
+	}
+
+	dispatcher.Stop()
+
+	if want := []string{"create:pending"}; fmt.Sprint(deliveries.ops) != fmt.Sprint(want) {
+		t.Fatalf("ops = %v, want %v (only the unsaved delivery is inserted)", deliveries.ops, want)
+	}
+}
@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Estados de una alerta
+const (
+	AlertStatusFiring   = "firing"
+	AlertStatusResolved = "resolved"
+)
+
+// Origen de una alerta (columna kind)
+const (
+	AlertKindThreshold = "threshold" // Regla de umbral evaluada por el AlertEngine
+	AlertKindOffline   = "offline"   // Planta sin reportar más allá de su umbral (ConnectivityWatcher)
+)
+
+// OfflineAlertRuleName es el nombre con el que se registran las alertas de planta offline
+const OfflineAlertRuleName = "Plant offline"
+
+// OfflineAlertMetric es la métrica de las alertas offline: segundos desde la última lectura recibida
+const OfflineAlertMetric = "silence_seconds"
+
+// AlertEntity representa una alerta disparada por una regla para una planta
+//
+// PROPÓSITO:
+// Se crea cuando la condición de la regla se mantiene durante su "for" y se actualiza
+// a "resolved" cuando el valor cruza el umbral de resolución (hysteresis).
+// Copia la condición de la regla para que el histórico no cambie si la regla se edita.
+//
+// Las alertas de kind offline no tienen regla (RuleID es el UUID nulo): Threshold es el
+// silencio máximo en segundos, Value el silencio al dispararse y EventID el último evento recibido.
+type AlertEntity struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Kind          string     `gorm:"type:varchar(20);not null;default:threshold;index:idx_alerts_kind" json:"kind" example:"threshold"`
+	RuleID        uuid.UUID  `gorm:"type:uuid;not null;index:idx_alerts_rule_id" json:"rule_id"`
+	RuleName      string     `gorm:"type:varchar(255);not null" json:"rule_name" example:"High temperature"`
+	PlantSourceId uuid.UUID  `gorm:"type:uuid;not null;index:idx_alerts_plant_source_id" json:"plant_source_id"`
+	Metric        string     `gorm:"type:varchar(100);not null" json:"metric" example:"temperature_celsius"`
+	Operator      string     `gorm:"type:varchar(2);not null" json:"operator" example:">"`
+	Threshold     float64    `gorm:"not null" json:"threshold" example:"45"`
+	Severity      string     `gorm:"type:varchar(20);not null" json:"severity" example:"warning"`
+	Status        string     `gorm:"type:varchar(20);not null;index:idx_alerts_status" json:"status" example:"firing"`
+	Value         float64    `gorm:"not null" json:"value" example:"47.3"`               // Valor que disparó la alerta
+	EventID       uuid.UUID  `gorm:"type:uuid;not null" json:"event_id"`                 // Evento que disparó la alerta
+	PendingSince  time.Time  `gorm:"not null" json:"pending_since"`                      // Primera lectura que cumplió la condición
+	FiredAt       time.Time  `gorm:"not null;index:idx_alerts_fired_at" json:"fired_at"` // Momento en que se cumplió el "for"
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`                              // Momento de resolución
+	ResolvedValue *float64   `json:"resolved_value,omitempty" example:"42.1"`            // Valor que resolvió la alerta
+	// Ventana de mantenimiento activa al dispararse (la severidad se bajó a info)
+	MaintenanceWindowID *uuid.UUID `gorm:"type:uuid" json:"maintenance_window_id,omitempty"`
+	CreatedAt           time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AlertEntity) TableName() string {
+	return "alerts"
+}
+
+// AlertFilter filtros opcionales para listar alertas
+type AlertFilter struct {
+	Status        string     // firing o resolved
+	PlantSourceId *uuid.UUID // Solo alertas de esta planta
+	RuleID        *uuid.UUID // Solo alertas de esta regla
+	Kind          string     // threshold u offline
+	Limit         int        // Máximo de resultados (0 = sin límite)
+}
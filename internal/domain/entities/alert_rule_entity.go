@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Operadores soportados por las reglas de alerta
+const (
+	OperatorGreater      = ">"
+	OperatorGreaterEqual = ">="
+	OperatorLess         = "<"
+	OperatorLessEqual    = "<="
+)
+
+// Alcance de una regla: todas las plantas, un tipo de planta o una planta concreta
+// El alcance por tipo usa la columna energy_plants.plant_type (migración plant-type)
+const (
+	RuleScopeAll       = "all"
+	RuleScopePlantType = "plant_type"
+	RuleScopePlant     = "plant"
+)
+
+// Severidades de alerta
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// AlertRuleEntity representa una regla de umbral evaluada sobre cada lectura del intake
+//
+// EJEMPLO: "temperature_celsius > 45 for 10m" con hysteresis 2
+// - Entra en pendiente cuando la temperatura supera 45
+// - Dispara si sigue por encima durante 10 minutos (según el timestamp de las lecturas)
+// - Se resuelve cuando baja de 43 (45 - hysteresis), evitando alertas intermitentes
+type AlertRuleEntity struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name       string    `gorm:"type:varchar(255);not null" json:"name" example:"High temperature"`
+	Metric     string    `gorm:"type:varchar(100);not null" json:"metric" example:"temperature_celsius"`
+	Operator   string    `gorm:"type:varchar(2);not null" json:"operator" example:">"`
+	Threshold  float64   `gorm:"not null" json:"threshold" example:"45"`
+	ForSeconds int64     `gorm:"not null;default:0" json:"for_seconds" example:"600"`
+	Hysteresis float64   `gorm:"not null;default:0" json:"hysteresis" example:"2"`
+	Severity   string    `gorm:"type:varchar(20);not null" json:"severity" example:"warning"`
+	ScopeType  string    `gorm:"type:varchar(20);not null" json:"scope_type" example:"all"`
+	ScopeValue string    `gorm:"type:varchar(255)" json:"scope_value,omitempty" example:"solar"`
+	Enabled    bool      `gorm:"not null" json:"enabled" example:"true"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AlertRuleEntity) TableName() string {
+	return "alert_rules"
+}
+
+// ForDuration devuelve cuánto debe mantenerse la condición antes de disparar
+func (r *AlertRuleEntity) ForDuration() time.Duration {
+	return time.Duration(r.ForSeconds) * time.Second
+}
+
+// Expression devuelve la regla en formato texto: "temperature_celsius > 45 for 10m0s"
+func (r *AlertRuleEntity) Expression() string {
+	expr := fmt.Sprintf("%s %s %s", r.Metric, r.Operator, strconv.FormatFloat(r.Threshold, 'f', -1, 64))
+	if r.ForSeconds > 0 {
+		expr += " for " + r.ForDuration().String()
+	}
+	return expr
+}
+
+// Matches indica si el valor cumple la condición de la regla
+func (r *AlertRuleEntity) Matches(value float64) bool {
+	switch r.Operator {
+	case OperatorGreater:
+		return value > r.Threshold
+	case OperatorGreaterEqual:
+		return value >= r.Threshold
+	case OperatorLess:
+		return value < r.Threshold
+	case OperatorLessEqual:
+		return value <= r.Threshold
+	}
+	return false
+}
+
+// Cleared indica si una alerta disparada puede resolverse con este valor
+// El umbral de resolución se desplaza "hysteresis" unidades en sentido contrario a la condición
+func (r *AlertRuleEntity) Cleared(value float64) bool {
+	if r.Hysteresis == 0 {
+		return !r.Matches(value)
+	}
+	switch r.Operator {
+	case OperatorGreater, OperatorGreaterEqual:
+		return value < r.Threshold-r.Hysteresis
+	case OperatorLess, OperatorLessEqual:
+		return value > r.Threshold+r.Hysteresis
+	}
+	return true
+}
+
+// AppliesTo indica si la regla aplica a la planta según su alcance
+func (r *AlertRuleEntity) AppliesTo(plantID uuid.UUID, plantType string) bool {
+	switch r.ScopeType {
+	case RuleScopeAll:
+		return true
+	case RuleScopePlantType:
+		return r.ScopeValue == plantType
+	case RuleScopePlant:
+		return r.ScopeValue == plantID.String()
+	}
+	return false
+}
+
+// Validate verifica que la regla sea coherente antes de guardarla
+func (r *AlertRuleEntity) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: name is required", domainerrors.ErrInvalidInput)
+	}
+	if !slices.Contains(ReadingMetrics, r.Metric) {
+		return fmt.Errorf("%w: unknown metric %q (expected one of %s)",
+			domainerrors.ErrInvalidInput, r.Metric, strings.Join(ReadingMetrics, ", "))
+	}
+	if !slices.Contains([]string{OperatorGreater, OperatorGreaterEqual, OperatorLess, OperatorLessEqual}, r.Operator) {
+		return fmt.Errorf("%w: unknown operator %q", domainerrors.ErrInvalidInput, r.Operator)
+	}
+	if r.ForSeconds < 0 || r.Hysteresis < 0 {
+		return fmt.Errorf("%w: for duration and hysteresis cannot be negative", domainerrors.ErrInvalidInput)
+	}
+	if !slices.Contains([]string{SeverityInfo, SeverityWarning, SeverityCritical}, r.Severity) {
+		return fmt.Errorf("%w: unknown severity %q (expected info, warning or critical)", domainerrors.ErrInvalidInput, r.Severity)
+	}
+
+	switch r.ScopeType {
+	case RuleScopeAll:
+		r.ScopeValue = ""
+	case RuleScopePlantType:
+		if !slices.Contains(PlantTypes, r.ScopeValue) {
+			return fmt.Errorf("%w: unknown plant type %q (expected one of %s)",
+				domainerrors.ErrInvalidInput, r.ScopeValue, strings.Join(PlantTypes, ", "))
+		}
+	case RuleScopePlant:
+		if _, err := uuid.Parse(r.ScopeValue); err != nil {
+			return fmt.Errorf("%w: scope_value must be a plant UUID", domainerrors.ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scope %q (expected all, plant_type or plant)", domainerrors.ErrInvalidInput, r.ScopeType)
+	}
+	return nil
+}
+
+// ParseAlertExpression interpreta una regla escrita como "<metric> <op> <threshold> [for <duration>]"
+//
+// EJEMPLOS:
+// - "temperature_celsius > 45 for 10m"
+// - "efficiency_percent < 78"
+func ParseAlertExpression(expr string) (metric string, operator string, threshold float64, forDuration time.Duration, err error) {
+	parts := strings.Fields(expr)
+	if len(parts) != 3 && len(parts) != 5 {
+		return "", "", 0, 0, fmt.Errorf("%w: expression must look like \"temperature_celsius > 45 for 10m\"", domainerrors.ErrInvalidInput)
+	}
+
+	metric, operator = parts[0], parts[1]
+	threshold, err = strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return "", "", 0, 0, fmt.Errorf("%w: invalid threshold %q", domainerrors.ErrInvalidInput, parts[2])
+	}
+
+	if len(parts) == 5 {
+		if parts[3] != "for" {
+			return "", "", 0, 0, fmt.Errorf("%w: expected \"for\" after the threshold", domainerrors.ErrInvalidInput)
+		}
+		forDuration, err = time.ParseDuration(parts[4])
+		if err != nil {
+			return "", "", 0, 0, fmt.Errorf("%w: invalid duration %q", domainerrors.ErrInvalidInput, parts[4])
+		}
+	}
+
+	return metric, operator, threshold, forDuration, nil
+}
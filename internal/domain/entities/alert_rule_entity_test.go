@@ -0,0 +1,54 @@
+package entities
+
+import "testing"
+
+func TestAlertRuleMatchesAndCleared(t *testing.T) {
+	tests := []struct {
+		name        string
+		operator    string
+		hysteresis  float64
+		value       float64
+		wantMatches bool
+		wantCleared bool
+	}{
+		// > 45 sin hysteresis: se resuelve apenas deja de cumplirse
+		{name: "greater above threshold", operator: OperatorGreater, value: 45.1, wantMatches: true, wantCleared: false},
+		{name: "greater at threshold", operator: OperatorGreater, value: 45, wantMatches: false, wantCleared: true},
+
+		// > 45 con hysteresis 2: solo se resuelve por debajo de 43
+		{name: "greater hysteresis above threshold", operator: OperatorGreater, hysteresis: 2, value: 46, wantMatches: true, wantCleared: false},
+		{name: "greater hysteresis inside the band", operator: OperatorGreater, hysteresis: 2, value: 44, wantMatches: false, wantCleared: false},
+		{name: "greater hysteresis at the resolve threshold", operator: OperatorGreater, hysteresis: 2, value: 43, wantMatches: false, wantCleared: false},
+		{name: "greater hysteresis below the resolve threshold", operator: OperatorGreater, hysteresis: 2, value: 42.9, wantMatches: false, wantCleared: true},
+
+		// >= 45
+		{name: "greater equal at threshold", operator: OperatorGreaterEqual, value: 45, wantMatches: true, wantCleared: false},
+		{name: "greater equal below threshold", operator: OperatorGreaterEqual, value: 44.9, wantMatches: false, wantCleared: true},
+		{name: "greater equal hysteresis inside the band", operator: OperatorGreaterEqual, hysteresis: 2, value: 43.5, wantMatches: false, wantCleared: false},
+
+		// < 45 con hysteresis 2: solo se resuelve por encima de 47
+		{name: "less below threshold", operator: OperatorLess, value: 44, wantMatches: true, wantCleared: false},
+		{name: "less at threshold", operator: OperatorLess, value: 45, wantMatches: false, wantCleared: true},
+		{name: "less hysteresis inside the band", operator: OperatorLess, hysteresis: 2, value: 46, wantMatches: false, wantCleared: false},
+		{name: "less hysteresis at the resolve threshold", operator: OperatorLess, hysteresis: 2, value: 47, wantMatches: false, wantCleared: false},
+		{name: "less hysteresis above the resolve threshold", operator: OperatorLess, hysteresis: 2, value: 47.1, wantMatches: false, wantCleared: true},
+
+		// <= 45
+		{name: "less equal at threshold", operator: OperatorLessEqual, value: 45, wantMatches: true, wantCleared: false},
+		{name: "less equal above threshold", operator: OperatorLessEqual, value: 45.1, wantMatches: false, wantCleared: true},
+
+		{name: "unknown operator never matches", operator: "==", value: 45, wantMatches: false, wantCleared: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &AlertRuleEntity{Operator: tt.operator, Threshold: 45, Hysteresis: tt.hysteresis}
+			if got := rule.Matches(tt.value); got != tt.wantMatches {
+				t.Errorf("Matches(%v) = %t, want %t", tt.value, got, tt.wantMatches)
+			}
+			if got := rule.Cleared(tt.value); got != tt.wantCleared {
+				t.Errorf("Cleared(%v) = %t, want %t", tt.value, got, tt.wantCleared)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dirección de una anomalía respecto de la línea base
+const (
+	AnomalyDirectionHigh = "high"
+	AnomalyDirectionLow  = "low"
+)
+
+// AnomalyEntity representa una lectura que se desvía de la línea base de su planta y métrica
+//
+// PROPÓSITO:
+// La detecta el AnomalyDetector en el path de intake. Guarda la línea base (media y desvío
+// EWMA) vigente al momento de la lectura para poder explicar por qué se marcó.
+type AnomalyEntity struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	PlantSourceId uuid.UUID `gorm:"type:uuid;not null;index:idx_anomalies_plant_source_id" json:"plant_source_id"`
+	Metric        string    `gorm:"type:varchar(100);not null;index:idx_anomalies_metric" json:"metric" example:"power_generated_mw"`
+	EventID       uuid.UUID `gorm:"type:uuid;not null" json:"event_id"` // Evento de la lectura anómala
+	Value         float64   `gorm:"not null" json:"value" example:"12.4"`
+	BaselineMean  float64   `gorm:"not null" json:"baseline_mean" example:"402.7"`
+	BaselineStd   float64   `gorm:"not null" json:"baseline_std" example:"35.1"`
+	Score         float64   `gorm:"not null" json:"score" example:"-11.12"` // z-score: (value - mean) / std
+	Threshold     float64   `gorm:"not null" json:"threshold" example:"4"`  // |score| mínimo vigente para la planta
+	Direction     string    `gorm:"type:varchar(10);not null" json:"direction" example:"low"`
+	DetectedAt    time.Time `gorm:"not null;index:idx_anomalies_detected_at" json:"detected_at"` // Timestamp de la lectura
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (AnomalyEntity) TableName() string {
+	return "anomalies"
+}
+
+// AnomalyFilter filtros opcionales para listar anomalías
+type AnomalyFilter struct {
+	PlantSourceId *uuid.UUID // Solo anomalías de esta planta
+	Metric        string     // Solo esta métrica
+	From          time.Time  // detected_at >= From (cero = sin límite)
+	To            time.Time  // detected_at < To (cero = sin límite)
+	Limit         int        // Máximo de resultados (0 = sin límite)
+}
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// AnomalySettingsEntity es la configuración del detector de anomalías de una planta
+//
+// PROPÓSITO:
+// Sin fila para la planta se usan los valores por defecto (variables ANOMALY_*).
+// Se edita en /api/v1/plants/:id/anomaly-settings.
+//
+// CAMPOS:
+// - ZThreshold: |z-score| a partir del cual una lectura es anómala
+// - Alpha: Peso de cada lectura nueva en la media y varianza EWMA (0 < alpha <= 1)
+// - WarmUp: Lecturas necesarias antes de empezar a detectar
+// - Metrics: Métricas evaluadas
+type AnomalySettingsEntity struct {
+	PlantSourceId uuid.UUID `gorm:"type:uuid;primaryKey" json:"plant_source_id"`
+	Enabled       bool      `gorm:"not null" json:"enabled" example:"true"`
+	ZThreshold    float64   `gorm:"not null" json:"z_threshold" example:"4"`
+	Alpha         float64   `gorm:"not null" json:"alpha" example:"0.05"`
+	WarmUp        int       `gorm:"not null" json:"warm_up" example:"30"`
+	Metrics       []string  `gorm:"type:text;serializer:json" json:"metrics" example:"power_generated_mw,efficiency_percent"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AnomalySettingsEntity) TableName() string {
+	return "anomaly_settings"
+}
+
+// Validate verifica rangos y métricas conocidas
+func (s *AnomalySettingsEntity) Validate() error {
+	if s.ZThreshold <= 0 {
+		return fmt.Errorf("%w: z_threshold must be greater than 0", domainerrors.ErrInvalidInput)
+	}
+	if s.Alpha <= 0 || s.Alpha > 1 {
+		return fmt.Errorf("%w: alpha must be in (0, 1]", domainerrors.ErrInvalidInput)
+	}
+	if s.WarmUp < 2 {
+		return fmt.Errorf("%w: warm_up must be at least 2 readings", domainerrors.ErrInvalidInput)
+	}
+	for _, metric := range s.Metrics {
+		if !slices.Contains(ReadingMetrics, metric) {
+			return fmt.Errorf("%w: unknown metric %q (expected one of %s)",
+				domainerrors.ErrInvalidInput, metric, strings.Join(ReadingMetrics, ", "))
+		}
+	}
+	return nil
+}
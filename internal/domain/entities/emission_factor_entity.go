@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// EmissionFactorEntity es el factor de emisión de la red (tCO2e por MWh) de una región en un período
+//
+// PROPÓSITO:
+// Las emisiones evitadas de una planta son su energía generada por el factor de la red que
+// desplaza. Los factores cambian por región y por año (mix de la red, nuevas publicaciones
+// oficiales), así que se guardan con su vigencia y una versión para poder reproducir reportes.
+//
+// REGLAS:
+// - Version es global y creciente: cada factor nuevo toma la siguiente (max + 1)
+// - Los factores no se modifican ni se borran; una corrección es un factor nuevo con versión mayor
+// - RegionNodeID apunta a un nodo región del portfolio; nil es el factor por defecto (plantas sin región)
+// - La vigencia es [ValidFrom, ValidTo) en días UTC; ValidTo nil = sin fin
+//
+// Un reporte calculado con la versión V usa solo factores con version <= V, por lo que
+// repetirlo con la misma versión da el mismo resultado aunque después se carguen factores nuevos.
+type EmissionFactorEntity struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Version      int64      `gorm:"not null;uniqueIndex:idx_emission_factors_version" json:"version" example:"3"`
+	RegionNodeID *uuid.UUID `gorm:"type:uuid;index:idx_emission_factors_region_node_id" json:"region_node_id,omitempty"`
+	ValidFrom    time.Time  `gorm:"type:date;not null" json:"valid_from" example:"2026-01-01T00:00:00Z"`
+	ValidTo      *time.Time `gorm:"type:date" json:"valid_to,omitempty" example:"2027-01-01T00:00:00Z"`
+	TCO2ePerMWh  float64    `gorm:"column:tco2e_per_mwh;not null" json:"tco2e_per_mwh" example:"0.21"`
+	Source       string     `gorm:"type:varchar(255)" json:"source,omitempty" example:"REE 2025 grid mix"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (EmissionFactorEntity) TableName() string {
+	return "emission_factors"
+}
+
+// Validate verifica el factor y la vigencia (alineada a días UTC)
+// La región la verifica el EmissionsService, que tiene acceso al portfolio
+func (f *EmissionFactorEntity) Validate() error {
+	if f.TCO2ePerMWh < 0 {
+		return fmt.Errorf("%w: tco2e_per_mwh cannot be negative", domainerrors.ErrInvalidInput)
+	}
+	if f.ValidFrom.IsZero() {
+		return fmt.Errorf("%w: valid_from is required", domainerrors.ErrInvalidInput)
+	}
+	if !PeriodStart(f.ValidFrom, GranularityDay).Equal(f.ValidFrom) {
+		return fmt.Errorf("%w: valid_from must be a UTC midnight", domainerrors.ErrInvalidInput)
+	}
+	if f.ValidTo != nil {
+		if !PeriodStart(*f.ValidTo, GranularityDay).Equal(*f.ValidTo) {
+			return fmt.Errorf("%w: valid_to must be a UTC midnight", domainerrors.ErrInvalidInput)
+		}
+		if !f.ValidTo.After(f.ValidFrom) {
+			return fmt.Errorf("%w: valid_to must be after valid_from", domainerrors.ErrInvalidInput)
+		}
+	}
+	if len(f.Source) > 255 {
+		return fmt.Errorf("%w: source must be at most 255 characters", domainerrors.ErrInvalidInput)
+	}
+	return nil
+}
+
+// Covers indica si el factor está vigente el día (medianoche UTC)
+func (f *EmissionFactorEntity) Covers(day time.Time) bool {
+	return !day.Before(f.ValidFrom) && (f.ValidTo == nil || day.Before(*f.ValidTo))
+}
+
+// EmissionFactorFilter filtros opcionales para listar factores
+type EmissionFactorFilter struct {
+	RegionNodeID *uuid.UUID // Solo factores de esta región
+	DefaultOnly  bool       // Solo factores por defecto (sin región)
+	MaxVersion   int64      // version <= MaxVersion (0 = todas)
+}
+
+// EmissionsPeriod son la energía generada y las emisiones evitadas en un período
+//
+// CAMPOS:
+// - AvoidedTCO2e: Suma de generated_mwh de cada día por el factor vigente ese día
+// - UncoveredMWh: Energía de días sin factor aplicable (no suma emisiones evitadas)
+type EmissionsPeriod struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	GeneratedMWh float64   `json:"generated_mwh" example:"1820.5"`
+	AvoidedTCO2e float64   `json:"avoided_tco2e" example:"382.3"`
+	UncoveredMWh float64   `json:"uncovered_mwh" example:"0"`
+}
+
+// PlantEmissionsReport son las emisiones evitadas de una planta por período y del rango completo
+type PlantEmissionsReport struct {
+	PlantSourceId uuid.UUID               `json:"plant_source_id"`
+	PlantName     string                  `json:"plant_name" example:"Solar Plant Alpha"`
+	RegionNodeID  *uuid.UUID              `json:"region_node_id,omitempty"` // Región del portfolio cuyo factor se aplica (nil = factor por defecto)
+	From          time.Time               `json:"from"`
+	To            time.Time               `json:"to"`
+	Granularity   string                  `json:"granularity" example:"month"`
+	FactorVersion int64                   `json:"factor_version" example:"3"`
+	Periods       []EmissionsPeriod       `json:"periods,omitempty"`
+	Total         EmissionsPeriod         `json:"total"`
+	Factors       []*EmissionFactorEntity `json:"factors,omitempty"` // Factores aplicados
+}
+
+// EmissionsReport son las emisiones evitadas de un nodo del portfolio o de la flota
+// Node es nil para la flota; Plants lleva solo el total de cada planta
+type EmissionsReport struct {
+	Node          *PortfolioNodeRef       `json:"node,omitempty"`
+	From          time.Time               `json:"from"`
+	To            time.Time               `json:"to"`
+	Granularity   string                  `json:"granularity" example:"month"`
+	FactorVersion int64                   `json:"factor_version" example:"3"`
+	Periods       []EmissionsPeriod       `json:"periods"`
+	Total         EmissionsPeriod         `json:"total"`
+	Plants        []PlantEmissionsReport  `json:"plants"`
+	Factors       []*EmissionFactorEntity `json:"factors"` // Factores aplicados
+}
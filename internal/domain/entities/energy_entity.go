@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// EnergyDailyEntity es la energía integrada de una planta en un día (UTC)
+//
+// PROPÓSITO:
+// El EnergyIntegrator la actualiza con cada lectura integrando la potencia con la regla del
+// trapecio; los totales mensuales y de la flota se obtienen sumando días.
+//
+// FUNCIONAMIENTO:
+// - Day es la medianoche UTC del día según el timestamp de las lecturas (no created_at)
+// - CoveredHours son las horas del día cubiertas por intervalos entre lecturas <= ENERGY_MAX_GAP
+type EnergyDailyEntity struct {
+	PlantSourceId uuid.UUID `gorm:"type:uuid;primaryKey" json:"plant_source_id"`
+	Day           time.Time `gorm:"type:date;primaryKey;index:idx_energy_daily_day" json:"day"`
+	GeneratedMWh  float64   `gorm:"column:generated_mwh;not null;default:0" json:"generated_mwh" example:"1820.5"`
+	ConsumedMWh   float64   `gorm:"column:consumed_mwh;not null;default:0" json:"consumed_mwh" example:"96.2"`
+	CoveredHours  float64   `gorm:"not null;default:0" json:"covered_hours" example:"23.5"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (EnergyDailyEntity) TableName() string {
+	return "energy_daily"
+}
+
+// EnergyDailyFilter filtros para leer totales diarios
+type EnergyDailyFilter struct {
+	PlantSourceId *uuid.UUID // Solo esta planta (nil = todas)
+	From          time.Time  // day >= From
+	To            time.Time  // day < To
+}
+
+// EnergyQuery rango y granularidad de un reporte de energía (from y to se alinean a días UTC)
+type EnergyQuery struct {
+	From        time.Time
+	To          time.Time
+	Granularity string // day, week o month
+}
+
+// Validate verifica la granularidad y que el rango no esté vacío
+func (q EnergyQuery) Validate() error {
+	switch q.Granularity {
+	case GranularityDay, GranularityWeek, GranularityMonth:
+	default:
+		return fmt.Errorf("%w: granularity must be day, week or month", domainerrors.ErrInvalidInput)
+	}
+	if !q.To.After(q.From) {
+		return fmt.Errorf("%w: to must be after from", domainerrors.ErrInvalidInput)
+	}
+	return nil
+}
+
+// EnergyPeriod es la energía de una planta (o de la flota) en un período
+type EnergyPeriod struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	GeneratedMWh float64   `json:"generated_mwh" example:"1820.5"`
+	ConsumedMWh  float64   `json:"consumed_mwh" example:"96.2"`
+	NetMWh       float64   `json:"net_mwh" example:"1724.3"` // Generada - consumida
+	CoveredHours float64   `json:"covered_hours" example:"23.5"`
+}
+
+// PlantEnergyReport es la energía de una planta por período y del rango completo
+type PlantEnergyReport struct {
+	PlantSourceId uuid.UUID      `json:"plant_source_id"`
+	PlantName     string         `json:"plant_name" example:"Solar Plant Alpha"`
+	From          time.Time      `json:"from"`
+	To            time.Time      `json:"to"`
+	Granularity   string         `json:"granularity" example:"day"`
+	Periods       []EnergyPeriod `json:"periods,omitempty"`
+	Total         EnergyPeriod   `json:"total"`
+}
+
+// FleetEnergyReport es la energía de toda la flota por período y el total de cada planta
+type FleetEnergyReport struct {
+	From        time.Time           `json:"from"`
+	To          time.Time           `json:"to"`
+	Granularity string              `json:"granularity" example:"month"`
+	Periods     []EnergyPeriod      `json:"periods"`
+	Total       EnergyPeriod        `json:"total"`
+	Plants      []PlantEnergyReport `json:"plants"` // Solo el total de cada planta
+}
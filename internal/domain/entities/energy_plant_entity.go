@@ -1,21 +1,46 @@
 package entities
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-	"time"
 )
 
 type EnergyPlants struct {
-	ID         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	PlantName  string         `gorm:"type:varchar(255);not null"`
-	Location   string         `gorm:"type:varchar(255)"`
-	CapacityMW float64        `gorm:"type:float"`
-	CreatedAt  time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" swaggerignore:"true"`
+	ID              uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	PlantName       string         `gorm:"type:varchar(255);not null"`
+	PlantType       string         `gorm:"type:varchar(20);not null;default:other;index"`
+	Location        string         `gorm:"type:varchar(255)"`
+	CapacityMW      float64        `gorm:"type:float"`
+	Status          string         `gorm:"type:varchar(20)"` // Último estado reportado (vacío hasta el primer evento con status)
+	StatusChangedAt *time.Time     // Momento del último cambio de estado
+	PortfolioNodeID *uuid.UUID     `gorm:"type:uuid;index:idx_energy_plants_portfolio_node_id"` // Sitio del portfolio al que pertenece (nil = sin asignar)
+	IntervalSeconds *int64         `gorm:"column:expected_interval_seconds"`                    // Intervalo de reporte esperado en segundos (nil = PLANT_EXPECTED_INTERVAL)
+	CreatedAt       time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" swaggerignore:"true"`
 }
 
 func (EnergyPlants) TableName() string {
 	return "energy_plants"
 }
+
+// Validate verifica los datos de alta de una planta
+func (p *EnergyPlants) Validate() error {
+	if strings.TrimSpace(p.PlantName) == "" {
+		return fmt.Errorf("%w: plant_name is required", domainerrors.ErrInvalidInput)
+	}
+	if !slices.Contains(PlantTypes, p.PlantType) {
+		return fmt.Errorf("%w: unknown plant_type %q (expected one of %s)", domainerrors.ErrInvalidInput, p.PlantType, strings.Join(PlantTypes, ", "))
+	}
+	if p.CapacityMW < 0 {
+		return fmt.Errorf("%w: capacity_mw cannot be negative", domainerrors.ErrInvalidInput)
+	}
+	return nil
+}
@@ -18,18 +18,33 @@ import (
 // - Source: Fuente del evento (nombre de la planta de energía)
 // - Data: Datos completos del evento en formato JSON almacenado como texto
 // - Metadata: Metadatos adicionales opcionales en formato JSON
-// - CreatedAt: Timestamp de cuando se guardó el evento en la base de datos
+// - MaintenanceWindowID: Ventana de mantenimiento activa al tomar la lectura (nil fuera de mantenimiento)
+// - Quality / QualityFlags: Resultado de los chequeos de plausibilidad (good o suspect y los chequeos que fallaron)
+// - EventTime: Momento en que se tomó la lectura (campo timestamp del mensaje; CreatedAt si falta o es inválido)
+// - CreatedAt: Timestamp de cuando se guardó el evento en la base de datos (tiempo de ingesta)
+//
+// CAMBIO: EventTime como columna propia e indexada
+// RAZÓN: Las consultas y agregados ordenan y filtran por el momento de la lectura y no por
+// el de ingesta; una lectura que llega tarde queda en su lugar cronológico
 //
 // CAMBIO REALIZADO: Archivo creado desde cero
 // RAZÓN: Necesitábamos una entidad para persistir eventos de Kafka en PostgreSQL
 type EventEntity struct {
 	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	EventType     string    `gorm:"type:varchar(100);index:idx_event_type;not null" json:"event_type"`
-	PlantSourceId uuid.UUID `gorm:"type:uuid;index:idx_plant_source_id;not null" json:"plant_source_id"`
+	PlantSourceId uuid.UUID `gorm:"type:uuid;index:idx_plant_source_id;index:idx_events_plant_event_time,priority:1;not null" json:"plant_source_id"`
 	Source        string    `gorm:"type:varchar(255)" json:"source"`
 	Data          string    `gorm:"type:text" json:"data"` // Cambiado de jsonb a text para compatibilidad con GORM string type
 	Metadata      string    `gorm:"type:text" json:"metadata,omitempty"`
-	CreatedAt     time.Time `gorm:"autoCreateTime;index:idx_created_at" json:"created_at"`
+	// CAMBIO: Ventana de mantenimiento activa cuando se tomó la lectura
+	// RAZÓN: Las lecturas tomadas durante un mantenimiento planificado quedan marcadas
+	MaintenanceWindowID *uuid.UUID `gorm:"type:uuid" json:"maintenance_window_id,omitempty"`
+	// CAMBIO: Calidad de la lectura según los chequeos de plausibilidad del intake
+	// RAZÓN: Las lecturas sospechosas se guardan marcadas en lugar de aceptarse sin más
+	Quality      string    `gorm:"type:varchar(10);not null;default:good;index:idx_events_quality" json:"quality" example:"suspect"`
+	QualityFlags []string  `gorm:"type:text;serializer:json" json:"quality_flags,omitempty" example:"above_capacity:power_generated_mw"`
+	EventTime    time.Time `gorm:"not null;index:idx_events_event_time;index:idx_events_plant_event_time,priority:2" json:"event_time"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;index:idx_created_at" json:"created_at"`
 	// Relaciones
 	PlantSource EnergyPlants `gorm:"foreignKey:PlantSourceId;references:ID" json:"plant_source,omitempty"`
 }
@@ -37,3 +52,24 @@ type EventEntity struct {
 func (EventEntity) TableName() string {
 	return "events"
 }
+
+// Calidad de un evento según los chequeos de plausibilidad (columna quality)
+const (
+	EventQualityGood    = "good"
+	EventQualitySuspect = "suspect"
+)
+
+// ValidEventQuality indica si el valor es un filtro de calidad válido (vacío = todas)
+func ValidEventQuality(quality string) bool {
+	return quality == "" || quality == EventQualityGood || quality == EventQualitySuspect
+}
+
+// EventFilter filtros para consultar eventos por planta y rango de event_time
+// CAMBIO: El rango se aplica sobre event_time en lugar de created_at
+// RAZÓN: Una lectura que llega tarde tiene que entrar en el rango de cuando se tomó
+type EventFilter struct {
+	PlantSourceId *uuid.UUID // Solo eventos de esta planta (nil = todas)
+	From          time.Time  // event_time >= From (cero = sin límite)
+	To            time.Time  // event_time < To (cero = sin límite)
+	Quality       string     // Solo eventos de esta calidad (good, suspect; vacío = todas)
+}
@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ForecastModelHoltWinters modelo Holt-Winters aditivo con tendencia amortiguada y estacionalidad diaria
+const ForecastModelHoltWinters = "holt_winters_additive"
+
+// ForecastEntity es una corrida del modelo de pronóstico de generación de una planta
+//
+// PROPÓSITO:
+// El ForecastService reentrena cada planta periódicamente con el historial horario de
+// power_generated_mw y guarda el pronóstico completo (Horizon horas) junto con el error
+// del backtest, para saber cuánto confiar en el modelo.
+//
+// FUNCIONAMIENTO:
+// - Points: un punto por hora a partir de TrainedTo con su intervalo de confianza
+// - Backtest*: el modelo se reentrena sin las últimas BacktestHours horas y se compara su
+// pronóstico con lo que la planta realmente generó en ese tramo
+// - BacktestNMAE es el MAE en % de la capacidad (nil si la planta no tiene CapacityMW)
+type ForecastEntity struct {
+	ID             uuid.UUID       `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	PlantSourceId  uuid.UUID       `gorm:"type:uuid;not null;index:idx_forecasts_plant_created,priority:1" json:"plant_source_id"`
+	Model          string          `gorm:"type:varchar(50);not null" json:"model" example:"holt_winters_additive"`
+	Alpha          float64         `gorm:"not null" json:"alpha" example:"0.2"`
+	Beta           float64         `gorm:"not null" json:"beta" example:"0.01"`
+	Gamma          float64         `gorm:"not null" json:"gamma" example:"0.3"`
+	SeasonLength   int             `gorm:"not null" json:"season_length" example:"24"` // Puntos por temporada (horas por día)
+	TrainedFrom    time.Time       `gorm:"not null" json:"trained_from"`
+	TrainedTo      time.Time       `gorm:"not null" json:"trained_to"` // Última hora con datos usada para entrenar
+	TrainingPoints int             `gorm:"not null" json:"training_points" example:"672"`
+	HorizonHours   int             `gorm:"not null" json:"horizon_hours" example:"168"`
+	Confidence     float64         `gorm:"not null" json:"confidence" example:"0.95"`
+	ResidualStd    float64         `gorm:"not null" json:"residual_std" example:"8.3"` // Desvío del error a un paso (MW)
+	Points         []ForecastPoint `gorm:"type:text;serializer:json" json:"points"`
+	BacktestHours  int             `gorm:"not null" json:"backtest_hours" example:"24"` // 0 si no hubo historial suficiente
+	BacktestMAE    *float64        `json:"backtest_mae,omitempty" example:"6.1"`
+	BacktestRMSE   *float64        `json:"backtest_rmse,omitempty" example:"9.4"`
+	BacktestNMAE   *float64        `json:"backtest_nmae_percent,omitempty" example:"4.1"`
+	CreatedAt      time.Time       `gorm:"autoCreateTime;index:idx_forecasts_plant_created,priority:2" json:"created_at"`
+}
+
+func (ForecastEntity) TableName() string {
+	return "forecasts"
+}
+
+// ForecastPoint valor pronosticado para una hora (MW promedio) con su intervalo de confianza
+type ForecastPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value" example:"87.5"`
+	Lower float64   `json:"lower" example:"70.2"`
+	Upper float64   `json:"upper" example:"104.8"`
+}
+
+// ForecastFilter filtros opcionales para listar corridas de una planta
+type ForecastFilter struct {
+	Limit int // Máximo de resultados (0 = sin límite)
+}
@@ -0,0 +1,187 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Recurrencias de una ventana de mantenimiento
+const (
+	RecurrenceNone    = "none"
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// Tratamiento de las alertas de una planta dentro de una ventana de mantenimiento
+const (
+	MaintenanceAlertSuppress  = "suppress"  // No se disparan alertas nuevas
+	MaintenanceAlertDowngrade = "downgrade" // Se disparan con severidad info
+)
+
+// TimeRange es un intervalo [Start, End)
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// MaintenanceWindowEntity representa un mantenimiento planificado de una planta
+//
+// PROPÓSITO:
+// Durante el mantenimiento las lecturas de baja eficiencia son esperables. Mientras una
+// ocurrencia de la ventana está activa:
+// - Las alertas de la planta se suprimen o se bajan a info según AlertPolicy
+// - Los eventos se guardan con maintenance_window_id
+// - Los KPIs pueden excluir esas horas (exclude_maintenance=true)
+//
+// RECURRENCIA:
+// StartsAt/EndsAt definen la primera ocurrencia. Con recurrence daily, weekly o monthly
+// se repite con la misma duración hasta RecurrenceUntil (sin límite si es nil).
+type MaintenanceWindowEntity struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	PlantSourceId   uuid.UUID  `gorm:"type:uuid;not null;index:idx_maintenance_windows_plant_source_id" json:"plant_source_id"`
+	Name            string     `gorm:"type:varchar(255);not null" json:"name" example:"Inverter replacement"`
+	StartsAt        time.Time  `gorm:"not null" json:"starts_at"`
+	EndsAt          time.Time  `gorm:"not null" json:"ends_at"`
+	Recurrence      string     `gorm:"type:varchar(20);not null;default:none" json:"recurrence" example:"weekly"`
+	RecurrenceUntil *time.Time `json:"recurrence_until,omitempty"` // Última ocurrencia empieza antes de este instante
+	AlertPolicy     string     `gorm:"type:varchar(20);not null;default:suppress" json:"alert_policy" example:"suppress"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (MaintenanceWindowEntity) TableName() string {
+	return "maintenance_windows"
+}
+
+// Validate verifica el rango, la recurrencia y la política de alertas
+// Una ocurrencia no puede durar más que el intervalo de recurrencia
+func (w *MaintenanceWindowEntity) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return fmt.Errorf("%w: name is required", domainerrors.ErrInvalidInput)
+	}
+	if !w.EndsAt.After(w.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", domainerrors.ErrInvalidInput)
+	}
+	if !slices.Contains([]string{RecurrenceNone, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly}, w.Recurrence) {
+		return fmt.Errorf("%w: unknown recurrence %q (expected none, daily, weekly or monthly)", domainerrors.ErrInvalidInput, w.Recurrence)
+	}
+	if w.Recurrence != RecurrenceNone {
+		if period := minRecurrencePeriod(w.Recurrence); w.EndsAt.Sub(w.StartsAt) > period {
+			return fmt.Errorf("%w: a %s window cannot last more than %s", domainerrors.ErrInvalidInput, w.Recurrence, period)
+		}
+		if w.RecurrenceUntil != nil && !w.RecurrenceUntil.After(w.StartsAt) {
+			return fmt.Errorf("%w: recurrence_until must be after starts_at", domainerrors.ErrInvalidInput)
+		}
+	}
+	if !slices.Contains([]string{MaintenanceAlertSuppress, MaintenanceAlertDowngrade}, w.AlertPolicy) {
+		return fmt.Errorf("%w: unknown alert_policy %q (expected suppress or downgrade)", domainerrors.ErrInvalidInput, w.AlertPolicy)
+	}
+	return nil
+}
+
+// Occurrences devuelve las ocurrencias que se solapan con [from, to) en orden cronológico
+func (w *MaintenanceWindowEntity) Occurrences(from, to time.Time) []TimeRange {
+	duration := w.EndsAt.Sub(w.StartsAt)
+	if w.Recurrence == RecurrenceNone || w.Recurrence == "" {
+		if w.StartsAt.Before(to) && w.EndsAt.After(from) {
+			return []TimeRange{{Start: w.StartsAt, End: w.EndsAt}}
+		}
+		return nil
+	}
+
+	// Primera ocurrencia que puede solaparse: estimación por defecto (los meses se cuentan de 31 días)
+	k := 0
+	if elapsed := from.Sub(w.StartsAt) - duration; elapsed > 0 {
+		k = int(elapsed/maxRecurrencePeriod(w.Recurrence)) - 1
+		k = max(k, 0)
+	}
+
+	var occurrences []TimeRange
+	for ; ; k++ {
+		start := w.occurrenceStart(k)
+		if !start.Before(to) || (w.RecurrenceUntil != nil && !start.Before(*w.RecurrenceUntil)) {
+			break
+		}
+		if end := start.Add(duration); end.After(from) {
+			occurrences = append(occurrences, TimeRange{Start: start, End: end})
+		}
+	}
+	return occurrences
+}
+
+// ActiveAt devuelve la ocurrencia que contiene t
+func (w *MaintenanceWindowEntity) ActiveAt(t time.Time) (TimeRange, bool) {
+	occurrences := w.Occurrences(t, t.Add(time.Nanosecond))
+	if len(occurrences) == 0 {
+		return TimeRange{}, false
+	}
+	return occurrences[0], true
+}
+
+// occurrenceStart devuelve el inicio de la ocurrencia k (0 = StartsAt)
+func (w *MaintenanceWindowEntity) occurrenceStart(k int) time.Time {
+	switch w.Recurrence {
+	case RecurrenceDaily:
+		return w.StartsAt.AddDate(0, 0, k)
+	case RecurrenceWeekly:
+		return w.StartsAt.AddDate(0, 0, 7*k)
+	case RecurrenceMonthly:
+		return w.StartsAt.AddDate(0, k, 0)
+	default:
+		return w.StartsAt
+	}
+}
+
+// minRecurrencePeriod es el intervalo más corto entre dos ocurrencias
+func minRecurrencePeriod(recurrence string) time.Duration {
+	switch recurrence {
+	case RecurrenceWeekly:
+		return 7 * 24 * time.Hour
+	case RecurrenceMonthly:
+		return 28 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// maxRecurrencePeriod es el intervalo más largo entre dos ocurrencias
+func maxRecurrencePeriod(recurrence string) time.Duration {
+	if recurrence == RecurrenceMonthly {
+		return 31 * 24 * time.Hour
+	}
+	return minRecurrencePeriod(recurrence)
+}
+
+// MaintenanceWindowFilter filtros opcionales para listar ventanas de mantenimiento
+type MaintenanceWindowFilter struct {
+	PlantSourceId *uuid.UUID // Solo ventanas de esta planta
+}
+
+// MergeTimeRanges ordena los intervalos y une los que se solapan
+func MergeTimeRanges(ranges []TimeRange) []TimeRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(ranges)
+	slices.SortFunc(sorted, func(a, b TimeRange) int { return a.Start.Compare(b.Start) })
+
+	merged := []TimeRange{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if !r.Start.After(last.End) {
+			if r.End.After(last.End) {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
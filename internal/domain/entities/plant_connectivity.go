@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Estados de conectividad de una planta
+const (
+	ConnectivityOnline    = "online"     // Reportó dentro de su umbral
+	ConnectivityOffline   = "offline"    // Sin lecturas más allá de su umbral
+	ConnectivityNeverSeen = "never_seen" // Nunca envió lecturas (no dispara alertas)
+)
+
+// PlantConnectivity es la última vez que se recibió una lectura de la planta y su estado
+//
+// CAMPOS:
+// - LastSeenAt: Ingesta de la última lectura (created_at); el silencio se mide contra la hora actual
+// - LastEventTime: event_time de la lectura más nueva recibida
+// - ExpectedIntervalSeconds: Intervalo de la planta o PLANT_EXPECTED_INTERVAL (CustomInterval indica cuál)
+// - OfflineAfterSeconds: Silencio a partir del cual la planta está offline (intervalo × PLANT_OFFLINE_MISSED_INTERVALS)
+// - AlertID: Alerta offline en estado firing, si la hay
+type PlantConnectivity struct {
+	PlantSourceId           uuid.UUID  `json:"plant_source_id"`
+	PlantName               string     `json:"plant_name" example:"Solar Plant Alpha"`
+	PlantType               string     `json:"plant_type" example:"solar"`
+	State                   string     `json:"state" example:"online"`
+	LastSeenAt              *time.Time `json:"last_seen_at,omitempty"`
+	LastEventTime           *time.Time `json:"last_event_time,omitempty"`
+	SilentSeconds           *float64   `json:"silent_seconds,omitempty" example:"42"`
+	ExpectedIntervalSeconds int64      `json:"expected_interval_seconds" example:"300"`
+	CustomInterval          bool       `json:"custom_interval" example:"false"`
+	OfflineAfterSeconds     int64      `json:"offline_after_seconds" example:"900"`
+	AlertID                 *uuid.UUID `json:"alert_id,omitempty"`
+}
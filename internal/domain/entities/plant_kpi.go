@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Granularidades de los reportes de KPIs
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week" // Semanas ISO (lunes a domingo)
+	GranularityMonth = "month"
+)
+
+// KPIPeriod son los KPIs de una planta (o de la flota) en un período
+//
+// CAMPOS:
+// - HoursInPeriod: Duración del período dentro del rango consultado
+// - CoveredHours: Horas cubiertas por lecturas (intervalos entre lecturas <= ENERGY_MAX_GAP)
+// - CapacityFactor: EnergyGeneratedMWh / (CapacityMW * (HoursInPeriod - MaintenanceHours)), entre 0 y 1
+// - MaintenanceHours: Horas excluidas por ventanas de mantenimiento (solo con ExcludeMaintenance;
+// en la flota suma las horas de todas las plantas)
+// - TimeInStatusHours: Horas en cada status reportado (operational, maintenance, standby, peak_load)
+// - Telemetry: Media, mínimo y máximo de las métricas propias del tipo de planta (irradiancia,
+// viento, embalse, carga); en la flota se combinan las plantas de cada tipo
+// - RoundTripEfficiencyPercent: Solo almacenamiento, energía descargada / energía cargada
+type KPIPeriod struct {
+	Start                      time.Time                 `json:"start"`
+	End                        time.Time                 `json:"end"`
+	HoursInPeriod              float64                   `json:"hours_in_period" example:"24"`
+	CoveredHours               float64                   `json:"covered_hours" example:"23.5"`
+	Readings                   int                       `json:"readings" example:"288"`
+	EnergyGeneratedMWh         float64                   `json:"energy_generated_mwh" example:"1820.5"`
+	EnergyConsumedMWh          float64                   `json:"energy_consumed_mwh" example:"96.2"`
+	NetExportMWh               float64                   `json:"net_export_mwh" example:"1724.3"`
+	MaintenanceHours           float64                   `json:"maintenance_hours,omitempty" example:"4"`
+	CapacityFactor             *float64                  `json:"capacity_factor,omitempty" example:"0.3792"` // nil si la capacidad es 0
+	AvgEfficiencyPercent       *float64                  `json:"avg_efficiency_percent,omitempty" example:"86.4"`
+	PeakOutputMW               *float64                  `json:"peak_output_mw,omitempty" example:"410.7"`
+	PeakOutputAt               *time.Time                `json:"peak_output_at,omitempty"`
+	TimeInStatusHours          map[string]float64        `json:"time_in_status_hours"`
+	Telemetry                  map[string]TelemetryStats `json:"telemetry,omitempty"`
+	RoundTripEfficiencyPercent *float64                  `json:"round_trip_efficiency_percent,omitempty" example:"88.5"`
+}
+
+// TelemetryStats resume las lecturas de una métrica en un período
+type TelemetryStats struct {
+	Mean     float64 `json:"mean" example:"7.4"`
+	Min      float64 `json:"min" example:"0.8"`
+	Max      float64 `json:"max" example:"18.2"`
+	Readings int     `json:"readings" example:"288"`
+}
+
+// PlantKPIReport son los KPIs de una planta por período y del rango completo
+type PlantKPIReport struct {
+	PlantSourceId      uuid.UUID   `json:"plant_source_id"`
+	PlantName          string      `json:"plant_name" example:"Solar Plant Alpha"`
+	PlantType          string      `json:"plant_type" example:"solar"`
+	CapacityMW         float64     `json:"capacity_mw" example:"200"`
+	From               time.Time   `json:"from"`
+	To                 time.Time   `json:"to"`
+	Granularity        string      `json:"granularity" example:"day"`
+	ExcludeMaintenance bool        `json:"exclude_maintenance"`
+	ExcludeSuspect     bool        `json:"exclude_suspect"`
+	Periods            []KPIPeriod `json:"periods,omitempty"`
+	Total              KPIPeriod   `json:"total"`
+}
+
+// FleetKPIReport son los KPIs agregados de todas las plantas y el total de cada una
+type FleetKPIReport struct {
+	From               time.Time        `json:"from"`
+	To                 time.Time        `json:"to"`
+	Granularity        string           `json:"granularity" example:"month"`
+	ExcludeMaintenance bool             `json:"exclude_maintenance"`
+	ExcludeSuspect     bool             `json:"exclude_suspect"`
+	CapacityMW         float64          `json:"capacity_mw" example:"1250"`
+	Periods            []KPIPeriod      `json:"periods"`
+	Total              KPIPeriod        `json:"total"`
+	Plants             []PlantKPIReport `json:"plants"` // Solo el total de cada planta
+}
+
+// KPIQuery rango y granularidad de un reporte de KPIs
+type KPIQuery struct {
+	From               time.Time
+	To                 time.Time
+	Granularity        string
+	ExcludeMaintenance bool // Descarta lecturas, energía y horas dentro de ventanas de mantenimiento
+	ExcludeSuspect     bool // Usa solo las lecturas con quality=good
+}
+
+// Validate verifica la granularidad y el rango (To > From, máximo maxRange)
+func (q KPIQuery) Validate(maxRange time.Duration) error {
+	switch q.Granularity {
+	case GranularityDay, GranularityWeek, GranularityMonth:
+	default:
+		return fmt.Errorf("%w: granularity must be day, week or month", domainerrors.ErrInvalidInput)
+	}
+	if !q.To.After(q.From) {
+		return fmt.Errorf("%w: to must be after from", domainerrors.ErrInvalidInput)
+	}
+	if maxRange > 0 && q.To.Sub(q.From) > maxRange {
+		return fmt.Errorf("%w: range cannot exceed %s", domainerrors.ErrInvalidInput, maxRange)
+	}
+	return nil
+}
+
+// PeriodStart devuelve el inicio (UTC) del período de la granularidad que contiene t
+func PeriodStart(t time.Time, granularity string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // Lunes = 0
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// NextPeriod devuelve el inicio del período siguiente a start
+func NextPeriod(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Métricas numéricas de telemetría que envían las plantas
+const (
+	MetricPowerGenerated = "power_generated_mw"
+	MetricPowerConsumed  = "power_consumed_mw"
+	MetricEfficiency     = "efficiency_percent"
+	MetricTemperature    = "temperature_celsius"
+)
+
+// ReadingMetrics lista las métricas sobre las que se pueden definir reglas
+// CAMBIO: Incluye las métricas propias de cada tipo de planta
+// RAZÓN: Las reglas y el detector de anomalías pueden vigilar irradiancia, viento, embalse o carga
+var ReadingMetrics = []string{
+	MetricPowerGenerated, MetricPowerConsumed, MetricEfficiency, MetricTemperature,
+	MetricIrradiance, MetricWindSpeed, MetricReservoirLevel, MetricStateOfCharge,
+}
+
+// PlantReading es una lectura de telemetría ya validada por el IntakeHandler
+//
+// PROPÓSITO:
+// No se persiste (el evento completo queda en la tabla events). Es la forma tipada
+// de la lectura que reciben los procesadores registrados en el intake (ej: AlertEngine).
+type PlantReading struct {
+	EventID       uuid.UUID          // Evento guardado en la tabla events
+	EventType     string             // power_reading, status_update, efficiency_report, alert
+	PlantSourceId uuid.UUID          // Planta que envió la lectura
+	PlantType     string             // Tipo de la planta (solar, wind, hydro, storage, other)
+	Status        string             // Estado reportado por la planta
+	Metrics       map[string]float64 // Campos numéricos del mensaje (power_generated_mw, temperature_celsius...)
+	Data          map[string]any     // Mensaje completo tal como se guardó en events.data
+	Timestamp     time.Time          // Timestamp del evento (event_time; o de recepción si el mensaje no lo trae)
+	ReceivedAt    time.Time          // Momento en que se guardó el evento (tiempo de ingesta)
+	QualityFlags  []string           // Chequeos de plausibilidad que falló la lectura (vacío = good)
+
+	MaintenanceWindow *MaintenanceWindowEntity // Ventana de mantenimiento activa al tomar la lectura (solo en el intake)
+	PlantCapacityMW   float64                  // Capacidad de la planta (solo en el intake; 0 = desconocida)
+}
+
+// NewPlantReading arma la lectura tipada a partir del evento guardado y del mensaje
+// Todos los campos numéricos del mensaje se exponen como métricas; el EventTime del evento
+// es la base de tiempo (en eventos sin EventTime, el timestamp del mensaje o su CreatedAt)
+func NewPlantReading(event *EventEntity, plantType string, data map[string]any) PlantReading {
+	reading := PlantReading{
+		EventID:       event.ID,
+		EventType:     event.EventType,
+		PlantSourceId: event.PlantSourceId,
+		PlantType:     plantType,
+		Metrics:       make(map[string]float64),
+		Data:          data,
+		Timestamp:     event.CreatedAt,
+		ReceivedAt:    event.CreatedAt,
+		QualityFlags:  event.QualityFlags,
+	}
+
+	for key, value := range data {
+		if v, ok := value.(float64); ok {
+			reading.Metrics[key] = v
+		}
+	}
+	if status, ok := data["status"].(string); ok {
+		reading.Status = status
+	}
+	reading.Timestamp = ReadingTimestamp(data, event.CreatedAt)
+	if !event.EventTime.IsZero() {
+		reading.Timestamp = event.EventTime
+	}
+
+	return reading
+}
+
+// ReadingTimestamp devuelve el timestamp del mensaje (RFC3339) o fallback si falta o es inválido, en UTC
+// No acota el adelanto: el intake recorta los timestamps futuros (EVENT_MAX_CLOCK_SKEW)
+func ReadingTimestamp(data map[string]any, fallback time.Time) time.Time {
+	if ts, ok := data["timestamp"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return parsed.UTC()
+		}
+	}
+	return fallback.UTC()
+}
+
+// PlantReadingFromEvent reconstruye la lectura de un evento ya guardado (events.data)
+func PlantReadingFromEvent(event *EventEntity, plantType string) (PlantReading, error) {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+		return PlantReading{}, err
+	}
+	return NewPlantReading(event, plantType, data), nil
+}
+
+// Suspect indica si la lectura falló algún chequeo de plausibilidad
+func (r PlantReading) Suspect() bool {
+	return len(r.QualityFlags) > 0
+}
+
+// Metric devuelve el valor de una métrica y si estaba presente en la lectura
+func (r PlantReading) Metric(name string) (float64, bool) {
+	v, ok := r.Metrics[name]
+	return v, ok
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Estados operativos que reportan las plantas en el campo "status"
+const (
+	PlantStatusOperational = "operational"
+	PlantStatusMaintenance = "maintenance"
+	PlantStatusStandby     = "standby"
+	PlantStatusPeakLoad    = "peak_load"
+)
+
+// PlantStatuses lista los estados válidos
+var PlantStatuses = []string{PlantStatusOperational, PlantStatusMaintenance, PlantStatusStandby, PlantStatusPeakLoad}
+
+// plantStatusTransitions transiciones permitidas desde cada estado
+// Una planta en peak_load baja a operational o standby antes de entrar en mantenimiento,
+// y al salir de mantenimiento no pasa directo a peak_load.
+var plantStatusTransitions = map[string][]string{
+	PlantStatusOperational: {PlantStatusStandby, PlantStatusPeakLoad, PlantStatusMaintenance},
+	PlantStatusPeakLoad:    {PlantStatusOperational, PlantStatusStandby},
+	PlantStatusStandby:     {PlantStatusOperational, PlantStatusMaintenance},
+	PlantStatusMaintenance: {PlantStatusStandby, PlantStatusOperational},
+}
+
+// StatusTransitionAllowed indica si la planta puede pasar de from a to (from vacío = primer estado)
+func StatusTransitionAllowed(from, to string) bool {
+	if from == "" {
+		return true
+	}
+	return slices.Contains(plantStatusTransitions[from], to)
+}
+
+// PlantStatusHistoryEntity registra cada cambio de estado de una planta
+//
+// PROPÓSITO:
+// Lo escribe el PlantStatusTracker a partir de los eventos ingeridos. Permite reconstruir
+// la línea de tiempo de la planta y el tiempo en cada estado de un período.
+// Allowed=false marca las transiciones fuera de las permitidas (aplicadas igualmente
+// salvo con PLANT_STATUS_STRICT_TRANSITIONS=true).
+type PlantStatusHistoryEntity struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	PlantSourceId uuid.UUID `gorm:"type:uuid;not null;index:idx_plant_status_history_plant_changed,priority:1" json:"plant_source_id"`
+	FromStatus    string    `gorm:"type:varchar(20)" json:"from_status,omitempty" example:"operational"` // Vacío en el primer estado conocido
+	ToStatus      string    `gorm:"type:varchar(20);not null" json:"to_status" example:"maintenance"`
+	Allowed       bool      `gorm:"not null" json:"allowed" example:"true"`
+	EventID       uuid.UUID `gorm:"type:uuid;not null" json:"event_id"` // Evento que reportó el nuevo estado
+	ChangedAt     time.Time `gorm:"not null;index:idx_plant_status_history_plant_changed,priority:2" json:"changed_at"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (PlantStatusHistoryEntity) TableName() string {
+	return "plant_status_history"
+}
+
+// PlantStatusFilter filtros para la línea de tiempo de una planta
+type PlantStatusFilter struct {
+	From  time.Time // changed_at >= From (cero = sin límite)
+	To    time.Time // changed_at < To (cero = sin límite)
+	Limit int       // Máximo de resultados (0 = sin límite)
+}
+
+// PlantStatusUnknown agrupa en el tiempo en estado el tramo anterior al primer estado conocido
+const PlantStatusUnknown = "unknown"
+
+// PlantTimeInState es el tiempo que una planta pasó en cada estado dentro de un período
+type PlantTimeInState struct {
+	PlantSourceId uuid.UUID          `json:"plant_source_id"`
+	From          time.Time          `json:"from"`
+	To            time.Time          `json:"to"` // Recortado a la hora actual si el período no terminó
+	Hours         map[string]float64 `json:"hours"`
+	Transitions   int                `json:"transitions" example:"3"` // Cambios de estado dentro del período
+}
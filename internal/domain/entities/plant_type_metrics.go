@@ -0,0 +1,143 @@
+package entities
+
+import "slices"
+
+// Tipos de planta (columna plant_type); cada tipo reporta además sus propias métricas
+// (ver PlantTypeDefinitions) y las reglas de alerta pueden limitarse a un tipo
+const (
+	PlantTypeSolar   = "solar"
+	PlantTypeWind    = "wind"
+	PlantTypeHydro   = "hydro"
+	PlantTypeStorage = "storage"
+	PlantTypeOther   = "other"
+)
+
+// PlantTypes lista los tipos de planta válidos
+var PlantTypes = []string{PlantTypeSolar, PlantTypeWind, PlantTypeHydro, PlantTypeStorage, PlantTypeOther}
+
+// Métricas propias de cada tipo de planta
+const (
+	MetricIrradiance     = "irradiance_w_m2"         // Solar: irradiancia sobre el plano de los paneles
+	MetricWindSpeed      = "wind_speed_m_s"          // Eólica: velocidad del viento a la altura del buje
+	MetricReservoirLevel = "reservoir_level_percent" // Hidro: nivel del embalse sobre su capacidad útil
+	MetricStateOfCharge  = "state_of_charge_percent" // Almacenamiento: estado de carga de las baterías
+)
+
+// MetricDefinition describe una métrica de telemetría: unidad y rango plausible
+// El rango se usa como chequeo de plausibilidad cuando QUALITY_RANGES no define uno propio
+type MetricDefinition struct {
+	Name        string   `json:"name" example:"wind_speed_m_s"`
+	Unit        string   `json:"unit" example:"m/s"`
+	Description string   `json:"description" example:"Wind speed at hub height"`
+	Min         *float64 `json:"min,omitempty" example:"0"`
+	Max         *float64 `json:"max,omitempty" example:"75"`
+}
+
+// PlantTypeDefinition son las métricas que reporta un tipo de planta
+//
+// PROPÓSITO:
+// Solar, eólica, hidro y almacenamiento reportan señales distintas. Todas las plantas envían
+// las métricas comunes (potencia, eficiencia, temperatura) y además las de su tipo:
+// - El intake marca como suspect (unexpected_metric) una métrica propia de otro tipo
+// - Los KPIs resumen las métricas del tipo de cada planta (media, mínimo y máximo)
+// - En almacenamiento power_generated_mw es descarga y power_consumed_mw es carga
+type PlantTypeDefinition struct {
+	Type        string             `json:"type" example:"wind"`
+	Description string             `json:"description" example:"Wind farm"`
+	Metrics     []MetricDefinition `json:"metrics"` // Comunes y propias del tipo
+}
+
+func metricBound(v float64) *float64 {
+	return &v
+}
+
+// CommonMetrics son las métricas que envían todas las plantas
+var CommonMetrics = []MetricDefinition{
+	{Name: MetricPowerGenerated, Unit: "MW", Description: "Power delivered to the grid (discharge for storage)", Min: metricBound(0)},
+	{Name: MetricPowerConsumed, Unit: "MW", Description: "Power drawn from the grid (charge for storage)", Min: metricBound(0)},
+	{Name: MetricEfficiency, Unit: "%", Description: "Conversion efficiency", Min: metricBound(0), Max: metricBound(100)},
+	{Name: MetricTemperature, Unit: "°C", Description: "Equipment operating temperature", Min: metricBound(-50), Max: metricBound(150)},
+}
+
+// typeMetrics son las métricas propias de cada tipo (other no tiene)
+var typeMetrics = map[string][]MetricDefinition{
+	PlantTypeSolar: {
+		{Name: MetricIrradiance, Unit: "W/m²", Description: "Plane-of-array irradiance", Min: metricBound(0), Max: metricBound(1500)},
+	},
+	PlantTypeWind: {
+		{Name: MetricWindSpeed, Unit: "m/s", Description: "Wind speed at hub height", Min: metricBound(0), Max: metricBound(75)},
+	},
+	PlantTypeHydro: {
+		{Name: MetricReservoirLevel, Unit: "%", Description: "Reservoir level over its usable capacity", Min: metricBound(0), Max: metricBound(100)},
+	},
+	PlantTypeStorage: {
+		{Name: MetricStateOfCharge, Unit: "%", Description: "Battery state of charge", Min: metricBound(0), Max: metricBound(100)},
+	},
+}
+
+var plantTypeDescriptions = map[string]string{
+	PlantTypeSolar:   "Solar photovoltaic plant",
+	PlantTypeWind:    "Wind farm",
+	PlantTypeHydro:   "Hydroelectric plant",
+	PlantTypeStorage: "Battery energy storage system",
+	PlantTypeOther:   "Other technology (common metrics only)",
+}
+
+// PlantTypeDefinitions devuelve la definición de cada tipo de planta en el orden de PlantTypes
+func PlantTypeDefinitions() []PlantTypeDefinition {
+	definitions := make([]PlantTypeDefinition, 0, len(PlantTypes))
+	for _, plantType := range PlantTypes {
+		definitions = append(definitions, PlantTypeDefinition{
+			Type:        plantType,
+			Description: plantTypeDescriptions[plantType],
+			Metrics:     MetricsForPlantType(plantType),
+		})
+	}
+	return definitions
+}
+
+// MetricsForPlantType devuelve las métricas comunes más las propias del tipo
+func MetricsForPlantType(plantType string) []MetricDefinition {
+	return append(slices.Clone(CommonMetrics), typeMetrics[plantType]...)
+}
+
+// TypeMetrics devuelve solo las métricas propias del tipo
+func TypeMetrics(plantType string) []MetricDefinition {
+	return typeMetrics[plantType]
+}
+
+// FindMetricDefinition busca una métrica entre las comunes y las de todos los tipos
+func FindMetricDefinition(name string) (MetricDefinition, bool) {
+	for _, definition := range CommonMetrics {
+		if definition.Name == name {
+			return definition, true
+		}
+	}
+	for _, plantType := range PlantTypes {
+		for _, definition := range typeMetrics[plantType] {
+			if definition.Name == name {
+				return definition, true
+			}
+		}
+	}
+	return MetricDefinition{}, false
+}
+
+// MetricPlantType devuelve el tipo al que pertenece una métrica propia ("" si es común o desconocida)
+func MetricPlantType(name string) string {
+	for plantType, definitions := range typeMetrics {
+		for _, definition := range definitions {
+			if definition.Name == name {
+				return plantType
+			}
+		}
+	}
+	return ""
+}
+
+// ExpectedMetric indica si una planta del tipo puede reportar la métrica
+// Las plantas other aceptan cualquier métrica (tecnología sin definición propia)
+func ExpectedMetric(plantType, name string) bool {
+	owner := MetricPlantType(name)
+	return owner == "" || owner == plantType || plantType == PlantTypeOther || !slices.Contains(PlantTypes, plantType)
+}
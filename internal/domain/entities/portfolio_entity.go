@@ -0,0 +1,151 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Niveles de la jerarquía del portfolio (organización → región → sitio → planta)
+const (
+	PortfolioLevelOrganization = "organization"
+	PortfolioLevelRegion       = "region"
+	PortfolioLevelSite         = "site"
+)
+
+// PortfolioLevels lista los niveles válidos de arriba hacia abajo
+var PortfolioLevels = []string{PortfolioLevelOrganization, PortfolioLevelRegion, PortfolioLevelSite}
+
+// PortfolioNodeEntity representa un nodo de la jerarquía del portfolio
+//
+// PROPÓSITO:
+// La flota se organiza como organización (dueño) → región → sitio → planta. Cada nodo
+// apunta a su padre; las plantas se asignan a un sitio (energy_plants.portfolio_node_id)
+// y los reportes agregan capacidad, potencia actual, energía y alertas de todo el subárbol.
+//
+// REGLAS:
+// - Una organización no tiene padre; una región cuelga de una organización y un sitio de una región
+// - El nivel no cambia después de crear el nodo
+// - Un nodo con hijos o plantas asignadas no se puede borrar
+type PortfolioNodeEntity struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index:idx_portfolio_nodes_parent_id" json:"parent_id,omitempty"`
+	Level       string     `gorm:"type:varchar(20);not null;index:idx_portfolio_nodes_level" json:"level" example:"site"`
+	Name        string     `gorm:"type:varchar(255);not null" json:"name" example:"Andalusia South"`
+	Description string     `gorm:"type:text" json:"description,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (PortfolioNodeEntity) TableName() string {
+	return "portfolio_nodes"
+}
+
+// Validate verifica el nombre, el nivel y que solo las organizaciones no tengan padre
+// (el nivel del padre lo verifica el PortfolioService, que tiene acceso al repositorio)
+func (n *PortfolioNodeEntity) Validate() error {
+	if strings.TrimSpace(n.Name) == "" {
+		return fmt.Errorf("%w: name is required", domainerrors.ErrInvalidInput)
+	}
+	if !slices.Contains(PortfolioLevels, n.Level) {
+		return fmt.Errorf("%w: unknown level %q (expected organization, region or site)", domainerrors.ErrInvalidInput, n.Level)
+	}
+	if n.Level == PortfolioLevelOrganization && n.ParentID != nil {
+		return fmt.Errorf("%w: an organization cannot have a parent", domainerrors.ErrInvalidInput)
+	}
+	if n.Level != PortfolioLevelOrganization && n.ParentID == nil {
+		return fmt.Errorf("%w: a %s needs a parent_id", domainerrors.ErrInvalidInput, n.Level)
+	}
+	return nil
+}
+
+// PortfolioParentLevel devuelve el nivel que debe tener el padre de un nodo ("" para organizaciones)
+func PortfolioParentLevel(level string) string {
+	switch level {
+	case PortfolioLevelRegion:
+		return PortfolioLevelOrganization
+	case PortfolioLevelSite:
+		return PortfolioLevelRegion
+	}
+	return ""
+}
+
+// PortfolioNodeFilter filtros opcionales para listar nodos
+type PortfolioNodeFilter struct {
+	Level    string     // Solo nodos de este nivel
+	ParentID *uuid.UUID // Solo hijos directos de este nodo
+}
+
+// PortfolioNodeRef identifica un nodo dentro del camino desde la organización
+type PortfolioNodeRef struct {
+	ID    uuid.UUID `json:"id"`
+	Level string    `json:"level" example:"region"`
+	Name  string    `json:"name" example:"Spain"`
+}
+
+// PortfolioTotals son los agregados de un conjunto de plantas
+//
+// CAMPOS:
+// - CurrentOutputMW: Suma del último power_generated_mw good de cada planta que reportó dentro de PORTFOLIO_OUTPUT_MAX_AGE
+// - ReportingPlants: Plantas que aportan a CurrentOutputMW
+// - GeneratedMWh / ConsumedMWh / NetMWh: Energía del rango (tabla energy_daily)
+// - FiringAlerts / AlertsBySeverity: Alertas en estado firing
+type PortfolioTotals struct {
+	PlantCount       int            `json:"plant_count" example:"12"`
+	CapacityMW       float64        `json:"capacity_mw" example:"640"`
+	CurrentOutputMW  float64        `json:"current_output_mw" example:"412.5"`
+	ReportingPlants  int            `json:"reporting_plants" example:"11"`
+	GeneratedMWh     float64        `json:"generated_mwh" example:"98120.4"`
+	ConsumedMWh      float64        `json:"consumed_mwh" example:"1520.7"`
+	NetMWh           float64        `json:"net_mwh" example:"96599.7"`
+	FiringAlerts     int            `json:"firing_alerts" example:"3"`
+	AlertsBySeverity map[string]int `json:"alerts_by_severity"`
+}
+
+// PortfolioNodeSummary son los agregados del subárbol de un nodo
+type PortfolioNodeSummary struct {
+	PortfolioNodeRef
+	PortfolioTotals
+}
+
+// PortfolioPlantSummary son las cifras de una planta asignada directamente al nodo
+type PortfolioPlantSummary struct {
+	PlantSourceId   uuid.UUID  `json:"plant_source_id"`
+	PlantName       string     `json:"plant_name" example:"Solar Plant Alpha"`
+	PlantType       string     `json:"plant_type" example:"solar"`
+	CapacityMW      float64    `json:"capacity_mw" example:"200"`
+	CurrentOutputMW *float64   `json:"current_output_mw,omitempty" example:"143.2"` // nil si no reportó dentro de PORTFOLIO_OUTPUT_MAX_AGE
+	OutputAt        *time.Time `json:"output_at,omitempty"`
+	GeneratedMWh    float64    `json:"generated_mwh" example:"30112.9"`
+	ConsumedMWh     float64    `json:"consumed_mwh" example:"410.2"`
+	FiringAlerts    int        `json:"firing_alerts" example:"1"`
+}
+
+// PortfolioReport son los agregados de un nodo, de cada hijo directo y de sus plantas
+type PortfolioReport struct {
+	PortfolioNodeSummary
+	Path     []PortfolioNodeRef      `json:"path"` // Desde la organización hasta el nodo (incluido)
+	From     time.Time               `json:"from"`
+	To       time.Time               `json:"to"`
+	Children []PortfolioNodeSummary  `json:"children"`
+	Plants   []PortfolioPlantSummary `json:"plants"` // Solo las asignadas directamente (sitios)
+}
+
+// PlantAlertCount es la cantidad de alertas de una planta por severidad
+type PlantAlertCount struct {
+	PlantSourceId uuid.UUID
+	Severity      string
+	Count         int
+}
+
+// PlantEnergyTotal es la energía de una planta sumada en un rango de días
+type PlantEnergyTotal struct {
+	PlantSourceId uuid.UUID
+	GeneratedMWh  float64 `gorm:"column:generated_mwh"`
+	ConsumedMWh   float64 `gorm:"column:consumed_mwh"`
+}
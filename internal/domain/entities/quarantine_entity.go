@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tratamiento de los mensajes de plantas que no están en energy_plants (UNKNOWN_PLANT_MODE)
+const (
+	UnknownPlantReject     = "reject"     // Se descartan (comportamiento original)
+	UnknownPlantQuarantine = "quarantine" // Se guardan en cuarentena hasta registrar la planta
+)
+
+// QuarantinedEventEntity es un mensaje del intake cuya planta todavía no está registrada
+//
+// PROPÓSITO:
+// Una planta recién puesta en marcha puede empezar a enviar lecturas antes de darse de alta.
+// Con UNKNOWN_PLANT_MODE=quarantine sus mensajes se guardan aquí en lugar de perderse.
+//
+// REGLAS:
+// - Data es el mensaje original; al liberar se vuelve a procesar con el intake (calidad,
+// mantenimiento, alertas, agregados) como si acabara de llegar
+// - Al liberar o descartar, las filas de la planta se borran
+// - EventTime se guarda solo para ordenar la liberación y mostrar el rango en la API
+type QuarantinedEventEntity struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	PlantSourceId uuid.UUID `gorm:"type:uuid;not null;index:idx_quarantined_events_plant_event_time,priority:1" json:"plant_source_id"`
+	EventType     string    `gorm:"type:varchar(100);not null" json:"event_type" example:"power_reading"`
+	Source        string    `gorm:"type:varchar(255)" json:"source" example:"Solar Plant Delta"`
+	Data          string    `gorm:"type:text;not null" json:"data"`
+	Metadata      string    `gorm:"type:text" json:"metadata,omitempty"`
+	EventTime     time.Time `gorm:"not null;index:idx_quarantined_events_plant_event_time,priority:2" json:"event_time"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (QuarantinedEventEntity) TableName() string {
+	return "quarantined_events"
+}
+
+// QuarantinedPlant resume los mensajes en cuarentena de una planta desconocida
+//
+// CAMPOS:
+// - Source: plant_name del mensaje más reciente (nombre sugerido al registrar la planta)
+// - FirstReceivedAt / LastReceivedAt: Rango de ingesta de los mensajes
+// - FirstEventTime / LastEventTime: Rango de event_time de las lecturas
+// - Registered: La planta ya existe (registrada por fuera de la cuarentena); falta liberar
+type QuarantinedPlant struct {
+	PlantSourceId   uuid.UUID `json:"plant_source_id"`
+	Source          string    `json:"source" example:"Solar Plant Delta"`
+	Events          int64     `json:"events" example:"120"`
+	FirstReceivedAt time.Time `json:"first_received_at"`
+	LastReceivedAt  time.Time `json:"last_received_at"`
+	FirstEventTime  time.Time `json:"first_event_time"`
+	LastEventTime   time.Time `json:"last_event_time"`
+	Registered      bool      `json:"registered" example:"false"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+// Chequeos de plausibilidad de las lecturas; cada flag es "<chequeo>:<métrica>"
+const (
+	QualityCheckRange      = "out_of_range"      // Fuera del rango configurado (o del definido para la métrica)
+	QualityCheckCapacity   = "above_capacity"    // Generación mayor a la capacidad de la planta (más la tolerancia)
+	QualityCheckRate       = "rate_of_change"    // Cambio por minuto respecto de la lectura anterior mayor al límite
+	QualityCheckStuck      = "stuck"             // El mismo valor (distinto de 0) repetido demasiadas lecturas seguidas
+	QualityCheckUnexpected = "unexpected_metric" // Métrica propia de otro tipo de planta (ej: wind_speed_m_s en una solar)
+	QualityCheckFuture     = "future_timestamp"  // timestamp más adelantado que EVENT_MAX_CLOCK_SKEW (event_time recortado a la recepción)
+)
+
+// QualityMetricTimestamp es la "métrica" del flag future_timestamp
+const QualityMetricTimestamp = "timestamp"
+
+// QualityFlag arma el flag de un chequeo que falló para una métrica
+func QualityFlag(check, metric string) string {
+	return check + ":" + metric
+}
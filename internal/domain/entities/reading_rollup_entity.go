@@ -0,0 +1,129 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Niveles de almacenamiento de las lecturas, del más fino al más grueso (columna resolution)
+const (
+	RollupTierRaw    = "raw" // Eventos originales (tabla events)
+	RollupTierMinute = "1m"
+	RollupTierHour   = "1h"
+	RollupTierDay    = "1d"
+)
+
+// RollupTiers lista los niveles de rollup del más fino al más grueso; cada uno se construye desde el anterior
+var RollupTiers = []string{RollupTierMinute, RollupTierHour, RollupTierDay}
+
+// RollupResolution devuelve el tamaño de bucket de un nivel de rollup (0 para raw)
+func RollupResolution(tier string) time.Duration {
+	switch tier {
+	case RollupTierMinute:
+		return time.Minute
+	case RollupTierHour:
+		return time.Hour
+	case RollupTierDay:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// ReadingRollupEntity es el resumen de una métrica de una planta en un bucket de tiempo
+//
+// PROPÓSITO:
+// Las lecturas originales se purgan después de la retención de raw; los rollups conservan
+// count, sum, min y max por bucket para seguir consultando series largas.
+//
+// FUNCIONAMIENTO:
+// - BucketStart es el inicio UTC del bucket según el event_time de las lecturas
+// - Solo se resumen lecturas good y métricas de ReadingMetrics
+// - 1m se construye desde events, 1h desde 1m y 1d desde 1h (count, sum, min y max se combinan sin perder exactitud)
+type ReadingRollupEntity struct {
+	PlantSourceId uuid.UUID `gorm:"type:uuid;primaryKey" json:"plant_source_id"`
+	Resolution    string    `gorm:"type:varchar(3);primaryKey;index:idx_reading_rollups_resolution_bucket,priority:1" json:"resolution" example:"1h"`
+	BucketStart   time.Time `gorm:"primaryKey;index:idx_reading_rollups_resolution_bucket,priority:2" json:"bucket_start"`
+	Metric        string    `gorm:"type:varchar(50);primaryKey" json:"metric" example:"power_generated_mw"`
+	Count         int64     `gorm:"not null" json:"count" example:"60"`
+	Sum           float64   `gorm:"not null" json:"sum" example:"7230.5"`
+	Min           float64   `gorm:"not null" json:"min" example:"110.2"`
+	Max           float64   `gorm:"not null" json:"max" example:"131.8"`
+}
+
+func (ReadingRollupEntity) TableName() string {
+	return "reading_rollups"
+}
+
+// RollupWatermarkEntity guarda hasta dónde está construido cada nivel de rollup
+// Los buckets anteriores a BuiltUntil están completos; la purga del nivel anterior no lo supera
+type RollupWatermarkEntity struct {
+	Resolution string    `gorm:"type:varchar(3);primaryKey" json:"resolution"`
+	BuiltUntil time.Time `gorm:"not null" json:"built_until"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (RollupWatermarkEntity) TableName() string {
+	return "rollup_watermarks"
+}
+
+// ReadingRollupFilter filtros para leer rollups de un nivel
+type ReadingRollupFilter struct {
+	Resolution    string     // Nivel (1m, 1h, 1d)
+	PlantSourceId *uuid.UUID // Solo esta planta (nil = todas)
+	Metric        string     // Solo esta métrica (vacío = todas)
+	From          time.Time  // bucket_start >= From
+	To            time.Time  // bucket_start < To
+}
+
+// ReadingSeriesQuery métrica, rango y tamaño de bucket de una serie de lecturas
+type ReadingSeriesQuery struct {
+	Metric string
+	From   time.Time
+	To     time.Time
+	Bucket time.Duration
+}
+
+// Validate verifica la métrica, el rango y el tamaño de bucket (como mucho maxBuckets buckets)
+func (q ReadingSeriesQuery) Validate(maxBuckets int) error {
+	if !slices.Contains(ReadingMetrics, q.Metric) {
+		return fmt.Errorf("%w: metric must be one of: %s", domainerrors.ErrInvalidInput, strings.Join(ReadingMetrics, ", "))
+	}
+	if q.Bucket < time.Second {
+		return fmt.Errorf("%w: bucket must be at least 1s", domainerrors.ErrInvalidInput)
+	}
+	if !q.To.After(q.From) {
+		return fmt.Errorf("%w: to must be after from", domainerrors.ErrInvalidInput)
+	}
+	if maxBuckets > 0 && int(q.To.Sub(q.From)/q.Bucket) > maxBuckets {
+		return fmt.Errorf("%w: range has more than %d buckets, use a larger bucket", domainerrors.ErrInvalidInput, maxBuckets)
+	}
+	return nil
+}
+
+// ReadingSeries serie de una métrica de una planta agregada por bucket
+// Tier indica de qué nivel se leyó (raw, 1m, 1h o 1d); los buckets sin lecturas no se incluyen
+type ReadingSeries struct {
+	PlantSourceId uuid.UUID            `json:"plant_source_id"`
+	Metric        string               `json:"metric" example:"power_generated_mw"`
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	Bucket        string               `json:"bucket" example:"1h0m0s"`
+	Tier          string               `json:"tier" example:"1m"`
+	Points        []ReadingSeriesPoint `json:"points"`
+}
+
+// ReadingSeriesPoint resumen de la métrica en un bucket
+type ReadingSeriesPoint struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count" example:"60"`
+	Mean  float64   `json:"mean" example:"120.5"`
+	Min   float64   `json:"min" example:"110.2"`
+	Max   float64   `json:"max" example:"131.8"`
+}
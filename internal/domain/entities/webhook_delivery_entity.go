@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Estados de una entrega de webhook
+const (
+	DeliveryStatusPending   = "pending"   // En cola o esperando el próximo reintento
+	DeliveryStatusDelivered = "delivered" // El destino respondió 2xx
+	DeliveryStatusFailed    = "failed"    // Se agotaron los intentos, error permanente o cola llena
+)
+
+// Tipos de notificación de alertas (los eventos usan su propio event_type)
+const (
+	NotificationAlertFiring   = "alert.firing"
+	NotificationAlertResolved = "alert.resolved"
+)
+
+// WebhookDeliveryEntity representa una notificación a entregar por webhook
+//
+// PROPÓSITO:
+// Se persiste antes de encolarse, así el log de entregas muestra también las que
+// nunca llegaron a enviarse (cola llena, reinicio del servicio) y pueden reenviarse.
+type WebhookDeliveryEntity struct {
+	ID             uuid.UUID              `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	SubscriptionID *uuid.UUID             `gorm:"type:uuid;index:idx_webhook_deliveries_subscription_id" json:"subscription_id,omitempty"` // nil = WEBHOOK_URL global
+	EventType      string                 `gorm:"type:varchar(100);not null;index:idx_webhook_deliveries_event_type" json:"event_type" example:"alert.firing"`
+	ResourceID     uuid.UUID              `gorm:"type:uuid;not null" json:"resource_id"` // Evento o alerta notificada
+	PlantSourceId  uuid.UUID              `gorm:"type:uuid;not null" json:"plant_source_id"`
+	URL            string                 `gorm:"type:varchar(2048);not null" json:"url" example:"https://hooks.example.com/energy"`
+	Payload        string                 `gorm:"type:text;not null" json:"payload"` // Cuerpo JSON firmado y enviado
+	Status         string                 `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_status" json:"status" example:"delivered"`
+	AttemptCount   int                    `gorm:"not null;default:0" json:"attempt_count" example:"1"`
+	LastStatusCode *int                   `json:"last_status_code,omitempty" example:"200"`
+	LastError      string                 `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time             `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time              `gorm:"autoCreateTime;index:idx_webhook_deliveries_created_at" json:"created_at"`
+	UpdatedAt      time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+	Attempts       []WebhookAttemptEntity `gorm:"foreignKey:DeliveryID;constraint:OnDelete:CASCADE" json:"attempts,omitempty"`
+}
+
+func (WebhookDeliveryEntity) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookAttemptEntity registra cada intento de entrega con su código de respuesta
+type WebhookAttemptEntity struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	DeliveryID uuid.UUID `gorm:"type:uuid;not null;index:idx_webhook_attempts_delivery_id" json:"delivery_id"`
+	Attempt    int       `gorm:"not null" json:"attempt" example:"1"`
+	StatusCode *int      `json:"status_code,omitempty" example:"503"` // nil si no hubo respuesta HTTP
+	Error      string    `gorm:"type:text" json:"error,omitempty" example:"webhook returned error status: 503"`
+	DurationMs int64     `gorm:"not null" json:"duration_ms" example:"120"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (WebhookAttemptEntity) TableName() string {
+	return "webhook_delivery_attempts"
+}
+
+// WebhookDeliveryFilter filtros opcionales para listar entregas
+type WebhookDeliveryFilter struct {
+	Status         string // pending, delivered o failed
+	EventType      string
+	SubscriptionID *uuid.UUID // Solo entregas de esta suscripción
+	Limit          int        // Máximo de resultados (0 = sin límite)
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+
+	"github.com/google/uuid"
+)
+
+// Estados de una suscripción de webhook
+const (
+	SubscriptionStatusActive   = "active"   // Recibe las notificaciones que pasan sus filtros
+	SubscriptionStatusPaused   = "paused"   // Pausada manualmente
+	SubscriptionStatusDisabled = "disabled" // Deshabilitada automáticamente tras N fallos consecutivos
+)
+
+// NotificationTest es el tipo de la notificación enviada por "send test event"
+const NotificationTest = "webhook.test"
+
+// WebhookSubscriptionEntity representa un consumidor de webhooks con su URL, secreto y filtros
+//
+// PROPÓSITO:
+// Cada equipo (guardia de operaciones, facturación, portal de partners) registra su propia
+// suscripción en /api/v1/webhooks en lugar de compartir WEBHOOK_URL.
+// Filtros vacíos significan "todos" (todos los tipos de evento / todas las plantas).
+type WebhookSubscriptionEntity struct {
+	ID                  uuid.UUID   `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name                string      `gorm:"type:varchar(255);not null" json:"name" example:"Ops pager"`
+	URL                 string      `gorm:"type:varchar(2048);not null" json:"url" example:"https://pager.example.com/hooks/energy"`
+	Secret              string      `gorm:"type:varchar(255);not null" json:"-"`
+	EventTypes          []string    `gorm:"type:text;serializer:json" json:"event_types" example:"alert.firing,alert.resolved"`
+	PlantIDs            []uuid.UUID `gorm:"type:text;serializer:json" json:"plant_ids"`
+	Status              string      `gorm:"type:varchar(20);not null;index:idx_webhook_subscriptions_status" json:"status" example:"active"`
+	ConsecutiveFailures int         `gorm:"not null;default:0" json:"consecutive_failures" example:"0"`
+	DisabledReason      string      `gorm:"type:text" json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookSubscriptionEntity) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Matches indica si la suscripción debe recibir la notificación
+func (s *WebhookSubscriptionEntity) Matches(eventType string, plantID uuid.UUID) bool {
+	if s.Status != SubscriptionStatusActive {
+		return false
+	}
+	if len(s.EventTypes) > 0 && !slices.Contains(s.EventTypes, eventType) {
+		return false
+	}
+	return len(s.PlantIDs) == 0 || slices.Contains(s.PlantIDs, plantID)
+}
+
+// Validate verifica nombre, URL (http/https), secreto y estado
+func (s *WebhookSubscriptionEntity) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name is required", domainerrors.ErrInvalidInput)
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", domainerrors.ErrInvalidInput)
+	}
+	if s.Secret == "" {
+		return fmt.Errorf("%w: secret is required", domainerrors.ErrInvalidInput)
+	}
+	if !slices.Contains([]string{SubscriptionStatusActive, SubscriptionStatusPaused, SubscriptionStatusDisabled}, s.Status) {
+		return fmt.Errorf("%w: unknown status %q (expected active or paused)", domainerrors.ErrInvalidInput, s.Status)
+	}
+	for _, eventType := range s.EventTypes {
+		if strings.TrimSpace(eventType) == "" {
+			return fmt.Errorf("%w: event_types cannot contain empty values", domainerrors.ErrInvalidInput)
+		}
+	}
+	return nil
+}
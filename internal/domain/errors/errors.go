@@ -14,6 +14,10 @@ var (
 	// Handlers should map this to HTTP 400 Bad Request
 	ErrInvalidInput = errors.New("invalid input")
 
+	// ErrConflict indicates that the operation conflicts with the current state of the resource
+	// Handlers should map this to HTTP 409 Conflict
+	ErrConflict = errors.New("conflict")
+
 	// ErrInternal indicates an unexpected internal error occurred
 	// Handlers should map this to HTTP 500 Internal Server Error
 	ErrInternal = errors.New("internal error")
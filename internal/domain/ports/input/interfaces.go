@@ -1,22 +1,74 @@
 package input
 
 import (
+	"context"
+	"time"
+
 	"monitoring-energy-service/internal/domain/entities"
 
 	"github.com/google/uuid"
 )
 
 // MessageHandler is the interface for handling Kafka messages
+// The context carries the trace context extracted from the message headers
 type MessageHandler interface {
-	HandleMessage(message []byte) error
+	HandleMessage(ctx context.Context, message []byte) error
+}
+
+// ReadingProcessor is implemented by components that react to each reading accepted by the intake
+// (e.g. the alert rule engine). It runs after the event has been persisted; an error is logged
+// but does not reject the message
+type ReadingProcessor interface {
+	ProcessReading(ctx context.Context, reading entities.PlantReading) error
+}
+
+// AlertListener is notified every time an alert fires or resolves (e.g. to deliver it by webhook)
+type AlertListener interface {
+	OnAlert(ctx context.Context, alert entities.AlertEntity) error
+}
+
+// MaintenanceCalendar reports whether a plant is inside a planned maintenance window
+// (e.g. so the intake can mark readings taken during maintenance)
+type MaintenanceCalendar interface {
+	// ActiveWindow returns the window active for the plant at the given time, or nil
+	ActiveWindow(ctx context.Context, plantID uuid.UUID, at time.Time) (*entities.MaintenanceWindowEntity, error)
+}
+
+// ReadingQualityChecker runs plausibility checks on a reading before the intake stores it
+// (ranges, capacity bound, rate of change, stuck values). Suspect readings are stored with
+// the returned flags instead of being dropped
+type ReadingQualityChecker interface {
+	// Check returns the failed checks as "<check>:<metric>" flags (empty = good); it does not change any state
+	Check(ctx context.Context, plant *entities.EnergyPlants, reading entities.PlantReading) []string
+	// Commit records the reading as the previous one of the plant once the event is stored
+	Commit(ctx context.Context, plant *entities.EnergyPlants, reading entities.PlantReading)
+}
+
+// EventQuarantine keeps the messages of plants that are not registered yet so they can be
+// released once the plant is registered (UNKNOWN_PLANT_MODE=quarantine)
+type EventQuarantine interface {
+	// Hold stores the message; false means it was not kept (quarantine disabled or full) and must be rejected
+	Hold(ctx context.Context, event *entities.QuarantinedEventEntity) (bool, error)
+}
+
+// MessageReplayer processes a stored message again through the intake path as if it had just
+// been consumed (e.g. releasing quarantined messages), without quarantining it a second time.
+// The event is saved with the given ID; a message whose ID is already saved is skipped, so
+// replaying it again is a no-op
+type MessageReplayer interface {
+	ReplayMessage(ctx context.Context, id uuid.UUID, message []byte) error
 }
 
 // KafkaServiceInterface defines the contract for Kafka operations
 type KafkaServiceInterface interface {
-	SendEvent(topic string, key string, event any) error
+	SendEvent(ctx context.Context, topic string, key string, event any) error
 	RegisterHandler(topic string, handler MessageHandler)
 	ConsumeEvents()
 	StopConsuming()
+	// ConsumerStatus reports whether the consume loop is running and when it last polled Kafka
+	ConsumerStatus() (running bool, lastPoll time.Time)
+	// CheckConnectivity verifies producer and consumer connectivity to the brokers
+	CheckConnectivity(ctx context.Context) error
 }
 
 // ExampleServiceInterface defines the contract for example business logic
@@ -27,3 +79,9 @@ type ExampleServiceInterface interface {
 	Update(entity *entities.ExampleEntity) (*entities.ExampleEntity, error)
 	Delete(id uuid.UUID) error
 }
+
+// AggregateRecomputer rebuilds the stored aggregates of a plant for a time range from the
+// persisted events (e.g. daily energy). Late readings trigger it for the days they fall into
+type AggregateRecomputer interface {
+	RecomputeRange(ctx context.Context, plantID uuid.UUID, from, to time.Time) error
+}
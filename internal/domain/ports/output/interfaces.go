@@ -1,30 +1,40 @@
 package output
 
 import (
+	"context"
+	"time"
+
 	"monitoring-energy-service/internal/domain/entities"
 
 	"github.com/google/uuid"
 )
 
 // KafkaAdapterInterface defines the contract for Kafka adapter operations
+// Headers carry cross-cutting metadata such as the W3C trace context (traceparent)
+// ReadMessage returns a nil message and nil error when no message arrived within the poll timeout
+// CheckProducer/CheckConsumer are used by the readiness probe
 type KafkaAdapterInterface interface {
-	SendMessage(topic, key string, message []byte) error
-	ReadMessage() (message []byte, topic string, err error)
+	SendMessage(topic, key string, message []byte, headers map[string]string) error
+	ReadMessage() (message []byte, topic string, headers map[string]string, err error)
 	SubscribeTopics(topics []string) error
+	CheckProducer(timeout time.Duration) error
+	CheckConsumer(timeout time.Duration) error
 }
 
 // WebhookAdapterInterface defines the contract for webhook operations
+// SendPayload POSTs the JSON body, signing it with HMAC-SHA256 when a secret is given, and returns
+// the HTTP status code (0 when no response was received) so callers can log and classify failures
 type WebhookAdapterInterface interface {
-	SendPayload(url string, payload any) error
+	SendPayload(ctx context.Context, url string, secret string, headers map[string]string, payload []byte) (statusCode int, err error)
 }
 
 // ExampleRepositoryInterface defines the contract for example data persistence
 type ExampleRepositoryInterface interface {
-	FindByID(id uuid.UUID) (*entities.ExampleEntity, error)
-	FindAll() ([]*entities.ExampleEntity, error)
-	Create(entity *entities.ExampleEntity) (*entities.ExampleEntity, error)
-	Update(entity *entities.ExampleEntity) (*entities.ExampleEntity, error)
-	Delete(id uuid.UUID) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.ExampleEntity, error)
+	FindAll(ctx context.Context) ([]*entities.ExampleEntity, error)
+	Create(ctx context.Context, entity *entities.ExampleEntity) (*entities.ExampleEntity, error)
+	Update(ctx context.Context, entity *entities.ExampleEntity) (*entities.ExampleEntity, error)
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 // EventRepositoryInterface define el contrato para la persistencia de eventos
@@ -38,11 +48,24 @@ type ExampleRepositoryInterface interface {
 // - FindAll: Lista todos los eventos (para API REST)
 // - FindByID: Obtiene un evento específico
 // - FindByEventType: Filtra eventos por tipo (power_reading, alert, etc.)
+// - FindByFilter: Eventos de una planta y rango de event_time en orden cronológico (reportes de KPIs)
+// - FindLatestByPlants: Último evento good de cada planta desde un instante (potencia actual del portfolio)
+// - FindEarliestEventTime: event_time más viejo guardado (punto de partida de los rollups)
+// - DeleteBefore: Purga los eventos anteriores a la retención de raw
+//
+// CAMBIO: Todos los métodos reciben context.Context
+// RAZÓN: Las queries de GORM se trazan como hijas del span del request o del mensaje
+// CAMBIO: FindAll y FindByEventType reciben la calidad a devolver (good, suspect; vacío = todas)
+// RAZÓN: Quien consulta decide si incluye las lecturas marcadas por los chequeos de plausibilidad
 type EventRepositoryInterface interface {
-	Create(entity *entities.EventEntity) (*entities.EventEntity, error)
-	FindAll() ([]*entities.EventEntity, error)
-	FindByID(id uuid.UUID) (*entities.EventEntity, error)
-	FindByEventType(eventType string) ([]*entities.EventEntity, error)
+	Create(ctx context.Context, entity *entities.EventEntity) (*entities.EventEntity, error)
+	FindAll(ctx context.Context, quality string) ([]*entities.EventEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.EventEntity, error)
+	FindByEventType(ctx context.Context, eventType, quality string) ([]*entities.EventEntity, error)
+	FindByFilter(ctx context.Context, filter entities.EventFilter) ([]*entities.EventEntity, error)
+	FindLatestByPlants(ctx context.Context, plantIDs []uuid.UUID, since time.Time) ([]*entities.EventEntity, error)
+	FindEarliestEventTime(ctx context.Context) (time.Time, error)
+	DeleteBefore(ctx context.Context, before time.Time, batchSize int) (int64, error)
 }
 
 // EnergyPlantRepositoryInterface define el contrato para la persistencia de plantas de energía
@@ -53,7 +76,263 @@ type EventRepositoryInterface interface {
 // MÉTODOS:
 // - FindByID: Verifica si una planta existe por su UUID
 // - Exists: Método rápido para validar existencia
+// - FindAll: Todas las plantas (reportes de la flota)
+// - FindByPortfolioNodes: Plantas asignadas a cualquiera de los nodos (reportes del portfolio)
+// - UpdatePortfolioNode: Asigna la planta a un sitio del portfolio (nil = sin asignar)
+// - UpdateExpectedInterval: Intervalo de reporte esperado de la planta (nil = valor por defecto)
+// - Create: Alta de una planta con un ID dado (registro desde la cuarentena)
 type EnergyPlantRepositoryInterface interface {
-	FindByID(id uuid.UUID) (*entities.EnergyPlants, error)
-	Exists(id uuid.UUID) (bool, error)
+	Create(ctx context.Context, plant *entities.EnergyPlants) (*entities.EnergyPlants, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.EnergyPlants, error)
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+	FindAll(ctx context.Context) ([]*entities.EnergyPlants, error)
+	FindByPortfolioNodes(ctx context.Context, nodeIDs []uuid.UUID) ([]*entities.EnergyPlants, error)
+	UpdatePortfolioNode(ctx context.Context, plantID uuid.UUID, nodeID *uuid.UUID) error
+	UpdateExpectedInterval(ctx context.Context, plantID uuid.UUID, seconds *int64) error
+}
+
+// AlertRuleRepositoryInterface define el contrato para la persistencia de reglas de alerta
+//
+// MÉTODOS:
+// - FindEnabled: Reglas activas que evalúa el AlertEngine
+// - Resto: CRUD expuesto en /api/v1/alert-rules
+type AlertRuleRepositoryInterface interface {
+	Create(ctx context.Context, rule *entities.AlertRuleEntity) (*entities.AlertRuleEntity, error)
+	FindAll(ctx context.Context) ([]*entities.AlertRuleEntity, error)
+	FindEnabled(ctx context.Context) ([]*entities.AlertRuleEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.AlertRuleEntity, error)
+	Update(ctx context.Context, rule *entities.AlertRuleEntity) (*entities.AlertRuleEntity, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AlertRepositoryInterface define el contrato para la persistencia de alertas
+//
+// MÉTODOS:
+// - Create / Update: El AlertEngine crea la alerta al disparar y la actualiza al resolver
+// - FindFiring: Alertas activas, para reconstruir el estado del AlertEngine al arrancar
+// - FindAll / FindByID: Consultas de /api/v1/alerts
+// - CountFiringByPlants: Alertas firing por planta y severidad (reportes del portfolio)
+type AlertRepositoryInterface interface {
+	Create(ctx context.Context, alert *entities.AlertEntity) (*entities.AlertEntity, error)
+	Update(ctx context.Context, alert *entities.AlertEntity) (*entities.AlertEntity, error)
+	FindFiring(ctx context.Context) ([]*entities.AlertEntity, error)
+	FindAll(ctx context.Context, filter entities.AlertFilter) ([]*entities.AlertEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.AlertEntity, error)
+	CountFiringByPlants(ctx context.Context, plantIDs []uuid.UUID) ([]entities.PlantAlertCount, error)
+}
+
+// WebhookDeliveryRepositoryInterface define el contrato para el log de entregas de webhooks
+//
+// MÉTODOS:
+// - Create / Update: El WebhookDispatcher guarda la entrega antes de encolarla y su estado tras cada intento
+// - CreateAttempt: Registra cada intento con su código de respuesta
+// - FindPending: Entregas que quedaron pendientes (se reencolan al arrancar)
+// - FindAll / FindByID: Consultas de /api/v1/webhook-deliveries (FindByID incluye los intentos)
+type WebhookDeliveryRepositoryInterface interface {
+	Create(ctx context.Context, delivery *entities.WebhookDeliveryEntity) (*entities.WebhookDeliveryEntity, error)
+	Update(ctx context.Context, delivery *entities.WebhookDeliveryEntity) (*entities.WebhookDeliveryEntity, error)
+	CreateAttempt(ctx context.Context, attempt *entities.WebhookAttemptEntity) error
+	FindPending(ctx context.Context) ([]*entities.WebhookDeliveryEntity, error)
+	FindAll(ctx context.Context, filter entities.WebhookDeliveryFilter) ([]*entities.WebhookDeliveryEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WebhookDeliveryEntity, error)
+}
+
+// WebhookSubscriptionRepositoryInterface define el contrato para las suscripciones de webhooks
+//
+// MÉTODOS:
+// - Create / FindAll / FindByID / Update / Delete: CRUD expuesto en /api/v1/webhooks
+// - RecordSuccess: Reinicia el contador de fallos consecutivos tras una entrega exitosa
+// - RecordFailure: Suma un fallo consecutivo y deshabilita la suscripción al llegar a maxFailures
+type WebhookSubscriptionRepositoryInterface interface {
+	Create(ctx context.Context, subscription *entities.WebhookSubscriptionEntity) (*entities.WebhookSubscriptionEntity, error)
+	FindAll(ctx context.Context) ([]*entities.WebhookSubscriptionEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscriptionEntity, error)
+	Update(ctx context.Context, subscription *entities.WebhookSubscriptionEntity) (*entities.WebhookSubscriptionEntity, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	RecordSuccess(ctx context.Context, id uuid.UUID) error
+	RecordFailure(ctx context.Context, id uuid.UUID, maxFailures int, reason string) (*entities.WebhookSubscriptionEntity, error)
+}
+
+// AnomalyRepositoryInterface define el contrato para las anomalías detectadas
+//
+// MÉTODOS:
+// - Create: El AnomalyDetector guarda cada lectura anómala
+// - FindAll / FindByID: Consultas de /api/v1/anomalies
+type AnomalyRepositoryInterface interface {
+	Create(ctx context.Context, anomaly *entities.AnomalyEntity) (*entities.AnomalyEntity, error)
+	FindAll(ctx context.Context, filter entities.AnomalyFilter) ([]*entities.AnomalyEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.AnomalyEntity, error)
+}
+
+// AnomalySettingsRepositoryInterface define el contrato para la configuración del detector por planta
+//
+// MÉTODOS:
+// - FindAll: Configuraciones propias de cada planta (el resto usa los valores por defecto)
+// - FindByPlant / Save / Delete: /api/v1/plants/:id/anomaly-settings (Save crea o reemplaza)
+type AnomalySettingsRepositoryInterface interface {
+	FindAll(ctx context.Context) ([]*entities.AnomalySettingsEntity, error)
+	FindByPlant(ctx context.Context, plantID uuid.UUID) (*entities.AnomalySettingsEntity, error)
+	Save(ctx context.Context, settings *entities.AnomalySettingsEntity) (*entities.AnomalySettingsEntity, error)
+	Delete(ctx context.Context, plantID uuid.UUID) error
+}
+
+// PlantStatusHistoryRepositoryInterface define el contrato para el historial de estados de las plantas
+//
+// MÉTODOS:
+// - RecordTransition: Guarda el cambio de estado y actualiza el estado actual de la planta en una transacción
+// - FindByPlant: Línea de tiempo de la planta ordenada por changed_at
+// - FindLastBefore: Último cambio anterior a un instante (estado vigente al inicio de un período)
+type PlantStatusHistoryRepositoryInterface interface {
+	RecordTransition(ctx context.Context, transition *entities.PlantStatusHistoryEntity) (*entities.PlantStatusHistoryEntity, error)
+	FindByPlant(ctx context.Context, plantID uuid.UUID, filter entities.PlantStatusFilter) ([]*entities.PlantStatusHistoryEntity, error)
+	FindLastBefore(ctx context.Context, plantID uuid.UUID, before time.Time) (*entities.PlantStatusHistoryEntity, error)
+}
+
+// MaintenanceWindowRepositoryInterface define el contrato para las ventanas de mantenimiento
+//
+// MÉTODOS:
+// - Create / FindByID / Update / Delete: CRUD expuesto en /api/v1/maintenance-windows
+// - FindAll: Ventanas filtradas por planta (el MaintenanceCalendar carga todas)
+type MaintenanceWindowRepositoryInterface interface {
+	Create(ctx context.Context, window *entities.MaintenanceWindowEntity) (*entities.MaintenanceWindowEntity, error)
+	FindAll(ctx context.Context, filter entities.MaintenanceWindowFilter) ([]*entities.MaintenanceWindowEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.MaintenanceWindowEntity, error)
+	Update(ctx context.Context, window *entities.MaintenanceWindowEntity) (*entities.MaintenanceWindowEntity, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ForecastRepositoryInterface define el contrato para las corridas de pronóstico de generación
+//
+// MÉTODOS:
+// - Create: El ForecastService guarda cada corrida con sus puntos y el backtest
+// - FindLatest: Corrida más reciente de la planta (la que sirve /plants/:id/forecast)
+// - FindByPlant: Corridas de la planta (más recientes primero) para seguir el error de backtest
+// - DeleteCreatedBefore: Purga las corridas anteriores a la retención
+type ForecastRepositoryInterface interface {
+	Create(ctx context.Context, forecast *entities.ForecastEntity) (*entities.ForecastEntity, error)
+	FindLatest(ctx context.Context, plantID uuid.UUID) (*entities.ForecastEntity, error)
+	FindByPlant(ctx context.Context, plantID uuid.UUID, filter entities.ForecastFilter) ([]*entities.ForecastEntity, error)
+	DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EnergyDailyRepositoryInterface define el contrato para la energía diaria integrada por planta
+//
+// MÉTODOS:
+// - AddIncrements: El EnergyIntegrator suma la energía de cada intervalo entre lecturas
+// - FindDaily: Totales diarios de una planta o de la flota (reportes por día, semana o mes)
+// - ReplaceRange: Recalcula un rango de días de una planta desde los eventos guardados
+// - SumByPlants: Energía de cada planta sumada en un rango de días (reportes del portfolio)
+type EnergyDailyRepositoryInterface interface {
+	AddIncrements(ctx context.Context, increments []*entities.EnergyDailyEntity) error
+	FindDaily(ctx context.Context, filter entities.EnergyDailyFilter) ([]*entities.EnergyDailyEntity, error)
+	ReplaceRange(ctx context.Context, plantID uuid.UUID, from, to time.Time, days []*entities.EnergyDailyEntity) error
+	SumByPlants(ctx context.Context, plantIDs []uuid.UUID, from, to time.Time) ([]entities.PlantEnergyTotal, error)
+}
+
+// ReadingRollupRepositoryInterface define el contrato para los rollups de lecturas por nivel (1m, 1h, 1d)
+//
+// MÉTODOS:
+// - Upsert: El RollupService guarda los buckets construidos (reemplaza los existentes)
+// - Find: Buckets de un nivel para las series de /plants/:id/readings y para construir el nivel siguiente
+// - ReplaceRange: Reconstruye un rango de una planta (lecturas tardías)
+// - DeleteBefore: Purga los buckets anteriores a la retención del nivel
+// - FindWatermarks / SaveWatermark: Hasta dónde está construido cada nivel
+type ReadingRollupRepositoryInterface interface {
+	Upsert(ctx context.Context, rollups []*entities.ReadingRollupEntity) error
+	Find(ctx context.Context, filter entities.ReadingRollupFilter) ([]*entities.ReadingRollupEntity, error)
+	ReplaceRange(ctx context.Context, plantID uuid.UUID, resolution string, from, to time.Time, rollups []*entities.ReadingRollupEntity) error
+	DeleteBefore(ctx context.Context, resolution string, before time.Time) (int64, error)
+	FindWatermarks(ctx context.Context) ([]*entities.RollupWatermarkEntity, error)
+	SaveWatermark(ctx context.Context, resolution string, builtUntil time.Time) error
+}
+
+// PortfolioNodeRepositoryInterface define el contrato para la jerarquía del portfolio
+//
+// MÉTODOS:
+// - Create / FindByID / Update / Delete: CRUD expuesto en /api/v1/portfolio/nodes
+// - FindAll: Nodos filtrados por nivel o padre (los reportes cargan todos para recorrer el árbol)
+type PortfolioNodeRepositoryInterface interface {
+	Create(ctx context.Context, node *entities.PortfolioNodeEntity) (*entities.PortfolioNodeEntity, error)
+	FindAll(ctx context.Context, filter entities.PortfolioNodeFilter) ([]*entities.PortfolioNodeEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.PortfolioNodeEntity, error)
+	Update(ctx context.Context, node *entities.PortfolioNodeEntity) (*entities.PortfolioNodeEntity, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// EmissionFactorRepositoryInterface define el contrato para los factores de emisión de la red
+//
+// MÉTODOS:
+// - Create: Guarda el factor con la siguiente versión (los factores no se modifican ni se borran)
+// - FindAll: Factores filtrados por región o versión máxima (el EmissionsService carga todos los de la versión del reporte)
+// - FindByID: Factor expuesto en /api/v1/emission-factors/:id
+// - LatestVersion: Versión más alta (0 sin factores); es la que usan los reportes por defecto
+type EmissionFactorRepositoryInterface interface {
+	Create(ctx context.Context, factor *entities.EmissionFactorEntity) (*entities.EmissionFactorEntity, error)
+	FindAll(ctx context.Context, filter entities.EmissionFactorFilter) ([]*entities.EmissionFactorEntity, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.EmissionFactorEntity, error)
+	LatestVersion(ctx context.Context) (int64, error)
+}
+
+// QuarantineRepositoryInterface define el contrato para los mensajes de plantas desconocidas en cuarentena
+//
+// MÉTODOS:
+// - Create: Guarda un mensaje en cuarentena
+// - CreateWithinLimit: Guarda un mensaje si la planta no llegó al límite (QUARANTINE_MAX_EVENTS_PER_PLANT), de forma atómica
+// - Summaries: Mensajes agrupados por planta (plantID nil = todas)
+// - FindByPlant: Mensajes de una planta ordenados por event_time (liberación y API)
+// - Delete: Borra un mensaje ya liberado
+// - DeleteByPlant: Descarta todos los mensajes de una planta
+type QuarantineRepositoryInterface interface {
+	Create(ctx context.Context, event *entities.QuarantinedEventEntity) (*entities.QuarantinedEventEntity, error)
+	CreateWithinLimit(ctx context.Context, event *entities.QuarantinedEventEntity, max int) (bool, error)
+	Summaries(ctx context.Context, plantID *uuid.UUID) ([]*entities.QuarantinedPlant, error)
+	FindByPlant(ctx context.Context, plantID uuid.UUID, limit int) ([]*entities.QuarantinedEventEntity, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByPlant(ctx context.Context, plantID uuid.UUID) (int64, error)
+}
+
+// MetricsRecorderInterface define el contrato para registrar métricas operativas
+//
+// PROPÓSITO:
+// Permite que los servicios de la capa api (KafkaService, IntakeHandler, EventGenerator)
+// publiquen métricas sin depender de Prometheus directamente.
+//
+// MÉTODOS:
+// - KafkaMessageConsumed / KafkaConsumeError: Mensajes leídos y errores de lectura por topic
+// - KafkaMessageProduced / KafkaProduceError: Mensajes enviados y errores de envío por topic
+// - ObserveHandlerDuration: Latencia de cada MessageHandler
+// - IntakeOutcome: Resultado del procesamiento en IntakeHandler (saved, unknown_plant, bad_json...)
+// - GeneratorBatch: Lote enviado por EventGenerator con eventos enviados y fallidos
+// - AlertTransition: Alerta disparada (firing) o resuelta (resolved) por severidad
+// - WebhookDelivery: Resultado de las entregas de webhooks (delivered, retried, failed, dropped)
+// - AnomalyDetected: Anomalías detectadas por métrica y dirección (high, low)
+// - PlantStatusTransition: Cambios de estado de las plantas por estado destino y resultado (applied, not_allowed, rejected)
+// - ForecastRun: Reentrenamientos de pronóstico por resultado (trained, skipped, failed)
+// - ReadingQualityFlag: Chequeos de plausibilidad fallidos por chequeo y métrica
+// - IngestionDelay: Demora entre el event_time de cada lectura guardada y su ingesta
+// - LateReading: Lecturas tardías por resultado (recompute, too_late)
+// - RollupBuckets: Buckets de rollup construidos por nivel (1m, 1h, 1d)
+// - RetentionPurged: Filas purgadas por la retención por nivel (raw, 1m, 1h)
+// - PlantsOffline: Plantas sin reportar más allá de su umbral en la última revisión
+// - QuarantineEvents: Mensajes de plantas desconocidas por resultado (quarantined, dropped, released, discarded)
+type MetricsRecorderInterface interface {
+	KafkaMessageConsumed(topic string)
+	KafkaConsumeError(topic string)
+	KafkaMessageProduced(topic string)
+	KafkaProduceError(topic string)
+	ObserveHandlerDuration(handler string, duration time.Duration, err error)
+	IntakeOutcome(outcome string)
+	GeneratorBatch(sent, failed int)
+	AlertTransition(severity, status string)
+	WebhookDelivery(outcome string)
+	AnomalyDetected(metric, direction string)
+	PlantStatusTransition(status, outcome string)
+	ForecastRun(outcome string)
+	ReadingQualityFlag(check, metric string)
+	IngestionDelay(delay time.Duration)
+	LateReading(outcome string)
+	RollupBuckets(tier string, buckets int)
+	RetentionPurged(tier string, rows int64)
+	PlantsOffline(count int)
+	QuarantineEvents(outcome string, count int)
 }
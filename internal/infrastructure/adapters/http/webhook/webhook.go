@@ -2,50 +2,85 @@ package webhook
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"monitoring-energy-service/internal/domain/ports/output"
+	"monitoring-energy-service/internal/infrastructure/logging"
+)
+
+// Headers que acompañan cada webhook firmado
+const (
+	SignatureHeader = "X-Webhook-Signature" // "sha256=<hex>" de HMAC-SHA256(secret, timestamp + "." + body)
+	TimestampHeader = "X-Webhook-Timestamp" // Unix seconds; el receptor debe rechazar timestamps viejos (replay)
 )
 
 type Adapter struct {
-	client *http.Client
+	client   *http.Client
+	logger   *slog.Logger
+	redactor *logging.Redactor
 }
 
 var _ output.WebhookAdapterInterface = &Adapter{}
 
-func NewAdapter(client *http.Client) *Adapter {
+func NewAdapter(client *http.Client, logger *slog.Logger, redactor *logging.Redactor) *Adapter {
 	return &Adapter{
-		client: client,
+		client:   client,
+		logger:   logger,
+		redactor: redactor,
 	}
 }
 
-func (a *Adapter) SendPayload(url string, payload any) error {
-	jsonData, err := json.Marshal(payload)
+// SendPayload envía el payload firmado y devuelve el código de respuesta
+// CAMBIO: Recibe el cuerpo ya serializado, el secreto de firma y headers adicionales
+// RAZÓN: El mismo cuerpo se reintenta y se guarda en el log de entregas; cada intento lleva
+// un timestamp nuevo y por lo tanto una firma nueva
+func (a *Adapter) SendPayload(ctx context.Context, url string, secret string, headers map[string]string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
 	if err != nil {
-		return fmt.Errorf("error marshaling webhook payload: %w", err)
+		return 0, fmt.Errorf("error creating webhook request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("error creating webhook request: %w", err)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(secret, timestamp, payload))
+	}
 
-	log.Printf("sending webhook request to %s with payload: %s", url, string(jsonData))
+	// CAMBIO: El payload pasa por la política de redacción en lugar de loguearse completo
+	a.logger.DebugContext(ctx, "sending webhook request", slog.String("url", url), a.redactor.Attr("payload", payload))
 
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("error sending webhook request: %w", err)
+		return 0, fmt.Errorf("error sending webhook request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned error status: %d", resp.StatusCode)
+		return resp.StatusCode, fmt.Errorf("webhook returned error status: %d", resp.StatusCode)
 	}
 
-	return nil
+	return resp.StatusCode, nil
+}
+
+// Sign calcula la firma hex de HMAC-SHA256 sobre "timestamp.body"
+// Los receptores la recalculan con el mismo secreto y la comparan en tiempo constante
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
 }
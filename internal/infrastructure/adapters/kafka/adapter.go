@@ -1,12 +1,22 @@
 package kafka
 
 import (
+	"fmt"
+	"time"
+
 	"monitoring-energy-service/internal/domain/ports/output"
 	"monitoring-energy-service/internal/infrastructure/conf/kafkaconf"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 )
 
+const (
+	deliveryTimeout = 15 * time.Second
+	// pollTimeout limita cuánto bloquea ReadMessage; permite que el loop de consumo
+	// revise stopChan y registre actividad aunque no lleguen mensajes
+	pollTimeout = time.Second
+)
+
 type KafkaAdapter struct {
 	producer *kafka.Producer
 	consumer *kafka.Consumer
@@ -25,24 +35,86 @@ func (ka *KafkaAdapter) SubscribeTopics(topics []string) error {
 	return ka.consumer.SubscribeTopics(topics, nil)
 }
 
-func (ka *KafkaAdapter) SendMessage(topic, key string, message []byte) error {
-	ka.producer.Produce(&kafka.Message{
+// SendMessage produce el mensaje y espera su delivery report
+// CAMBIO: Ahora devuelve los errores de Produce y del delivery report en lugar de ignorarlos
+// RAZÓN: Las métricas de errores de envío por topic necesitan saber si el mensaje llegó al broker
+// CAMBIO: Recibe headers que se envían como headers del mensaje de Kafka
+// RAZÓN: Transportan el contexto de trazas W3C (traceparent) entre productor y consumidor
+func (ka *KafkaAdapter) SendMessage(topic, key string, message []byte, headers map[string]string) error {
+	deliveryChan := make(chan kafka.Event, 1)
+
+	kafkaHeaders := make([]kafka.Header, 0, len(headers))
+	for k, v := range headers {
+		kafkaHeaders = append(kafkaHeaders, kafka.Header{Key: k, Value: []byte(v)})
+	}
+
+	err := ka.producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
 		Key:            []byte(key),
 		Value:          message,
-	}, nil)
+		Headers:        kafkaHeaders,
+	}, deliveryChan)
+	if err != nil {
+		return fmt.Errorf("error producing message to topic %s: %w", topic, err)
+	}
 
-	ka.producer.Flush(15 * 1000)
+	select {
+	case e := <-deliveryChan:
+		if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+			return fmt.Errorf("delivery failed for topic %s: %w", topic, m.TopicPartition.Error)
+		}
+	case <-time.After(deliveryTimeout):
+		return fmt.Errorf("delivery report timeout for topic %s", topic)
+	}
 
 	return nil
 }
 
-func (ka *KafkaAdapter) ReadMessage() (message []byte, topic string, err error) {
-	msg, err := ka.consumer.ReadMessage(-1)
+// ReadMessage lee el siguiente mensaje esperando como máximo pollTimeout
+// CAMBIO: Ya no bloquea indefinidamente; si no hay mensajes devuelve message nil y err nil
+// RAZÓN: El loop de consumo debe seguir vivo (y reportarlo en /readyz) aunque el topic esté vacío
+// CAMBIO: En los errores de un mensaje (msg con TopicPartition.Error) devuelve su topic
+// RAZÓN: kafka_consume_errors_total se etiqueta por topic; los errores del cliente no traen ninguno
+func (ka *KafkaAdapter) ReadMessage() (message []byte, topic string, headers map[string]string, err error) {
+	msg, err := ka.consumer.ReadMessage(pollTimeout)
 	if err != nil {
-		return nil, "", err
+		if kafkaErr, ok := err.(kafka.Error); ok && kafkaErr.Code() == kafka.ErrTimedOut {
+			return nil, "", nil, nil
+		}
+		if msg != nil && msg.TopicPartition.Topic != nil {
+			topic = *msg.TopicPartition.Topic
+		}
+		return nil, topic, nil, err
+	}
+
+	headers = make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
 	}
 
 	topic = *msg.TopicPartition.Topic
-	return msg.Value, topic, nil
+	return msg.Value, topic, headers, nil
+}
+
+// CheckProducer verifica que el productor puede obtener metadata del cluster
+func (ka *KafkaAdapter) CheckProducer(timeout time.Duration) error {
+	if _, err := ka.producer.GetMetadata(nil, false, int(timeout.Milliseconds())); err != nil {
+		return fmt.Errorf("producer cannot reach brokers: %w", err)
+	}
+	return nil
+}
+
+// CheckConsumer verifica que el consumidor puede obtener metadata y está suscrito a algún topic
+func (ka *KafkaAdapter) CheckConsumer(timeout time.Duration) error {
+	if _, err := ka.consumer.GetMetadata(nil, false, int(timeout.Milliseconds())); err != nil {
+		return fmt.Errorf("consumer cannot reach brokers: %w", err)
+	}
+	topics, err := ka.consumer.Subscription()
+	if err != nil {
+		return fmt.Errorf("error reading consumer subscription: %w", err)
+	}
+	if len(topics) == 0 {
+		return fmt.Errorf("consumer is not subscribed to any topic")
+	}
+	return nil
 }
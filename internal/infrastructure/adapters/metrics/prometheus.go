@@ -0,0 +1,356 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "monitoring_energy"
+
+// PrometheusRecorder implementa MetricsRecorderInterface sobre un registry de Prometheus
+//
+// PROPÓSITO:
+// Centraliza todas las métricas del servicio (HTTP, Kafka, handlers, intake, DB y generador)
+// en un registry propio que se expone en GET /metrics.
+type PrometheusRecorder struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	kafkaConsumed      *prometheus.CounterVec
+	kafkaConsumeErrors *prometheus.CounterVec
+	kafkaProduced      *prometheus.CounterVec
+	kafkaProduceErrors *prometheus.CounterVec
+
+	handlerDuration *prometheus.HistogramVec
+	intakeOutcomes  *prometheus.CounterVec
+
+	generatorBatches prometheus.Counter
+	generatorEvents  *prometheus.CounterVec
+
+	alertTransitions *prometheus.CounterVec
+
+	webhookDeliveries *prometheus.CounterVec
+
+	anomalies *prometheus.CounterVec
+
+	statusTransitions *prometheus.CounterVec
+	forecastRuns      *prometheus.CounterVec
+	qualityFlags      *prometheus.CounterVec
+	ingestionDelay    prometheus.Histogram
+	lateReadings      *prometheus.CounterVec
+	rollupBuckets     *prometheus.CounterVec
+	retentionPurged   *prometheus.CounterVec
+	plantsOffline     prometheus.Gauge
+	quarantineEvents  *prometheus.CounterVec
+}
+
+var _ output.MetricsRecorderInterface = &PrometheusRecorder{}
+
+// NewPrometheusRecorder crea el registry con los collectors de Go y del proceso ya registrados
+func NewPrometheusRecorder() *PrometheusRecorder {
+	registry := prometheus.NewRegistry()
+
+	r := &PrometheusRecorder{
+		registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		kafkaConsumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "messages_consumed_total",
+			Help:      "Kafka messages consumed by topic.",
+		}, []string{"topic"}),
+		kafkaConsumeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "consume_errors_total",
+			Help:      "Kafka read errors by topic.",
+		}, []string{"topic"}),
+		kafkaProduced: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "messages_produced_total",
+			Help:      "Kafka messages produced by topic.",
+		}, []string{"topic"}),
+		kafkaProduceErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "produce_errors_total",
+			Help:      "Kafka produce errors by topic.",
+		}, []string{"topic"}),
+		handlerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "kafka",
+			Name:      "handler_duration_seconds",
+			Help:      "Message handler latency by handler and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"handler", "result"}),
+		intakeOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "intake",
+			Name:      "messages_total",
+			Help:      "Intake messages by outcome.",
+		}, []string{"outcome"}),
+		generatorBatches: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "generator",
+			Name:      "batches_total",
+			Help:      "Event generator batches sent.",
+		}),
+		generatorEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "generator",
+			Name:      "events_total",
+			Help:      "Event generator events by result.",
+		}, []string{"result"}),
+		alertTransitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "alerts",
+			Name:      "transitions_total",
+			Help:      "Alerts fired and resolved by severity.",
+		}, []string{"severity", "status"}),
+		webhookDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "deliveries_total",
+			Help:      "Webhook delivery outcomes (delivered, retried, failed, dropped).",
+		}, []string{"outcome"}),
+		anomalies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "anomalies",
+			Name:      "detected_total",
+			Help:      "Anomalous readings detected by metric and direction.",
+		}, []string{"metric", "direction"}),
+		statusTransitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "plant_status",
+			Name:      "transitions_total",
+			Help:      "Plant status changes by target status and outcome (applied, not_allowed, rejected).",
+		}, []string{"status", "outcome"}),
+		forecastRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "forecast",
+			Name:      "runs_total",
+			Help:      "Per-plant forecast retrainings by outcome (trained, skipped, failed).",
+		}, []string{"outcome"}),
+		qualityFlags: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "reading_quality",
+			Name:      "flags_total",
+			Help:      "Failed plausibility checks on incoming readings by check and metric.",
+		}, []string{"check", "metric"}),
+		ingestionDelay: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "intake",
+			Name:      "ingestion_delay_seconds",
+			Help:      "Delay between the event time of a saved reading and its ingestion.",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600, 21600, 86400},
+		}),
+		lateReadings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "intake",
+			Name:      "late_readings_total",
+			Help:      "Readings older than the newest one of their plant by outcome (recompute, too_late).",
+		}, []string{"outcome"}),
+		rollupBuckets: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rollup",
+			Name:      "buckets_total",
+			Help:      "Reading rollup buckets built by tier (1m, 1h, 1d).",
+		}, []string{"tier"}),
+		retentionPurged: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "retention",
+			Name:      "purged_rows_total",
+			Help:      "Rows deleted by the retention policy by tier (raw, 1m, 1h).",
+		}, []string{"tier"}),
+		plantsOffline: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "connectivity",
+			Name:      "plants_offline",
+			Help:      "Plants silent for longer than their offline threshold at the last check.",
+		}),
+		quarantineEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "quarantine",
+			Name:      "events_total",
+			Help:      "Messages from unknown plants by outcome (quarantined, dropped, released, discarded).",
+		}, []string{"outcome"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		r.httpRequests,
+		r.httpDuration,
+		r.kafkaConsumed,
+		r.kafkaConsumeErrors,
+		r.kafkaProduced,
+		r.kafkaProduceErrors,
+		r.handlerDuration,
+		r.intakeOutcomes,
+		r.generatorBatches,
+		r.generatorEvents,
+		r.alertTransitions,
+		r.webhookDeliveries,
+		r.anomalies,
+		r.statusTransitions,
+		r.forecastRuns,
+		r.qualityFlags,
+		r.ingestionDelay,
+		r.lateReadings,
+		r.rollupBuckets,
+		r.retentionPurged,
+		r.plantsOffline,
+		r.quarantineEvents,
+	)
+
+	return r
+}
+
+// RegisterDBStats expone las estadísticas del pool de sql.DB (conexiones abiertas, en uso, esperas...)
+func (r *PrometheusRecorder) RegisterDBStats(db *sql.DB, dbName string) error {
+	return r.registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Registry permite a otros adapters registrar sus propios collectors
+func (r *PrometheusRecorder) Registry() *prometheus.Registry {
+	return r.registry
+}
+
+// Handler devuelve el handler HTTP para GET /metrics
+func (r *PrometheusRecorder) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
+
+// GinMiddleware registra contador y latencia de cada request por ruta de gin y status
+// Se usa la ruta registrada (ctx.FullPath) y no la URL para evitar cardinalidad ilimitada
+func (r *PrometheusRecorder) GinMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(ctx.Writer.Status())
+		method := ctx.Request.Method
+
+		r.httpRequests.WithLabelValues(route, method, status).Inc()
+		r.httpDuration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+func (r *PrometheusRecorder) KafkaMessageConsumed(topic string) {
+	r.kafkaConsumed.WithLabelValues(topicLabel(topic)).Inc()
+}
+
+func (r *PrometheusRecorder) KafkaConsumeError(topic string) {
+	r.kafkaConsumeErrors.WithLabelValues(topicLabel(topic)).Inc()
+}
+
+func (r *PrometheusRecorder) KafkaMessageProduced(topic string) {
+	r.kafkaProduced.WithLabelValues(topicLabel(topic)).Inc()
+}
+
+func (r *PrometheusRecorder) KafkaProduceError(topic string) {
+	r.kafkaProduceErrors.WithLabelValues(topicLabel(topic)).Inc()
+}
+
+func (r *PrometheusRecorder) ObserveHandlerDuration(handler string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	r.handlerDuration.WithLabelValues(handler, result).Observe(duration.Seconds())
+}
+
+func (r *PrometheusRecorder) IntakeOutcome(outcome string) {
+	r.intakeOutcomes.WithLabelValues(outcome).Inc()
+}
+
+func (r *PrometheusRecorder) GeneratorBatch(sent, failed int) {
+	r.generatorBatches.Inc()
+	r.generatorEvents.WithLabelValues("sent").Add(float64(sent))
+	r.generatorEvents.WithLabelValues("failed").Add(float64(failed))
+}
+
+func (r *PrometheusRecorder) AlertTransition(severity, status string) {
+	r.alertTransitions.WithLabelValues(severity, status).Inc()
+}
+
+func (r *PrometheusRecorder) WebhookDelivery(outcome string) {
+	r.webhookDeliveries.WithLabelValues(outcome).Inc()
+}
+
+func (r *PrometheusRecorder) AnomalyDetected(metric, direction string) {
+	r.anomalies.WithLabelValues(metric, direction).Inc()
+}
+
+func (r *PrometheusRecorder) PlantStatusTransition(status, outcome string) {
+	r.statusTransitions.WithLabelValues(status, outcome).Inc()
+}
+
+func (r *PrometheusRecorder) ForecastRun(outcome string) {
+	r.forecastRuns.WithLabelValues(outcome).Inc()
+}
+
+func (r *PrometheusRecorder) ReadingQualityFlag(check, metric string) {
+	r.qualityFlags.WithLabelValues(check, metric).Inc()
+}
+
+// IngestionDelay registra la demora; una demora negativa (reloj de la planta adelantado) cuenta como 0
+func (r *PrometheusRecorder) IngestionDelay(delay time.Duration) {
+	r.ingestionDelay.Observe(max(delay, 0).Seconds())
+}
+
+func (r *PrometheusRecorder) LateReading(outcome string) {
+	r.lateReadings.WithLabelValues(outcome).Inc()
+}
+
+func (r *PrometheusRecorder) RollupBuckets(tier string, buckets int) {
+	r.rollupBuckets.WithLabelValues(tier).Add(float64(buckets))
+}
+
+func (r *PrometheusRecorder) RetentionPurged(tier string, rows int64) {
+	r.retentionPurged.WithLabelValues(tier).Add(float64(rows))
+}
+
+func (r *PrometheusRecorder) PlantsOffline(count int) {
+	r.plantsOffline.Set(float64(count))
+}
+
+func (r *PrometheusRecorder) QuarantineEvents(outcome string, count int) {
+	r.quarantineEvents.WithLabelValues(outcome).Add(float64(count))
+}
+
+// topicLabel evita labels vacíos si un productor o consumidor no informa el topic
+func topicLabel(topic string) string {
+	if topic == "" {
+		return "unknown"
+	}
+	return topic
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AlertRepository implementa la persistencia de alertas disparadas y resueltas (tabla alerts)
+type AlertRepository struct {
+	db *gorm.DB
+}
+
+var _ output.AlertRepositoryInterface = &AlertRepository{}
+
+// NewAlertRepository crea una nueva instancia del repositorio de alertas
+func NewAlertRepository(db *gorm.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+func (r *AlertRepository) Create(ctx context.Context, alert *entities.AlertEntity) (*entities.AlertEntity, error) {
+	if err := r.db.WithContext(ctx).Create(alert).Error; err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func (r *AlertRepository) Update(ctx context.Context, alert *entities.AlertEntity) (*entities.AlertEntity, error) {
+	if err := r.db.WithContext(ctx).Save(alert).Error; err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// FindFiring devuelve las alertas activas; el AlertEngine las usa para reconstruir su estado
+func (r *AlertRepository) FindFiring(ctx context.Context) ([]*entities.AlertEntity, error) {
+	var alerts []*entities.AlertEntity
+	if err := r.db.WithContext(ctx).Where("status = ?", entities.AlertStatusFiring).Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// FindAll lista alertas aplicando los filtros indicados (más recientes primero)
+func (r *AlertRepository) FindAll(ctx context.Context, filter entities.AlertFilter) ([]*entities.AlertEntity, error) {
+	query := r.db.WithContext(ctx).Order("fired_at DESC")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.PlantSourceId != nil {
+		query = query.Where("plant_source_id = ?", *filter.PlantSourceId)
+	}
+	if filter.RuleID != nil {
+		query = query.Where("rule_id = ?", *filter.RuleID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var alerts []*entities.AlertEntity
+	if err := query.Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// CountFiringByPlants cuenta las alertas firing de las plantas indicadas por planta y severidad
+func (r *AlertRepository) CountFiringByPlants(ctx context.Context, plantIDs []uuid.UUID) ([]entities.PlantAlertCount, error) {
+	counts := make([]entities.PlantAlertCount, 0)
+	if len(plantIDs) == 0 {
+		return counts, nil
+	}
+	err := r.db.WithContext(ctx).Model(&entities.AlertEntity{}).
+		Select("plant_source_id, severity, COUNT(*) AS count").
+		Where("status = ? AND plant_source_id IN ?", entities.AlertStatusFiring, plantIDs).
+		Group("plant_source_id, severity").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *AlertRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.AlertEntity, error) {
+	var alert entities.AlertEntity
+	if err := r.db.WithContext(ctx).First(&alert, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &alert, nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AlertRuleRepository implementa la persistencia de las reglas de alerta (tabla alert_rules)
+type AlertRuleRepository struct {
+	db *gorm.DB
+}
+
+var _ output.AlertRuleRepositoryInterface = &AlertRuleRepository{}
+
+// NewAlertRuleRepository crea una nueva instancia del repositorio de reglas
+func NewAlertRuleRepository(db *gorm.DB) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *entities.AlertRuleEntity) (*entities.AlertRuleEntity, error) {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *AlertRuleRepository) FindAll(ctx context.Context) ([]*entities.AlertRuleEntity, error) {
+	var rules []*entities.AlertRuleEntity
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FindEnabled devuelve solo las reglas activas (las que evalúa el AlertEngine)
+func (r *AlertRuleRepository) FindEnabled(ctx context.Context) ([]*entities.AlertRuleEntity, error) {
+	var rules []*entities.AlertRuleEntity
+	if err := r.db.WithContext(ctx).Where("enabled = ?", true).Order("created_at ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *AlertRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.AlertRuleEntity, error) {
+	var rule entities.AlertRuleEntity
+	if err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *entities.AlertRuleEntity) (*entities.AlertRuleEntity, error) {
+	if err := r.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *AlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.AlertRuleEntity{}, "id = ?", id).Error
+}
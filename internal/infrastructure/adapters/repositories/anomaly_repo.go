@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnomalyRepository implementa la persistencia de las anomalías detectadas (tabla anomalies)
+type AnomalyRepository struct {
+	db *gorm.DB
+}
+
+var _ output.AnomalyRepositoryInterface = &AnomalyRepository{}
+
+// NewAnomalyRepository crea una nueva instancia del repositorio de anomalías
+func NewAnomalyRepository(db *gorm.DB) *AnomalyRepository {
+	return &AnomalyRepository{db: db}
+}
+
+func (r *AnomalyRepository) Create(ctx context.Context, anomaly *entities.AnomalyEntity) (*entities.AnomalyEntity, error) {
+	if err := r.db.WithContext(ctx).Create(anomaly).Error; err != nil {
+		return nil, err
+	}
+	return anomaly, nil
+}
+
+// FindAll lista anomalías aplicando los filtros indicados (más recientes primero)
+func (r *AnomalyRepository) FindAll(ctx context.Context, filter entities.AnomalyFilter) ([]*entities.AnomalyEntity, error) {
+	query := r.db.WithContext(ctx).Order("detected_at DESC")
+	if filter.PlantSourceId != nil {
+		query = query.Where("plant_source_id = ?", *filter.PlantSourceId)
+	}
+	if filter.Metric != "" {
+		query = query.Where("metric = ?", filter.Metric)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("detected_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("detected_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var anomalies []*entities.AnomalyEntity
+	if err := query.Find(&anomalies).Error; err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
+
+func (r *AnomalyRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.AnomalyEntity, error) {
+	var anomaly entities.AnomalyEntity
+	if err := r.db.WithContext(ctx).First(&anomaly, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &anomaly, nil
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnomalySettingsRepository implementa la configuración del detector por planta (tabla anomaly_settings)
+type AnomalySettingsRepository struct {
+	db *gorm.DB
+}
+
+var _ output.AnomalySettingsRepositoryInterface = &AnomalySettingsRepository{}
+
+// NewAnomalySettingsRepository crea una nueva instancia del repositorio de configuración
+func NewAnomalySettingsRepository(db *gorm.DB) *AnomalySettingsRepository {
+	return &AnomalySettingsRepository{db: db}
+}
+
+func (r *AnomalySettingsRepository) FindAll(ctx context.Context) ([]*entities.AnomalySettingsEntity, error) {
+	var settings []*entities.AnomalySettingsEntity
+	if err := r.db.WithContext(ctx).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *AnomalySettingsRepository) FindByPlant(ctx context.Context, plantID uuid.UUID) (*entities.AnomalySettingsEntity, error) {
+	var settings entities.AnomalySettingsEntity
+	if err := r.db.WithContext(ctx).First(&settings, "plant_source_id = ?", plantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// Save crea o reemplaza la configuración de la planta
+func (r *AnomalySettingsRepository) Save(ctx context.Context, settings *entities.AnomalySettingsEntity) (*entities.AnomalySettingsEntity, error) {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "plant_source_id"}}, UpdateAll: true}).
+		Create(settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *AnomalySettingsRepository) Delete(ctx context.Context, plantID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.AnomalySettingsEntity{}, "plant_source_id = ?", plantID).Error
+}
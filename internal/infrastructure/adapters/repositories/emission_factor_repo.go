@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmissionFactorRepository implementa la persistencia de los factores de emisión (tabla emission_factors)
+type EmissionFactorRepository struct {
+	db *gorm.DB
+}
+
+var _ output.EmissionFactorRepositoryInterface = &EmissionFactorRepository{}
+
+// NewEmissionFactorRepository crea una nueva instancia del repositorio de factores de emisión
+func NewEmissionFactorRepository(db *gorm.DB) *EmissionFactorRepository {
+	return &EmissionFactorRepository{db: db}
+}
+
+// Create guarda el factor con la versión siguiente a la más alta
+// La tabla se bloquea durante la transacción para que dos altas simultáneas no tomen la misma versión
+func (r *EmissionFactorRepository) Create(ctx context.Context, factor *entities.EmissionFactorEntity) (*entities.EmissionFactorEntity, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE emission_factors IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		var latest int64
+		if err := tx.Model(&entities.EmissionFactorEntity{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		factor.Version = latest + 1
+		return tx.Create(factor).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return factor, nil
+}
+
+// FindAll lista los factores del filtro ordenados por versión
+func (r *EmissionFactorRepository) FindAll(ctx context.Context, filter entities.EmissionFactorFilter) ([]*entities.EmissionFactorEntity, error) {
+	query := r.db.WithContext(ctx).Order("version ASC")
+	if filter.RegionNodeID != nil {
+		query = query.Where("region_node_id = ?", *filter.RegionNodeID)
+	}
+	if filter.DefaultOnly {
+		query = query.Where("region_node_id IS NULL")
+	}
+	if filter.MaxVersion > 0 {
+		query = query.Where("version <= ?", filter.MaxVersion)
+	}
+
+	var factors []*entities.EmissionFactorEntity
+	if err := query.Find(&factors).Error; err != nil {
+		return nil, err
+	}
+	return factors, nil
+}
+
+func (r *EmissionFactorRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.EmissionFactorEntity, error) {
+	var factor entities.EmissionFactorEntity
+	if err := r.db.WithContext(ctx).First(&factor, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &factor, nil
+}
+
+// LatestVersion devuelve la versión más alta (0 si no hay factores)
+func (r *EmissionFactorRepository) LatestVersion(ctx context.Context) (int64, error) {
+	var latest int64
+	err := r.db.WithContext(ctx).Model(&entities.EmissionFactorEntity{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error
+	return latest, err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnergyDailyRepository implementa la persistencia de la energía diaria por planta (tabla energy_daily)
+type EnergyDailyRepository struct {
+	db *gorm.DB
+}
+
+var _ output.EnergyDailyRepositoryInterface = &EnergyDailyRepository{}
+
+// NewEnergyDailyRepository crea una nueva instancia del repositorio de energía diaria
+func NewEnergyDailyRepository(db *gorm.DB) *EnergyDailyRepository {
+	return &EnergyDailyRepository{db: db}
+}
+
+// AddIncrements suma los incrementos a los totales de cada planta y día (crea el día si no existe)
+func (r *EnergyDailyRepository) AddIncrements(ctx context.Context, increments []*entities.EnergyDailyEntity) error {
+	if len(increments) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "plant_source_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"generated_mwh": gorm.Expr("energy_daily.generated_mwh + excluded.generated_mwh"),
+				"consumed_mwh":  gorm.Expr("energy_daily.consumed_mwh + excluded.consumed_mwh"),
+				"covered_hours": gorm.Expr("energy_daily.covered_hours + excluded.covered_hours"),
+				"updated_at":    gorm.Expr("excluded.updated_at"),
+			}),
+		}).
+		Create(increments).Error
+}
+
+// FindDaily lista los totales diarios del filtro ordenados por día
+func (r *EnergyDailyRepository) FindDaily(ctx context.Context, filter entities.EnergyDailyFilter) ([]*entities.EnergyDailyEntity, error) {
+	query := r.db.WithContext(ctx).Order("day ASC")
+	if filter.PlantSourceId != nil {
+		query = query.Where("plant_source_id = ?", *filter.PlantSourceId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("day >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("day < ?", filter.To)
+	}
+
+	var days []*entities.EnergyDailyEntity
+	if err := query.Find(&days).Error; err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// SumByPlants suma la energía de los días [from, to) de cada una de las plantas indicadas
+func (r *EnergyDailyRepository) SumByPlants(ctx context.Context, plantIDs []uuid.UUID, from, to time.Time) ([]entities.PlantEnergyTotal, error) {
+	totals := make([]entities.PlantEnergyTotal, 0)
+	if len(plantIDs) == 0 {
+		return totals, nil
+	}
+	err := r.db.WithContext(ctx).Model(&entities.EnergyDailyEntity{}).
+		Select("plant_source_id, SUM(generated_mwh) AS generated_mwh, SUM(consumed_mwh) AS consumed_mwh").
+		Where("plant_source_id IN ? AND day >= ? AND day < ?", plantIDs, from, to).
+		Group("plant_source_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// ReplaceRange reemplaza en una transacción los días [from, to) de la planta por los indicados
+func (r *EnergyDailyRepository) ReplaceRange(ctx context.Context, plantID uuid.UUID, from, to time.Time, days []*entities.EnergyDailyEntity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("plant_source_id = ? AND day >= ? AND day < ?", plantID, from, to).
+			Delete(&entities.EnergyDailyEntity{}).Error
+		if err != nil {
+			return err
+		}
+		if len(days) == 0 {
+			return nil
+		}
+		return tx.Create(days).Error
+	})
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 
 	"monitoring-energy-service/internal/domain/entities"
@@ -31,14 +32,22 @@ func NewEnergyPlantRepository(db *gorm.DB) *EnergyPlantRepository {
 	return &EnergyPlantRepository{db: db}
 }
 
+// Create da de alta la planta; si trae ID se respeta (plantas registradas desde la cuarentena)
+func (r *EnergyPlantRepository) Create(ctx context.Context, plant *entities.EnergyPlants) (*entities.EnergyPlants, error) {
+	if err := r.db.WithContext(ctx).Create(plant).Error; err != nil {
+		return nil, err
+	}
+	return plant, nil
+}
+
 // FindByID busca una planta por su UUID
 // CAMBIO: Método nuevo
 // RAZÓN: Permite validar que una planta existe antes de guardar eventos
 // CAMBIO: Ahora traduce gorm.ErrRecordNotFound a domainerrors.ErrNotFound
 // RAZÓN: Permite a los handlers distinguir entre 404 (not found) y 500 (internal error)
-func (r *EnergyPlantRepository) FindByID(id uuid.UUID) (*entities.EnergyPlants, error) {
+func (r *EnergyPlantRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.EnergyPlants, error) {
 	var plant entities.EnergyPlants
-	if err := r.db.First(&plant, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&plant, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domainerrors.ErrNotFound
 		}
@@ -47,12 +56,57 @@ func (r *EnergyPlantRepository) FindByID(id uuid.UUID) (*entities.EnergyPlants,
 	return &plant, nil
 }
 
+// FindAll devuelve todas las plantas ordenadas por nombre
+func (r *EnergyPlantRepository) FindAll(ctx context.Context) ([]*entities.EnergyPlants, error) {
+	var plants []*entities.EnergyPlants
+	if err := r.db.WithContext(ctx).Order("plant_name ASC").Find(&plants).Error; err != nil {
+		return nil, err
+	}
+	return plants, nil
+}
+
+// FindByPortfolioNodes devuelve las plantas asignadas a cualquiera de los nodos indicados
+func (r *EnergyPlantRepository) FindByPortfolioNodes(ctx context.Context, nodeIDs []uuid.UUID) ([]*entities.EnergyPlants, error) {
+	plants := make([]*entities.EnergyPlants, 0)
+	if len(nodeIDs) == 0 {
+		return plants, nil
+	}
+	if err := r.db.WithContext(ctx).Where("portfolio_node_id IN ?", nodeIDs).Order("plant_name ASC").Find(&plants).Error; err != nil {
+		return nil, err
+	}
+	return plants, nil
+}
+
+// UpdatePortfolioNode asigna la planta a un nodo del portfolio (nil = sin asignar)
+func (r *EnergyPlantRepository) UpdatePortfolioNode(ctx context.Context, plantID uuid.UUID, nodeID *uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entities.EnergyPlants{}).Where("id = ?", plantID).Update("portfolio_node_id", nodeID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateExpectedInterval guarda el intervalo de reporte esperado de la planta (nil = valor por defecto)
+func (r *EnergyPlantRepository) UpdateExpectedInterval(ctx context.Context, plantID uuid.UUID, seconds *int64) error {
+	result := r.db.WithContext(ctx).Model(&entities.EnergyPlants{}).Where("id = ?", plantID).Update("expected_interval_seconds", seconds)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrNotFound
+	}
+	return nil
+}
+
 // Exists verifica rápidamente si una planta existe por UUID
 // CAMBIO: Método nuevo
 // RAZÓN: Método optimizado para solo verificar existencia sin cargar toda la entidad
-func (r *EnergyPlantRepository) Exists(id uuid.UUID) (bool, error) {
+func (r *EnergyPlantRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&entities.EnergyPlants{}).Where("id = ?", id).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&entities.EnergyPlants{}).Where("id = ?", id).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"monitoring-energy-service/internal/domain/entities"
 	domainerrors "monitoring-energy-service/internal/domain/errors"
@@ -34,19 +36,22 @@ func NewEventRepository(db *gorm.DB) *EventRepository {
 // Create guarda un nuevo evento en la base de datos
 // CAMBIO: Método nuevo
 // RAZÓN: Permite al IntakeHandler guardar eventos consumidos desde Kafka
-func (r *EventRepository) Create(entity *entities.EventEntity) (*entities.EventEntity, error) {
-	if err := r.db.Create(entity).Error; err != nil {
+func (r *EventRepository) Create(ctx context.Context, entity *entities.EventEntity) (*entities.EventEntity, error) {
+	if err := r.db.WithContext(ctx).Create(entity).Error; err != nil {
 		return nil, err
 	}
 	return entity, nil
 }
 
-// FindAll obtiene todos los eventos ordenados por fecha de creación (más recientes primero)
+// FindAll obtiene todos los eventos ordenados por event_time (más recientes primero)
 // CAMBIO: Método nuevo
 // RAZÓN: Permite a la API REST devolver todos los eventos para consulta
-func (r *EventRepository) FindAll() ([]*entities.EventEntity, error) {
+// CAMBIO: Recibe la calidad a devolver (vacío = todas)
+// CAMBIO: Ordena por event_time en lugar de created_at
+// RAZÓN: Una lectura que llega tarde aparece en el lugar de cuando se tomó
+func (r *EventRepository) FindAll(ctx context.Context, quality string) ([]*entities.EventEntity, error) {
 	var entities []*entities.EventEntity
-	if err := r.db.Order("created_at DESC").Find(&entities).Error; err != nil {
+	if err := withQuality(r.db.WithContext(ctx), quality).Order("event_time DESC").Find(&entities).Error; err != nil {
 		return nil, err
 	}
 	return entities, nil
@@ -57,9 +62,9 @@ func (r *EventRepository) FindAll() ([]*entities.EventEntity, error) {
 // RAZÓN: Permite a la API REST obtener un evento individual
 // CAMBIO: Ahora traduce gorm.ErrRecordNotFound a domainerrors.ErrNotFound
 // RAZÓN: Permite a los handlers distinguir entre 404 (not found) y 500 (internal error)
-func (r *EventRepository) FindByID(id uuid.UUID) (*entities.EventEntity, error) {
+func (r *EventRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.EventEntity, error) {
 	var entity entities.EventEntity
-	if err := r.db.First(&entity, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&entity, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domainerrors.ErrNotFound
 		}
@@ -68,13 +73,93 @@ func (r *EventRepository) FindByID(id uuid.UUID) (*entities.EventEntity, error)
 	return &entity, nil
 }
 
+// FindByFilter devuelve los eventos de una planta y rango de event_time en orden cronológico
+// (el filtro por planta y rango usa idx_events_plant_event_time)
+func (r *EventRepository) FindByFilter(ctx context.Context, filter entities.EventFilter) ([]*entities.EventEntity, error) {
+	query := r.db.WithContext(ctx).Order("event_time ASC")
+	if filter.PlantSourceId != nil {
+		query = query.Where("plant_source_id = ?", *filter.PlantSourceId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("event_time >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("event_time < ?", filter.To)
+	}
+	query = withQuality(query, filter.Quality)
+
+	var events []*entities.EventEntity
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
 // FindByEventType filtra eventos por tipo (power_reading, alert, etc.)
 // CAMBIO: Método nuevo
 // RAZÓN: Permite filtrar eventos por tipo para análisis específicos
-func (r *EventRepository) FindByEventType(eventType string) ([]*entities.EventEntity, error) {
+// CAMBIO: Recibe la calidad a devolver (vacío = todas)
+// CAMBIO: Ordena por event_time en lugar de created_at
+func (r *EventRepository) FindByEventType(ctx context.Context, eventType, quality string) ([]*entities.EventEntity, error) {
 	var entities []*entities.EventEntity
-	if err := r.db.Where("event_type = ?", eventType).Order("created_at DESC").Find(&entities).Error; err != nil {
+	if err := withQuality(r.db.WithContext(ctx), quality).Where("event_type = ?", eventType).Order("event_time DESC").Find(&entities).Error; err != nil {
 		return nil, err
 	}
 	return entities, nil
 }
+
+// FindLatestByPlants devuelve el último evento good de cada planta tomado desde since
+// (DISTINCT ON sobre plant_source_id; el filtro por planta y event_time usa idx_events_plant_event_time)
+func (r *EventRepository) FindLatestByPlants(ctx context.Context, plantIDs []uuid.UUID, since time.Time) ([]*entities.EventEntity, error) {
+	events := make([]*entities.EventEntity, 0)
+	if len(plantIDs) == 0 {
+		return events, nil
+	}
+	err := r.db.WithContext(ctx).
+		Select("DISTINCT ON (plant_source_id) *").
+		Where("plant_source_id IN ? AND event_time >= ? AND quality = ?", plantIDs, since, entities.EventQualityGood).
+		Order("plant_source_id, event_time DESC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// FindEarliestEventTime devuelve el event_time más viejo guardado (cero si no hay eventos)
+func (r *EventRepository) FindEarliestEventTime(ctx context.Context) (time.Time, error) {
+	var earliest *time.Time
+	if err := r.db.WithContext(ctx).Model(&entities.EventEntity{}).Select("MIN(event_time)").Scan(&earliest).Error; err != nil {
+		return time.Time{}, err
+	}
+	if earliest == nil {
+		return time.Time{}, nil
+	}
+	return *earliest, nil
+}
+
+// DeleteBefore purga los eventos con event_time anterior a before en lotes de batchSize
+// (cada lote es una sentencia corta para no bloquear el intake) y devuelve cuántos borró
+func (r *EventRepository) DeleteBefore(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	var total int64
+	for {
+		result := r.db.WithContext(ctx).
+			Where("id IN (?)", r.db.Model(&entities.EventEntity{}).Select("id").Where("event_time < ?", before).Limit(batchSize)).
+			Delete(&entities.EventEntity{})
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// withQuality filtra por la columna quality (vacío = sin filtro)
+func withQuality(query *gorm.DB, quality string) *gorm.DB {
+	if quality == "" {
+		return query
+	}
+	return query.Where("quality = ?", quality)
+}
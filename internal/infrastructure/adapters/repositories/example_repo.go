@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 
 	"monitoring-energy-service/internal/domain/entities"
@@ -24,9 +25,9 @@ func NewExampleRepository(db *gorm.DB) *ExampleRepository {
 // FindByID busca un ejemplo específico por su ID
 // CAMBIO: Ahora traduce gorm.ErrRecordNotFound a domainerrors.ErrNotFound
 // RAZÓN: Permite a los handlers distinguir entre 404 (not found) y 500 (internal error)
-func (r *ExampleRepository) FindByID(id uuid.UUID) (*entities.ExampleEntity, error) {
+func (r *ExampleRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.ExampleEntity, error) {
 	var entity entities.ExampleEntity
-	if err := r.db.First(&entity, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&entity, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domainerrors.ErrNotFound
 		}
@@ -35,28 +36,28 @@ func (r *ExampleRepository) FindByID(id uuid.UUID) (*entities.ExampleEntity, err
 	return &entity, nil
 }
 
-func (r *ExampleRepository) FindAll() ([]*entities.ExampleEntity, error) {
+func (r *ExampleRepository) FindAll(ctx context.Context) ([]*entities.ExampleEntity, error) {
 	var entities []*entities.ExampleEntity
-	if err := r.db.Find(&entities).Error; err != nil {
+	if err := r.db.WithContext(ctx).Find(&entities).Error; err != nil {
 		return nil, err
 	}
 	return entities, nil
 }
 
-func (r *ExampleRepository) Create(entity *entities.ExampleEntity) (*entities.ExampleEntity, error) {
-	if err := r.db.Create(entity).Error; err != nil {
+func (r *ExampleRepository) Create(ctx context.Context, entity *entities.ExampleEntity) (*entities.ExampleEntity, error) {
+	if err := r.db.WithContext(ctx).Create(entity).Error; err != nil {
 		return nil, err
 	}
 	return entity, nil
 }
 
-func (r *ExampleRepository) Update(entity *entities.ExampleEntity) (*entities.ExampleEntity, error) {
-	if err := r.db.Save(entity).Error; err != nil {
+func (r *ExampleRepository) Update(ctx context.Context, entity *entities.ExampleEntity) (*entities.ExampleEntity, error) {
+	if err := r.db.WithContext(ctx).Save(entity).Error; err != nil {
 		return nil, err
 	}
 	return entity, nil
 }
 
-func (r *ExampleRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&entities.ExampleEntity{}, "id = ?", id).Error
+func (r *ExampleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.ExampleEntity{}, "id = ?", id).Error
 }
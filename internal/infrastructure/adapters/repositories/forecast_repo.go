@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ForecastRepository implementa la persistencia de las corridas de pronóstico (tabla forecasts)
+type ForecastRepository struct {
+	db *gorm.DB
+}
+
+var _ output.ForecastRepositoryInterface = &ForecastRepository{}
+
+// NewForecastRepository crea una nueva instancia del repositorio de pronósticos
+func NewForecastRepository(db *gorm.DB) *ForecastRepository {
+	return &ForecastRepository{db: db}
+}
+
+func (r *ForecastRepository) Create(ctx context.Context, forecast *entities.ForecastEntity) (*entities.ForecastEntity, error) {
+	if err := r.db.WithContext(ctx).Create(forecast).Error; err != nil {
+		return nil, err
+	}
+	return forecast, nil
+}
+
+// FindLatest devuelve la corrida más reciente de la planta (ErrNotFound si nunca se entrenó)
+func (r *ForecastRepository) FindLatest(ctx context.Context, plantID uuid.UUID) (*entities.ForecastEntity, error) {
+	var forecast entities.ForecastEntity
+	err := r.db.WithContext(ctx).
+		Where("plant_source_id = ?", plantID).
+		Order("created_at DESC").
+		First(&forecast).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &forecast, nil
+}
+
+// FindByPlant lista las corridas de la planta (más recientes primero)
+func (r *ForecastRepository) FindByPlant(ctx context.Context, plantID uuid.UUID, filter entities.ForecastFilter) ([]*entities.ForecastEntity, error) {
+	query := r.db.WithContext(ctx).Where("plant_source_id = ?", plantID).Order("created_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var forecasts []*entities.ForecastEntity
+	if err := query.Find(&forecasts).Error; err != nil {
+		return nil, err
+	}
+	return forecasts, nil
+}
+
+// DeleteCreatedBefore borra las corridas creadas antes de before y devuelve cuántas se borraron
+func (r *ForecastRepository) DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&entities.ForecastEntity{})
+	return result.RowsAffected, result.Error
+}
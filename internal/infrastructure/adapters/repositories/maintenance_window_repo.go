@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaintenanceWindowRepository implementa la persistencia de ventanas de mantenimiento (tabla maintenance_windows)
+type MaintenanceWindowRepository struct {
+	db *gorm.DB
+}
+
+var _ output.MaintenanceWindowRepositoryInterface = &MaintenanceWindowRepository{}
+
+// NewMaintenanceWindowRepository crea una nueva instancia del repositorio de ventanas de mantenimiento
+func NewMaintenanceWindowRepository(db *gorm.DB) *MaintenanceWindowRepository {
+	return &MaintenanceWindowRepository{db: db}
+}
+
+func (r *MaintenanceWindowRepository) Create(ctx context.Context, window *entities.MaintenanceWindowEntity) (*entities.MaintenanceWindowEntity, error) {
+	if err := r.db.WithContext(ctx).Create(window).Error; err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+// FindAll lista las ventanas aplicando los filtros indicados (por inicio de la primera ocurrencia)
+func (r *MaintenanceWindowRepository) FindAll(ctx context.Context, filter entities.MaintenanceWindowFilter) ([]*entities.MaintenanceWindowEntity, error) {
+	query := r.db.WithContext(ctx).Order("starts_at ASC")
+	if filter.PlantSourceId != nil {
+		query = query.Where("plant_source_id = ?", *filter.PlantSourceId)
+	}
+
+	var windows []*entities.MaintenanceWindowEntity
+	if err := query.Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+func (r *MaintenanceWindowRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.MaintenanceWindowEntity, error) {
+	var window entities.MaintenanceWindowEntity
+	if err := r.db.WithContext(ctx).First(&window, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &window, nil
+}
+
+func (r *MaintenanceWindowRepository) Update(ctx context.Context, window *entities.MaintenanceWindowEntity) (*entities.MaintenanceWindowEntity, error) {
+	if err := r.db.WithContext(ctx).Save(window).Error; err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+func (r *MaintenanceWindowRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.MaintenanceWindowEntity{}, "id = ?", id).Error
+}
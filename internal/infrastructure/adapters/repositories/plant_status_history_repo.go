@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PlantStatusHistoryRepository implementa el historial de estados de las plantas (tabla plant_status_history)
+type PlantStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+var _ output.PlantStatusHistoryRepositoryInterface = &PlantStatusHistoryRepository{}
+
+// NewPlantStatusHistoryRepository crea una nueva instancia del repositorio de historial de estados
+func NewPlantStatusHistoryRepository(db *gorm.DB) *PlantStatusHistoryRepository {
+	return &PlantStatusHistoryRepository{db: db}
+}
+
+// RecordTransition guarda el cambio y actualiza status y status_changed_at de la planta
+func (r *PlantStatusHistoryRepository) RecordTransition(ctx context.Context, transition *entities.PlantStatusHistoryEntity) (*entities.PlantStatusHistoryEntity, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(transition).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.EnergyPlants{}).
+			Where("id = ?", transition.PlantSourceId).
+			Updates(map[string]any{
+				"status":            transition.ToStatus,
+				"status_changed_at": transition.ChangedAt,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transition, nil
+}
+
+// FindByPlant lista los cambios de estado de la planta en orden cronológico
+func (r *PlantStatusHistoryRepository) FindByPlant(ctx context.Context, plantID uuid.UUID, filter entities.PlantStatusFilter) ([]*entities.PlantStatusHistoryEntity, error) {
+	query := r.db.WithContext(ctx).Where("plant_source_id = ?", plantID).Order("changed_at ASC")
+	if !filter.From.IsZero() {
+		query = query.Where("changed_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("changed_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var history []*entities.PlantStatusHistoryEntity
+	if err := query.Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// FindLastBefore devuelve el último cambio anterior a before (ErrNotFound si no hay)
+func (r *PlantStatusHistoryRepository) FindLastBefore(ctx context.Context, plantID uuid.UUID, before time.Time) (*entities.PlantStatusHistoryEntity, error) {
+	var transition entities.PlantStatusHistoryEntity
+	err := r.db.WithContext(ctx).
+		Where("plant_source_id = ? AND changed_at < ?", plantID, before).
+		Order("changed_at DESC").
+		First(&transition).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &transition, nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PortfolioNodeRepository implementa la persistencia de la jerarquía del portfolio (tabla portfolio_nodes)
+type PortfolioNodeRepository struct {
+	db *gorm.DB
+}
+
+var _ output.PortfolioNodeRepositoryInterface = &PortfolioNodeRepository{}
+
+// NewPortfolioNodeRepository crea una nueva instancia del repositorio de nodos del portfolio
+func NewPortfolioNodeRepository(db *gorm.DB) *PortfolioNodeRepository {
+	return &PortfolioNodeRepository{db: db}
+}
+
+func (r *PortfolioNodeRepository) Create(ctx context.Context, node *entities.PortfolioNodeEntity) (*entities.PortfolioNodeEntity, error) {
+	if err := r.db.WithContext(ctx).Create(node).Error; err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// FindAll lista los nodos aplicando los filtros indicados, ordenados por nombre
+func (r *PortfolioNodeRepository) FindAll(ctx context.Context, filter entities.PortfolioNodeFilter) ([]*entities.PortfolioNodeEntity, error) {
+	query := r.db.WithContext(ctx).Order("name ASC")
+	if filter.Level != "" {
+		query = query.Where("level = ?", filter.Level)
+	}
+	if filter.ParentID != nil {
+		query = query.Where("parent_id = ?", *filter.ParentID)
+	}
+
+	var nodes []*entities.PortfolioNodeEntity
+	if err := query.Find(&nodes).Error; err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (r *PortfolioNodeRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.PortfolioNodeEntity, error) {
+	var node entities.PortfolioNodeEntity
+	if err := r.db.WithContext(ctx).First(&node, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &node, nil
+}
+
+func (r *PortfolioNodeRepository) Update(ctx context.Context, node *entities.PortfolioNodeEntity) (*entities.PortfolioNodeEntity, error) {
+	if err := r.db.WithContext(ctx).Save(node).Error; err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (r *PortfolioNodeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.PortfolioNodeEntity{}, "id = ?", id).Error
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QuarantineRepository implementa la persistencia de los mensajes en cuarentena (tabla quarantined_events)
+type QuarantineRepository struct {
+	db *gorm.DB
+}
+
+var _ output.QuarantineRepositoryInterface = &QuarantineRepository{}
+
+// NewQuarantineRepository crea una nueva instancia del repositorio de cuarentena
+func NewQuarantineRepository(db *gorm.DB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+func (r *QuarantineRepository) Create(ctx context.Context, event *entities.QuarantinedEventEntity) (*entities.QuarantinedEventEntity, error) {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CreateWithinLimit guarda el mensaje solo si la planta tiene menos de max en cuarentena (max <= 0 = sin límite)
+// El conteo y el insert van en una transacción con un advisory lock por planta, así dos mensajes
+// concurrentes no superan el límite
+func (r *QuarantineRepository) CreateWithinLimit(ctx context.Context, event *entities.QuarantinedEventEntity, max int) (bool, error) {
+	if max <= 0 {
+		_, err := r.Create(ctx, event)
+		return err == nil, err
+	}
+
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "quarantined_events:"+event.PlantSourceId.String()).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&entities.QuarantinedEventEntity{}).Where("plant_source_id = ?", event.PlantSourceId).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(max) {
+			return nil
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// Summaries agrupa los mensajes por planta, las más recientes primero
+// Source es el del último mensaje recibido; Registered lo completa el QuarantineService
+func (r *QuarantineRepository) Summaries(ctx context.Context, plantID *uuid.UUID) ([]*entities.QuarantinedPlant, error) {
+	query := r.db.WithContext(ctx).Model(&entities.QuarantinedEventEntity{}).
+		Select(`plant_source_id,
+			(array_agg(source ORDER BY created_at DESC))[1] AS source,
+			COUNT(*) AS events,
+			MIN(created_at) AS first_received_at,
+			MAX(created_at) AS last_received_at,
+			MIN(event_time) AS first_event_time,
+			MAX(event_time) AS last_event_time`).
+		Group("plant_source_id").
+		Order("last_received_at DESC")
+	if plantID != nil {
+		query = query.Where("plant_source_id = ?", *plantID)
+	}
+
+	var summaries []*entities.QuarantinedPlant
+	if err := query.Scan(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// FindByPlant devuelve los mensajes de la planta en orden cronológico (limit <= 0 = todos)
+func (r *QuarantineRepository) FindByPlant(ctx context.Context, plantID uuid.UUID, limit int) ([]*entities.QuarantinedEventEntity, error) {
+	query := r.db.WithContext(ctx).Where("plant_source_id = ?", plantID).Order("event_time ASC, created_at ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var events []*entities.QuarantinedEventEntity
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *QuarantineRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&entities.QuarantinedEventEntity{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *QuarantineRepository) DeleteByPlant(ctx context.Context, plantID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Where("plant_source_id = ?", plantID).Delete(&entities.QuarantinedEventEntity{})
+	return result.RowsAffected, result.Error
+}
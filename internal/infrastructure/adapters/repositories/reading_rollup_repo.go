@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReadingRollupRepository implementa la persistencia de los rollups de lecturas
+// (tablas reading_rollups y rollup_watermarks)
+type ReadingRollupRepository struct {
+	db *gorm.DB
+}
+
+var _ output.ReadingRollupRepositoryInterface = &ReadingRollupRepository{}
+
+// NewReadingRollupRepository crea una nueva instancia del repositorio de rollups
+func NewReadingRollupRepository(db *gorm.DB) *ReadingRollupRepository {
+	return &ReadingRollupRepository{db: db}
+}
+
+// Upsert guarda los buckets reemplazando los que ya existían (construir un rango dos veces da lo mismo)
+func (r *ReadingRollupRepository) Upsert(ctx context.Context, rollups []*entities.ReadingRollupEntity) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "plant_source_id"}, {Name: "resolution"}, {Name: "bucket_start"}, {Name: "metric"}},
+			DoUpdates: clause.AssignmentColumns([]string{"count", "sum", "min", "max"}),
+		}).
+		CreateInBatches(rollups, 500).Error
+}
+
+// Find lista los buckets del filtro ordenados por bucket_start
+func (r *ReadingRollupRepository) Find(ctx context.Context, filter entities.ReadingRollupFilter) ([]*entities.ReadingRollupEntity, error) {
+	query := r.db.WithContext(ctx).Where("resolution = ?", filter.Resolution).Order("bucket_start ASC")
+	if filter.PlantSourceId != nil {
+		query = query.Where("plant_source_id = ?", *filter.PlantSourceId)
+	}
+	if filter.Metric != "" {
+		query = query.Where("metric = ?", filter.Metric)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("bucket_start >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("bucket_start < ?", filter.To)
+	}
+
+	var rollups []*entities.ReadingRollupEntity
+	if err := query.Find(&rollups).Error; err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// ReplaceRange reemplaza en una transacción los buckets [from, to) del nivel y la planta por los indicados
+func (r *ReadingRollupRepository) ReplaceRange(ctx context.Context, plantID uuid.UUID, resolution string, from, to time.Time, rollups []*entities.ReadingRollupEntity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("plant_source_id = ? AND resolution = ? AND bucket_start >= ? AND bucket_start < ?", plantID, resolution, from, to).
+			Delete(&entities.ReadingRollupEntity{}).Error
+		if err != nil {
+			return err
+		}
+		if len(rollups) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rollups, 500).Error
+	})
+}
+
+// DeleteBefore purga los buckets del nivel anteriores a before y devuelve cuántos borró
+func (r *ReadingRollupRepository) DeleteBefore(ctx context.Context, resolution string, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("resolution = ? AND bucket_start < ?", resolution, before).
+		Delete(&entities.ReadingRollupEntity{})
+	return result.RowsAffected, result.Error
+}
+
+// FindWatermarks devuelve hasta dónde está construido cada nivel (los niveles nunca construidos no aparecen)
+func (r *ReadingRollupRepository) FindWatermarks(ctx context.Context) ([]*entities.RollupWatermarkEntity, error) {
+	var watermarks []*entities.RollupWatermarkEntity
+	if err := r.db.WithContext(ctx).Find(&watermarks).Error; err != nil {
+		return nil, err
+	}
+	return watermarks, nil
+}
+
+// SaveWatermark crea o actualiza hasta dónde está construido un nivel
+func (r *ReadingRollupRepository) SaveWatermark(ctx context.Context, resolution string, builtUntil time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "resolution"}},
+			DoUpdates: clause.AssignmentColumns([]string{"built_until", "updated_at"}),
+		}).
+		Create(&entities.RollupWatermarkEntity{Resolution: resolution, BuiltUntil: builtUntil}).Error
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookDeliveryRepository implementa el log de entregas de webhooks
+// (tablas webhook_deliveries y webhook_delivery_attempts)
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+var _ output.WebhookDeliveryRepositoryInterface = &WebhookDeliveryRepository{}
+
+// NewWebhookDeliveryRepository crea una nueva instancia del repositorio de entregas
+func NewWebhookDeliveryRepository(db *gorm.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *entities.WebhookDeliveryEntity) (*entities.WebhookDeliveryEntity, error) {
+	if err := r.db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Update guarda el estado de la entrega sin tocar los intentos ya registrados
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery *entities.WebhookDeliveryEntity) (*entities.WebhookDeliveryEntity, error) {
+	if err := r.db.WithContext(ctx).Omit("Attempts").Save(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) CreateAttempt(ctx context.Context, attempt *entities.WebhookAttemptEntity) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+// FindPending devuelve las entregas pendientes más antiguas primero
+func (r *WebhookDeliveryRepository) FindPending(ctx context.Context) ([]*entities.WebhookDeliveryEntity, error) {
+	var deliveries []*entities.WebhookDeliveryEntity
+	err := r.db.WithContext(ctx).
+		Where("status = ?", entities.DeliveryStatusPending).
+		Order("created_at ASC").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FindAll lista entregas aplicando los filtros indicados (más recientes primero)
+func (r *WebhookDeliveryRepository) FindAll(ctx context.Context, filter entities.WebhookDeliveryFilter) ([]*entities.WebhookDeliveryEntity, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.SubscriptionID != nil {
+		query = query.Where("subscription_id = ?", *filter.SubscriptionID)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var deliveries []*entities.WebhookDeliveryEntity
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FindByID devuelve la entrega con todos sus intentos ordenados
+func (r *WebhookDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WebhookDeliveryEntity, error) {
+	var delivery entities.WebhookDeliveryEntity
+	err := r.db.WithContext(ctx).
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB { return db.Order("attempt ASC") }).
+		First(&delivery, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
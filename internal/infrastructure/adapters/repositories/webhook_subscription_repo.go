@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/domain/ports/output"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookSubscriptionRepository implementa la persistencia de las suscripciones de webhooks
+// (tabla webhook_subscriptions)
+type WebhookSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+var _ output.WebhookSubscriptionRepositoryInterface = &WebhookSubscriptionRepository{}
+
+// NewWebhookSubscriptionRepository crea una nueva instancia del repositorio de suscripciones
+func NewWebhookSubscriptionRepository(db *gorm.DB) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{db: db}
+}
+
+func (r *WebhookSubscriptionRepository) Create(ctx context.Context, subscription *entities.WebhookSubscriptionEntity) (*entities.WebhookSubscriptionEntity, error) {
+	if err := r.db.WithContext(ctx).Create(subscription).Error; err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) FindAll(ctx context.Context) ([]*entities.WebhookSubscriptionEntity, error) {
+	var subscriptions []*entities.WebhookSubscriptionEntity
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *WebhookSubscriptionRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscriptionEntity, error) {
+	var subscription entities.WebhookSubscriptionEntity
+	if err := r.db.WithContext(ctx).First(&subscription, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domainerrors.ErrNotFound
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) Update(ctx context.Context, subscription *entities.WebhookSubscriptionEntity) (*entities.WebhookSubscriptionEntity, error) {
+	if err := r.db.WithContext(ctx).Save(subscription).Error; err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (r *WebhookSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entities.WebhookSubscriptionEntity{}, "id = ?", id).Error
+}
+
+// RecordSuccess reinicia el contador sin tocar el resto de columnas (editables desde la API)
+func (r *WebhookSubscriptionRepository) RecordSuccess(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.WebhookSubscriptionEntity{}).
+		Where("id = ? AND consecutive_failures > 0", id).
+		UpdateColumn("consecutive_failures", 0).Error
+}
+
+// RecordFailure incrementa el contador en la base (varios workers pueden fallar a la vez)
+// y pasa a disabled solo las suscripciones activas que alcanzaron maxFailures
+func (r *WebhookSubscriptionRepository) RecordFailure(ctx context.Context, id uuid.UUID, maxFailures int, reason string) (*entities.WebhookSubscriptionEntity, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.WebhookSubscriptionEntity{}).
+			Where("id = ?", id).
+			UpdateColumn("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error; err != nil {
+			return err
+		}
+		if maxFailures <= 0 {
+			return nil
+		}
+		return tx.Model(&entities.WebhookSubscriptionEntity{}).
+			Where("id = ? AND status = ? AND consecutive_failures >= ?", id, entities.SubscriptionStatusActive, maxFailures).
+			UpdateColumns(map[string]any{
+				"status":          entities.SubscriptionStatusDisabled,
+				"disabled_reason": reason,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, id)
+}
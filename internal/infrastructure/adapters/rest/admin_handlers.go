@@ -0,0 +1,174 @@
+package rest
+
+// admin_handlers.go - Handlers del servidor de administración
+//
+// PROPÓSITO:
+// Se sirven en un listener separado (ADMIN_PORT) y nunca en el puerto público.
+// Todos los endpoints requieren ADMIN_API_KEY (header X-Admin-Api-Key o Authorization: Bearer).
+//
+// ENDPOINTS:
+// - GET /admin/version   - Build info (fecha, commit, versión de Go, uptime)
+// - GET /admin/config    - Configuración efectiva con secretos enmascarados
+// - GET /admin/loglevel  - Nivel actual del logger
+// - PUT /admin/loglevel  - Cambia el nivel del logger sin reiniciar
+// - /admin/simulator/*   - Control del simulador de lecturas (simulator_handlers.go)
+// - /debug/pprof/*       - Profiling de net/http/pprof
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+	"time"
+
+	"monitoring-energy-service/internal/infrastructure/conf"
+	"monitoring-energy-service/internal/infrastructure/container"
+	"monitoring-energy-service/internal/infrastructure/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminApiKeyHeader es el header con la API key de administración
+const AdminApiKeyHeader = "X-Admin-Api-Key"
+
+// VersionResponse represents the build info of the running binary
+type VersionResponse struct {
+	BuildDate     string    `json:"build_date" example:"2026-01-10T17:11:00Z"`
+	GitCommit     string    `json:"git_commit" example:"c8bd6e2"`
+	GoVersion     string    `json:"go_version" example:"go1.24.0"`
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds" example:"3600"`
+}
+
+// LogLevelRequest represents the request body for changing the log level
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required" example:"debug"`
+}
+
+// LogLevelResponse represents the current log level
+type LogLevelResponse struct {
+	Level string `json:"level" example:"INFO"`
+}
+
+// AdminAuth valida la API key de administración comparándola en tiempo constante
+func AdminAuth(apiKey string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provided := ctx.GetHeader(AdminApiKeyHeader)
+		if provided == "" {
+			provided, _ = strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		}
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin api key"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// GetVersion godoc
+// @Summary      Build info
+// @Description  Get the build date, git commit, Go version and uptime of the running binary (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  VersionResponse
+// @Failure      401  {object}  ErrorResponse
+// @Router       /admin/version [get]
+func GetVersion(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		info := c.BuildInfo
+		ctx.JSON(http.StatusOK, VersionResponse{
+			BuildDate:     info.BuildDate,
+			GitCommit:     info.GitCommit,
+			GoVersion:     runtime.Version(),
+			StartedAt:     info.StartedAt,
+			UptimeSeconds: int64(time.Since(info.StartedAt).Seconds()),
+		})
+	}
+}
+
+// GetEffectiveConfig godoc
+// @Summary      Effective configuration
+// @Description  Get the effective configuration by environment variable; secrets (SECRET, PASSWORD, API_KEY) are masked (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  map[string]interface{}
+// @Failure      401  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /admin/config [get]
+func GetEffectiveConfig(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		values, err := conf.EffectiveConfig(c.GetConfig())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, values)
+	}
+}
+
+// GetLogLevel godoc
+// @Summary      Get log level
+// @Description  Get the current level of the application logger (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  LogLevelResponse
+// @Failure      401  {object}  ErrorResponse
+// @Router       /admin/loglevel [get]
+func GetLogLevel(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, LogLevelResponse{Level: c.LogLevel.Level().String()})
+	}
+}
+
+// SetLogLevel godoc
+// @Summary      Change log level
+// @Description  Change the level of the application logger at runtime (debug, info, warn, error) (admin listener)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminApiKey
+// @Param        request  body      LogLevelRequest  true  "New log level"
+// @Success      200      {object}  LogLevelResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Router       /admin/loglevel [put]
+func SetLogLevel(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req LogLevelRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		previous := c.LogLevel.Level()
+		if err := logging.SetLevel(c.LogLevel, req.Level); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Logger.InfoContext(ctx.Request.Context(), "log level changed",
+			slog.String("from", previous.String()),
+			slog.String("to", c.LogLevel.Level().String()))
+		ctx.JSON(http.StatusOK, LogLevelResponse{Level: c.LogLevel.Level().String()})
+	}
+}
+
+// registerPprof expone los handlers de net/http/pprof bajo /debug/pprof
+// pprof.Index resuelve los perfiles por nombre (heap, goroutine, allocs, block, mutex, threadcreate)
+func registerPprof(router *gin.RouterGroup) {
+	debug := router.Group("/debug/pprof")
+	{
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		debug.GET("/:profile", gin.WrapF(pprof.Index))
+	}
+}
@@ -0,0 +1,371 @@
+package rest
+
+// alert_handlers.go - Handlers REST para reglas de alerta y alertas
+//
+// ENDPOINTS:
+// - GET    /api/v1/alert-rules      - Lista las reglas
+// - POST   /api/v1/alert-rules      - Crea una regla ("temperature_celsius > 45 for 10m")
+// - GET    /api/v1/alert-rules/:id  - Obtiene una regla
+// - PUT    /api/v1/alert-rules/:id  - Modifica una regla
+// - DELETE /api/v1/alert-rules/:id  - Borra una regla (sus alertas activas se resuelven)
+// - GET    /api/v1/alerts           - Lista alertas (filtros: status, plant_id, rule_id, kind, limit)
+// - GET    /api/v1/alerts/:id       - Obtiene una alerta
+//
+// Cada cambio en las reglas recarga el AlertEngine para que aplique desde la siguiente lectura.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AlertRuleRequest represents the request body for creating or updating an alert rule
+type AlertRuleRequest struct {
+	Name       string  `json:"name" binding:"required" example:"High temperature"`
+	Expression string  `json:"expression" binding:"required" example:"temperature_celsius > 45 for 10m"`
+	Hysteresis float64 `json:"hysteresis" example:"2"`
+	Severity   string  `json:"severity" example:"warning"`
+	Scope      string  `json:"scope" example:"plant_type"`
+	ScopeValue string  `json:"scope_value" example:"solar"`
+	Enabled    *bool   `json:"enabled" example:"true"`
+}
+
+// AlertRuleResponse represents an alert rule with its textual expression
+type AlertRuleResponse struct {
+	*entities.AlertRuleEntity
+	Expression string `json:"expression" example:"temperature_celsius > 45 for 10m0s"`
+}
+
+func newAlertRuleResponse(rule *entities.AlertRuleEntity) AlertRuleResponse {
+	return AlertRuleResponse{AlertRuleEntity: rule, Expression: rule.Expression()}
+}
+
+// applyAlertRuleRequest valida el request y lo vuelca sobre la regla
+func applyAlertRuleRequest(ctx context.Context, c *container.Container, req AlertRuleRequest, rule *entities.AlertRuleEntity) error {
+	metric, operator, threshold, forDuration, err := entities.ParseAlertExpression(req.Expression)
+	if err != nil {
+		return err
+	}
+
+	rule.Name = req.Name
+	rule.Metric = metric
+	rule.Operator = operator
+	rule.Threshold = threshold
+	rule.ForSeconds = int64(forDuration.Seconds())
+	rule.Hysteresis = req.Hysteresis
+	rule.Severity = req.Severity
+	if rule.Severity == "" {
+		rule.Severity = entities.SeverityWarning
+	}
+	rule.ScopeType = req.Scope
+	if rule.ScopeType == "" {
+		rule.ScopeType = entities.RuleScopeAll
+	}
+	rule.ScopeValue = req.ScopeValue
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	if rule.ScopeType == entities.RuleScopePlant {
+		exists, err := c.EnergyPlantRepository.Exists(ctx, uuid.MustParse(rule.ScopeValue))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: plant %s does not exist", domainerrors.ErrInvalidInput, rule.ScopeValue)
+		}
+	}
+	return nil
+}
+
+// validationErrorStatus traduce los errores de validación a 400 y el resto a 500
+func validationErrorStatus(err error) int {
+	if errors.Is(err, domainerrors.ErrInvalidInput) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// ListAlertRules godoc
+// @Summary      List alert rules
+// @Description  Get all threshold alert rules
+// @Tags         alerts
+// @Produce      json
+// @Success      200  {array}   AlertRuleResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/alert-rules [get]
+func ListAlertRules(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rules, err := c.AlertRuleRepository.FindAll(ctx.Request.Context())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]AlertRuleResponse, 0, len(rules))
+		for _, rule := range rules {
+			response = append(response, newAlertRuleResponse(rule))
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// CreateAlertRule godoc
+// @Summary      Create an alert rule
+// @Description  Create a threshold rule written as "<metric> <op> <threshold> [for <duration>]", scoped to all plants (scope=all), a plant type (scope=plant_type) or one plant (scope=plant)
+// @Tags         alerts
+// @Accept       json
+// @Produce      json
+// @Param        rule  body      AlertRuleRequest  true  "Alert rule"
+// @Success      201   {object}  AlertRuleResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/alert-rules [post]
+func CreateAlertRule(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AlertRuleRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		rule := &entities.AlertRuleEntity{}
+		if err := applyAlertRuleRequest(ctx.Request.Context(), c, req, rule); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		created, err := c.AlertRuleRepository.Create(ctx.Request.Context(), rule)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.AlertEngine.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, newAlertRuleResponse(created))
+	}
+}
+
+// GetAlertRule godoc
+// @Summary      Get an alert rule by ID
+// @Description  Get a single alert rule by its UUID
+// @Tags         alerts
+// @Produce      json
+// @Param        id   path      string  true  "Alert rule ID"
+// @Success      200  {object}  AlertRuleResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/alert-rules/{id} [get]
+func GetAlertRule(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		rule, err := c.AlertRuleRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "alert rule not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newAlertRuleResponse(rule))
+	}
+}
+
+// UpdateAlertRule godoc
+// @Summary      Update an alert rule
+// @Description  Replace an alert rule; firing alerts are kept until they resolve, or resolved at once if the rule is disabled
+// @Tags         alerts
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string            true  "Alert rule ID"
+// @Param        rule  body      AlertRuleRequest  true  "Alert rule"
+// @Success      200   {object}  AlertRuleResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/alert-rules/{id} [put]
+func UpdateAlertRule(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req AlertRuleRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		rule, err := c.AlertRuleRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "alert rule not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := applyAlertRuleRequest(ctx.Request.Context(), c, req, rule); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		updated, err := c.AlertRuleRepository.Update(ctx.Request.Context(), rule)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.AlertEngine.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newAlertRuleResponse(updated))
+	}
+}
+
+// DeleteAlertRule godoc
+// @Summary      Delete an alert rule
+// @Description  Delete an alert rule; its firing alerts are resolved
+// @Tags         alerts
+// @Param        id   path      string  true  "Alert rule ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/alert-rules/{id} [delete]
+func DeleteAlertRule(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if err := c.AlertRuleRepository.Delete(ctx.Request.Context(), id); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.AlertEngine.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// ListAlerts godoc
+// @Summary      List alerts
+// @Description  Get fired and resolved alerts, most recent first. kind=threshold are alert rule alerts; kind=offline are raised when a plant stops reporting (rule_id is the nil UUID)
+// @Tags         alerts
+// @Produce      json
+// @Param        status    query     string  false  "firing or resolved"
+// @Param        plant_id  query     string  false  "Plant UUID"
+// @Param        rule_id   query     string  false  "Alert rule UUID"
+// @Param        kind      query     string  false  "threshold or offline"
+// @Param        limit     query     int     false  "Maximum number of alerts"
+// @Success      200       {array}   entities.AlertEntity
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/alerts [get]
+func ListAlerts(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter := entities.AlertFilter{Status: ctx.Query("status")}
+		if filter.Status != "" && filter.Status != entities.AlertStatusFiring && filter.Status != entities.AlertStatusResolved {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "status must be firing or resolved"})
+			return
+		}
+		if v := ctx.Query("plant_id"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid plant_id"})
+				return
+			}
+			filter.PlantSourceId = &id
+		}
+		if v := ctx.Query("rule_id"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid rule_id"})
+				return
+			}
+			filter.RuleID = &id
+		}
+		filter.Kind = ctx.Query("kind")
+		if filter.Kind != "" && filter.Kind != entities.AlertKindThreshold && filter.Kind != entities.AlertKindOffline {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "kind must be threshold or offline"})
+			return
+		}
+		if v := ctx.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		alerts, err := c.AlertRepository.FindAll(ctx.Request.Context(), filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, alerts)
+	}
+}
+
+// GetAlert godoc
+// @Summary      Get an alert by ID
+// @Description  Get a single alert by its UUID
+// @Tags         alerts
+// @Produce      json
+// @Param        id   path      string  true  "Alert ID"
+// @Success      200  {object}  entities.AlertEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/alerts/{id} [get]
+func GetAlert(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		alert, err := c.AlertRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, alert)
+	}
+}
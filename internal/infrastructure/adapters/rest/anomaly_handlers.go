@@ -0,0 +1,281 @@
+package rest
+
+// anomaly_handlers.go - Handlers REST de las anomalías y de la configuración del detector
+//
+// ENDPOINTS:
+// - GET    /api/v1/anomalies                    - Lista anomalías (filtros: plant_id, metric, from, to, limit)
+// - GET    /api/v1/anomalies/:id                - Obtiene una anomalía
+// - GET    /api/v1/plants/:id/anomaly-settings  - Configuración efectiva del detector para la planta
+// - PUT    /api/v1/plants/:id/anomaly-settings  - Configuración propia de la planta
+// - DELETE /api/v1/plants/:id/anomaly-settings  - Vuelve a los valores por defecto (ANOMALY_*)
+//
+// Cada cambio de configuración descarta la línea base de la planta (se reconstruye desde el historial).
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AnomalySettingsRequest represents the per-plant anomaly detector settings; omitted fields keep their current value
+type AnomalySettingsRequest struct {
+	Enabled    *bool    `json:"enabled" example:"true"`
+	ZThreshold *float64 `json:"z_threshold" example:"4"`
+	Alpha      *float64 `json:"alpha" example:"0.05"`
+	WarmUp     *int     `json:"warm_up" example:"30"`
+	Metrics    []string `json:"metrics" example:"power_generated_mw,efficiency_percent"`
+}
+
+// AnomalySettingsResponse represents the effective settings of a plant and whether they are its own or the defaults
+type AnomalySettingsResponse struct {
+	*entities.AnomalySettingsEntity
+	Source string `json:"source" example:"plant"` // plant or default
+}
+
+func newAnomalySettingsResponse(settings *entities.AnomalySettingsEntity, custom bool) AnomalySettingsResponse {
+	source := "default"
+	if custom {
+		source = "plant"
+	}
+	return AnomalySettingsResponse{AnomalySettingsEntity: settings, Source: source}
+}
+
+// ListAnomalies godoc
+// @Summary      List anomalies
+// @Description  Get readings flagged as anomalous by the detector, most recent first
+// @Tags         anomalies
+// @Produce      json
+// @Param        plant_id  query     string  false  "Plant UUID"
+// @Param        metric    query     string  false  "Metric (e.g. power_generated_mw)"
+// @Param        from      query     string  false  "Detected at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        to        query     string  false  "Detected before (RFC3339 or YYYY-MM-DD)"
+// @Param        limit     query     int     false  "Maximum number of anomalies"
+// @Success      200       {array}   entities.AnomalyEntity
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/anomalies [get]
+func ListAnomalies(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter := entities.AnomalyFilter{Metric: ctx.Query("metric")}
+		if v := ctx.Query("plant_id"); v != "" {
+			plantID, err := uuid.Parse(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid plant_id"})
+				return
+			}
+			filter.PlantSourceId = &plantID
+		}
+		if v := ctx.Query("from"); v != "" {
+			from, err := parseReportTime(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+				return
+			}
+			filter.From = from
+		}
+		if v := ctx.Query("to"); v != "" {
+			to, err := parseReportTime(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+				return
+			}
+			filter.To = to
+		}
+		if v := ctx.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		anomalies, err := c.AnomalyRepository.FindAll(ctx.Request.Context(), filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, anomalies)
+	}
+}
+
+// GetAnomaly godoc
+// @Summary      Get an anomaly by ID
+// @Description  Get a single anomaly with its score and the baseline it was compared against
+// @Tags         anomalies
+// @Produce      json
+// @Param        id   path      string  true  "Anomaly ID"
+// @Success      200  {object}  entities.AnomalyEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/anomalies/{id} [get]
+func GetAnomaly(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		anomaly, err := c.AnomalyRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "anomaly not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, anomaly)
+	}
+}
+
+// GetAnomalySettings godoc
+// @Summary      Get the anomaly detector settings of a plant
+// @Description  Get the effective detector settings of a plant: its own (source=plant) or the ANOMALY_* defaults (source=default)
+// @Tags         anomalies
+// @Produce      json
+// @Param        id   path      string  true  "Plant ID"
+// @Success      200  {object}  AnomalySettingsResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/anomaly-settings [get]
+func GetAnomalySettings(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := findPlantID(ctx, c)
+		if !ok {
+			return
+		}
+
+		settings, custom, err := c.AnomalyDetector.Settings(ctx.Request.Context(), plantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newAnomalySettingsResponse(settings, custom))
+	}
+}
+
+// UpdateAnomalySettings godoc
+// @Summary      Tune the anomaly detector for a plant
+// @Description  Set the plant's own detector settings; omitted fields keep their current value. The plant baseline is rebuilt from recent history with the next reading.
+// @Tags         anomalies
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                  true  "Plant ID"
+// @Param        settings  body      AnomalySettingsRequest  true  "Detector settings"
+// @Success      200       {object}  AnomalySettingsResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/anomaly-settings [put]
+func UpdateAnomalySettings(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AnomalySettingsRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		plantID, ok := findPlantID(ctx, c)
+		if !ok {
+			return
+		}
+
+		settings, _, err := c.AnomalyDetector.Settings(ctx.Request.Context(), plantID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Enabled != nil {
+			settings.Enabled = *req.Enabled
+		}
+		if req.ZThreshold != nil {
+			settings.ZThreshold = *req.ZThreshold
+		}
+		if req.Alpha != nil {
+			settings.Alpha = *req.Alpha
+		}
+		if req.WarmUp != nil {
+			settings.WarmUp = *req.WarmUp
+		}
+		if req.Metrics != nil {
+			settings.Metrics = req.Metrics
+		}
+		if err := settings.Validate(); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		saved, err := c.AnomalySettingsRepository.Save(ctx.Request.Context(), settings)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.AnomalyDetector.Reload(ctx.Request.Context(), plantID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newAnomalySettingsResponse(saved, true))
+	}
+}
+
+// DeleteAnomalySettings godoc
+// @Summary      Reset the anomaly detector settings of a plant
+// @Description  Remove the plant's own settings so the ANOMALY_* defaults apply again
+// @Tags         anomalies
+// @Param        id   path      string  true  "Plant ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/anomaly-settings [delete]
+func DeleteAnomalySettings(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := findPlantID(ctx, c)
+		if !ok {
+			return
+		}
+
+		if err := c.AnomalySettingsRepository.Delete(ctx.Request.Context(), plantID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.AnomalyDetector.Reload(ctx.Request.Context(), plantID); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// findPlantID obtiene el :id de la planta y verifica que exista; si no, ya respondió el error
+func findPlantID(ctx *gin.Context, c *container.Container) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return uuid.Nil, false
+	}
+
+	exists, err := c.EnergyPlantRepository.Exists(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	if !exists {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,139 @@
+package rest
+
+// connectivity_handlers.go - Handlers REST de la conectividad de las plantas
+//
+// ENDPOINTS:
+// - GET /api/v1/connectivity               - Última lectura y estado de cada planta (state)
+// - GET /api/v1/plants/:id/connectivity    - Conectividad de una planta
+// - PUT /api/v1/plants/:id/connectivity    - Cambia el intervalo de reporte esperado de la planta
+//
+// Una planta está offline cuando no envía lecturas durante PLANT_OFFLINE_MISSED_INTERVALS
+// veces su intervalo esperado (ConnectivityWatcher).
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PlantConnectivityRequest represents the expected reporting interval of a plant
+type PlantConnectivityRequest struct {
+	ExpectedInterval *string `json:"expected_interval" example:"5m"` // Go duration, or null to use PLANT_EXPECTED_INTERVAL
+}
+
+// ListConnectivity godoc
+// @Summary      List plant connectivity
+// @Description  Last reading received from each plant and whether it is online, offline (silent for longer than its expected interval times PLANT_OFFLINE_MISSED_INTERVALS) or never_seen
+// @Tags         connectivity
+// @Produce      json
+// @Param        state  query     string  false  "Filter by state (online, offline, never_seen)"
+// @Success      200    {array}   entities.PlantConnectivity
+// @Failure      400    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /api/v1/connectivity [get]
+func ListConnectivity(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		state := ctx.Query("state")
+		switch state {
+		case "", entities.ConnectivityOnline, entities.ConnectivityOffline, entities.ConnectivityNeverSeen:
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid state"})
+			return
+		}
+
+		result, err := c.ConnectivityWatcher.Connectivity(ctx.Request.Context(), state, time.Now().UTC())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	}
+}
+
+// GetPlantConnectivity godoc
+// @Summary      Get plant connectivity
+// @Description  Last reading received from a plant, its expected reporting interval and whether it is online
+// @Tags         connectivity
+// @Produce      json
+// @Param        id   path      string  true  "Plant ID"
+// @Success      200  {object}  entities.PlantConnectivity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/connectivity [get]
+func GetPlantConnectivity(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		result, err := c.ConnectivityWatcher.PlantConnectivity(ctx.Request.Context(), id, time.Now().UTC())
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	}
+}
+
+// UpdatePlantConnectivity godoc
+// @Summary      Set plant expected interval
+// @Description  Set how often a plant is expected to report (Go duration, at least 1s), or send expected_interval null to use PLANT_EXPECTED_INTERVAL. The new threshold applies from the next check.
+// @Tags         connectivity
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                    true  "Plant ID"
+// @Param        interval  body      PlantConnectivityRequest  true  "Expected interval"
+// @Success      200       {object}  entities.PlantConnectivity
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/connectivity [put]
+func UpdatePlantConnectivity(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req PlantConnectivityRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		var interval *time.Duration
+		if req.ExpectedInterval != nil {
+			d, err := time.ParseDuration(*req.ExpectedInterval)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid expected_interval"})
+				return
+			}
+			interval = &d
+		}
+
+		result, err := c.ConnectivityWatcher.SetExpectedInterval(ctx.Request.Context(), id, interval)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	}
+}
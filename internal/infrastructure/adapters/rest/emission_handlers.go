@@ -0,0 +1,309 @@
+package rest
+
+// emission_handlers.go - Handlers REST de los factores de emisión y las emisiones evitadas
+//
+// ENDPOINTS:
+// - GET  /api/v1/emission-factors                  - Lista factores (filtros: region_node_id, default_only, max_version)
+// - POST /api/v1/emission-factors                  - Crea un factor con la versión siguiente
+// - GET  /api/v1/emission-factors/:id              - Obtiene un factor
+// - GET  /api/v1/plants/:id/emissions              - Emisiones evitadas de una planta por período
+// - GET  /api/v1/portfolio/nodes/:id/emissions     - Emisiones evitadas de un nodo y de cada una de sus plantas
+// - GET  /api/v1/emissions                         - Emisiones evitadas de la flota y de cada planta
+//
+// Los reportes aceptan from, to y granularity como los de energía y factor_version para
+// repetir un reporte con los factores de una versión anterior. Los factores no se modifican ni borran.
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// EmissionFactorRequest represents the request body for creating an emission factor
+type EmissionFactorRequest struct {
+	RegionNodeID *uuid.UUID `json:"region_node_id"`                                     // Portfolio region, or null for the default factor
+	ValidFrom    string     `json:"valid_from" binding:"required" example:"2026-01-01"` // YYYY-MM-DD (UTC)
+	ValidTo      string     `json:"valid_to" example:"2027-01-01"`                      // YYYY-MM-DD (UTC, exclusive); empty = open ended
+	TCO2ePerMWh  *float64   `json:"tco2e_per_mwh" binding:"required" example:"0.21"`    // Tonnes of CO2e per MWh of grid electricity
+	Source       string     `json:"source" example:"REE 2025 grid mix"`                 // Publication the factor comes from
+}
+
+func (r EmissionFactorRequest) factor() (entities.EmissionFactorEntity, error) {
+	factor := entities.EmissionFactorEntity{
+		RegionNodeID: r.RegionNodeID,
+		TCO2ePerMWh:  *r.TCO2ePerMWh,
+		Source:       r.Source,
+	}
+	validFrom, err := parseReportTime(r.ValidFrom)
+	if err != nil {
+		return factor, fmt.Errorf("%w: invalid valid_from", domainerrors.ErrInvalidInput)
+	}
+	factor.ValidFrom = validFrom
+	if r.ValidTo != "" {
+		validTo, err := parseReportTime(r.ValidTo)
+		if err != nil {
+			return factor, fmt.Errorf("%w: invalid valid_to", domainerrors.ErrInvalidInput)
+		}
+		factor.ValidTo = &validTo
+	}
+	return factor, nil
+}
+
+// parseFactorVersion lee factor_version (0 = la versión más alta)
+func parseFactorVersion(ctx *gin.Context) (int64, error) {
+	v := ctx.Query("factor_version")
+	if v == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("%w: invalid factor_version", domainerrors.ErrInvalidInput)
+	}
+	return version, nil
+}
+
+// parseEmissionsQuery lee el rango, la granularidad y la versión de factores de un reporte de emisiones
+func parseEmissionsQuery(ctx *gin.Context) (entities.EnergyQuery, int64, error) {
+	query, err := parseEnergyQuery(ctx)
+	if err != nil {
+		return query, 0, err
+	}
+	version, err := parseFactorVersion(ctx)
+	return query, version, err
+}
+
+// ListEmissionFactors godoc
+// @Summary      List emission factors
+// @Description  Get grid emission factors ordered by version, optionally only those of a region, only the defaults (no region) or only up to a version
+// @Tags         emissions
+// @Produce      json
+// @Param        region_node_id  query     string  false  "Portfolio region UUID"
+// @Param        default_only    query     bool    false  "Only factors without region"
+// @Param        max_version     query     int     false  "Only factors with version <= max_version"
+// @Success      200             {array}   entities.EmissionFactorEntity
+// @Failure      400             {object}  ErrorResponse
+// @Failure      500             {object}  ErrorResponse
+// @Router       /api/v1/emission-factors [get]
+func ListEmissionFactors(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var filter entities.EmissionFactorFilter
+		if v := ctx.Query("region_node_id"); v != "" {
+			regionID, err := uuid.Parse(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid region_node_id"})
+				return
+			}
+			filter.RegionNodeID = &regionID
+		}
+		if v := ctx.Query("default_only"); v != "" {
+			defaultOnly, err := strconv.ParseBool(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid default_only"})
+				return
+			}
+			filter.DefaultOnly = defaultOnly
+		}
+		if v := ctx.Query("max_version"); v != "" {
+			maxVersion, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || maxVersion < 1 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid max_version"})
+				return
+			}
+			filter.MaxVersion = maxVersion
+		}
+
+		factors, err := c.EmissionFactorRepository.FindAll(ctx.Request.Context(), filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, factors)
+	}
+}
+
+// CreateEmissionFactor godoc
+// @Summary      Create an emission factor
+// @Description  Add a grid emission factor (tCO2e per MWh) for a portfolio region, or a default factor without region, valid for whole UTC days [valid_from, valid_to). The factor gets the next version; factors cannot be changed or deleted, so a correction is a new factor. Reports use the highest version that covers each day.
+// @Tags         emissions
+// @Accept       json
+// @Produce      json
+// @Param        factor  body      EmissionFactorRequest  true  "Emission factor"
+// @Success      201     {object}  entities.EmissionFactorEntity
+// @Failure      400     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/v1/emission-factors [post]
+func CreateEmissionFactor(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req EmissionFactorRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		factor, err := req.factor()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		created, err := c.EmissionsService.CreateFactor(ctx.Request.Context(), &factor)
+		if err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetEmissionFactor godoc
+// @Summary      Get an emission factor by ID
+// @Description  Get a single grid emission factor
+// @Tags         emissions
+// @Produce      json
+// @Param        id   path      string  true  "Emission factor ID"
+// @Success      200  {object}  entities.EmissionFactorEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/emission-factors/{id} [get]
+func GetEmissionFactor(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		factor, err := c.EmissionFactorRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "emission factor not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, factor)
+	}
+}
+
+// GetPlantEmissions godoc
+// @Summary      Get plant avoided emissions
+// @Description  Avoided emissions (tCO2e) of a plant per day, week or month: generated energy of each day times the grid factor of the plant region valid that day (the default factor when the region has none). Energy of days without any factor is reported as uncovered_mwh. factor_version and the applied factors are returned so the report can be reproduced.
+// @Tags         emissions
+// @Produce      json
+// @Param        id              path      string  true   "Plant ID"
+// @Param        from            query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)"
+// @Param        to              query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)"
+// @Param        granularity     query     string  false  "day, week or month (default day)"
+// @Param        factor_version  query     int     false  "Use only factors up to this version (default latest)"
+// @Success      200             {object}  entities.PlantEmissionsReport
+// @Failure      400             {object}  ErrorResponse
+// @Failure      404             {object}  ErrorResponse
+// @Failure      500             {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/emissions [get]
+func GetPlantEmissions(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		query, version, err := parseEmissionsQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.EmissionsService.PlantReport(ctx.Request.Context(), id, query, version)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// GetPortfolioNodeEmissions godoc
+// @Summary      Get portfolio node avoided emissions
+// @Description  Avoided emissions (tCO2e) of all plants under an organization, region or site per period, plus the range total of every plant. Each plant uses the factor of its own region.
+// @Tags         emissions
+// @Produce      json
+// @Param        id              path      string  true   "Portfolio node ID"
+// @Param        from            query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)"
+// @Param        to              query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)"
+// @Param        granularity     query     string  false  "day, week or month (default day)"
+// @Param        factor_version  query     int     false  "Use only factors up to this version (default latest)"
+// @Success      200             {object}  entities.EmissionsReport
+// @Failure      400             {object}  ErrorResponse
+// @Failure      404             {object}  ErrorResponse
+// @Failure      500             {object}  ErrorResponse
+// @Router       /api/v1/portfolio/nodes/{id}/emissions [get]
+func GetPortfolioNodeEmissions(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		query, version, err := parseEmissionsQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.EmissionsService.NodeReport(ctx.Request.Context(), id, query, version)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "portfolio node not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// GetFleetEmissions godoc
+// @Summary      Get fleet avoided emissions
+// @Description  Avoided emissions (tCO2e) of all plants together per period, plus the range total of every plant
+// @Tags         emissions
+// @Produce      json
+// @Param        from            query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)"
+// @Param        to              query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)"
+// @Param        granularity     query     string  false  "day, week or month (default day)"
+// @Param        factor_version  query     int     false  "Use only factors up to this version (default latest)"
+// @Success      200             {object}  entities.EmissionsReport
+// @Failure      400             {object}  ErrorResponse
+// @Failure      500             {object}  ErrorResponse
+// @Router       /api/v1/emissions [get]
+func GetFleetEmissions(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query, version, err := parseEmissionsQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.EmissionsService.FleetReport(ctx.Request.Context(), query, version)
+		if err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,147 @@
+package rest
+
+// energy_handlers.go - Handlers REST de la energía (MWh) integrada desde las lecturas
+//
+// ENDPOINTS:
+// - GET  /api/v1/plants/:id/energy            - Energía de una planta por período (from, to, granularity)
+// - POST /api/v1/plants/:id/energy/recompute  - Recalcula los días del rango desde los eventos guardados
+// - GET  /api/v1/energy                       - Energía de la flota por período y total de cada planta
+//
+// Sin from/to se reportan los últimos 30 días; from y to se alinean a días completos (UTC).
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// parseEnergyQuery lee from, to y granularity con las mismas reglas que los reportes de KPIs
+func parseEnergyQuery(ctx *gin.Context) (entities.EnergyQuery, error) {
+	query, err := parseKPIQuery(ctx, time.Time{})
+	if err != nil {
+		return entities.EnergyQuery{}, err
+	}
+	return entities.EnergyQuery{From: query.From, To: query.To, Granularity: query.Granularity}, nil
+}
+
+// GetPlantEnergy godoc
+// @Summary      Get plant energy
+// @Description  Energy generated, consumed and net (MWh) of a plant per day, week (ISO, Monday first) or month (UTC), integrated from power readings with the trapezoidal rule using the reading timestamp. Gaps longer than ENERGY_MAX_GAP count as no data. from and to are widened to whole days.
+// @Tags         energy
+// @Produce      json
+// @Param        id           path      string  true   "Plant ID"
+// @Param        from         query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)"
+// @Param        to           query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)"
+// @Param        granularity  query     string  false  "day, week or month (default day)"
+// @Success      200          {object}  entities.PlantEnergyReport
+// @Failure      400          {object}  ErrorResponse
+// @Failure      404          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/energy [get]
+func GetPlantEnergy(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		query, err := parseEnergyQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.EnergyIntegrator.PlantReport(ctx.Request.Context(), id, query)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// RecomputePlantEnergy godoc
+// @Summary      Recompute plant energy
+// @Description  Integrates again the stored readings of a plant for whole days in the range and replaces its daily totals. Use it after a backfill or to pick up readings that arrived out of order.
+// @Tags         energy
+// @Produce      json
+// @Param        id    path      string  true   "Plant ID"
+// @Param        from  query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago or the first day still within ROLLUP_RAW_RETENTION); earlier days are rejected"
+// @Param        to    query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)"
+// @Success      200   {array}   entities.EnergyDailyEntity
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/energy/recompute [post]
+func RecomputePlantEnergy(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		query, err := parseEnergyQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Sin from, el rango por defecto empieza en el primer día cuyos eventos no se purgaron
+		if earliest := c.EnergyIntegrator.RecomputeStart(); ctx.Query("from") == "" && query.From.Before(earliest) {
+			query.From = earliest
+		}
+
+		days, err := c.EnergyIntegrator.Recompute(ctx.Request.Context(), id, query.From, query.To)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, days)
+	}
+}
+
+// GetFleetEnergy godoc
+// @Summary      Get fleet energy
+// @Description  Energy generated, consumed and net (MWh) of all plants together per period, plus the range total of every plant. Covered hours are summed across plants.
+// @Tags         energy
+// @Produce      json
+// @Param        from         query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)"
+// @Param        to           query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default end of today)"
+// @Param        granularity  query     string  false  "day, week or month (default day)"
+// @Success      200          {object}  entities.FleetEnergyReport
+// @Failure      400          {object}  ErrorResponse
+// @Failure      500          {object}  ErrorResponse
+// @Router       /api/v1/energy [get]
+func GetFleetEnergy(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query, err := parseEnergyQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.EnergyIntegrator.FleetReport(ctx.Request.Context(), query)
+		if err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
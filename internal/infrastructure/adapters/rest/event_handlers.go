@@ -10,14 +10,18 @@ package rest
 // RAZÓN: Necesitábamos una API REST para consultar eventos desde cualquier cliente HTTP
 //
 // ENDPOINTS CREADOS:
-// - GET /api/v1/events           - Lista todos los eventos (ordenados por fecha DESC)
+// - GET /api/v1/events           - Lista todos los eventos (ordenados por event_time DESC)
 // - GET /api/v1/events/:id       - Obtiene un evento específico por UUID
 // - GET /api/v1/events/type/:type - Filtra eventos por tipo (power_reading, alert, etc.)
+//
+// CAMBIO: Los listados aceptan ?quality=good|suspect
+// RAZÓN: Permite excluir (o ver solo) las lecturas marcadas por los chequeos de plausibilidad
 
 import (
 	"errors"
 	"net/http"
 
+	"monitoring-energy-service/internal/domain/entities"
 	domainerrors "monitoring-energy-service/internal/domain/errors"
 	"monitoring-energy-service/internal/infrastructure/container"
 
@@ -31,16 +35,23 @@ import (
 //
 // ListEvents godoc
 // @Summary      List all events
-// @Description  Get all events from the database ordered by creation time
+// @Description  Get all events from the database ordered by event_time (when the reading was taken), newest first; created_at is the ingestion time. Use quality=good to leave out readings flagged by the plausibility checks, or quality=suspect to list only those.
 // @Tags         events
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   entities.EventEntity
-// @Failure      500  {object}  ErrorResponse
+// @Param        quality  query     string  false  "good or suspect (default all)"
+// @Success      200      {array}   entities.EventEntity
+// @Failure      400      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
 // @Router       /api/v1/events [get]
 func ListEvents(c *container.Container) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		events, err := c.EventRepository.FindAll()
+		quality, ok := parseQuality(ctx)
+		if !ok {
+			return
+		}
+
+		events, err := c.EventRepository.FindAll(ctx.Request.Context(), quality)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -75,7 +86,7 @@ func GetEvent(c *container.Container) gin.HandlerFunc {
 			return
 		}
 
-		event, err := c.EventRepository.FindByID(id)
+		event, err := c.EventRepository.FindByID(ctx.Request.Context(), id)
 		if err != nil {
 			if errors.Is(err, domainerrors.ErrNotFound) {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": "event not found"})
@@ -94,19 +105,25 @@ func GetEvent(c *container.Container) gin.HandlerFunc {
 //
 // GetEventsByType godoc
 // @Summary      Get events by type
-// @Description  Get all events filtered by event type
+// @Description  Get all events filtered by event type, optionally by quality, ordered by event_time (newest first)
 // @Tags         events
 // @Accept       json
 // @Produce      json
-// @Param        type   path      string  true  "Event Type"
-// @Success      200  {array}   entities.EventEntity
-// @Failure      500  {object}  ErrorResponse
+// @Param        type     path      string  true   "Event Type"
+// @Param        quality  query     string  false  "good or suspect (default all)"
+// @Success      200      {array}   entities.EventEntity
+// @Failure      400      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
 // @Router       /api/v1/events/type/{type} [get]
 func GetEventsByType(c *container.Container) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		eventType := ctx.Param("type")
+		quality, ok := parseQuality(ctx)
+		if !ok {
+			return
+		}
 
-		events, err := c.EventRepository.FindByEventType(eventType)
+		events, err := c.EventRepository.FindByEventType(ctx.Request.Context(), eventType, quality)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -114,3 +131,17 @@ func GetEventsByType(c *container.Container) gin.HandlerFunc {
 		ctx.JSON(http.StatusOK, events)
 	}
 }
+
+// parseQuality lee el filtro ?quality (good, suspect; "all" o vacío = todas)
+// Responde 400 y devuelve false si el valor no es válido
+func parseQuality(ctx *gin.Context) (string, bool) {
+	quality := ctx.Query("quality")
+	if quality == "all" {
+		quality = ""
+	}
+	if !entities.ValidEventQuality(quality) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "quality must be good, suspect or all"})
+		return "", false
+	}
+	return quality, true
+}
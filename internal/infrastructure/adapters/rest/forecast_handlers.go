@@ -0,0 +1,197 @@
+package rest
+
+// forecast_handlers.go - Handlers REST del pronóstico de generación por planta
+//
+// ENDPOINTS:
+// - GET  /api/v1/plants/:id/forecast       - Última corrida con los puntos de las próximas horas (horizon)
+// - POST /api/v1/plants/:id/forecast       - Reentrena el modelo de la planta ahora
+// - GET  /api/v1/plants/:id/forecast/runs  - Corridas anteriores con su error de backtest (sin puntos)
+//
+// El ForecastService reentrena todas las plantas cada FORECAST_RETRAIN_INTERVAL.
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ForecastRunResponse represents a forecast model run without its points
+type ForecastRunResponse struct {
+	ID             uuid.UUID `json:"id"`
+	Model          string    `json:"model" example:"holt_winters_additive"`
+	Alpha          float64   `json:"alpha" example:"0.2"`
+	Beta           float64   `json:"beta" example:"0.01"`
+	Gamma          float64   `json:"gamma" example:"0.3"`
+	TrainedFrom    time.Time `json:"trained_from"`
+	TrainedTo      time.Time `json:"trained_to"`
+	TrainingPoints int       `json:"training_points" example:"672"`
+	ResidualStd    float64   `json:"residual_std" example:"8.3"`
+	BacktestHours  int       `json:"backtest_hours" example:"24"`
+	BacktestMAE    *float64  `json:"backtest_mae,omitempty" example:"6.1"`
+	BacktestRMSE   *float64  `json:"backtest_rmse,omitempty" example:"9.4"`
+	BacktestNMAE   *float64  `json:"backtest_nmae_percent,omitempty" example:"4.1"` // MAE as % of plant capacity
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// GetPlantForecast godoc
+// @Summary      Get plant production forecast
+// @Description  Hourly power_generated_mw forecast of the latest model run (additive Holt-Winters with daily seasonality) with confidence intervals, from the current hour up to the requested horizon. Backtest metrics tell how far the model missed the last hours it did not see.
+// @Tags         forecasts
+// @Produce      json
+// @Param        id       path      string  true   "Plant ID"
+// @Param        horizon  query     string  false  "Forecast horizon, e.g. 24h or 7d (default 24h)"
+// @Success      200      {object}  entities.ForecastEntity
+// @Failure      400      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/forecast [get]
+func GetPlantForecast(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := findPlantID(ctx, c)
+		if !ok {
+			return
+		}
+
+		horizon := 24 * time.Hour
+		if v := ctx.Query("horizon"); v != "" {
+			parsed, err := parseForecastHorizon(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			horizon = parsed
+		}
+
+		forecast, err := c.ForecastService.Latest(ctx.Request.Context(), plantID, horizon)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "no forecast available for this plant yet"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, forecast)
+	}
+}
+
+// TrainPlantForecast godoc
+// @Summary      Retrain plant forecast
+// @Description  Fits the plant model on its recent history now and stores a new run. Needs at least two days of readings and one in the last day.
+// @Tags         forecasts
+// @Produce      json
+// @Param        id   path      string  true  "Plant ID"
+// @Success      201  {object}  entities.ForecastEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/forecast [post]
+func TrainPlantForecast(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		forecast, err := c.ForecastService.Train(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, forecast)
+	}
+}
+
+// ListPlantForecastRuns godoc
+// @Summary      List plant forecast runs
+// @Description  Previous model runs of a plant (newest first) with their fitted parameters and backtest error, to track how much the forecast can be trusted
+// @Tags         forecasts
+// @Produce      json
+// @Param        id     path      string  true   "Plant ID"
+// @Param        limit  query     int     false  "Maximum number of runs (default 20)"
+// @Success      200    {array}   ForecastRunResponse
+// @Failure      400    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/forecast/runs [get]
+func ListPlantForecastRuns(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := findPlantID(ctx, c)
+		if !ok {
+			return
+		}
+
+		filter := entities.ForecastFilter{Limit: 20}
+		if v := ctx.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		runs, err := c.ForecastRepository.FindByPlant(ctx.Request.Context(), plantID, filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := make([]ForecastRunResponse, 0, len(runs))
+		for _, run := range runs {
+			response = append(response, ForecastRunResponse{
+				ID:             run.ID,
+				Model:          run.Model,
+				Alpha:          run.Alpha,
+				Beta:           run.Beta,
+				Gamma:          run.Gamma,
+				TrainedFrom:    run.TrainedFrom,
+				TrainedTo:      run.TrainedTo,
+				TrainingPoints: run.TrainingPoints,
+				ResidualStd:    run.ResidualStd,
+				BacktestHours:  run.BacktestHours,
+				BacktestMAE:    run.BacktestMAE,
+				BacktestRMSE:   run.BacktestRMSE,
+				BacktestNMAE:   run.BacktestNMAE,
+				CreatedAt:      run.CreatedAt,
+			})
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// parseForecastHorizon acepta duraciones de Go (24h) o días (7d)
+func parseForecastHorizon(value string) (time.Duration, error) {
+	var horizon time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, errors.New("invalid horizon")
+		}
+		horizon = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errors.New("invalid horizon")
+		}
+		horizon = parsed
+	}
+	if horizon < time.Hour {
+		return 0, errors.New("horizon must be at least 1h")
+	}
+	return horizon, nil
+}
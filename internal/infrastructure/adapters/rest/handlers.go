@@ -40,7 +40,7 @@ type ErrorResponse struct {
 // @Router       /api/v1/examples [get]
 func ListExamples(c *container.Container) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		examples, err := c.ExampleRepository.FindAll()
+		examples, err := c.ExampleRepository.FindAll(ctx.Request.Context())
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -69,7 +69,7 @@ func GetExample(c *container.Container) gin.HandlerFunc {
 			return
 		}
 
-		example, err := c.ExampleRepository.FindByID(id)
+		example, err := c.ExampleRepository.FindByID(ctx.Request.Context(), id)
 		if err != nil {
 			if errors.Is(err, domainerrors.ErrNotFound) {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": "example not found"})
@@ -106,7 +106,7 @@ func CreateExample(c *container.Container) gin.HandlerFunc {
 			Description: req.Description,
 		}
 
-		created, err := c.ExampleRepository.Create(entity)
+		created, err := c.ExampleRepository.Create(ctx.Request.Context(), entity)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -137,7 +137,7 @@ func UpdateExample(c *container.Container) gin.HandlerFunc {
 			return
 		}
 
-		existing, err := c.ExampleRepository.FindByID(id)
+		existing, err := c.ExampleRepository.FindByID(ctx.Request.Context(), id)
 		if err != nil {
 			if errors.Is(err, domainerrors.ErrNotFound) {
 				ctx.JSON(http.StatusNotFound, gin.H{"error": "example not found"})
@@ -160,7 +160,7 @@ func UpdateExample(c *container.Container) gin.HandlerFunc {
 			existing.Description = req.Description
 		}
 
-		updated, err := c.ExampleRepository.Update(existing)
+		updated, err := c.ExampleRepository.Update(ctx.Request.Context(), existing)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -189,7 +189,7 @@ func DeleteExample(c *container.Container) gin.HandlerFunc {
 			return
 		}
 
-		if err := c.ExampleRepository.Delete(id); err != nil {
+		if err := c.ExampleRepository.Delete(ctx.Request.Context(), id); err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -0,0 +1,38 @@
+package rest
+
+// health_handlers.go - Handler de readiness (/readyz)
+//
+// PROPÓSITO:
+// /healthz solo indica que el proceso responde (liveness).
+// /readyz ejecuta los checks registrados en el Container (base de datos, migraciones,
+// Kafka, consumidor y generador) y devuelve el estado de cada uno.
+
+import (
+	"net/http"
+
+	"monitoring-energy-service/internal/infrastructure/container"
+	"monitoring-energy-service/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Readiness ejecuta los checks de readiness y responde 503 si alguno falla
+//
+// Readiness godoc
+// @Summary      Readiness probe
+// @Description  Run the readiness checks (database, migrations, Kafka connectivity, consumer loop, event generator) and report the status of each one
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Failure      503  {object}  health.Report
+// @Router       /readyz [get]
+func Readiness(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		report := c.HealthChecker.Run(ctx.Request.Context())
+		status := http.StatusOK
+		if report.Status != health.StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+		ctx.JSON(status, report)
+	}
+}
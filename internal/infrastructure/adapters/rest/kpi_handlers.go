@@ -0,0 +1,151 @@
+package rest
+
+// kpi_handlers.go - Handlers REST de los reportes de KPIs
+//
+// ENDPOINTS:
+// - GET /api/v1/plants/:id/kpis  - KPIs de una planta por período (from, to, granularity)
+// - GET /api/v1/kpis             - KPIs de la flota por período y total de cada planta
+//
+// Sin from/to se reportan los últimos 30 días; granularity por defecto es day.
+// Con exclude_maintenance=true las ventanas de mantenimiento no cuentan para ningún KPI.
+// Con exclude_suspect=true se descartan las lecturas que fallaron los chequeos de plausibilidad.
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// parseKPIQuery lee from, to (RFC3339 o YYYY-MM-DD), granularity, exclude_maintenance y exclude_suspect de la query string
+// Sin from, el rango empieza 30 días atrás o en el primer día cuyos eventos no se purgaron (earliest)
+func parseKPIQuery(ctx *gin.Context, earliest time.Time) (entities.KPIQuery, error) {
+	query := entities.KPIQuery{
+		Granularity: ctx.DefaultQuery("granularity", entities.GranularityDay),
+		To:          time.Now().UTC(),
+	}
+
+	if v := ctx.Query("to"); v != "" {
+		to, err := parseReportTime(v)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid to", domainerrors.ErrInvalidInput)
+		}
+		query.To = to
+	}
+	if v := ctx.Query("exclude_maintenance"); v != "" {
+		exclude, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid exclude_maintenance", domainerrors.ErrInvalidInput)
+		}
+		query.ExcludeMaintenance = exclude
+	}
+	if v := ctx.Query("exclude_suspect"); v != "" {
+		exclude, err := strconv.ParseBool(v)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid exclude_suspect", domainerrors.ErrInvalidInput)
+		}
+		query.ExcludeSuspect = exclude
+	}
+	query.From = entities.PeriodStart(query.To.AddDate(0, 0, -30), entities.GranularityDay)
+	if query.From.Before(earliest) {
+		query.From = earliest
+	}
+	if v := ctx.Query("from"); v != "" {
+		from, err := parseReportTime(v)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid from", domainerrors.ErrInvalidInput)
+		}
+		query.From = from
+	}
+	return query, nil
+}
+
+// parseReportTime acepta RFC3339 o una fecha YYYY-MM-DD (medianoche UTC)
+func parseReportTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// GetPlantKPIs godoc
+// @Summary      Get plant KPIs
+// @Description  Capacity factor, average efficiency, peak output, energy generated and consumed, net export and time in each status for a plant, per day, week (ISO, Monday first) or month (UTC). Energy is integrated from good power readings with the trapezoidal rule, like the energy endpoints; gaps longer than ENERGY_MAX_GAP count as no data. telemetry summarizes the metrics of the plant type (irradiance, wind speed, reservoir level, state of charge); storage plants also report round_trip_efficiency_percent (discharged / charged energy).
+// @Tags         kpis
+// @Produce      json
+// @Param        id                   path      string  true   "Plant ID"
+// @Param        from                 query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago or the first day still within ROLLUP_RAW_RETENTION); earlier times are rejected"
+// @Param        to                   query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default now)"
+// @Param        granularity          query     string  false  "day, week or month (default day)"
+// @Param        exclude_maintenance  query     bool    false  "Leave maintenance windows out of every KPI and of the available capacity"
+// @Param        exclude_suspect      query     bool    false  "Use only readings that passed the plausibility checks (quality=good)"
+// @Success      200                  {object}  entities.PlantKPIReport
+// @Failure      400                  {object}  ErrorResponse
+// @Failure      404                  {object}  ErrorResponse
+// @Failure      500                  {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/kpis [get]
+func GetPlantKPIs(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		query, err := parseKPIQuery(ctx, c.KPIService.EarliestFrom())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.KPIService.PlantReport(ctx.Request.Context(), id, query)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// GetFleetKPIs godoc
+// @Summary      Get fleet KPIs
+// @Description  KPIs of all plants together per period, plus the range total of every plant. The fleet capacity factor uses the summed capacity; covered hours are summed across plants and the peak is the highest single-plant peak. telemetry combines the plants that report each type-specific metric, and round_trip_efficiency_percent covers the storage plants.
+// @Tags         kpis
+// @Produce      json
+// @Param        from                 query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago or the first day still within ROLLUP_RAW_RETENTION); earlier times are rejected"
+// @Param        to                   query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default now)"
+// @Param        granularity          query     string  false  "day, week or month (default day)"
+// @Param        exclude_maintenance  query     bool    false  "Leave maintenance windows out of every KPI and of the available capacity"
+// @Param        exclude_suspect      query     bool    false  "Use only readings that passed the plausibility checks (quality=good)"
+// @Success      200                  {object}  entities.FleetKPIReport
+// @Failure      400                  {object}  ErrorResponse
+// @Failure      500                  {object}  ErrorResponse
+// @Router       /api/v1/kpis [get]
+func GetFleetKPIs(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query, err := parseKPIQuery(ctx, c.KPIService.EarliestFrom())
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.KPIService.FleetReport(ctx.Request.Context(), query)
+		if err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
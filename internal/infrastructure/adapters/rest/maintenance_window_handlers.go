@@ -0,0 +1,300 @@
+package rest
+
+// maintenance_window_handlers.go - Handlers REST de las ventanas de mantenimiento
+//
+// ENDPOINTS:
+// - GET    /api/v1/maintenance-windows      - Lista ventanas (filtros: plant_id, active)
+// - POST   /api/v1/maintenance-windows      - Crea una ventana (única o recurrente)
+// - GET    /api/v1/maintenance-windows/:id  - Obtiene una ventana
+// - PUT    /api/v1/maintenance-windows/:id  - Modifica una ventana
+// - DELETE /api/v1/maintenance-windows/:id  - Borra una ventana
+//
+// Cada cambio recarga el MaintenanceCalendar para que aplique desde la siguiente lectura.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maintenanceLookahead es hasta dónde se busca la próxima ocurrencia de una ventana
+const maintenanceLookahead = 400 * 24 * time.Hour
+
+// MaintenanceWindowRequest represents the request body for creating or updating a maintenance window
+type MaintenanceWindowRequest struct {
+	PlantSourceId   uuid.UUID  `json:"plant_source_id" binding:"required"`
+	Name            string     `json:"name" binding:"required" example:"Inverter replacement"`
+	StartsAt        time.Time  `json:"starts_at" binding:"required"`
+	EndsAt          time.Time  `json:"ends_at" binding:"required"`
+	Recurrence      string     `json:"recurrence" example:"weekly"`     // none (default), daily, weekly or monthly
+	RecurrenceUntil *time.Time `json:"recurrence_until"`                // Last occurrence starts before this time
+	AlertPolicy     string     `json:"alert_policy" example:"suppress"` // suppress (default) or downgrade
+}
+
+// MaintenanceWindowResponse represents a maintenance window with its current or next occurrence
+type MaintenanceWindowResponse struct {
+	*entities.MaintenanceWindowEntity
+	Active         bool                `json:"active" example:"false"`
+	NextOccurrence *entities.TimeRange `json:"next_occurrence,omitempty"` // Current occurrence when active
+}
+
+func newMaintenanceWindowResponse(window *entities.MaintenanceWindowEntity, now time.Time) MaintenanceWindowResponse {
+	response := MaintenanceWindowResponse{MaintenanceWindowEntity: window}
+	if occurrences := window.Occurrences(now, now.Add(maintenanceLookahead)); len(occurrences) > 0 {
+		response.NextOccurrence = &occurrences[0]
+		response.Active = !now.Before(occurrences[0].Start)
+	}
+	return response
+}
+
+// applyMaintenanceWindowRequest valida el request y lo vuelca sobre la ventana
+func applyMaintenanceWindowRequest(ctx context.Context, c *container.Container, req MaintenanceWindowRequest, window *entities.MaintenanceWindowEntity) error {
+	window.PlantSourceId = req.PlantSourceId
+	window.Name = req.Name
+	window.StartsAt = req.StartsAt.UTC()
+	window.EndsAt = req.EndsAt.UTC()
+	window.Recurrence = req.Recurrence
+	if window.Recurrence == "" {
+		window.Recurrence = entities.RecurrenceNone
+	}
+	window.RecurrenceUntil = nil
+	if req.RecurrenceUntil != nil && window.Recurrence != entities.RecurrenceNone {
+		until := req.RecurrenceUntil.UTC()
+		window.RecurrenceUntil = &until
+	}
+	window.AlertPolicy = req.AlertPolicy
+	if window.AlertPolicy == "" {
+		window.AlertPolicy = entities.MaintenanceAlertSuppress
+	}
+
+	if err := window.Validate(); err != nil {
+		return err
+	}
+
+	exists, err := c.EnergyPlantRepository.Exists(ctx, window.PlantSourceId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: plant %s does not exist", domainerrors.ErrInvalidInput, window.PlantSourceId)
+	}
+	return nil
+}
+
+// ListMaintenanceWindows godoc
+// @Summary      List maintenance windows
+// @Description  Get planned maintenance windows, optionally only those of a plant or only those active now
+// @Tags         maintenance
+// @Produce      json
+// @Param        plant_id  query     string  false  "Plant UUID"
+// @Param        active    query     bool    false  "Only windows with an occurrence in progress"
+// @Success      200       {array}   MaintenanceWindowResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/maintenance-windows [get]
+func ListMaintenanceWindows(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var filter entities.MaintenanceWindowFilter
+		if v := ctx.Query("plant_id"); v != "" {
+			plantID, err := uuid.Parse(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid plant_id"})
+				return
+			}
+			filter.PlantSourceId = &plantID
+		}
+		onlyActive := false
+		if v := ctx.Query("active"); v != "" {
+			active, err := strconv.ParseBool(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid active"})
+				return
+			}
+			onlyActive = active
+		}
+
+		windows, err := c.MaintenanceWindowRepository.FindAll(ctx.Request.Context(), filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		now := time.Now().UTC()
+		response := make([]MaintenanceWindowResponse, 0, len(windows))
+		for _, window := range windows {
+			item := newMaintenanceWindowResponse(window, now)
+			if onlyActive && !item.Active {
+				continue
+			}
+			response = append(response, item)
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// CreateMaintenanceWindow godoc
+// @Summary      Create a maintenance window
+// @Description  Plan a maintenance window for a plant. With recurrence daily, weekly or monthly, starts_at/ends_at are the first occurrence. While an occurrence is active, new alerts of the plant are suppressed (alert_policy=suppress) or fired as info (alert_policy=downgrade), and its readings are stored with maintenance_window_id.
+// @Tags         maintenance
+// @Accept       json
+// @Produce      json
+// @Param        window  body      MaintenanceWindowRequest  true  "Maintenance window"
+// @Success      201     {object}  MaintenanceWindowResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/v1/maintenance-windows [post]
+func CreateMaintenanceWindow(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req MaintenanceWindowRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		window := &entities.MaintenanceWindowEntity{}
+		if err := applyMaintenanceWindowRequest(ctx.Request.Context(), c, req, window); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		created, err := c.MaintenanceWindowRepository.Create(ctx.Request.Context(), window)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.MaintenanceCalendar.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, newMaintenanceWindowResponse(created, time.Now().UTC()))
+	}
+}
+
+// GetMaintenanceWindow godoc
+// @Summary      Get a maintenance window by ID
+// @Description  Get a single maintenance window with its current or next occurrence
+// @Tags         maintenance
+// @Produce      json
+// @Param        id   path      string  true  "Maintenance window ID"
+// @Success      200  {object}  MaintenanceWindowResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/maintenance-windows/{id} [get]
+func GetMaintenanceWindow(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		window, err := c.MaintenanceWindowRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "maintenance window not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newMaintenanceWindowResponse(window, time.Now().UTC()))
+	}
+}
+
+// UpdateMaintenanceWindow godoc
+// @Summary      Update a maintenance window
+// @Description  Replace a maintenance window; it applies from the next reading
+// @Tags         maintenance
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                    true  "Maintenance window ID"
+// @Param        window  body      MaintenanceWindowRequest  true  "Maintenance window"
+// @Success      200     {object}  MaintenanceWindowResponse
+// @Failure      400     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/v1/maintenance-windows/{id} [put]
+func UpdateMaintenanceWindow(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req MaintenanceWindowRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		window, err := c.MaintenanceWindowRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "maintenance window not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := applyMaintenanceWindowRequest(ctx.Request.Context(), c, req, window); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		updated, err := c.MaintenanceWindowRepository.Update(ctx.Request.Context(), window)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.MaintenanceCalendar.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newMaintenanceWindowResponse(updated, time.Now().UTC()))
+	}
+}
+
+// DeleteMaintenanceWindow godoc
+// @Summary      Delete a maintenance window
+// @Description  Delete a maintenance window; readings already marked keep their maintenance_window_id
+// @Tags         maintenance
+// @Param        id   path      string  true  "Maintenance window ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/maintenance-windows/{id} [delete]
+func DeleteMaintenanceWindow(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if err := c.MaintenanceWindowRepository.Delete(ctx.Request.Context(), id); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.MaintenanceCalendar.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
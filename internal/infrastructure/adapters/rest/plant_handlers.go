@@ -0,0 +1,179 @@
+package rest
+
+// plant_handlers.go - Handlers REST de las plantas de energía
+//
+// ENDPOINTS:
+// - GET /api/v1/plants      - Lista las plantas (filtro opcional ?type=)
+// - GET /api/v1/plants/:id  - Obtiene una planta
+// - GET /api/v1/plant-types - Tipos de planta y las métricas que reporta cada uno
+//
+// Cada planta indica si está en una ventana de mantenimiento activa.
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PlantResponse represents an energy plant with its current status and maintenance state
+type PlantResponse struct {
+	ID                uuid.UUID                `json:"id"`
+	PlantName         string                   `json:"plant_name" example:"Solar Plant Alpha"`
+	PlantType         string                   `json:"plant_type" example:"solar"`
+	Location          string                   `json:"location" example:"Seville"`
+	CapacityMW        float64                  `json:"capacity_mw" example:"200"`
+	Status            string                   `json:"status,omitempty" example:"operational"`
+	StatusChangedAt   *time.Time               `json:"status_changed_at,omitempty"`
+	PortfolioNodeID   *uuid.UUID               `json:"portfolio_node_id,omitempty"`                       // Site the plant belongs to
+	ExpectedInterval  *int64                   `json:"expected_interval_seconds,omitempty" example:"300"` // Own reporting interval (default PLANT_EXPECTED_INTERVAL)
+	InMaintenance     bool                     `json:"in_maintenance" example:"false"`
+	MaintenanceWindow *ActiveMaintenanceWindow `json:"maintenance_window,omitempty"`
+}
+
+// ActiveMaintenanceWindow represents the maintenance window occurrence a plant is currently in
+type ActiveMaintenanceWindow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name" example:"Inverter replacement"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AlertPolicy string    `json:"alert_policy" example:"suppress"`
+}
+
+// activeMaintenance devuelve la ocurrencia de mantenimiento en curso de la planta (nil si no hay)
+func activeMaintenance(ctx context.Context, c *container.Container, plantID uuid.UUID, now time.Time) (*ActiveMaintenanceWindow, error) {
+	window, err := c.MaintenanceCalendar.ActiveWindow(ctx, plantID, now)
+	if err != nil || window == nil {
+		return nil, err
+	}
+	occurrence, _ := window.ActiveAt(now)
+	return &ActiveMaintenanceWindow{
+		ID:          window.ID,
+		Name:        window.Name,
+		Start:       occurrence.Start,
+		End:         occurrence.End,
+		AlertPolicy: window.AlertPolicy,
+	}, nil
+}
+
+func newPlantResponse(ctx context.Context, c *container.Container, plant *entities.EnergyPlants, now time.Time) (PlantResponse, error) {
+	response := PlantResponse{
+		ID:               plant.ID,
+		PlantName:        plant.PlantName,
+		PlantType:        plant.PlantType,
+		Location:         plant.Location,
+		CapacityMW:       plant.CapacityMW,
+		Status:           plant.Status,
+		StatusChangedAt:  plant.StatusChangedAt,
+		PortfolioNodeID:  plant.PortfolioNodeID,
+		ExpectedInterval: plant.IntervalSeconds,
+	}
+	maintenance, err := activeMaintenance(ctx, c, plant.ID, now)
+	if err != nil {
+		return response, err
+	}
+	response.InMaintenance = maintenance != nil
+	response.MaintenanceWindow = maintenance
+	return response, nil
+}
+
+// ListPlants godoc
+// @Summary      List plants
+// @Description  Get all energy plants ordered by name, with their current status and whether they are in an active maintenance window
+// @Tags         plants
+// @Produce      json
+// @Param        type  query     string  false  "Plant type"  Enums(solar, wind, hydro, storage, other)
+// @Success      200   {array}   PlantResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/plants [get]
+func ListPlants(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantType := ctx.Query("type")
+		if plantType != "" && !slices.Contains(entities.PlantTypes, plantType) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid type, must be one of: " + strings.Join(entities.PlantTypes, ", ")})
+			return
+		}
+
+		plants, err := c.EnergyPlantRepository.FindAll(ctx.Request.Context())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		now := time.Now().UTC()
+		response := make([]PlantResponse, 0, len(plants))
+		for _, plant := range plants {
+			if plantType != "" && plant.PlantType != plantType {
+				continue
+			}
+			item, err := newPlantResponse(ctx.Request.Context(), c, plant, now)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			response = append(response, item)
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// GetPlant godoc
+// @Summary      Get a plant by ID
+// @Description  Get a single energy plant with its current status and active maintenance window
+// @Tags         plants
+// @Produce      json
+// @Param        id   path      string  true  "Plant ID"
+// @Success      200  {object}  PlantResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/plants/{id} [get]
+func GetPlant(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		plant, err := c.EnergyPlantRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response, err := newPlantResponse(ctx.Request.Context(), c, plant, time.Now().UTC())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// ListPlantTypes godoc
+// @Summary      List plant types
+// @Description  Plant technologies with the telemetry each one reports: the common metrics (power, efficiency, temperature) plus irradiance for solar, wind speed for wind, reservoir level for hydro and state of charge for storage. A type-specific metric sent by a plant of another type is stored with quality=suspect (unexpected_metric); values outside the listed min/max are flagged out_of_range unless QUALITY_RANGES overrides them.
+// @Tags         plants
+// @Produce      json
+// @Success      200  {array}  entities.PlantTypeDefinition
+// @Router       /api/v1/plant-types [get]
+func ListPlantTypes(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, entities.PlantTypeDefinitions())
+	}
+}
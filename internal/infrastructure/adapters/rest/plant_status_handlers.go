@@ -0,0 +1,167 @@
+package rest
+
+// plant_status_handlers.go - Handlers REST del estado operativo de las plantas
+//
+// ENDPOINTS:
+// - GET /api/v1/plants/:id/status                - Estado actual y desde cuándo
+// - GET /api/v1/plants/:id/status/history        - Línea de tiempo de cambios (from, to, limit)
+// - GET /api/v1/plants/:id/status/time-in-state  - Horas en cada estado del período (from, to)
+//
+// El estado se actualiza con el status de las lecturas ingeridas (PlantStatusTracker).
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PlantStatusResponse represents the current operational status of a plant
+type PlantStatusResponse struct {
+	PlantSourceId   uuid.UUID  `json:"plant_source_id"`
+	Status          string     `json:"status" example:"operational"` // Empty until the plant reports a status
+	Since           *time.Time `json:"since,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds" example:"5400"` // Time in the current status
+	InMaintenance   bool       `json:"in_maintenance" example:"false"`  // Inside an active maintenance window
+}
+
+// GetPlantStatus godoc
+// @Summary      Get plant status
+// @Description  Current operational status of a plant (operational, maintenance, standby, peak_load) as reported by its latest readings
+// @Tags         plant-status
+// @Produce      json
+// @Param        id   path      string  true  "Plant ID"
+// @Success      200  {object}  PlantStatusResponse
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/status [get]
+func GetPlantStatus(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		plant, err := c.EnergyPlantRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := PlantStatusResponse{PlantSourceId: plant.ID, Status: plant.Status, Since: plant.StatusChangedAt}
+		if plant.StatusChangedAt != nil {
+			response.DurationSeconds = time.Since(*plant.StatusChangedAt).Seconds()
+		}
+		maintenance, err := activeMaintenance(ctx.Request.Context(), c, plant.ID, time.Now().UTC())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		response.InMaintenance = maintenance != nil
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// GetPlantStatusHistory godoc
+// @Summary      Get plant status timeline
+// @Description  Status changes of a plant in chronological order. Transitions outside the allowed ones are flagged with allowed=false.
+// @Tags         plant-status
+// @Produce      json
+// @Param        id     path      string  true   "Plant ID"
+// @Param        from   query     string  false  "Changed at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        to     query     string  false  "Changed before (RFC3339 or YYYY-MM-DD)"
+// @Param        limit  query     int     false  "Maximum number of changes"
+// @Success      200    {array}   entities.PlantStatusHistoryEntity
+// @Failure      400    {object}  ErrorResponse
+// @Failure      404    {object}  ErrorResponse
+// @Failure      500    {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/status/history [get]
+func GetPlantStatusHistory(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := findPlantID(ctx, c)
+		if !ok {
+			return
+		}
+
+		var filter entities.PlantStatusFilter
+		if v := ctx.Query("from"); v != "" {
+			from, err := parseReportTime(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+				return
+			}
+			filter.From = from
+		}
+		if v := ctx.Query("to"); v != "" {
+			to, err := parseReportTime(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+				return
+			}
+			filter.To = to
+		}
+		if v := ctx.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		history, err := c.PlantStatusHistoryRepository.FindByPlant(ctx.Request.Context(), plantID, filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, history)
+	}
+}
+
+// GetPlantTimeInState godoc
+// @Summary      Get plant time in each status
+// @Description  Hours a plant spent in each status during a period. The status at the start of the period is the last one reported before it; time before the first known status is reported as unknown.
+// @Tags         plant-status
+// @Produce      json
+// @Param        id    path      string  true   "Plant ID"
+// @Param        from  query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 30 days ago)"
+// @Param        to    query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default now)"
+// @Success      200   {object}  entities.PlantTimeInState
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/status/time-in-state [get]
+func GetPlantTimeInState(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := findPlantID(ctx, c)
+		if !ok {
+			return
+		}
+
+		query, err := parseKPIQuery(ctx, time.Time{})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := c.PlantStatusTracker.TimeInState(ctx.Request.Context(), plantID, query.From, query.To)
+		if err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, result)
+	}
+}
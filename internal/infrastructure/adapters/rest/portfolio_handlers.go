@@ -0,0 +1,307 @@
+package rest
+
+// portfolio_handlers.go - Handlers REST de la jerarquía del portfolio
+//
+// ENDPOINTS:
+// - GET    /api/v1/portfolio/nodes              - Lista nodos (filtros: level, parent_id)
+// - POST   /api/v1/portfolio/nodes              - Crea una organización, región o sitio
+// - GET    /api/v1/portfolio/nodes/:id          - Obtiene un nodo
+// - PUT    /api/v1/portfolio/nodes/:id          - Modifica nombre, descripción o padre
+// - DELETE /api/v1/portfolio/nodes/:id          - Borra un nodo sin hijos ni plantas
+// - GET    /api/v1/portfolio/nodes/:id/summary  - Agregados del nodo, de sus hijos y de sus plantas
+// - PUT    /api/v1/plants/:id/portfolio-node    - Asigna una planta a un sitio
+//
+// La jerarquía es organización → región → sitio → planta.
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PortfolioNodeRequest represents the request body for creating or updating a portfolio node
+type PortfolioNodeRequest struct {
+	Level       string     `json:"level" example:"site"` // organization, region or site (required on create, cannot change)
+	Name        string     `json:"name" binding:"required" example:"Andalusia South"`
+	ParentID    *uuid.UUID `json:"parent_id"` // Organization for a region, region for a site
+	Description string     `json:"description" example:"Solar parks south of Seville"`
+}
+
+// PlantPortfolioNodeRequest represents the request body for assigning a plant to a site
+type PlantPortfolioNodeRequest struct {
+	NodeID *uuid.UUID `json:"node_id"` // Site ID, or null to unassign
+}
+
+func (r PortfolioNodeRequest) node() entities.PortfolioNodeEntity {
+	return entities.PortfolioNodeEntity{
+		Level:       r.Level,
+		Name:        r.Name,
+		ParentID:    r.ParentID,
+		Description: r.Description,
+	}
+}
+
+// ListPortfolioNodes godoc
+// @Summary      List portfolio nodes
+// @Description  Get organizations, regions and sites ordered by name, optionally only one level or only the direct children of a node
+// @Tags         portfolio
+// @Produce      json
+// @Param        level      query     string  false  "organization, region or site"
+// @Param        parent_id  query     string  false  "Parent node UUID"
+// @Success      200        {array}   entities.PortfolioNodeEntity
+// @Failure      400        {object}  ErrorResponse
+// @Failure      500        {object}  ErrorResponse
+// @Router       /api/v1/portfolio/nodes [get]
+func ListPortfolioNodes(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter := entities.PortfolioNodeFilter{Level: ctx.Query("level")}
+		if filter.Level != "" && !slices.Contains(entities.PortfolioLevels, filter.Level) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid level"})
+			return
+		}
+		if v := ctx.Query("parent_id"); v != "" {
+			parentID, err := uuid.Parse(v)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid parent_id"})
+				return
+			}
+			filter.ParentID = &parentID
+		}
+
+		nodes, err := c.PortfolioNodeRepository.FindAll(ctx.Request.Context(), filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, nodes)
+	}
+}
+
+// CreatePortfolioNode godoc
+// @Summary      Create a portfolio node
+// @Description  Create an organization (no parent), a region (parent is an organization) or a site (parent is a region)
+// @Tags         portfolio
+// @Accept       json
+// @Produce      json
+// @Param        node  body      PortfolioNodeRequest  true  "Portfolio node"
+// @Success      201   {object}  entities.PortfolioNodeEntity
+// @Failure      400   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/portfolio/nodes [post]
+func CreatePortfolioNode(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req PortfolioNodeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		node := req.node()
+		created, err := c.PortfolioService.CreateNode(ctx.Request.Context(), &node)
+		if err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, created)
+	}
+}
+
+// GetPortfolioNode godoc
+// @Summary      Get a portfolio node by ID
+// @Description  Get a single organization, region or site
+// @Tags         portfolio
+// @Produce      json
+// @Param        id   path      string  true  "Portfolio node ID"
+// @Success      200  {object}  entities.PortfolioNodeEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/portfolio/nodes/{id} [get]
+func GetPortfolioNode(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		node, err := c.PortfolioNodeRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "portfolio node not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, node)
+	}
+}
+
+// UpdatePortfolioNode godoc
+// @Summary      Update a portfolio node
+// @Description  Rename a node, change its description or move it under another parent of the level above. The level cannot change.
+// @Tags         portfolio
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                true  "Portfolio node ID"
+// @Param        node  body      PortfolioNodeRequest  true  "Portfolio node"
+// @Success      200   {object}  entities.PortfolioNodeEntity
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/portfolio/nodes/{id} [put]
+func UpdatePortfolioNode(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req PortfolioNodeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		updated, err := c.PortfolioService.UpdateNode(ctx.Request.Context(), id, req.node())
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "portfolio node not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
+
+// DeletePortfolioNode godoc
+// @Summary      Delete a portfolio node
+// @Description  Delete a node that has no child nodes and no plants assigned
+// @Tags         portfolio
+// @Param        id   path      string  true  "Portfolio node ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/portfolio/nodes/{id} [delete]
+func DeletePortfolioNode(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if err := c.PortfolioService.DeleteNode(ctx.Request.Context(), id); err != nil {
+			switch {
+			case errors.Is(err, domainerrors.ErrNotFound):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "portfolio node not found"})
+			case errors.Is(err, domainerrors.ErrConflict):
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// GetPortfolioSummary godoc
+// @Summary      Get portfolio node summary
+// @Description  Roll-up of every plant below the node: plant count, capacity, current output (latest good reading within PORTFOLIO_OUTPUT_MAX_AGE), energy of the range and firing alerts by severity. Also returned for each direct child node and for the plants assigned directly to the node. from and to are widened to whole days.
+// @Tags         portfolio
+// @Produce      json
+// @Param        id    path      string  true   "Portfolio node ID"
+// @Param        from  query     string  false  "Energy range start (RFC3339 or YYYY-MM-DD, default 30 days ago)"
+// @Param        to    query     string  false  "Energy range end, exclusive (RFC3339 or YYYY-MM-DD, default end of today)"
+// @Success      200   {object}  entities.PortfolioReport
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/portfolio/nodes/{id}/summary [get]
+func GetPortfolioSummary(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		query, err := parseKPIQuery(ctx, time.Time{})
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := c.PortfolioService.Report(ctx.Request.Context(), id, query.From, query.To)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "portfolio node not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, report)
+	}
+}
+
+// AssignPlantPortfolioNode godoc
+// @Summary      Assign a plant to a site
+// @Description  Place a plant under a site of the portfolio, or send node_id null to leave it unassigned
+// @Tags         portfolio
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                     true  "Plant ID"
+// @Param        node  body      PlantPortfolioNodeRequest  true  "Site"
+// @Success      200   {object}  PlantResponse
+// @Failure      400   {object}  ErrorResponse
+// @Failure      404   {object}  ErrorResponse
+// @Failure      500   {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/portfolio-node [put]
+func AssignPlantPortfolioNode(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		var req PlantPortfolioNodeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		plant, err := c.PortfolioService.AssignPlant(ctx.Request.Context(), id, req.NodeID)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		response, err := newPlantResponse(ctx.Request.Context(), c, plant, time.Now().UTC())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,267 @@
+package rest
+
+// quarantine_handlers.go - Handlers REST de la cuarentena de plantas desconocidas
+//
+// ENDPOINTS:
+// - GET    /api/v1/quarantine                      - Mensajes en cuarentena agrupados por planta
+// - GET    /api/v1/quarantine/:plant_id            - Resumen de una planta
+// - GET    /api/v1/quarantine/:plant_id/events     - Mensajes de una planta (limit)
+// - POST   /api/v1/quarantine/:plant_id/register   - Da de alta la planta y libera sus mensajes
+// - POST   /api/v1/quarantine/:plant_id/release    - Libera los mensajes de una planta ya registrada
+// - DELETE /api/v1/quarantine/:plant_id            - Descarta los mensajes de una planta
+//
+// Solo se llena con UNKNOWN_PLANT_MODE=quarantine (QuarantineService).
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultQuarantineEventsLimit es la cantidad de mensajes devuelta sin limit
+const defaultQuarantineEventsLimit = 100
+
+// RegisterQuarantinedPlantRequest represents the plant to register for quarantined events
+type RegisterQuarantinedPlantRequest struct {
+	PlantName  string  `json:"plant_name" example:"Solar Plant Delta"` // Default: plant_name of the latest quarantined message
+	PlantType  string  `json:"plant_type" example:"solar"`             // Default: other
+	Location   string  `json:"location" example:"Almería"`
+	CapacityMW float64 `json:"capacity_mw" example:"50"`
+}
+
+// QuarantineReleaseResponse represents the quarantined events released into events
+type QuarantineReleaseResponse struct {
+	PlantSourceId uuid.UUID      `json:"plant_source_id"`
+	Released      int            `json:"released" example:"120"`
+	Plant         *PlantResponse `json:"plant,omitempty"` // Registered plant (register only)
+}
+
+// QuarantineDiscardResponse represents the quarantined events discarded
+type QuarantineDiscardResponse struct {
+	PlantSourceId uuid.UUID `json:"plant_source_id"`
+	Discarded     int64     `json:"discarded" example:"120"`
+}
+
+// parsePlantIDParam lee el plant_id de la ruta y responde 400 si no es un UUID
+func parsePlantIDParam(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("plant_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid plant_id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ListQuarantine godoc
+// @Summary      List quarantined plants
+// @Description  Messages from plants that are not registered, grouped by plant_source_id (most recent activity first). Only filled with UNKNOWN_PLANT_MODE=quarantine.
+// @Tags         quarantine
+// @Produce      json
+// @Success      200  {array}   entities.QuarantinedPlant
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/quarantine [get]
+func ListQuarantine(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plants, err := c.QuarantineService.List(ctx.Request.Context())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, plants)
+	}
+}
+
+// GetQuarantinedPlant godoc
+// @Summary      Get a quarantined plant
+// @Description  Number of quarantined messages of a plant, their time range and whether the plant is already registered
+// @Tags         quarantine
+// @Produce      json
+// @Param        plant_id  path      string  true  "Plant source ID"
+// @Success      200       {object}  entities.QuarantinedPlant
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/quarantine/{plant_id} [get]
+func GetQuarantinedPlant(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := parsePlantIDParam(ctx)
+		if !ok {
+			return
+		}
+
+		plant, err := c.QuarantineService.Get(ctx.Request.Context(), plantID)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "no quarantined events for plant"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, plant)
+	}
+}
+
+// ListQuarantinedEvents godoc
+// @Summary      List quarantined events of a plant
+// @Description  Quarantined messages of a plant ordered by event time
+// @Tags         quarantine
+// @Produce      json
+// @Param        plant_id  path      string  true   "Plant source ID"
+// @Param        limit     query     int     false  "Maximum number of events (default 100)"
+// @Success      200       {array}   entities.QuarantinedEventEntity
+// @Failure      400       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/quarantine/{plant_id}/events [get]
+func ListQuarantinedEvents(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := parsePlantIDParam(ctx)
+		if !ok {
+			return
+		}
+
+		limit := defaultQuarantineEventsLimit
+		if v := ctx.Query("limit"); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed <= 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			limit = parsed
+		}
+
+		events, err := c.QuarantineService.Events(ctx.Request.Context(), plantID, limit)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, events)
+	}
+}
+
+// RegisterQuarantinedPlant godoc
+// @Summary      Register a quarantined plant
+// @Description  Create the plant with the quarantined plant_source_id and release its messages into events. Released messages go through the intake again (quality checks, maintenance, alerts, aggregates) in event time order.
+// @Tags         quarantine
+// @Accept       json
+// @Produce      json
+// @Param        plant_id  path      string                           true  "Plant source ID"
+// @Param        plant     body      RegisterQuarantinedPlantRequest  true  "Plant"
+// @Success      201       {object}  QuarantineReleaseResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/quarantine/{plant_id}/register [post]
+func RegisterQuarantinedPlant(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := parsePlantIDParam(ctx)
+		if !ok {
+			return
+		}
+
+		var req RegisterQuarantinedPlantRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		plant, released, err := c.QuarantineService.Register(ctx.Request.Context(), &entities.EnergyPlants{
+			ID:         plantID,
+			PlantName:  req.PlantName,
+			PlantType:  req.PlantType,
+			Location:   req.Location,
+			CapacityMW: req.CapacityMW,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, domainerrors.ErrNotFound):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "no quarantined events for plant"})
+			case errors.Is(err, domainerrors.ErrConflict):
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		response := QuarantineReleaseResponse{PlantSourceId: plant.ID, Released: released}
+		plantResponse, err := newPlantResponse(ctx.Request.Context(), c, plant, time.Now().UTC())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		response.Plant = &plantResponse
+		ctx.JSON(http.StatusCreated, response)
+	}
+}
+
+// ReleaseQuarantinedEvents godoc
+// @Summary      Release quarantined events
+// @Description  Release the quarantined messages of a plant that is already registered into events. Stops at the first error; the remaining messages stay quarantined and can be released again.
+// @Tags         quarantine
+// @Produce      json
+// @Param        plant_id  path      string  true  "Plant source ID"
+// @Success      200       {object}  QuarantineReleaseResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      409       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/quarantine/{plant_id}/release [post]
+func ReleaseQuarantinedEvents(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := parsePlantIDParam(ctx)
+		if !ok {
+			return
+		}
+
+		released, err := c.QuarantineService.Release(ctx.Request.Context(), plantID)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrConflict) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, QuarantineReleaseResponse{PlantSourceId: plantID, Released: released})
+	}
+}
+
+// DiscardQuarantinedEvents godoc
+// @Summary      Discard quarantined events
+// @Description  Delete the quarantined messages of a plant without storing them
+// @Tags         quarantine
+// @Produce      json
+// @Param        plant_id  path      string  true  "Plant source ID"
+// @Success      200       {object}  QuarantineDiscardResponse
+// @Failure      400       {object}  ErrorResponse
+// @Failure      404       {object}  ErrorResponse
+// @Failure      500       {object}  ErrorResponse
+// @Router       /api/v1/quarantine/{plant_id} [delete]
+func DiscardQuarantinedEvents(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		plantID, ok := parsePlantIDParam(ctx)
+		if !ok {
+			return
+		}
+
+		discarded, err := c.QuarantineService.Discard(ctx.Request.Context(), plantID)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "no quarantined events for plant"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, QuarantineDiscardResponse{PlantSourceId: plantID, Discarded: discarded})
+	}
+}
@@ -0,0 +1,97 @@
+package rest
+
+// reading_handlers.go - Handlers REST de las series de lecturas
+//
+// ENDPOINTS:
+// - GET /api/v1/plants/:id/readings - Serie de una métrica agregada por bucket (metric, from, to, bucket)
+//
+// La serie se lee del nivel más fino que cubre el rango (raw, rollups de 1m, 1h o 1d).
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// parseReadingSeriesQuery lee metric, from, to y bucket (por defecto las últimas 24 horas en buckets de 1 hora)
+func parseReadingSeriesQuery(ctx *gin.Context) (entities.ReadingSeriesQuery, error) {
+	query := entities.ReadingSeriesQuery{
+		Metric: ctx.DefaultQuery("metric", entities.MetricPowerGenerated),
+		To:     time.Now().UTC(),
+		Bucket: time.Hour,
+	}
+
+	if v := ctx.Query("to"); v != "" {
+		to, err := parseReportTime(v)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid to", domainerrors.ErrInvalidInput)
+		}
+		query.To = to
+	}
+	query.From = query.To.Add(-24 * time.Hour)
+	if v := ctx.Query("from"); v != "" {
+		from, err := parseReportTime(v)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid from", domainerrors.ErrInvalidInput)
+		}
+		query.From = from
+	}
+	if v := ctx.Query("bucket"); v != "" {
+		bucket, err := time.ParseDuration(v)
+		if err != nil {
+			return query, fmt.Errorf("%w: invalid bucket", domainerrors.ErrInvalidInput)
+		}
+		query.Bucket = bucket
+	}
+	return query, nil
+}
+
+// GetPlantReadings godoc
+// @Summary      Get plant reading series
+// @Description  Count, mean, min and max of a metric of a plant per bucket, from good readings only. The series is read from the finest tier that still keeps the range and whose resolution divides the bucket: raw events (ROLLUP_RAW_RETENTION), 1-minute rollups (ROLLUP_1M_RETENTION), 1-hour rollups (ROLLUP_1H_RETENTION) or daily rollups (kept forever); tier tells which one was used. Buckets are aligned to UTC and empty buckets are left out; the most recent minutes may be missing from a rollup tier until its next build.
+// @Tags         plants
+// @Produce      json
+// @Param        id      path      string  true   "Plant ID"
+// @Param        metric  query     string  false  "Metric (default power_generated_mw)"
+// @Param        from    query     string  false  "Start (RFC3339 or YYYY-MM-DD, default 24 hours ago)"
+// @Param        to      query     string  false  "End, exclusive (RFC3339 or YYYY-MM-DD, default now)"
+// @Param        bucket  query     string  false  "Bucket size as a Go duration, e.g. 1m, 15m, 1h, 24h (default 1h)"
+// @Success      200     {object}  entities.ReadingSeries
+// @Failure      400     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/v1/plants/{id}/readings [get]
+func GetPlantReadings(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		query, err := parseReadingSeriesQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		series, err := c.RollupService.Series(ctx.Request.Context(), id, query)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "plant not found"})
+				return
+			}
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, series)
+	}
+}
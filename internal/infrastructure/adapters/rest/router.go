@@ -34,5 +34,138 @@ func SetupRoutes(router *gin.RouterGroup, c *container.Container) {
 			events.GET("/:id", GetEvent(c))                // GET /api/v1/events/:id - Obtiene uno por ID
 			events.GET("/type/:type", GetEventsByType(c)) // GET /api/v1/events/type/:type - Filtra por tipo
 		}
+
+		plants := api.Group("/plants")
+		{
+			plants.GET("", ListPlants(c))
+			plants.GET("/:id", GetPlant(c))
+			plants.GET("/:id/kpis", GetPlantKPIs(c))
+			plants.GET("/:id/readings", GetPlantReadings(c))
+			plants.GET("/:id/anomaly-settings", GetAnomalySettings(c))
+			plants.PUT("/:id/anomaly-settings", UpdateAnomalySettings(c))
+			plants.DELETE("/:id/anomaly-settings", DeleteAnomalySettings(c))
+			plants.GET("/:id/status", GetPlantStatus(c))
+			plants.GET("/:id/status/history", GetPlantStatusHistory(c))
+			plants.GET("/:id/status/time-in-state", GetPlantTimeInState(c))
+			plants.GET("/:id/forecast", GetPlantForecast(c))
+			plants.POST("/:id/forecast", TrainPlantForecast(c))
+			plants.GET("/:id/forecast/runs", ListPlantForecastRuns(c))
+			plants.GET("/:id/energy", GetPlantEnergy(c))
+			plants.POST("/:id/energy/recompute", RecomputePlantEnergy(c))
+			plants.PUT("/:id/portfolio-node", AssignPlantPortfolioNode(c))
+			plants.GET("/:id/emissions", GetPlantEmissions(c))
+			plants.GET("/:id/connectivity", GetPlantConnectivity(c))
+			plants.PUT("/:id/connectivity", UpdatePlantConnectivity(c))
+		}
+
+		api.GET("/plant-types", ListPlantTypes(c))
+		api.GET("/kpis", GetFleetKPIs(c))
+		api.GET("/energy", GetFleetEnergy(c))
+		api.GET("/emissions", GetFleetEmissions(c))
+		api.GET("/connectivity", ListConnectivity(c))
+
+		portfolio := api.Group("/portfolio/nodes")
+		{
+			portfolio.GET("", ListPortfolioNodes(c))
+			portfolio.POST("", CreatePortfolioNode(c))
+			portfolio.GET("/:id", GetPortfolioNode(c))
+			portfolio.PUT("/:id", UpdatePortfolioNode(c))
+			portfolio.DELETE("/:id", DeletePortfolioNode(c))
+			portfolio.GET("/:id/summary", GetPortfolioSummary(c))
+			portfolio.GET("/:id/emissions", GetPortfolioNodeEmissions(c))
+		}
+
+		emissionFactors := api.Group("/emission-factors")
+		{
+			emissionFactors.GET("", ListEmissionFactors(c))
+			emissionFactors.POST("", CreateEmissionFactor(c))
+			emissionFactors.GET("/:id", GetEmissionFactor(c))
+		}
+
+		quarantine := api.Group("/quarantine")
+		{
+			quarantine.GET("", ListQuarantine(c))
+			quarantine.GET("/:plant_id", GetQuarantinedPlant(c))
+			quarantine.GET("/:plant_id/events", ListQuarantinedEvents(c))
+			quarantine.POST("/:plant_id/register", RegisterQuarantinedPlant(c))
+			quarantine.POST("/:plant_id/release", ReleaseQuarantinedEvents(c))
+			quarantine.DELETE("/:plant_id", DiscardQuarantinedEvents(c))
+		}
+
+		alertRules := api.Group("/alert-rules")
+		{
+			alertRules.GET("", ListAlertRules(c))
+			alertRules.POST("", CreateAlertRule(c))
+			alertRules.GET("/:id", GetAlertRule(c))
+			alertRules.PUT("/:id", UpdateAlertRule(c))
+			alertRules.DELETE("/:id", DeleteAlertRule(c))
+		}
+
+		alerts := api.Group("/alerts")
+		{
+			alerts.GET("", ListAlerts(c))
+			alerts.GET("/:id", GetAlert(c))
+		}
+
+		anomalies := api.Group("/anomalies")
+		{
+			anomalies.GET("", ListAnomalies(c))
+			anomalies.GET("/:id", GetAnomaly(c))
+		}
+
+		maintenanceWindows := api.Group("/maintenance-windows")
+		{
+			maintenanceWindows.GET("", ListMaintenanceWindows(c))
+			maintenanceWindows.POST("", CreateMaintenanceWindow(c))
+			maintenanceWindows.GET("/:id", GetMaintenanceWindow(c))
+			maintenanceWindows.PUT("/:id", UpdateMaintenanceWindow(c))
+			maintenanceWindows.DELETE("/:id", DeleteMaintenanceWindow(c))
+		}
+
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.GET("", ListWebhookSubscriptions(c))
+			webhooks.POST("", CreateWebhookSubscription(c))
+			webhooks.GET("/:id", GetWebhookSubscription(c))
+			webhooks.PUT("/:id", UpdateWebhookSubscription(c))
+			webhooks.DELETE("/:id", DeleteWebhookSubscription(c))
+			webhooks.GET("/:id/deliveries", ListWebhookSubscriptionDeliveries(c))
+			webhooks.POST("/:id/test", SendWebhookTest(c))
+		}
+
+		webhookDeliveries := api.Group("/webhook-deliveries")
+		{
+			webhookDeliveries.GET("", ListWebhookDeliveries(c))
+			webhookDeliveries.GET("/:id", GetWebhookDelivery(c))
+			webhookDeliveries.POST("/:id/redeliver", RedeliverWebhook(c))
+		}
+	}
+}
+
+// SetupAdminRoutes configura las rutas del servidor de administración
+// Se montan en un router propio escuchando en ADMIN_PORT; todas pasan por AdminAuth
+func SetupAdminRoutes(router *gin.RouterGroup, c *container.Container) {
+	secured := router.Group("", AdminAuth(c.GetConfig().AdminApiKey))
+	{
+		admin := secured.Group("/admin")
+		{
+			admin.GET("/version", GetVersion(c))
+			admin.GET("/config", GetEffectiveConfig(c))
+			admin.GET("/loglevel", GetLogLevel(c))
+			admin.PUT("/loglevel", SetLogLevel(c))
+
+			simulator := admin.Group("/simulator")
+			{
+				simulator.GET("", GetSimulatorStatus(c))
+				simulator.POST("/start", StartSimulator(c))
+				simulator.POST("/stop", StopSimulator(c))
+				simulator.POST("/pause", PauseSimulator(c))
+				simulator.POST("/resume", ResumeSimulator(c))
+				simulator.PUT("/rate", SetSimulatorRate(c))
+				simulator.POST("/burst", SimulatorBurst(c))
+			}
+		}
+
+		registerPprof(secured)
 	}
 }
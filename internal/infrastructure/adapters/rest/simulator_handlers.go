@@ -0,0 +1,251 @@
+package rest
+
+// simulator_handlers.go - Control del simulador de lecturas (EventGenerator) en caliente
+//
+// Se sirven en el servidor de administración (ADMIN_PORT, requieren ADMIN_API_KEY).
+//
+// ENDPOINTS:
+// - GET  /admin/simulator         - Estado, configuración actual y contadores
+// - POST /admin/simulator/start   - Arranca el loop de lotes
+// - POST /admin/simulator/stop    - Detiene el loop (corta el lote en curso)
+// - POST /admin/simulator/pause   - Deja de enviar lotes sin detener el loop
+// - POST /admin/simulator/resume  - Vuelve a enviar lotes desde el próximo intervalo
+// - PUT  /admin/simulator/rate    - Cambia rate, batch_size o interval
+// - POST /admin/simulator/burst   - Envía una ráfaga de N eventos para las plantas elegidas
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"monitoring-energy-service/internal/api"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SimulatorStatusResponse represents the state and counters of the simulator
+type SimulatorStatusResponse struct {
+	State       string     `json:"state" example:"running"` // running, paused or stopped
+	Topic       string     `json:"topic" example:"events.default"`
+	BatchSize   int        `json:"batch_size" example:"30"`
+	Interval    string     `json:"interval" example:"5m0s"`
+	Rate        float64    `json:"rate" example:"10"` // Events per second within a batch (0 = no pause)
+	Seed        int64      `json:"seed" example:"42"`
+	Sent        int64      `json:"sent" example:"1200"`  // Since the service started, bursts included
+	Failed      int64      `json:"failed" example:"0"`   // Events rejected by Kafka
+	Batches     int64      `json:"batches" example:"40"` // Batches and bursts with events
+	Plants      int        `json:"plants" example:"4"`   // Plants with simulated state
+	LastBatchAt *time.Time `json:"last_batch_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// SimulatorRateRequest represents the simulator settings to change (omitted fields keep their value)
+type SimulatorRateRequest struct {
+	Rate      *float64 `json:"rate" example:"5"`        // Events per second within a batch (0 = no pause)
+	BatchSize *int     `json:"batch_size" example:"60"` // Events per batch (1-10000)
+	Interval  *string  `json:"interval" example:"1m"`   // Go duration between batches (>= 1s)
+}
+
+// SimulatorBurstRequest represents a one-off burst of events
+type SimulatorBurstRequest struct {
+	Count    int         `json:"count" binding:"required" example:"100"` // Number of events (1-10000)
+	PlantIDs []uuid.UUID `json:"plant_ids"`                              // Plants to simulate (empty = all registered plants)
+}
+
+// SimulatorBurstResponse represents the result of a burst
+type SimulatorBurstResponse struct {
+	Sent     int         `json:"sent" example:"100"`
+	Failed   int         `json:"failed" example:"0"`
+	PlantIDs []uuid.UUID `json:"plant_ids"`
+}
+
+// newSimulatorStatusResponse convierte el estado del EventGenerator en la respuesta
+func newSimulatorStatusResponse(status api.SimulatorStatus) SimulatorStatusResponse {
+	response := SimulatorStatusResponse{
+		State:     status.State,
+		Topic:     status.Config.Topic,
+		BatchSize: status.Config.BatchSize,
+		Interval:  status.Config.Interval.String(),
+		Rate:      status.Config.Rate,
+		Seed:      status.Config.Seed,
+		Sent:      status.Sent,
+		Failed:    status.Failed,
+		Batches:   status.Batches,
+		Plants:    status.Plants,
+		LastError: status.LastError,
+	}
+	if !status.LastBatchAt.IsZero() {
+		response.LastBatchAt = &status.LastBatchAt
+	}
+	if !status.LastErrorAt.IsZero() {
+		response.LastErrorAt = &status.LastErrorAt
+	}
+	return response
+}
+
+// simulatorControl responde el estado del simulador después de aplicar action (409 si el estado no lo permite)
+func simulatorControl(c *container.Container, action func() error) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := action(); err != nil {
+			if errors.Is(err, domainerrors.ErrConflict) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newSimulatorStatusResponse(c.EventGenerator.Status()))
+	}
+}
+
+// GetSimulatorStatus godoc
+// @Summary      Simulator status
+// @Description  Get the state (running, paused, stopped), current settings and counters of the event simulator (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  SimulatorStatusResponse
+// @Failure      401  {object}  ErrorResponse
+// @Router       /admin/simulator [get]
+func GetSimulatorStatus(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, newSimulatorStatusResponse(c.EventGenerator.Status()))
+	}
+}
+
+// StartSimulator godoc
+// @Summary      Start the simulator
+// @Description  Start sending batches of simulated readings: one immediately, then one every interval (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  SimulatorStatusResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Router       /admin/simulator/start [post]
+func StartSimulator(c *container.Container) gin.HandlerFunc {
+	return simulatorControl(c, c.EventGenerator.Start)
+}
+
+// StopSimulator godoc
+// @Summary      Stop the simulator
+// @Description  Stop the simulator loop; an ongoing batch is cut short. Simulated plant state and counters are kept (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  SimulatorStatusResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Router       /admin/simulator/stop [post]
+func StopSimulator(c *container.Container) gin.HandlerFunc {
+	return simulatorControl(c, c.EventGenerator.Stop)
+}
+
+// PauseSimulator godoc
+// @Summary      Pause the simulator
+// @Description  Stop sending batches without stopping the loop; an ongoing batch is cut short (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  SimulatorStatusResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Router       /admin/simulator/pause [post]
+func PauseSimulator(c *container.Container) gin.HandlerFunc {
+	return simulatorControl(c, c.EventGenerator.Pause)
+}
+
+// ResumeSimulator godoc
+// @Summary      Resume the simulator
+// @Description  Send batches again from the next interval (admin listener)
+// @Tags         admin
+// @Produce      json
+// @Security     AdminApiKey
+// @Success      200  {object}  SimulatorStatusResponse
+// @Failure      401  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Router       /admin/simulator/resume [post]
+func ResumeSimulator(c *container.Container) gin.HandlerFunc {
+	return simulatorControl(c, c.EventGenerator.Resume)
+}
+
+// SetSimulatorRate godoc
+// @Summary      Change simulator rate
+// @Description  Change the events per second, batch size or interval without a restart. Rate and batch size apply from the next batch; a new interval restarts the ticker (admin listener)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminApiKey
+// @Param        request  body      SimulatorRateRequest  true  "Settings to change"
+// @Success      200      {object}  SimulatorStatusResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Router       /admin/simulator/rate [put]
+func SetSimulatorRate(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SimulatorRateRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		update := api.SimulatorRate{Rate: req.Rate, BatchSize: req.BatchSize}
+		if req.Interval != nil {
+			d, err := time.ParseDuration(*req.Interval)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid interval"})
+				return
+			}
+			update.Interval = &d
+		}
+
+		if _, err := c.EventGenerator.SetRate(update); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, newSimulatorStatusResponse(c.EventGenerator.Status()))
+	}
+}
+
+// SimulatorBurst godoc
+// @Summary      Send a burst of events
+// @Description  Send count simulated readings right away, spread in turn over the given plants (all registered plants when plant_ids is empty). Works while the simulator is stopped or paused (admin listener)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminApiKey
+// @Param        request  body      SimulatorBurstRequest  true  "Burst"
+// @Success      200      {object}  SimulatorBurstResponse
+// @Failure      400      {object}  ErrorResponse
+// @Failure      401      {object}  ErrorResponse
+// @Failure      404      {object}  ErrorResponse
+// @Failure      409      {object}  ErrorResponse
+// @Failure      500      {object}  ErrorResponse
+// @Router       /admin/simulator/burst [post]
+func SimulatorBurst(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SimulatorBurstRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := c.EventGenerator.Burst(ctx.Request.Context(), req.Count, req.PlantIDs)
+		if err != nil {
+			switch {
+			case errors.Is(err, domainerrors.ErrNotFound):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, domainerrors.ErrConflict):
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			}
+			return
+		}
+		ctx.JSON(http.StatusOK, SimulatorBurstResponse{Sent: result.Sent, Failed: result.Failed, PlantIDs: result.Plants})
+	}
+}
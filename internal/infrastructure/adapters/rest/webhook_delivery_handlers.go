@@ -0,0 +1,129 @@
+package rest
+
+// webhook_delivery_handlers.go - Handlers REST del log de entregas de webhooks
+//
+// ENDPOINTS:
+// - GET  /api/v1/webhook-deliveries                - Lista entregas (filtros: status, event_type, limit)
+// - GET  /api/v1/webhook-deliveries/:id            - Obtiene una entrega con todos sus intentos
+// - POST /api/v1/webhook-deliveries/:id/redeliver  - Vuelve a encolar una entrega failed o delivered
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"monitoring-energy-service/internal/api"
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListWebhookDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  Get the webhook delivery log, most recent first
+// @Tags         webhooks
+// @Produce      json
+// @Param        status      query     string  false  "pending, delivered or failed"
+// @Param        event_type  query     string  false  "Event type (e.g. power_reading, alert.firing)"
+// @Param        limit       query     int     false  "Maximum number of deliveries"
+// @Success      200         {array}   entities.WebhookDeliveryEntity
+// @Failure      400         {object}  ErrorResponse
+// @Failure      500         {object}  ErrorResponse
+// @Router       /api/v1/webhook-deliveries [get]
+func ListWebhookDeliveries(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		filter := entities.WebhookDeliveryFilter{
+			Status:    ctx.Query("status"),
+			EventType: ctx.Query("event_type"),
+		}
+		if v := ctx.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		deliveries, err := c.WebhookDeliveryRepository.FindAll(ctx.Request.Context(), filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, deliveries)
+	}
+}
+
+// GetWebhookDelivery godoc
+// @Summary      Get a webhook delivery by ID
+// @Description  Get a webhook delivery with every attempt and its response code
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Delivery ID"
+// @Success      200  {object}  entities.WebhookDeliveryEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/webhook-deliveries/{id} [get]
+func GetWebhookDelivery(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		delivery, err := c.WebhookDeliveryRepository.FindByID(ctx.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, domainerrors.ErrNotFound) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "webhook delivery not found"})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, delivery)
+	}
+}
+
+// RedeliverWebhook godoc
+// @Summary      Redeliver a webhook
+// @Description  Queue a failed or delivered webhook delivery again with the same payload and delivery ID
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Delivery ID"
+// @Success      202  {object}  entities.WebhookDeliveryEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      409  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/webhook-deliveries/{id}/redeliver [post]
+func RedeliverWebhook(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		delivery, err := c.WebhookDispatcher.Redeliver(ctx.Request.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, domainerrors.ErrNotFound):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "webhook delivery not found"})
+			case errors.Is(err, domainerrors.ErrConflict):
+				ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case errors.Is(err, api.ErrDeliveryQueueFull):
+				ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		ctx.JSON(http.StatusAccepted, delivery)
+	}
+}
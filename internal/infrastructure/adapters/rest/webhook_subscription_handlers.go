@@ -0,0 +1,365 @@
+package rest
+
+// webhook_subscription_handlers.go - Handlers REST de las suscripciones de webhooks
+//
+// ENDPOINTS:
+// - GET    /api/v1/webhooks                 - Lista las suscripciones
+// - POST   /api/v1/webhooks                 - Crea una suscripción (devuelve el secreto una sola vez)
+// - GET    /api/v1/webhooks/:id             - Obtiene una suscripción
+// - PUT    /api/v1/webhooks/:id             - Modifica URL, filtros o estado (active / paused)
+// - DELETE /api/v1/webhooks/:id             - Borra una suscripción (su historial de entregas se conserva)
+// - GET    /api/v1/webhooks/:id/deliveries  - Historial de entregas de la suscripción
+// - POST   /api/v1/webhooks/:id/test        - Envía un evento webhook.test firmado
+//
+// Cada cambio recarga el WebhookDispatcher para que aplique desde la siguiente notificación.
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"monitoring-energy-service/internal/api"
+	"monitoring-energy-service/internal/domain/entities"
+	domainerrors "monitoring-energy-service/internal/domain/errors"
+	"monitoring-energy-service/internal/infrastructure/container"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookSubscriptionRequest represents the request body for creating or updating a webhook subscription
+type WebhookSubscriptionRequest struct {
+	Name       string      `json:"name" binding:"required" example:"Ops pager"`
+	URL        string      `json:"url" binding:"required" example:"https://pager.example.com/hooks/energy"`
+	Secret     string      `json:"secret" example:"s3cr3t"` // Empty on create = generated; empty on update = unchanged
+	EventTypes []string    `json:"event_types" example:"alert.firing,alert.resolved"`
+	PlantIDs   []uuid.UUID `json:"plant_ids"`
+	Status     string      `json:"status" example:"active"` // active or paused (default active)
+}
+
+// WebhookSubscriptionResponse represents a webhook subscription; the secret is only returned on create
+type WebhookSubscriptionResponse struct {
+	*entities.WebhookSubscriptionEntity
+	Secret string `json:"secret,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015"`
+}
+
+// applyWebhookSubscriptionRequest valida el request y lo vuelca sobre la suscripción
+//
+// Pasar a active una suscripción deshabilitada por fallos reinicia su contador.
+func applyWebhookSubscriptionRequest(ctx context.Context, c *container.Container, req WebhookSubscriptionRequest, subscription *entities.WebhookSubscriptionEntity) error {
+	status := req.Status
+	if status == "" {
+		status = entities.SubscriptionStatusActive
+	}
+	if status != entities.SubscriptionStatusActive && status != entities.SubscriptionStatusPaused {
+		return fmt.Errorf("%w: status must be active or paused", domainerrors.ErrInvalidInput)
+	}
+	if status == entities.SubscriptionStatusActive && subscription.Status != entities.SubscriptionStatusActive {
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledReason = ""
+	}
+
+	subscription.Name = req.Name
+	subscription.URL = req.URL
+	if req.Secret != "" {
+		subscription.Secret = req.Secret
+	}
+	subscription.EventTypes = req.EventTypes
+	if subscription.EventTypes == nil {
+		subscription.EventTypes = []string{}
+	}
+	subscription.PlantIDs = req.PlantIDs
+	if subscription.PlantIDs == nil {
+		subscription.PlantIDs = []uuid.UUID{}
+	}
+	subscription.Status = status
+
+	if err := subscription.Validate(); err != nil {
+		return err
+	}
+
+	for _, plantID := range subscription.PlantIDs {
+		exists, err := c.EnergyPlantRepository.Exists(ctx, plantID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: plant %s does not exist", domainerrors.ErrInvalidInput, plantID)
+		}
+	}
+	return nil
+}
+
+// generateWebhookSecret genera un secreto aleatorio de 32 bytes en hexadecimal
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// findWebhookSubscription obtiene la suscripción del :id; si no existe ya respondió el error
+func findWebhookSubscription(ctx *gin.Context, c *container.Container) (*entities.WebhookSubscriptionEntity, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+
+	subscription, err := c.WebhookSubscriptionRepository.FindByID(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "webhook subscription not found"})
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return subscription, true
+}
+
+// ListWebhookSubscriptions godoc
+// @Summary      List webhook subscriptions
+// @Description  Get all webhook subscriptions with their filters, state and consecutive failures
+// @Tags         webhooks
+// @Produce      json
+// @Success      200  {array}   entities.WebhookSubscriptionEntity
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/webhooks [get]
+func ListWebhookSubscriptions(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		subscriptions, err := c.WebhookSubscriptionRepository.FindAll(ctx.Request.Context())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, subscriptions)
+	}
+}
+
+// CreateWebhookSubscription godoc
+// @Summary      Create a webhook subscription
+// @Description  Register a webhook endpoint with its own HMAC secret and event type / plant filters (empty filters = everything). If no secret is sent one is generated; the secret is only returned in this response.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        subscription  body      WebhookSubscriptionRequest  true  "Webhook subscription"
+// @Success      201           {object}  WebhookSubscriptionResponse
+// @Failure      400           {object}  ErrorResponse
+// @Failure      500           {object}  ErrorResponse
+// @Router       /api/v1/webhooks [post]
+func CreateWebhookSubscription(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req WebhookSubscriptionRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.Secret == "" {
+			secret, err := generateWebhookSecret()
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			req.Secret = secret
+		}
+
+		subscription := &entities.WebhookSubscriptionEntity{}
+		if err := applyWebhookSubscriptionRequest(ctx.Request.Context(), c, req, subscription); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		created, err := c.WebhookSubscriptionRepository.Create(ctx.Request.Context(), subscription)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.WebhookDispatcher.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusCreated, WebhookSubscriptionResponse{WebhookSubscriptionEntity: created, Secret: created.Secret})
+	}
+}
+
+// GetWebhookSubscription godoc
+// @Summary      Get a webhook subscription by ID
+// @Description  Get a single webhook subscription by its UUID
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {object}  entities.WebhookSubscriptionEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/webhooks/{id} [get]
+func GetWebhookSubscription(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		subscription, ok := findWebhookSubscription(ctx, c)
+		if !ok {
+			return
+		}
+		ctx.JSON(http.StatusOK, subscription)
+	}
+}
+
+// UpdateWebhookSubscription godoc
+// @Summary      Update a webhook subscription
+// @Description  Replace the URL, filters and state (active or paused) of a subscription. Setting an auto-disabled subscription back to active resets its failure counter. An empty secret keeps the current one.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id            path      string                      true  "Subscription ID"
+// @Param        subscription  body      WebhookSubscriptionRequest  true  "Webhook subscription"
+// @Success      200           {object}  entities.WebhookSubscriptionEntity
+// @Failure      400           {object}  ErrorResponse
+// @Failure      404           {object}  ErrorResponse
+// @Failure      500           {object}  ErrorResponse
+// @Router       /api/v1/webhooks/{id} [put]
+func UpdateWebhookSubscription(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req WebhookSubscriptionRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		subscription, ok := findWebhookSubscription(ctx, c)
+		if !ok {
+			return
+		}
+
+		if err := applyWebhookSubscriptionRequest(ctx.Request.Context(), c, req, subscription); err != nil {
+			ctx.JSON(validationErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		updated, err := c.WebhookSubscriptionRepository.Update(ctx.Request.Context(), subscription)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.WebhookDispatcher.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
+
+// DeleteWebhookSubscription godoc
+// @Summary      Delete a webhook subscription
+// @Description  Delete a webhook subscription; its delivery history is kept in /api/v1/webhook-deliveries
+// @Tags         webhooks
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/webhooks/{id} [delete]
+func DeleteWebhookSubscription(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		if err := c.WebhookSubscriptionRepository.Delete(ctx.Request.Context(), id); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := c.WebhookDispatcher.Reload(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// ListWebhookSubscriptionDeliveries godoc
+// @Summary      List deliveries of a webhook subscription
+// @Description  Get the delivery history of a subscription, most recent first
+// @Tags         webhooks
+// @Produce      json
+// @Param        id      path      string  true   "Subscription ID"
+// @Param        status  query     string  false  "pending, delivered or failed"
+// @Param        limit   query     int     false  "Maximum number of deliveries"
+// @Success      200     {array}   entities.WebhookDeliveryEntity
+// @Failure      400     {object}  ErrorResponse
+// @Failure      404     {object}  ErrorResponse
+// @Failure      500     {object}  ErrorResponse
+// @Router       /api/v1/webhooks/{id}/deliveries [get]
+func ListWebhookSubscriptionDeliveries(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		subscription, ok := findWebhookSubscription(ctx, c)
+		if !ok {
+			return
+		}
+
+		filter := entities.WebhookDeliveryFilter{
+			Status:         ctx.Query("status"),
+			SubscriptionID: &subscription.ID,
+		}
+		if v := ctx.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		deliveries, err := c.WebhookDeliveryRepository.FindAll(ctx.Request.Context(), filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, deliveries)
+	}
+}
+
+// SendWebhookTest godoc
+// @Summary      Send a test event
+// @Description  Queue a signed webhook.test notification to the subscription, regardless of its filters and state
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      202  {object}  entities.WebhookDeliveryEntity
+// @Failure      400  {object}  ErrorResponse
+// @Failure      404  {object}  ErrorResponse
+// @Failure      503  {object}  ErrorResponse
+// @Failure      500  {object}  ErrorResponse
+// @Router       /api/v1/webhooks/{id}/test [post]
+func SendWebhookTest(c *container.Container) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+
+		delivery, err := c.WebhookDispatcher.SendTest(ctx.Request.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, domainerrors.ErrNotFound):
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "webhook subscription not found"})
+			case errors.Is(err, api.ErrDeliveryQueueFull):
+				ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			default:
+				ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+		ctx.JSON(http.StatusAccepted, delivery)
+	}
+}
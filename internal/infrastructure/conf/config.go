@@ -1,9 +1,9 @@
 package conf
 
 import (
-	"fmt"
-	"log"
+	"log/slog"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -18,14 +18,124 @@ type Config struct {
 	ProducerTopic string `env:"PRODUCER_TOPIC" envDefault:"events.output"`
 
 	// Webhook
-	WebhookEnabled bool   `env:"WEBHOOK_ENABLED" envDefault:"false"`
-	WebhookUrl     string `env:"WEBHOOK_URL"`
+	WebhookEnabled        bool          `env:"WEBHOOK_ENABLED" envDefault:"false"`
+	WebhookUrl            string        `env:"WEBHOOK_URL"`
+	WebhookSecret         string        `env:"WEBHOOK_SECRET"`      // Clave HMAC-SHA256 para X-Webhook-Signature
+	WebhookEventTypes     string        `env:"WEBHOOK_EVENT_TYPES"` // Tipos a entregar separados por coma (vacío = todos)
+	WebhookQueueSize      int           `env:"WEBHOOK_QUEUE_SIZE" envDefault:"1000"`
+	WebhookWorkers        int           `env:"WEBHOOK_WORKERS" envDefault:"2"`
+	WebhookMaxAttempts    int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	WebhookInitialBackoff time.Duration `env:"WEBHOOK_INITIAL_BACKOFF" envDefault:"1s"`
+	WebhookMaxBackoff     time.Duration `env:"WEBHOOK_MAX_BACKOFF" envDefault:"1m"`
+	WebhookMaxFailures    int           `env:"WEBHOOK_SUBSCRIPTION_MAX_FAILURES" envDefault:"10"` // Fallos consecutivos antes de deshabilitar una suscripción (0 = nunca)
+
+	// KPIs (/api/v1/kpis, /api/v1/plants/:id/kpis)
+	KPIMaxRange time.Duration `env:"KPI_MAX_RANGE" envDefault:"8784h"` // Rango máximo de un reporte (366 días); la energía usa ENERGY_MAX_GAP
+
+	// Reading quality (chequeos de plausibilidad en el intake)
+	QualityEnabled           bool    `env:"QUALITY_ENABLED" envDefault:"true"`
+	QualityRanges            string  `env:"QUALITY_RANGES" envDefault:"power_generated_mw:0:,power_consumed_mw:0:,efficiency_percent:0:100,temperature_celsius:-50:150"`
+	QualityCapacityTolerance float64 `env:"QUALITY_CAPACITY_TOLERANCE" envDefault:"0.05"`          // Fracción tolerada por encima de CapacityMW
+	QualityMaxRates          string  `env:"QUALITY_MAX_RATES" envDefault:"temperature_celsius:10"` // métrica:cambio máximo por minuto
+	QualityMaxRampPercent    float64 `env:"QUALITY_MAX_RAMP_PERCENT" envDefault:"50"`              // Cambio máximo de generación por minuto en % de CapacityMW
+	QualityStuckReadings     int     `env:"QUALITY_STUCK_READINGS" envDefault:"12"`                // Lecturas seguidas con el mismo valor (0 = sin chequeo)
+	QualityStuckMetrics      string  `env:"QUALITY_STUCK_METRICS" envDefault:"power_generated_mw,temperature_celsius,efficiency_percent"`
+
+	// Energy (/api/v1/plants/:id/energy, /api/v1/energy)
+	EnergyMaxGap time.Duration `env:"ENERGY_MAX_GAP" envDefault:"15m"` // Intervalo máximo entre lecturas que se integra en energy_daily
+
+	// Event time (lecturas tardías o fuera de orden)
+	EventAllowedLateness       time.Duration `env:"EVENT_ALLOWED_LATENESS" envDefault:"48h"`       // Demora máxima (ingesta - event_time) que recalcula los agregados
+	EventLateRecomputeInterval time.Duration `env:"EVENT_LATE_RECOMPUTE_INTERVAL" envDefault:"1m"` // Cada cuánto se recalculan los días con lecturas tardías
+	EventMaxClockSkew          time.Duration `env:"EVENT_MAX_CLOCK_SKEW" envDefault:"5m"`          // Adelanto máximo de timestamp sobre la recepción (más = suspect con event_time recortado; 0 = sin límite)
+
+	// Rollups y retención (/api/v1/plants/:id/readings)
+	RollupEnabled         bool          `env:"ROLLUP_ENABLED" envDefault:"true"`
+	RollupInterval        time.Duration `env:"ROLLUP_INTERVAL" envDefault:"5m"`        // Cada cuánto se construyen los rollups y se purga
+	RollupDelay           time.Duration `env:"ROLLUP_DELAY" envDefault:"2m"`           // Antigüedad mínima de un minuto antes de resumirlo
+	RollupRawRetention    time.Duration `env:"ROLLUP_RAW_RETENTION" envDefault:"720h"` // Retención de los eventos (30 días; 0 = sin purga)
+	RollupMinuteRetention time.Duration `env:"ROLLUP_1M_RETENTION" envDefault:"2160h"` // Retención de los rollups de 1 minuto (90 días)
+	RollupHourRetention   time.Duration `env:"ROLLUP_1H_RETENTION" envDefault:"8784h"` // Retención de los rollups de 1 hora (366 días); los diarios no se purgan
+	RollupMaxBuckets      int           `env:"ROLLUP_MAX_BUCKETS" envDefault:"10000"`  // Buckets máximos de una serie de lecturas
+
+	// Portfolio (/api/v1/portfolio)
+	PortfolioOutputMaxAge time.Duration `env:"PORTFOLIO_OUTPUT_MAX_AGE" envDefault:"15m"` // Antigüedad máxima de la lectura que cuenta como potencia actual
+
+	// Anomaly detection (valores por defecto; cada planta puede reemplazarlos vía API)
+	AnomalyEnabled       bool          `env:"ANOMALY_ENABLED" envDefault:"true"`
+	AnomalyZThreshold    float64       `env:"ANOMALY_Z_THRESHOLD" envDefault:"4"`
+	AnomalyAlpha         float64       `env:"ANOMALY_ALPHA" envDefault:"0.05"` // Peso de cada lectura en la media/varianza EWMA
+	AnomalyWarmUp        int           `env:"ANOMALY_WARM_UP" envDefault:"30"` // Lecturas antes de empezar a detectar
+	AnomalyMetrics       string        `env:"ANOMALY_METRICS" envDefault:"power_generated_mw,power_consumed_mw,efficiency_percent,temperature_celsius"`
+	AnomalyHistoryWindow time.Duration `env:"ANOMALY_HISTORY_WINDOW" envDefault:"24h"` // Historial para reconstruir la línea base al arrancar
+
+	// Plant status (/api/v1/plants/:id/status)
+	PlantStatusStrictTransitions bool `env:"PLANT_STATUS_STRICT_TRANSITIONS" envDefault:"false"` // Descarta transiciones no permitidas en lugar de marcarlas
+
+	// Plant connectivity (/api/v1/connectivity; cada planta puede reemplazar el intervalo vía API)
+	PlantOfflineEnabled         bool          `env:"PLANT_OFFLINE_ENABLED" envDefault:"true"`
+	PlantExpectedInterval       time.Duration `env:"PLANT_EXPECTED_INTERVAL" envDefault:"1h"`       // Intervalo de reporte esperado de las plantas sin intervalo propio
+	PlantOfflineMissedIntervals int           `env:"PLANT_OFFLINE_MISSED_INTERVALS" envDefault:"3"` // Intervalos sin lecturas antes de considerar la planta offline
+	PlantOfflineCheckInterval   time.Duration `env:"PLANT_OFFLINE_CHECK_INTERVAL" envDefault:"30s"` // Cada cuánto se revisa el silencio de las plantas
+	PlantOfflineSeverity        string        `env:"PLANT_OFFLINE_SEVERITY" envDefault:"critical"`  // Severidad de las alertas offline
+
+	// Simulator (EventGenerator)
+	SimulatorEnabled   bool          `env:"SIMULATOR_ENABLED" envDefault:"true"`  // Arranca con el servicio (false = desde /admin/simulator/start)
+	SimulatorTopic     string        `env:"SIMULATOR_TOPIC"`                      // Tópico de destino (vacío = CONSUMER_TOPIC)
+	SimulatorBatchSize int           `env:"SIMULATOR_BATCH_SIZE" envDefault:"30"` // Eventos por lote
+	SimulatorInterval  time.Duration `env:"SIMULATOR_INTERVAL" envDefault:"5m"`   // Cada cuánto se envía un lote
+	SimulatorRate      float64       `env:"SIMULATOR_RATE" envDefault:"10"`       // Eventos por segundo dentro de un lote (0 = sin pausa)
+	SimulatorSeed      int64         `env:"SIMULATOR_SEED" envDefault:"0"`        // Semilla de la fuente aleatoria (0 = distinta en cada arranque)
+
+	// Unknown plants (/api/v1/quarantine)
+	UnknownPlantMode            string `env:"UNKNOWN_PLANT_MODE" envDefault:"reject"`             // reject (descarta) o quarantine (guarda hasta registrar la planta)
+	QuarantineMaxEventsPerPlant int    `env:"QUARANTINE_MAX_EVENTS_PER_PLANT" envDefault:"10000"` // Mensajes guardados por planta (0 = sin límite)
+
+	// Forecasting (/api/v1/plants/:id/forecast)
+	ForecastEnabled         bool          `env:"FORECAST_ENABLED" envDefault:"true"`
+	ForecastRetrainInterval time.Duration `env:"FORECAST_RETRAIN_INTERVAL" envDefault:"6h"`  // Cada cuánto se reentrenan todas las plantas
+	ForecastTrainingWindow  time.Duration `env:"FORECAST_TRAINING_WINDOW" envDefault:"672h"` // Historial usado para entrenar (28 días)
+	ForecastHorizon         time.Duration `env:"FORECAST_HORIZON" envDefault:"168h"`         // Horizonte pronosticado en cada corrida (7 días)
+	ForecastBacktestHorizon time.Duration `env:"FORECAST_BACKTEST_HORIZON" envDefault:"24h"` // Tramo final reservado para medir el error
+	ForecastConfidence      float64       `env:"FORECAST_CONFIDENCE" envDefault:"0.95"`      // Nivel de los intervalos de confianza
+	ForecastRetention       time.Duration `env:"FORECAST_RETENTION" envDefault:"720h"`       // Antigüedad máxima de las corridas guardadas
 
 	// CORS
 	AllowedCorsSuffixes string `env:"ALLOWED_CORS_SUFFIXES" envDefault:".spotcloud.io"`
+
+	// Logging
+	LogLevel         string `env:"LOG_LEVEL" envDefault:"info"`          // debug, info, warn, error
+	LogPayloadMode   string `env:"LOG_PAYLOAD_MODE" envDefault:"fields"` // none, metadata, fields, full
+	LogPayloadFields string `env:"LOG_PAYLOAD_FIELDS" envDefault:"event_type,plant_source_id,plant_name,status"`
+
+	// Tracing (OpenTelemetry)
+	// El endpoint OTLP se configura con las variables estándar OTEL_EXPORTER_OTLP_*
+	TracingEnabled     bool    `env:"TRACING_ENABLED" envDefault:"false"`
+	TracingExporter    string  `env:"TRACING_EXPORTER" envDefault:"otlp"` // otlp, stdout o file
+	TracingFile        string  `env:"TRACING_FILE" envDefault:"./tmp/traces.json"`
+	TracingSampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+	ServiceName        string  `env:"OTEL_SERVICE_NAME" envDefault:"monitoring-energy-service"`
+
+	// Readiness (/readyz)
+	ReadinessCheckTimeout time.Duration `env:"READINESS_CHECK_TIMEOUT" envDefault:"2s"`          // Timeout de cada check
+	ConsumerMaxPollAge    time.Duration `env:"READINESS_CONSUMER_MAX_POLL_AGE" envDefault:"30s"` // Máximo sin que el consumidor consulte Kafka
+
+	// Admin server (listener separado: /admin/*, /debug/pprof/*)
+	// Sin ADMIN_API_KEY el servidor de administración no se inicia
+	AdminPort   string `env:"ADMIN_PORT" envDefault:"9001"`
+	AdminApiKey string `env:"ADMIN_API_KEY"`
 }
 
+// OnSetConfig registra cada variable de configuración al arrancar
+// CAMBIO: El enmascarado se movió a MaskValue
+// RAZÓN: /admin/config aplica exactamente las mismas reglas que el log de arranque
 func OnSetConfig(tag string, value interface{}, isDefault bool) {
+	slog.Info("config", slog.String("key", tag), slog.Any("value", MaskValue(tag, value)), slog.Bool("default", isDefault))
+}
+
+// MaskValue enmascara el valor si la variable es un secreto (SECRET, PASSWORD o API_KEY en el nombre)
+// Conserva los 2 primeros y 2 últimos caracteres: "postgres" -> "po****es"
+func MaskValue(tag string, value interface{}) interface{} {
 	if strings.Contains(tag, "SECRET") ||
 		strings.Contains(tag, "PASSWORD") ||
 		strings.Contains(tag, "API_KEY") {
@@ -35,11 +145,5 @@ func OnSetConfig(tag string, value interface{}, isDefault bool) {
 			value = string(rune[:2]) + repeatedStars + string(rune[len(rune)-2:])
 		}
 	}
-
-	msg := fmt.Sprintf("%s=%v", tag, value)
-	if isDefault {
-		msg += " (default)"
-	}
-
-	log.Println(msg)
+	return value
 }
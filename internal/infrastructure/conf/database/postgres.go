@@ -1,8 +1,9 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 
 	"monitoring-energy-service/internal/domain/entities"
 	"monitoring-energy-service/internal/infrastructure/conf"
+	"monitoring-energy-service/internal/infrastructure/conf/tracing"
 
 	"github.com/pressly/goose/v3"
 	"gorm.io/driver/postgres"
@@ -25,13 +27,18 @@ func SetupDatabasePsql() (*gorm.DB, error) {
 	DBuri := conf.DBUriPsql(dbSetting)
 	db, err := gorm.Open(postgres.Open(DBuri), &gorm.Config{})
 	if err != nil {
-		log.Fatalf("Unable to connect to database: %v\n", err)
+		slog.Error("Unable to connect to database", slog.Any("error", err))
 		return nil, err
 	}
 
+	// Cada query genera un span de OpenTelemetry (no-op si el tracing está deshabilitado)
+	if err := db.Use(&tracing.GormPlugin{}); err != nil {
+		return nil, fmt.Errorf("error registering gorm tracing plugin: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Unable to set in database MaxIdleConns and MaxOpenConns, error: %v\n", err)
+		slog.Error("Unable to set in database MaxIdleConns and MaxOpenConns", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -44,26 +51,7 @@ func SetupDatabasePsql() (*gorm.DB, error) {
 }
 
 func PerformMigrations(db *gorm.DB) error {
-	possiblePaths := []string{
-		"./migrations",
-		"../migrations",
-		"../../migrations",
-		"../../../migrations",
-		"../../../../migrations",
-	}
-
-	if rootDir, err := findProjectRoot(); err == nil {
-		possiblePaths = append(possiblePaths, filepath.Join(rootDir, "migrations"))
-	}
-
-	var migrationsDir string
-	for _, path := range possiblePaths {
-		if _, err := os.Stat(path); !os.IsNotExist(err) {
-			migrationsDir = path
-			break
-		}
-	}
-
+	migrationsDir := findMigrationsDir()
 	if migrationsDir == "" {
 		slog.Warn("No migrations directory found, skipping migrations")
 		return nil
@@ -86,6 +74,59 @@ func PerformMigrations(db *gorm.DB) error {
 	return nil
 }
 
+// MigrationVersions devuelve la versión aplicada en la base de datos y la última versión disponible
+// en el directorio de migraciones; el check de readiness falla si no coinciden
+func MigrationVersions(ctx context.Context, db *gorm.DB) (current int64, expected int64, err error) {
+	migrationsDir := findMigrationsDir()
+	if migrationsDir == "" {
+		return 0, 0, errors.New("migrations directory not found")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, 0, fmt.Errorf("error accessing underlying database connection: %w", err)
+	}
+
+	current, err = goose.GetDBVersionContext(ctx, sqlDB)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading database migration version: %w", err)
+	}
+
+	migrations, err := goose.CollectMigrations(migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error collecting migrations: %w", err)
+	}
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading last migration: %w", err)
+	}
+
+	return current, last.Version, nil
+}
+
+// findMigrationsDir busca el directorio de migraciones relativo al directorio de trabajo
+// y a la raíz del proyecto; devuelve "" si no existe
+func findMigrationsDir() string {
+	possiblePaths := []string{
+		"./migrations",
+		"../migrations",
+		"../../migrations",
+		"../../../migrations",
+		"../../../../migrations",
+	}
+
+	if rootDir, err := findProjectRoot(); err == nil {
+		possiblePaths = append(possiblePaths, filepath.Join(rootDir, "migrations"))
+	}
+
+	for _, path := range possiblePaths {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			return path
+		}
+	}
+	return ""
+}
+
 func findProjectRoot() (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -119,7 +160,7 @@ func SeedDB(db *gorm.DB, directory string) error {
 		entity := item.entity
 		var count int64
 		if db.Model(entity).Count(&count); count == 0 {
-			log.Printf("Seeding model <%s>", name)
+			slog.Info("Seeding model", slog.String("model", name))
 			err := seedData(db, directory, name)
 			if err != nil {
 				return err
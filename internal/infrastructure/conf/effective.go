@@ -0,0 +1,45 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// EffectiveConfig devuelve la configuración efectiva del servicio indexada por variable de entorno
+//
+// PROPÓSITO:
+// Lo usa /admin/config. Incluye Config y DBSettings; los secretos se enmascaran
+// con MaskValue, las mismas reglas que OnSetConfig aplica en el log de arranque.
+func EffectiveConfig(cfg Config) (map[string]interface{}, error) {
+	dbSettings := DBSettings{}
+	if err := env.Parse(&dbSettings); err != nil {
+		return nil, fmt.Errorf("error reading database settings: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	collectEnvValues(values, reflect.ValueOf(cfg))
+	collectEnvValues(values, reflect.ValueOf(dbSettings))
+	return values, nil
+}
+
+// collectEnvValues recorre los campos con tag `env` del struct y agrega su valor enmascarado
+func collectEnvValues(values map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("env")
+		if tag == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		var value interface{} = v.Field(i).Interface()
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		values[name] = MaskValue(name, value)
+	}
+}
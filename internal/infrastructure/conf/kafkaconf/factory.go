@@ -1,7 +1,8 @@
 package kafkaconf
 
 import (
-	"log"
+	"log/slog"
+	"os"
 	"strings"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
@@ -12,9 +13,9 @@ func deliveryReport(deliveryChan chan kafka.Event) {
 		switch ev := e.(type) {
 		case *kafka.Message:
 			if ev.TopicPartition.Error != nil {
-				log.Printf("Delivery failed: %v\n", ev.TopicPartition.Error)
+				slog.Error("Delivery failed", slog.Any("error", ev.TopicPartition.Error))
 			} else {
-				log.Printf("Delivered message to %v\n", ev.TopicPartition)
+				slog.Debug("Delivered message", slog.String("topic_partition", ev.TopicPartition.String()))
 			}
 		}
 	}
@@ -41,11 +42,12 @@ func (kf *KafkaFactory) NewProducer() *kafka.Producer {
 		"bootstrap.servers":   kf.brokerList,
 		"retries":             1,
 		"socket.timeout.ms":   5000,
-		"go.delivery.reports": false,
+		"go.delivery.reports": true,
 	})
 
 	if err != nil {
-		log.Fatalf("Failed to create producer: %s", err)
+		slog.Error("Failed to create producer", slog.Any("error", err))
+		os.Exit(1)
 	}
 
 	go deliveryReport(deliveryChan)
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"monitoring-energy-service/internal/infrastructure/conf"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// ShutdownFunc vacía los spans pendientes y cierra el exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// SetupTracing configura el TracerProvider global de OpenTelemetry
+//
+// PROPÓSITO:
+// Permite seguir una lectura desde EventGenerator → Kafka → IntakeHandler → PostgreSQL → REST.
+// El propagador W3C (traceparent/tracestate) se registra siempre, aunque el tracing esté
+// deshabilitado, para no cortar el contexto que llega desde otros servicios.
+//
+// EXPORTERS:
+// - otlp: OTLP/HTTP, configurado con OTEL_EXPORTER_OTLP_ENDPOINT y relacionadas
+// - stdout: Spans en formato JSON por stdout (debug local)
+// - file: Spans en formato JSON en TRACING_FILE (debug local)
+func SetupTracing(ctx context.Context, cfg conf.Config) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.TracingEnabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeExporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.DeploymentEnvironmentName(cfg.Env),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	slog.Info("Tracing enabled", slog.String("exporter", cfg.TracingExporter))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeExporter != nil {
+			closeExporter()
+		}
+		return err
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg conf.Config) (sdktrace.SpanExporter, func(), error) {
+	switch cfg.TracingExporter {
+	case "otlp":
+		exporter, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating otlp exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating stdout exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case "file":
+		if err := os.MkdirAll(filepath.Dir(cfg.TracingFile), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("error creating traces directory: %w", err)
+		}
+		file, err := os.OpenFile(cfg.TracingFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening traces file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("error creating file exporter: %w", err)
+		}
+		return exporter, func() { file.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q (expected otlp, stdout or file)", cfg.TracingExporter)
+	}
+}
@@ -1,22 +1,40 @@
 package container
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"monitoring-energy-service/internal/api"
 	"monitoring-energy-service/internal/domain/ports/input"
 	"monitoring-energy-service/internal/domain/ports/output"
 	"monitoring-energy-service/internal/infrastructure/adapters/http/webhook"
 	"monitoring-energy-service/internal/infrastructure/adapters/kafka"
+	"monitoring-energy-service/internal/infrastructure/adapters/metrics"
 	"monitoring-energy-service/internal/infrastructure/adapters/repositories"
 	"monitoring-energy-service/internal/infrastructure/conf"
+	"monitoring-energy-service/internal/infrastructure/conf/database"
 	"monitoring-energy-service/internal/infrastructure/conf/kafkaconf"
+	"monitoring-energy-service/internal/infrastructure/health"
+	"monitoring-energy-service/internal/infrastructure/logging"
 
 	"gorm.io/gorm"
 )
 
 type ContainerOption func(*Container)
 
+// BuildInfo datos del binario en ejecución (inyectados con -ldflags en el build)
+type BuildInfo struct {
+	BuildDate string
+	GitCommit string
+	StartedAt time.Time
+}
+
 // Container mantiene todas las dependencias de la aplicación (Dependency Injection)
 //
 // CAMBIOS REALIZADOS:
@@ -24,14 +42,47 @@ type ContainerOption func(*Container)
 // - Agregado EventGenerator: Para generar eventos automáticamente
 // - Agregado EnergyPlantRepository: Para validar plantas antes de guardar eventos
 type Container struct {
-	db                    *gorm.DB
-	cfg                   conf.Config
-	KafkaService          input.KafkaServiceInterface
-	WebhookAdapter        output.WebhookAdapterInterface
-	ExampleRepository     output.ExampleRepositoryInterface
-	EventRepository       output.EventRepositoryInterface       // Para gestionar eventos en DB
-	EnergyPlantRepository output.EnergyPlantRepositoryInterface // Para validar plantas
-	EventGenerator        *api.EventGenerator                   // Para generar eventos cada 5 min
+	db                            *gorm.DB
+	cfg                           conf.Config
+	KafkaService                  input.KafkaServiceInterface
+	WebhookAdapter                output.WebhookAdapterInterface
+	ExampleRepository             output.ExampleRepositoryInterface
+	EventRepository               output.EventRepositoryInterface               // Para gestionar eventos en DB
+	EnergyPlantRepository         output.EnergyPlantRepositoryInterface         // Para validar plantas
+	EventGenerator                *api.EventGenerator                           // Simulador de lecturas (SIMULATOR_*)
+	Metrics                       *metrics.PrometheusRecorder                   // Registry de métricas expuesto en /metrics
+	Logger                        *slog.Logger                                  // Logger único de la aplicación
+	LogLevel                      *slog.LevelVar                                // Nivel del logger, modificable en caliente
+	Redactor                      *logging.Redactor                             // Política de redacción de payloads en logs
+	AlertRuleRepository           output.AlertRuleRepositoryInterface           // Reglas de alerta (/api/v1/alert-rules)
+	AlertRepository               output.AlertRepositoryInterface               // Alertas disparadas y resueltas (/api/v1/alerts)
+	AlertEngine                   *api.AlertEngine                              // Evalúa las reglas en el path de intake
+	WebhookDeliveryRepository     output.WebhookDeliveryRepositoryInterface     // Log de entregas de webhooks
+	WebhookDispatcher             *api.WebhookDispatcher                        // Entrega eventos y alertas firmados con reintentos
+	WebhookSubscriptionRepository output.WebhookSubscriptionRepositoryInterface // Suscripciones de /api/v1/webhooks
+	AnomalyRepository             output.AnomalyRepositoryInterface             // Anomalías detectadas (/api/v1/anomalies)
+	AnomalySettingsRepository     output.AnomalySettingsRepositoryInterface     // Configuración del detector por planta
+	AnomalyDetector               *api.AnomalyDetector                          // Detección EWMA/z-score en el path de intake
+	KPIService                    *api.KPIService                               // KPIs por planta y de la flota
+	PlantStatusHistoryRepository  output.PlantStatusHistoryRepositoryInterface  // Historial de estados de las plantas
+	PlantStatusTracker            *api.PlantStatusTracker                       // Estado actual y cambios de estado desde el intake
+	MaintenanceWindowRepository   output.MaintenanceWindowRepositoryInterface   // Ventanas de mantenimiento (/api/v1/maintenance-windows)
+	MaintenanceCalendar           *api.MaintenanceCalendar                      // Ventana activa de cada planta (intake y respuestas de plantas)
+	ForecastRepository            output.ForecastRepositoryInterface            // Corridas de pronóstico (tabla forecasts)
+	EnergyDailyRepository         output.EnergyDailyRepositoryInterface         // Energía diaria integrada por planta (tabla energy_daily)
+	EnergyIntegrator              *api.EnergyIntegrator                         // Integra la potencia de las lecturas en MWh diarios
+	LateDataHandler               *api.LateDataHandler                          // Recalcula los agregados afectados por lecturas tardías
+	ForecastService               *api.ForecastService                          // Pronóstico Holt-Winters por planta con reentrenamiento periódico
+	PortfolioNodeRepository       output.PortfolioNodeRepositoryInterface       // Jerarquía organización → región → sitio (/api/v1/portfolio)
+	PortfolioService              *api.PortfolioService                         // Valida la jerarquía y calcula los agregados de cada nodo
+	EmissionFactorRepository      output.EmissionFactorRepositoryInterface      // Factores de emisión de la red versionados (/api/v1/emission-factors)
+	EmissionsService              *api.EmissionsService                         // Calcula las emisiones evitadas (tCO2e) de plantas, nodos y flota
+	ReadingRollupRepository       output.ReadingRollupRepositoryInterface       // Rollups de lecturas por nivel (tabla reading_rollups)
+	RollupService                 *api.RollupService                            // Construye los rollups, purga por retención y sirve las series de lecturas
+	ConnectivityWatcher           *api.ConnectivityWatcher                      // Detecta plantas sin reportar y dispara alertas offline
+	QuarantineService             *api.QuarantineService                        // Mensajes de plantas desconocidas en cuarentena (/api/v1/quarantine)
+	HealthChecker                 *health.Checker                               // Checks de readiness expuestos en /readyz
+	BuildInfo                     BuildInfo                                     // Expuesto en /admin/version
 }
 
 func NewContainer(
@@ -48,6 +99,26 @@ func NewContainer(
 		opt(container)
 	}
 
+	// Logger por defecto si no se inyectó uno con WithLogger
+	if container.Logger == nil {
+		container.Logger = slog.Default()
+		container.LogLevel = &slog.LevelVar{}
+	}
+	logger := container.Logger
+
+	redactor := logging.NewRedactor(container.cfg.LogPayloadMode, container.cfg.LogPayloadFields)
+	container.Redactor = redactor
+
+	// Initialize metrics
+	// El pool de sql.DB se expone como métricas (conexiones abiertas, en uso, esperas)
+	metricsRecorder := metrics.NewPrometheusRecorder()
+	if sqlDB, err := db.DB(); err == nil {
+		if err := metricsRecorder.RegisterDBStats(sqlDB, db.Migrator().CurrentDatabase()); err != nil {
+			logger.Warn("Couldn't register database metrics", slog.Any("error", err))
+		}
+	}
+	container.Metrics = metricsRecorder
+
 	// Initialize repositories
 	exampleRepository := repositories.NewExampleRepository(db)
 	container.ExampleRepository = exampleRepository
@@ -62,36 +133,416 @@ func NewContainer(
 	energyPlantRepository := repositories.NewEnergyPlantRepository(db)
 	container.EnergyPlantRepository = energyPlantRepository
 
+	alertRuleRepository := repositories.NewAlertRuleRepository(db)
+	container.AlertRuleRepository = alertRuleRepository
+
+	alertRepository := repositories.NewAlertRepository(db)
+	container.AlertRepository = alertRepository
+
+	anomalyRepository := repositories.NewAnomalyRepository(db)
+	container.AnomalyRepository = anomalyRepository
+
+	anomalySettingsRepository := repositories.NewAnomalySettingsRepository(db)
+	container.AnomalySettingsRepository = anomalySettingsRepository
+
+	plantStatusHistoryRepository := repositories.NewPlantStatusHistoryRepository(db)
+	container.PlantStatusHistoryRepository = plantStatusHistoryRepository
+
+	maintenanceWindowRepository := repositories.NewMaintenanceWindowRepository(db)
+	container.MaintenanceWindowRepository = maintenanceWindowRepository
+
+	energyDailyRepository := repositories.NewEnergyDailyRepository(db)
+	container.EnergyDailyRepository = energyDailyRepository
+
+	forecastRepository := repositories.NewForecastRepository(db)
+	container.ForecastRepository = forecastRepository
+
+	portfolioNodeRepository := repositories.NewPortfolioNodeRepository(db)
+	container.PortfolioNodeRepository = portfolioNodeRepository
+
+	readingRollupRepository := repositories.NewReadingRollupRepository(db)
+	container.ReadingRollupRepository = readingRollupRepository
+
+	emissionFactorRepository := repositories.NewEmissionFactorRepository(db)
+	container.EmissionFactorRepository = emissionFactorRepository
+
+	quarantineRepository := repositories.NewQuarantineRepository(db)
+
+	webhookDeliveryRepository := repositories.NewWebhookDeliveryRepository(db)
+	container.WebhookDeliveryRepository = webhookDeliveryRepository
+
+	webhookSubscriptionRepository := repositories.NewWebhookSubscriptionRepository(db)
+	container.WebhookSubscriptionRepository = webhookSubscriptionRepository
+
 	// Initialize Kafka
 	kafkaFactory := kafkaconf.NewKafkaFactory(kafkaBrokers, autoOffset)
 	kafkaAdapter := kafka.NewKafkaAdapter(kafkaFactory, consumerGroup)
-	kafkaService := api.NewKafkaService(kafkaAdapter)
+	kafkaService := api.NewKafkaService(kafkaAdapter, metricsRecorder, logger, redactor)
 	container.KafkaService = kafkaService
 
 	// Initialize Webhook adapter
-	webhookAdapter := webhook.NewAdapter(httpClient)
+	webhookAdapter := webhook.NewAdapter(httpClient, logger, redactor)
 	container.WebhookAdapter = webhookAdapter
 
+	// Ventanas de mantenimiento planificadas (marcan lecturas y suprimen o bajan alertas)
+	maintenanceCalendar := api.NewMaintenanceCalendar(maintenanceWindowRepository, logger)
+	container.MaintenanceCalendar = maintenanceCalendar
+
+	// Chequeos de plausibilidad de las lecturas (las sospechosas se guardan marcadas)
+	qualityRanges, err := parseQualityRanges(container.cfg.QualityRanges)
+	if err != nil {
+		logger.Warn("Invalid QUALITY_RANGES entries ignored", slog.Any("error", err))
+	}
+	qualityRates, err := parseQualityRates(container.cfg.QualityMaxRates)
+	if err != nil {
+		logger.Warn("Invalid QUALITY_MAX_RATES entries ignored", slog.Any("error", err))
+	}
+	qualityChecker := api.NewQualityChecker(metricsRecorder, api.QualityCheckerConfig{
+		Enabled:           container.cfg.QualityEnabled,
+		Ranges:            qualityRanges,
+		CapacityTolerance: container.cfg.QualityCapacityTolerance,
+		MaxRates:          qualityRates,
+		MaxRampPercent:    container.cfg.QualityMaxRampPercent,
+		StuckReadings:     container.cfg.QualityStuckReadings,
+		StuckMetrics:      splitList(container.cfg.QualityStuckMetrics),
+	})
+
+	// Cuarentena de los mensajes de plantas desconocidas (UNKNOWN_PLANT_MODE=quarantine)
+	quarantineService := api.NewQuarantineService(quarantineRepository, energyPlantRepository, metricsRecorder, logger,
+		api.QuarantineServiceConfig{
+			Mode:              container.cfg.UnknownPlantMode,
+			MaxEventsPerPlant: container.cfg.QuarantineMaxEventsPerPlant,
+		})
+	container.QuarantineService = quarantineService
+
 	// Register Kafka handlers here
 	// CAMBIO: IntakeHandler ahora recibe eventRepository y energyPlantRepository
 	// RAZÓN: Necesita validar plantas antes de guardar eventos
-	intakeHandler := api.NewIntakeHandler(eventRepository, energyPlantRepository)
+	// CAMBIO: Recibe el QualityChecker
+	// RAZÓN: Las lecturas implausibles se guardan con quality=suspect y sus flags
+	// CAMBIO: Recibe el QuarantineService, que a su vez libera los mensajes a través del intake
+	// RAZÓN: Los mensajes de plantas desconocidas se guardan hasta registrar la planta
+	intakeHandler := api.NewIntakeHandler(eventRepository, energyPlantRepository, maintenanceCalendar, qualityChecker, quarantineService, metricsRecorder, logger, redactor,
+		api.IntakeHandlerConfig{MaxClockSkew: container.cfg.EventMaxClockSkew})
 	kafkaService.RegisterHandler(container.cfg.ConsumerTopic, intakeHandler)
+	quarantineService.SetReplayer(intakeHandler)
+
+	// Reglas de umbral evaluadas con cada lectura guardada por el intake
+	alertEngine := api.NewAlertEngine(alertRuleRepository, alertRepository, metricsRecorder, logger)
+	intakeHandler.RegisterProcessor(alertEngine)
+	container.AlertEngine = alertEngine
+
+	// Estado actual de cada planta e historial de cambios a partir del status de las lecturas
+	plantStatusTracker := api.NewPlantStatusTracker(energyPlantRepository, plantStatusHistoryRepository, metricsRecorder, logger,
+		api.PlantStatusTrackerConfig{
+			StrictTransitions: container.cfg.PlantStatusStrictTransitions,
+		})
+	intakeHandler.RegisterProcessor(plantStatusTracker)
+	container.PlantStatusTracker = plantStatusTracker
+
+	// Plantas sin lecturas más allá de su intervalo esperado (la revisión periódica se inicia en main)
+	connectivityWatcher := api.NewConnectivityWatcher(energyPlantRepository, eventRepository, alertRepository, maintenanceCalendar, metricsRecorder, logger,
+		api.ConnectivityWatcherConfig{
+			Enabled:          container.cfg.PlantOfflineEnabled,
+			ExpectedInterval: container.cfg.PlantExpectedInterval,
+			MissedIntervals:  container.cfg.PlantOfflineMissedIntervals,
+			CheckInterval:    container.cfg.PlantOfflineCheckInterval,
+			Severity:         container.cfg.PlantOfflineSeverity,
+		})
+	intakeHandler.RegisterProcessor(connectivityWatcher)
+	container.ConnectivityWatcher = connectivityWatcher
+
+	// Detección de anomalías por planta y métrica (línea base EWMA reconstruida desde el historial)
+	anomalyDetector := api.NewAnomalyDetector(eventRepository, anomalyRepository, anomalySettingsRepository, metricsRecorder, logger,
+		api.AnomalyDetectorConfig{
+			Enabled:       container.cfg.AnomalyEnabled,
+			ZThreshold:    container.cfg.AnomalyZThreshold,
+			Alpha:         container.cfg.AnomalyAlpha,
+			WarmUp:        container.cfg.AnomalyWarmUp,
+			Metrics:       splitList(container.cfg.AnomalyMetrics),
+			HistoryWindow: container.cfg.AnomalyHistoryWindow,
+		})
+	intakeHandler.RegisterProcessor(anomalyDetector)
+	container.AnomalyDetector = anomalyDetector
 
-	// CAMBIO: Inicializa Event Generator con topic "intake"
-	// RAZÓN: Genera automáticamente 30 eventos cada 5 minutos enviándolos a Kafka
-	eventGenerator := api.NewEventGenerator(kafkaService, "intake")
+	// Eventos que sobreviven a la purga del RollupService (0 = no se purgan)
+	var rawRetention time.Duration
+	if container.cfg.RollupEnabled {
+		rawRetention = container.cfg.RollupRawRetention
+	}
+
+	// Energía (MWh) diaria por planta integrada a medida que llegan las lecturas
+	energyIntegrator := api.NewEnergyIntegrator(eventRepository, energyPlantRepository, energyDailyRepository, logger,
+		api.EnergyIntegratorConfig{
+			MaxGap:       container.cfg.EnergyMaxGap,
+			RawRetention: rawRetention,
+		})
+	intakeHandler.RegisterProcessor(energyIntegrator)
+	container.EnergyIntegrator = energyIntegrator
+
+	// Lecturas tardías: recalculan los días afectados de los agregados (el recálculo periódico se inicia en main)
+	lateDataHandler := api.NewLateDataHandler(eventRepository, metricsRecorder, logger,
+		api.LateDataConfig{
+			AllowedLateness:   container.cfg.EventAllowedLateness,
+			RecomputeInterval: container.cfg.EventLateRecomputeInterval,
+			Margin:            container.cfg.EnergyMaxGap,
+		})
+	lateDataHandler.RegisterRecomputer(energyIntegrator)
+
+	// Rollups de 1m, 1h y 1d con retención por nivel (la construcción periódica se inicia en main)
+	rollupService := api.NewRollupService(eventRepository, energyPlantRepository, readingRollupRepository, metricsRecorder, logger,
+		api.RollupServiceConfig{
+			Enabled:         container.cfg.RollupEnabled,
+			Interval:        container.cfg.RollupInterval,
+			Delay:           container.cfg.RollupDelay,
+			RawRetention:    container.cfg.RollupRawRetention,
+			MinuteRetention: container.cfg.RollupMinuteRetention,
+			HourRetention:   container.cfg.RollupHourRetention,
+			MaxBuckets:      container.cfg.RollupMaxBuckets,
+		})
+	lateDataHandler.RegisterRecomputer(rollupService)
+	container.RollupService = rollupService
+	if container.cfg.RollupEnabled && container.cfg.RollupRawRetention > 0 {
+		if container.cfg.RollupRawRetention < container.cfg.ForecastTrainingWindow {
+			logger.Warn("ROLLUP_RAW_RETENTION is shorter than FORECAST_TRAINING_WINDOW, forecasts will train on less history")
+		}
+		if container.cfg.RollupRawRetention < container.cfg.EventAllowedLateness {
+			logger.Warn("ROLLUP_RAW_RETENTION is shorter than EVENT_ALLOWED_LATENESS, late readings may be purged before they are recomputed")
+		}
+	}
+	intakeHandler.RegisterProcessor(lateDataHandler)
+	container.LateDataHandler = lateDataHandler
+
+	// CAMBIO: El WebhookAdapter se usa a través del WebhookDispatcher (cola acotada + reintentos)
+	// RAZÓN: Los eventos aceptados y las alertas se entregan al webhook sin bloquear el consumo de Kafka
+	// CAMBIO: Además de WEBHOOK_URL entrega a las suscripciones de /api/v1/webhooks
+	// RAZÓN: Cada equipo registra su propio endpoint, secreto y filtros
+	webhookDispatcher := api.NewWebhookDispatcher(webhookAdapter, webhookDeliveryRepository, webhookSubscriptionRepository, metricsRecorder, logger,
+		api.WebhookDispatcherConfig{
+			Enabled:        container.cfg.WebhookEnabled,
+			URL:            container.cfg.WebhookUrl,
+			Secret:         container.cfg.WebhookSecret,
+			EventTypes:     splitList(container.cfg.WebhookEventTypes),
+			QueueSize:      container.cfg.WebhookQueueSize,
+			Workers:        container.cfg.WebhookWorkers,
+			MaxAttempts:    container.cfg.WebhookMaxAttempts,
+			InitialBackoff: container.cfg.WebhookInitialBackoff,
+			MaxBackoff:     container.cfg.WebhookMaxBackoff,
+			MaxFailures:    container.cfg.WebhookMaxFailures,
+		})
+	container.WebhookDispatcher = webhookDispatcher
+	if container.cfg.WebhookEnabled && container.cfg.WebhookUrl == "" {
+		logger.Warn("WEBHOOK_ENABLED is true but WEBHOOK_URL is empty, only webhook subscriptions will be notified")
+	}
+	if container.cfg.WebhookEnabled && container.cfg.WebhookUrl != "" && container.cfg.WebhookSecret == "" {
+		logger.Warn("WEBHOOK_ENABLED is true but WEBHOOK_SECRET is empty, WEBHOOK_URL is disabled to avoid unsigned deliveries")
+	}
+	intakeHandler.RegisterProcessor(webhookDispatcher)
+	alertEngine.RegisterListener(webhookDispatcher)
+	connectivityWatcher.RegisterListener(webhookDispatcher)
+
+	// CAMBIO: Inicializa Event Generator con la configuración SIMULATOR_*
+	// RAZÓN: Genera automáticamente lotes de eventos de las plantas registradas enviándolos a Kafka
+	// CAMBIO: Sin SIMULATOR_TOPIC envía a CONSUMER_TOPIC
+	// RAZÓN: El topic "intake" fijo no era el que consume el IntakeHandler por defecto
+	simulatorTopic := container.cfg.SimulatorTopic
+	if simulatorTopic == "" {
+		simulatorTopic = container.cfg.ConsumerTopic
+	}
+	eventGenerator := api.NewEventGenerator(kafkaService, energyPlantRepository, metricsRecorder, logger,
+		api.EventGeneratorConfig{
+			Topic:     simulatorTopic,
+			BatchSize: container.cfg.SimulatorBatchSize,
+			Interval:  container.cfg.SimulatorInterval,
+			Rate:      container.cfg.SimulatorRate,
+			Seed:      container.cfg.SimulatorSeed,
+		})
 	container.EventGenerator = eventGenerator
 
+	// Reportes de KPIs calculados desde los eventos guardados
+	container.KPIService = api.NewKPIService(eventRepository, energyPlantRepository, maintenanceWindowRepository, logger,
+		api.KPIServiceConfig{
+			MaxGap:       container.cfg.EnergyMaxGap,
+			MaxRange:     container.cfg.KPIMaxRange,
+			RawRetention: rawRetention,
+		})
+	if rawRetention > 0 && rawRetention < container.cfg.KPIMaxRange {
+		logger.Warn("ROLLUP_RAW_RETENTION is shorter than KPI_MAX_RANGE, KPIs are only available for the last ROLLUP_RAW_RETENTION")
+	}
+
+	// Pronóstico de generación por planta (el reentrenamiento periódico se inicia en main)
+	container.ForecastService = api.NewForecastService(eventRepository, energyPlantRepository, forecastRepository, metricsRecorder, logger,
+		api.ForecastServiceConfig{
+			Enabled:         container.cfg.ForecastEnabled,
+			RetrainInterval: container.cfg.ForecastRetrainInterval,
+			TrainingWindow:  container.cfg.ForecastTrainingWindow,
+			Horizon:         container.cfg.ForecastHorizon,
+			BacktestHorizon: container.cfg.ForecastBacktestHorizon,
+			Confidence:      container.cfg.ForecastConfidence,
+			Retention:       container.cfg.ForecastRetention,
+		})
+
+	// Jerarquía del portfolio y agregados por nodo
+	container.PortfolioService = api.NewPortfolioService(portfolioNodeRepository, energyPlantRepository, eventRepository, energyDailyRepository, alertRepository, logger,
+		api.PortfolioServiceConfig{
+			OutputMaxAge: container.cfg.PortfolioOutputMaxAge,
+		})
+
+	// Factores de emisión y emisiones evitadas
+	container.EmissionsService = api.NewEmissionsService(emissionFactorRepository, energyPlantRepository, portfolioNodeRepository, energyDailyRepository, logger)
+
+	container.HealthChecker = newHealthChecker(container)
+
 	return container
 }
 
+// splitList separa una lista de la configuración separada por comas, ignorando vacíos
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseQualityRanges lee QUALITY_RANGES ("métrica:min:max" separados por comas; un límite
+// vacío no se chequea). Las entradas inválidas se ignoran y se devuelven en el error
+func parseQualityRanges(value string) (map[string]api.QualityRange, error) {
+	ranges := make(map[string]api.QualityRange)
+	var errs []error
+	for _, item := range splitList(value) {
+		parts := strings.Split(item, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			errs = append(errs, fmt.Errorf("invalid range %q, expected metric:min:max", item))
+			continue
+		}
+		var r api.QualityRange
+		var err error
+		if r.Min, err = parseOptionalFloat(parts[1]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid min in %q: %w", item, err))
+			continue
+		}
+		if r.Max, err = parseOptionalFloat(parts[2]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid max in %q: %w", item, err))
+			continue
+		}
+		ranges[parts[0]] = r
+	}
+	return ranges, errors.Join(errs...)
+}
+
+// parseQualityRates lee QUALITY_MAX_RATES ("métrica:máximo" por minuto separados por comas)
+// Las entradas inválidas se ignoran y se devuelven en el error
+func parseQualityRates(value string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	var errs []error
+	for _, item := range splitList(value) {
+		metric, limit, ok := strings.Cut(item, ":")
+		rate, err := strconv.ParseFloat(strings.TrimSpace(limit), 64)
+		if !ok || metric == "" || err != nil || rate <= 0 {
+			errs = append(errs, fmt.Errorf("invalid rate %q, expected metric:max_per_minute", item))
+			continue
+		}
+		rates[metric] = rate
+	}
+	return rates, errors.Join(errs...)
+}
+
+func parseOptionalFloat(value string) (*float64, error) {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// newHealthChecker registra los checks de readiness de cada dependencia
+//
+// FUNCIONAMIENTO:
+// - database: ping al pool de PostgreSQL
+// - migrations: la versión aplicada coincide con la última migración disponible
+// - kafka: productor y consumidor obtienen metadata de los brokers
+// - kafka_consumer: el loop de consumo está corriendo y consultó Kafka recientemente
+// - event_generator: el goroutine del generador sigue activo (solo con SIMULATOR_ENABLED)
+func newHealthChecker(c *Container) *health.Checker {
+	timeout := c.cfg.ReadinessCheckTimeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	maxPollAge := c.cfg.ConsumerMaxPollAge
+	if maxPollAge <= 0 {
+		maxPollAge = 30 * time.Second
+	}
+
+	checker := health.NewChecker(timeout)
+
+	checker.Register("database", func(ctx context.Context) error {
+		sqlDB, err := c.db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+
+	checker.Register("migrations", func(ctx context.Context) error {
+		current, expected, err := database.MigrationVersions(ctx, c.db)
+		if err != nil {
+			return err
+		}
+		if current < expected {
+			return fmt.Errorf("pending migrations: database at version %d, expected %d", current, expected)
+		}
+		return nil
+	})
+
+	checker.Register("kafka", c.KafkaService.CheckConnectivity)
+
+	checker.Register("kafka_consumer", func(ctx context.Context) error {
+		running, lastPoll := c.KafkaService.ConsumerStatus()
+		if !running {
+			return errors.New("consumer loop is not running")
+		}
+		if age := time.Since(lastPoll); age > maxPollAge {
+			return fmt.Errorf("consumer has not polled kafka for %s", age.Round(time.Second))
+		}
+		return nil
+	})
+
+	// CAMBIO: El check falla solo si el simulador corre pero dejó de enviar lotes
+	// RAZÓN: Detenerlo o pausarlo desde /admin/simulator no debe sacar al servicio de readiness
+	checker.Register("event_generator", func(ctx context.Context) error {
+		return c.EventGenerator.Check()
+	})
+
+	return checker
+}
+
 func WithConfig(config conf.Config) ContainerOption {
 	return func(c *Container) {
 		c.cfg = config
 	}
 }
 
+// WithLogger inyecta el logger de la aplicación y su nivel (para cambiarlo en caliente)
+func WithLogger(logger *slog.Logger, level *slog.LevelVar) ContainerOption {
+	return func(c *Container) {
+		c.Logger = logger
+		c.LogLevel = level
+	}
+}
+
+// WithBuildInfo inyecta la fecha de build y el commit definidos con -ldflags en main
+func WithBuildInfo(buildDate, gitCommit string) ContainerOption {
+	return func(c *Container) {
+		c.BuildInfo = BuildInfo{BuildDate: buildDate, GitCommit: gitCommit, StartedAt: time.Now()}
+	}
+}
+
 func (c *Container) GetConfig() conf.Config {
 	return c.cfg
 }
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// CorrelationHeader es el header HTTP con el que se recibe y devuelve el correlation ID
+	CorrelationHeader = "X-Correlation-ID"
+	// RequestIDHeader se acepta como alternativa cuando el cliente no envía X-Correlation-ID
+	RequestIDHeader = "X-Request-ID"
+	// CorrelationKafkaHeader es el header de Kafka que transporta el correlation ID
+	CorrelationKafkaHeader = "correlation_id"
+)
+
+type correlationKey struct{}
+
+// WithCorrelationID devuelve un contexto con el correlation ID indicado
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID devuelve el correlation ID del contexto o "" si no hay ninguno
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// NewCorrelationID genera un correlation ID nuevo
+func NewCorrelationID() string {
+	return uuid.NewString()
+}
+
+// EnsureCorrelationID devuelve el contexto con un correlation ID, generando uno si no existe
+func EnsureCorrelationID(ctx context.Context) (context.Context, string) {
+	if id := CorrelationID(ctx); id != "" {
+		return ctx, id
+	}
+	id := NewCorrelationID()
+	return WithCorrelationID(ctx, id), id
+}
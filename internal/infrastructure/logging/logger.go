@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewLogger crea el logger slog de la aplicación
+//
+// PROPÓSITO:
+// Único logger del servicio, inyectado a través del Container.
+// - ENVIRONMENT=dev: formato texto legible
+// - Cualquier otro entorno: JSON (una línea por registro) para agregadores de logs
+//
+// El nivel se controla con un slog.LevelVar, lo que permite cambiarlo en caliente.
+// Cada registro incluye correlation_id, trace_id y span_id si están en el contexto.
+func NewLogger(w io.Writer, env string, level string) (*slog.Logger, *slog.LevelVar, error) {
+	levelVar := &slog.LevelVar{}
+	if err := SetLevel(levelVar, level); err != nil {
+		return nil, nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: levelVar}
+
+	var handler slog.Handler
+	if env == "dev" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(&contextHandler{Handler: handler}), levelVar, nil
+}
+
+// SetLevel cambia el nivel del logger a partir de su nombre (debug, info, warn, error)
+func SetLevel(levelVar *slog.LevelVar, level string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(level))); err != nil {
+		return fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", level)
+	}
+	levelVar.Set(l)
+	return nil
+}
+
+// contextHandler agrega los identificadores de correlación y de traza del contexto a cada registro
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := CorrelationID(ctx); id != "" {
+		r.AddAttrs(slog.String("correlation_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinMiddleware asigna un correlation ID a cada request y registra su resultado con slog
+//
+// FUNCIONAMIENTO:
+// 1. Usa X-Correlation-ID (o X-Request-ID) si el cliente lo envía; si no, genera uno
+// 2. Lo guarda en el contexto del request para que todos los logs lo incluyan
+// 3. Lo devuelve en el header X-Correlation-ID de la respuesta
+// 4. Registra método, ruta, status y latencia (los paths de skipPaths no se registran)
+func GinMiddleware(logger *slog.Logger, skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[p] = true
+	}
+
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(CorrelationHeader)
+		if id == "" {
+			id = ctx.GetHeader(RequestIDHeader)
+		}
+		if id == "" {
+			id = NewCorrelationID()
+		}
+
+		ctx.Request = ctx.Request.WithContext(WithCorrelationID(ctx.Request.Context(), id))
+		ctx.Header(CorrelationHeader, id)
+
+		start := time.Now()
+		ctx.Next()
+
+		if skip[ctx.Request.URL.Path] || skip[ctx.FullPath()] {
+			return
+		}
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+
+		logger.LogAttrs(ctx.Request.Context(), level, "http request",
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+		)
+	}
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+// Modos de la política de redacción de payloads (LOG_PAYLOAD_MODE)
+const (
+	PayloadModeNone     = "none"     // No se registra nada del payload
+	PayloadModeMetadata = "metadata" // Solo tamaño y SHA256
+	PayloadModeFields   = "fields"   // Tamaño, SHA256 y los campos permitidos (LOG_PAYLOAD_FIELDS)
+	PayloadModeFull     = "full"     // Payload completo (solo para debug local)
+)
+
+// Redactor aplica la política de redacción a los payloads antes de loguearlos
+//
+// PROPÓSITO:
+// Los mensajes de Kafka y los webhooks pueden contener datos sensibles. Todo payload que
+// se registre en logs pasa por el Redactor en lugar de loguearse directamente.
+type Redactor struct {
+	mode   string
+	fields []string
+}
+
+// NewRedactor crea un Redactor; un modo desconocido se trata como "metadata"
+func NewRedactor(mode string, fields string) *Redactor {
+	switch mode {
+	case PayloadModeNone, PayloadModeMetadata, PayloadModeFields, PayloadModeFull:
+	default:
+		mode = PayloadModeMetadata
+	}
+
+	allowed := make([]string, 0)
+	for _, f := range strings.Split(fields, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			allowed = append(allowed, f)
+		}
+	}
+
+	return &Redactor{mode: mode, fields: allowed}
+}
+
+// Attr devuelve el atributo de log para el payload según la política configurada
+func (r *Redactor) Attr(key string, payload []byte) slog.Attr {
+	if r.mode == PayloadModeNone {
+		return slog.Attr{}
+	}
+	if r.mode == PayloadModeFull {
+		return slog.String(key, string(payload))
+	}
+
+	hash := sha256.Sum256(payload)
+	attrs := []any{
+		slog.Int("size", len(payload)),
+		slog.String("sha256", hex.EncodeToString(hash[:])),
+	}
+
+	if r.mode == PayloadModeFields {
+		var data map[string]any
+		if err := json.Unmarshal(payload, &data); err == nil {
+			for _, f := range r.fields {
+				if v, ok := data[f]; ok {
+					attrs = append(attrs, slog.Any(f, v))
+				}
+			}
+		}
+	}
+
+	return slog.Group(key, attrs...)
+}
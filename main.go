@@ -1,7 +1,8 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -11,7 +12,9 @@ import (
 	"monitoring-energy-service/internal/infrastructure/adapters/rest"
 	"monitoring-energy-service/internal/infrastructure/conf"
 	"monitoring-energy-service/internal/infrastructure/conf/database"
+	"monitoring-energy-service/internal/infrastructure/conf/tracing"
 	"monitoring-energy-service/internal/infrastructure/container"
+	"monitoring-energy-service/internal/infrastructure/logging"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/gin-contrib/cors"
@@ -19,6 +22,7 @@ import (
 	"github.com/joho/godotenv"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 var (
@@ -41,22 +45,33 @@ var (
 // @BasePath  /
 
 // @schemes http https
-func main() {
-	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 
-	log.Printf("build date: %s", buildDate)
-	log.Printf("build git commit: %s", gitCommit)
+// @securityDefinitions.apikey  AdminApiKey
+// @in                          header
+// @name                        X-Admin-Api-Key
+// @description                 Admin endpoints are served on ADMIN_PORT, not on the public port
+func main() {
+	slog.Info("build info", slog.String("build_date", buildDate), slog.String("git_commit", gitCommit))
 
 	err := godotenv.Load(".env")
 	if err != nil {
-		log.Printf("WARNING - Couldn't load .env file, error: %v", err)
+		slog.Warn("Couldn't load .env file", slog.Any("error", err))
 	}
 
 	cfg := &conf.Config{}
 	opts := env.Options{OnSet: conf.OnSetConfig}
 	if err := env.ParseWithOptions(cfg, opts); err != nil {
-		log.Fatalf("%+v\n", err)
+		slog.Error("Invalid configuration", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// Logger único de la aplicación: JSON fuera de dev, nivel configurable con LOG_LEVEL
+	logger, logLevel, err := logging.NewLogger(os.Stdout, cfg.Env, cfg.LogLevel)
+	if err != nil {
+		slog.Error("Invalid logger configuration", slog.Any("error", err))
+		os.Exit(1)
 	}
+	slog.SetDefault(logger)
 
 	port := cfg.Port
 	environment := cfg.Env
@@ -68,19 +83,34 @@ func main() {
 	consumerGroup := cfg.ConsumeGroup
 	autoOffsetKafka := "latest"
 
+	shutdownTracing, err := tracing.SetupTracing(context.Background(), *cfg)
+	if err != nil {
+		logger.Error("Error when initializing tracing", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("Error shutting down tracing", slog.Any("error", err))
+		}
+	}()
+
 	db, err := database.SetupDatabasePsql()
 	if err != nil {
-		log.Fatalf("Error when initializing database, error: %v", err)
+		logger.Error("Error when initializing database", slog.Any("error", err))
+		os.Exit(1)
 	}
 
 	err = database.PerformMigrations(db)
 	if err != nil {
-		log.Fatalf("Error when performing migrations to database, error: %v", err)
+		logger.Error("Error when performing migrations to database", slog.Any("error", err))
+		os.Exit(1)
 	}
 
 	err = database.SeedDB(db, "./db")
 	if err != nil {
-		log.Fatalf("Error when seeding database, error: %v", err)
+		logger.Error("Error when seeding database", slog.Any("error", err))
 		os.Exit(1)
 	}
 	timeoutSeconds := cfg.HttpClientTimeout
@@ -95,19 +125,45 @@ func main() {
 		httpClient,
 		autoOffsetKafka,
 		container.WithConfig(*cfg),
+		container.WithLogger(logger, logLevel),
+		container.WithBuildInfo(buildDate, gitCommit),
 	)
 
 	// Start Kafka consumer in background
 	go c.KafkaService.ConsumeEvents()
 
-	// Start Event Generator in background (sends 30 events every 5 minutes)
-	go c.EventGenerator.Start()
+	// Webhook workers (cola acotada; reencola las entregas pendientes de la ejecución anterior)
+	c.WebhookDispatcher.Start(context.Background())
+
+	// Reentrenamiento periódico de los pronósticos de generación
+	c.ForecastService.Start(context.Background())
+
+	// Recálculo periódico de los días con lecturas tardías
+	c.LateDataHandler.Start(context.Background())
+
+	// Construcción de los rollups de lecturas y purga por retención
+	c.RollupService.Start(context.Background())
+
+	// Revisión periódica de las plantas que dejaron de reportar
+	c.ConnectivityWatcher.Start(context.Background())
+
+	// Start Event Generator in background (SIMULATOR_BATCH_SIZE events every SIMULATOR_INTERVAL)
+	// Con SIMULATOR_ENABLED=false queda detenido; se arranca con POST /admin/simulator/start
+	if cfg.SimulatorEnabled {
+		if err := c.EventGenerator.Start(); err != nil {
+			logger.Error("failed to start event generator", slog.Any("error", err))
+		}
+	} else {
+		logger.Info("Event Generator disabled")
+	}
 
 	router := gin.New()
-	router.Use(gin.LoggerWithConfig(gin.LoggerConfig{
-		SkipPaths: []string{"/healthz", "/readyz", "/swagger/*any"},
-	}))
+	router.Use(logging.GinMiddleware(logger, "/healthz", "/readyz", "/metrics", "/swagger/*any"))
 	router.Use(gin.Recovery())
+	router.Use(c.Metrics.GinMiddleware())
+	router.Use(otelgin.Middleware(cfg.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return r.URL.Path != "/healthz" && r.URL.Path != "/readyz" && r.URL.Path != "/metrics"
+	})))
 
 	suffixesFromEnv := strings.Split(cfg.AllowedCorsSuffixes, ",")
 	allowedSuffixes := make([]string, 0, len(suffixesFromEnv))
@@ -133,8 +189,8 @@ func main() {
 			return false
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", logging.CorrelationHeader, logging.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", logging.CorrelationHeader},
 		AllowCredentials: true,
 		MaxAge:           24 * time.Hour,
 	}))
@@ -145,18 +201,45 @@ func main() {
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(c.Metrics.Handler()))
+
+	// /healthz: liveness (el proceso responde); /readyz: checks reales de dependencias
 	router.GET("/healthz", gin.WrapF(HealthCheck))
-	router.GET("/readyz", gin.WrapF(HealthCheck))
+	router.GET("/readyz", rest.Readiness(c))
 
 	if environment == "dev" {
-		log.Printf("Running in development mode")
-		log.Printf("Swagger UI available at http://localhost:%s/swagger/index.html", port)
+		logger.Info("Running in development mode")
+		logger.Info("Swagger UI available", slog.String("url", "http://localhost:"+port+"/swagger/index.html"))
 	}
 
-	log.Printf("Server starting on port %s", port)
+	// Admin server en un listener separado (build info, config efectiva, log level, pprof)
+	if cfg.AdminApiKey != "" {
+		go runAdminServer(c, logger, cfg.AdminPort)
+	} else {
+		logger.Warn("ADMIN_API_KEY not set, admin server disabled")
+	}
+
+	logger.Info("Server starting", slog.String("port", port))
 
 	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("failed to run server: %v", err)
+		logger.Error("failed to run server", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// runAdminServer sirve /admin/* y /debug/pprof/* en ADMIN_PORT
+// No usa CORS ni se documenta en el host público: solo debe ser accesible desde la red interna
+func runAdminServer(c *container.Container, logger *slog.Logger, adminPort string) {
+	adminRouter := gin.New()
+	adminRouter.Use(logging.GinMiddleware(logger))
+	adminRouter.Use(gin.Recovery())
+	rest.SetupAdminRoutes(adminRouter.Group(""), c)
+
+	logger.Info("Admin server starting", slog.String("port", adminPort))
+	if err := adminRouter.Run(":" + adminPort); err != nil {
+		logger.Error("failed to run admin server", slog.Any("error", err))
+		os.Exit(1)
 	}
 }
 
@@ -0,0 +1,61 @@
+-- +goose Up
+-- create "alert_rules" table
+CREATE TABLE "alert_rules" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "name" character varying(255) NOT NULL,
+  "metric" character varying(100) NOT NULL,
+  "operator" character varying(2) NOT NULL,
+  "threshold" numeric NOT NULL,
+  "for_seconds" bigint NOT NULL DEFAULT 0,
+  "hysteresis" numeric NOT NULL DEFAULT 0,
+  "severity" character varying(20) NOT NULL,
+  "scope_type" character varying(20) NOT NULL,
+  "scope_value" character varying(255) NULL,
+  "enabled" boolean NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create "alerts" table
+CREATE TABLE "alerts" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "rule_id" uuid NOT NULL,
+  "rule_name" character varying(255) NOT NULL,
+  "plant_source_id" uuid NOT NULL,
+  "metric" character varying(100) NOT NULL,
+  "operator" character varying(2) NOT NULL,
+  "threshold" numeric NOT NULL,
+  "severity" character varying(20) NOT NULL,
+  "status" character varying(20) NOT NULL,
+  "value" numeric NOT NULL,
+  "event_id" uuid NOT NULL,
+  "pending_since" timestamptz NOT NULL,
+  "fired_at" timestamptz NOT NULL,
+  "resolved_at" timestamptz NULL,
+  "resolved_value" numeric NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_alerts_fired_at" to table: "alerts"
+CREATE INDEX "idx_alerts_fired_at" ON "alerts" ("fired_at");
+-- create index "idx_alerts_plant_source_id" to table: "alerts"
+CREATE INDEX "idx_alerts_plant_source_id" ON "alerts" ("plant_source_id");
+-- create index "idx_alerts_rule_id" to table: "alerts"
+CREATE INDEX "idx_alerts_rule_id" ON "alerts" ("rule_id");
+-- create index "idx_alerts_status" to table: "alerts"
+CREATE INDEX "idx_alerts_status" ON "alerts" ("status");
+
+-- +goose Down
+-- reverse: create index "idx_alerts_status" to table: "alerts"
+DROP INDEX "idx_alerts_status";
+-- reverse: create index "idx_alerts_rule_id" to table: "alerts"
+DROP INDEX "idx_alerts_rule_id";
+-- reverse: create index "idx_alerts_plant_source_id" to table: "alerts"
+DROP INDEX "idx_alerts_plant_source_id";
+-- reverse: create index "idx_alerts_fired_at" to table: "alerts"
+DROP INDEX "idx_alerts_fired_at";
+-- reverse: create "alerts" table
+DROP TABLE "alerts";
+-- reverse: create "alert_rules" table
+DROP TABLE "alert_rules";
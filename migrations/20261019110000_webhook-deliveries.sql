@@ -0,0 +1,52 @@
+-- +goose Up
+-- create "webhook_deliveries" table
+CREATE TABLE "webhook_deliveries" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "event_type" character varying(100) NOT NULL,
+  "resource_id" uuid NOT NULL,
+  "plant_source_id" uuid NOT NULL,
+  "url" character varying(2048) NOT NULL,
+  "payload" text NOT NULL,
+  "status" character varying(20) NOT NULL,
+  "attempt_count" bigint NOT NULL DEFAULT 0,
+  "last_status_code" bigint NULL,
+  "last_error" text NULL,
+  "delivered_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_webhook_deliveries_created_at" to table: "webhook_deliveries"
+CREATE INDEX "idx_webhook_deliveries_created_at" ON "webhook_deliveries" ("created_at");
+-- create index "idx_webhook_deliveries_event_type" to table: "webhook_deliveries"
+CREATE INDEX "idx_webhook_deliveries_event_type" ON "webhook_deliveries" ("event_type");
+-- create index "idx_webhook_deliveries_status" to table: "webhook_deliveries"
+CREATE INDEX "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+-- create "webhook_delivery_attempts" table
+CREATE TABLE "webhook_delivery_attempts" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "delivery_id" uuid NOT NULL,
+  "attempt" bigint NOT NULL,
+  "status_code" bigint NULL,
+  "error" text NULL,
+  "duration_ms" bigint NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_webhook_deliveries_attempts" FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- create index "idx_webhook_attempts_delivery_id" to table: "webhook_delivery_attempts"
+CREATE INDEX "idx_webhook_attempts_delivery_id" ON "webhook_delivery_attempts" ("delivery_id");
+
+-- +goose Down
+-- reverse: create index "idx_webhook_attempts_delivery_id" to table: "webhook_delivery_attempts"
+DROP INDEX "idx_webhook_attempts_delivery_id";
+-- reverse: create "webhook_delivery_attempts" table
+DROP TABLE "webhook_delivery_attempts";
+-- reverse: create index "idx_webhook_deliveries_status" to table: "webhook_deliveries"
+DROP INDEX "idx_webhook_deliveries_status";
+-- reverse: create index "idx_webhook_deliveries_event_type" to table: "webhook_deliveries"
+DROP INDEX "idx_webhook_deliveries_event_type";
+-- reverse: create index "idx_webhook_deliveries_created_at" to table: "webhook_deliveries"
+DROP INDEX "idx_webhook_deliveries_created_at";
+-- reverse: create "webhook_deliveries" table
+DROP TABLE "webhook_deliveries";
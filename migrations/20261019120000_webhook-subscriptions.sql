@@ -0,0 +1,32 @@
+-- +goose Up
+-- modify "webhook_deliveries" table
+ALTER TABLE "webhook_deliveries" ADD COLUMN "subscription_id" uuid NULL;
+-- create index "idx_webhook_deliveries_subscription_id" to table: "webhook_deliveries"
+CREATE INDEX "idx_webhook_deliveries_subscription_id" ON "webhook_deliveries" ("subscription_id");
+-- create "webhook_subscriptions" table
+CREATE TABLE "webhook_subscriptions" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "name" character varying(255) NOT NULL,
+  "url" character varying(2048) NOT NULL,
+  "secret" character varying(255) NOT NULL,
+  "event_types" text NULL,
+  "plant_ids" text NULL,
+  "status" character varying(20) NOT NULL,
+  "consecutive_failures" bigint NOT NULL DEFAULT 0,
+  "disabled_reason" text NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_webhook_subscriptions_status" to table: "webhook_subscriptions"
+CREATE INDEX "idx_webhook_subscriptions_status" ON "webhook_subscriptions" ("status");
+
+-- +goose Down
+-- reverse: create index "idx_webhook_subscriptions_status" to table: "webhook_subscriptions"
+DROP INDEX "idx_webhook_subscriptions_status";
+-- reverse: create "webhook_subscriptions" table
+DROP TABLE "webhook_subscriptions";
+-- reverse: create index "idx_webhook_deliveries_subscription_id" to table: "webhook_deliveries"
+DROP INDEX "idx_webhook_deliveries_subscription_id";
+-- reverse: modify "webhook_deliveries" table
+ALTER TABLE "webhook_deliveries" DROP COLUMN "subscription_id";
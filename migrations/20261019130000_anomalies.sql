@@ -0,0 +1,46 @@
+-- +goose Up
+-- create "anomalies" table
+CREATE TABLE "anomalies" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "plant_source_id" uuid NOT NULL,
+  "metric" character varying(100) NOT NULL,
+  "event_id" uuid NOT NULL,
+  "value" numeric NOT NULL,
+  "baseline_mean" numeric NOT NULL,
+  "baseline_std" numeric NOT NULL,
+  "score" numeric NOT NULL,
+  "threshold" numeric NOT NULL,
+  "direction" character varying(10) NOT NULL,
+  "detected_at" timestamptz NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_anomalies_detected_at" to table: "anomalies"
+CREATE INDEX "idx_anomalies_detected_at" ON "anomalies" ("detected_at");
+-- create index "idx_anomalies_metric" to table: "anomalies"
+CREATE INDEX "idx_anomalies_metric" ON "anomalies" ("metric");
+-- create index "idx_anomalies_plant_source_id" to table: "anomalies"
+CREATE INDEX "idx_anomalies_plant_source_id" ON "anomalies" ("plant_source_id");
+-- create "anomaly_settings" table
+CREATE TABLE "anomaly_settings" (
+  "plant_source_id" uuid NOT NULL,
+  "enabled" boolean NOT NULL,
+  "z_threshold" numeric NOT NULL,
+  "alpha" numeric NOT NULL,
+  "warm_up" bigint NOT NULL,
+  "metrics" text NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("plant_source_id")
+);
+
+-- +goose Down
+-- reverse: create "anomaly_settings" table
+DROP TABLE "anomaly_settings";
+-- reverse: create index "idx_anomalies_plant_source_id" to table: "anomalies"
+DROP INDEX "idx_anomalies_plant_source_id";
+-- reverse: create index "idx_anomalies_metric" to table: "anomalies"
+DROP INDEX "idx_anomalies_metric";
+-- reverse: create index "idx_anomalies_detected_at" to table: "anomalies"
+DROP INDEX "idx_anomalies_detected_at";
+-- reverse: create "anomalies" table
+DROP TABLE "anomalies";
@@ -0,0 +1,25 @@
+-- +goose Up
+-- modify "energy_plants" table
+ALTER TABLE "energy_plants" ADD COLUMN "status" character varying(20) NULL, ADD COLUMN "status_changed_at" timestamptz NULL;
+-- create "plant_status_history" table
+CREATE TABLE "plant_status_history" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "plant_source_id" uuid NOT NULL,
+  "from_status" character varying(20) NULL,
+  "to_status" character varying(20) NOT NULL,
+  "allowed" boolean NOT NULL,
+  "event_id" uuid NOT NULL,
+  "changed_at" timestamptz NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_plant_status_history_plant_changed" to table: "plant_status_history"
+CREATE INDEX "idx_plant_status_history_plant_changed" ON "plant_status_history" ("plant_source_id", "changed_at");
+
+-- +goose Down
+-- reverse: create index "idx_plant_status_history_plant_changed" to table: "plant_status_history"
+DROP INDEX "idx_plant_status_history_plant_changed";
+-- reverse: create "plant_status_history" table
+DROP TABLE "plant_status_history";
+-- reverse: modify "energy_plants" table
+ALTER TABLE "energy_plants" DROP COLUMN "status_changed_at", DROP COLUMN "status";
@@ -0,0 +1,31 @@
+-- +goose Up
+-- modify "alerts" table
+ALTER TABLE "alerts" ADD COLUMN "maintenance_window_id" uuid NULL;
+-- modify "events" table
+ALTER TABLE "events" ADD COLUMN "maintenance_window_id" uuid NULL;
+-- create "maintenance_windows" table
+CREATE TABLE "maintenance_windows" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "plant_source_id" uuid NOT NULL,
+  "name" character varying(255) NOT NULL,
+  "starts_at" timestamptz NOT NULL,
+  "ends_at" timestamptz NOT NULL,
+  "recurrence" character varying(20) NOT NULL DEFAULT 'none',
+  "recurrence_until" timestamptz NULL,
+  "alert_policy" character varying(20) NOT NULL DEFAULT 'suppress',
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_maintenance_windows_plant_source_id" to table: "maintenance_windows"
+CREATE INDEX "idx_maintenance_windows_plant_source_id" ON "maintenance_windows" ("plant_source_id");
+
+-- +goose Down
+-- reverse: create index "idx_maintenance_windows_plant_source_id" to table: "maintenance_windows"
+DROP INDEX "idx_maintenance_windows_plant_source_id";
+-- reverse: create "maintenance_windows" table
+DROP TABLE "maintenance_windows";
+-- reverse: modify "events" table
+ALTER TABLE "events" DROP COLUMN "maintenance_window_id";
+-- reverse: modify "alerts" table
+ALTER TABLE "alerts" DROP COLUMN "maintenance_window_id";
@@ -0,0 +1,32 @@
+-- +goose Up
+-- create "forecasts" table
+CREATE TABLE "forecasts" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "plant_source_id" uuid NOT NULL,
+  "model" character varying(50) NOT NULL,
+  "alpha" numeric NOT NULL,
+  "beta" numeric NOT NULL,
+  "gamma" numeric NOT NULL,
+  "season_length" bigint NOT NULL,
+  "trained_from" timestamptz NOT NULL,
+  "trained_to" timestamptz NOT NULL,
+  "training_points" bigint NOT NULL,
+  "horizon_hours" bigint NOT NULL,
+  "confidence" numeric NOT NULL,
+  "residual_std" numeric NOT NULL,
+  "points" text NULL,
+  "backtest_hours" bigint NOT NULL,
+  "backtest_mae" numeric NULL,
+  "backtest_rmse" numeric NULL,
+  "backtest_nmae" numeric NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_forecasts_plant_created" to table: "forecasts"
+CREATE INDEX "idx_forecasts_plant_created" ON "forecasts" ("plant_source_id", "created_at");
+
+-- +goose Down
+-- reverse: create index "idx_forecasts_plant_created" to table: "forecasts"
+DROP INDEX "idx_forecasts_plant_created";
+-- reverse: create "forecasts" table
+DROP TABLE "forecasts";
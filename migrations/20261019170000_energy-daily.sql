@@ -0,0 +1,19 @@
+-- +goose Up
+-- create "energy_daily" table
+CREATE TABLE "energy_daily" (
+  "plant_source_id" uuid NOT NULL,
+  "day" date NOT NULL,
+  "generated_mwh" numeric NOT NULL DEFAULT 0,
+  "consumed_mwh" numeric NOT NULL DEFAULT 0,
+  "covered_hours" numeric NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("plant_source_id", "day")
+);
+-- create index "idx_energy_daily_day" to table: "energy_daily"
+CREATE INDEX "idx_energy_daily_day" ON "energy_daily" ("day");
+
+-- +goose Down
+-- reverse: create index "idx_energy_daily_day" to table: "energy_daily"
+DROP INDEX "idx_energy_daily_day";
+-- reverse: create "energy_daily" table
+DROP TABLE "energy_daily";
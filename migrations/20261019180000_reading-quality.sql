@@ -0,0 +1,11 @@
+-- +goose Up
+-- modify "events" table
+ALTER TABLE "events" ADD COLUMN "quality" character varying(10) NOT NULL DEFAULT 'good', ADD COLUMN "quality_flags" text NULL;
+-- create index "idx_events_quality" to table: "events"
+CREATE INDEX "idx_events_quality" ON "events" ("quality");
+
+-- +goose Down
+-- reverse: create index "idx_events_quality" to table: "events"
+DROP INDEX "idx_events_quality";
+-- reverse: modify "events" table
+ALTER TABLE "events" DROP COLUMN "quality_flags", DROP COLUMN "quality";
@@ -0,0 +1,32 @@
+-- +goose Up
+-- modify "energy_plants" table
+ALTER TABLE "energy_plants" ADD COLUMN "portfolio_node_id" uuid NULL;
+-- create index "idx_energy_plants_portfolio_node_id" to table: "energy_plants"
+CREATE INDEX "idx_energy_plants_portfolio_node_id" ON "energy_plants" ("portfolio_node_id");
+-- create "portfolio_nodes" table
+CREATE TABLE "portfolio_nodes" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "parent_id" uuid NULL,
+  "level" character varying(20) NOT NULL,
+  "name" character varying(255) NOT NULL,
+  "description" text NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_portfolio_nodes_level" to table: "portfolio_nodes"
+CREATE INDEX "idx_portfolio_nodes_level" ON "portfolio_nodes" ("level");
+-- create index "idx_portfolio_nodes_parent_id" to table: "portfolio_nodes"
+CREATE INDEX "idx_portfolio_nodes_parent_id" ON "portfolio_nodes" ("parent_id");
+
+-- +goose Down
+-- reverse: create index "idx_portfolio_nodes_parent_id" to table: "portfolio_nodes"
+DROP INDEX "idx_portfolio_nodes_parent_id";
+-- reverse: create index "idx_portfolio_nodes_level" to table: "portfolio_nodes"
+DROP INDEX "idx_portfolio_nodes_level";
+-- reverse: create "portfolio_nodes" table
+DROP TABLE "portfolio_nodes";
+-- reverse: create index "idx_energy_plants_portfolio_node_id" to table: "energy_plants"
+DROP INDEX "idx_energy_plants_portfolio_node_id";
+-- reverse: modify "energy_plants" table
+ALTER TABLE "energy_plants" DROP COLUMN "portfolio_node_id";
@@ -0,0 +1,22 @@
+-- +goose Up
+-- modify "events" table
+ALTER TABLE "events" ADD COLUMN "event_time" timestamptz NULL;
+-- backfill "event_time" from the reading timestamp (created_at when missing or invalid)
+UPDATE "events" SET "event_time" = CASE
+  WHEN "data"::jsonb->>'timestamp' ~ '^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$' THEN ("data"::jsonb->>'timestamp')::timestamptz
+  ELSE "created_at"
+END;
+-- modify "events" table
+ALTER TABLE "events" ALTER COLUMN "event_time" SET NOT NULL;
+-- create index "idx_events_event_time" to table: "events"
+CREATE INDEX "idx_events_event_time" ON "events" ("event_time");
+-- create index "idx_events_plant_event_time" to table: "events"
+CREATE INDEX "idx_events_plant_event_time" ON "events" ("plant_source_id", "event_time");
+
+-- +goose Down
+-- reverse: create index "idx_events_plant_event_time" to table: "events"
+DROP INDEX "idx_events_plant_event_time";
+-- reverse: create index "idx_events_event_time" to table: "events"
+DROP INDEX "idx_events_event_time";
+-- reverse: modify "events" table
+ALTER TABLE "events" DROP COLUMN "event_time";
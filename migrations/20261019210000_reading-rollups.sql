@@ -0,0 +1,30 @@
+-- +goose Up
+-- create "reading_rollups" table
+CREATE TABLE "reading_rollups" (
+  "plant_source_id" uuid NOT NULL,
+  "resolution" character varying(3) NOT NULL,
+  "bucket_start" timestamptz NOT NULL,
+  "metric" character varying(50) NOT NULL,
+  "count" bigint NOT NULL,
+  "sum" numeric NOT NULL,
+  "min" numeric NOT NULL,
+  "max" numeric NOT NULL,
+  PRIMARY KEY ("plant_source_id", "resolution", "bucket_start", "metric")
+);
+-- create index "idx_reading_rollups_resolution_bucket" to table: "reading_rollups"
+CREATE INDEX "idx_reading_rollups_resolution_bucket" ON "reading_rollups" ("resolution", "bucket_start");
+-- create "rollup_watermarks" table
+CREATE TABLE "rollup_watermarks" (
+  "resolution" character varying(3) NOT NULL,
+  "built_until" timestamptz NOT NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("resolution")
+);
+
+-- +goose Down
+-- reverse: create "rollup_watermarks" table
+DROP TABLE "rollup_watermarks";
+-- reverse: create index "idx_reading_rollups_resolution_bucket" to table: "reading_rollups"
+DROP INDEX "idx_reading_rollups_resolution_bucket";
+-- reverse: create "reading_rollups" table
+DROP TABLE "reading_rollups";
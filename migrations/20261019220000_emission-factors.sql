@@ -0,0 +1,25 @@
+-- +goose Up
+-- create "emission_factors" table
+CREATE TABLE "emission_factors" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "version" bigint NOT NULL,
+  "region_node_id" uuid NULL,
+  "valid_from" date NOT NULL,
+  "valid_to" date NULL,
+  "tco2e_per_mwh" numeric NOT NULL,
+  "source" character varying(255) NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_emission_factors_region_node_id" to table: "emission_factors"
+CREATE INDEX "idx_emission_factors_region_node_id" ON "emission_factors" ("region_node_id");
+-- create index "idx_emission_factors_version" to table: "emission_factors"
+CREATE UNIQUE INDEX "idx_emission_factors_version" ON "emission_factors" ("version");
+
+-- +goose Down
+-- reverse: create index "idx_emission_factors_version" to table: "emission_factors"
+DROP INDEX "idx_emission_factors_version";
+-- reverse: create index "idx_emission_factors_region_node_id" to table: "emission_factors"
+DROP INDEX "idx_emission_factors_region_node_id";
+-- reverse: create "emission_factors" table
+DROP TABLE "emission_factors";
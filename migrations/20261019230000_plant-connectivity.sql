@@ -0,0 +1,15 @@
+-- +goose Up
+-- modify "alerts" table
+ALTER TABLE "alerts" ADD COLUMN "kind" character varying(20) NOT NULL DEFAULT 'threshold';
+-- create index "idx_alerts_kind" to table: "alerts"
+CREATE INDEX "idx_alerts_kind" ON "alerts" ("kind");
+-- modify "energy_plants" table
+ALTER TABLE "energy_plants" ADD COLUMN "expected_interval_seconds" bigint NULL;
+
+-- +goose Down
+-- reverse: modify "energy_plants" table
+ALTER TABLE "energy_plants" DROP COLUMN "expected_interval_seconds";
+-- reverse: create index "idx_alerts_kind" to table: "alerts"
+DROP INDEX "idx_alerts_kind";
+-- reverse: modify "alerts" table
+ALTER TABLE "alerts" DROP COLUMN "kind";
@@ -0,0 +1,21 @@
+-- +goose Up
+-- create "quarantined_events" table
+CREATE TABLE "quarantined_events" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "plant_source_id" uuid NOT NULL,
+  "event_type" character varying(100) NOT NULL,
+  "source" character varying(255) NULL,
+  "data" text NOT NULL,
+  "metadata" text NULL,
+  "event_time" timestamptz NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- create index "idx_quarantined_events_plant_event_time" to table: "quarantined_events"
+CREATE INDEX "idx_quarantined_events_plant_event_time" ON "quarantined_events" ("plant_source_id", "event_time");
+
+-- +goose Down
+-- reverse: create index "idx_quarantined_events_plant_event_time" to table: "quarantined_events"
+DROP INDEX "idx_quarantined_events_plant_event_time";
+-- reverse: create "quarantined_events" table
+DROP TABLE "quarantined_events";
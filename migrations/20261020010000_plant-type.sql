@@ -0,0 +1,16 @@
+-- +goose Up
+-- modify "energy_plants" table
+ALTER TABLE "energy_plants" ADD COLUMN IF NOT EXISTS "plant_type" character varying(20) NOT NULL DEFAULT 'other';
+-- backfill "plant_type" from the plant name for rows that still have the default
+UPDATE "energy_plants" SET "plant_type" = 'solar' WHERE "plant_type" = 'other' AND "plant_name" ILIKE '%solar%';
+UPDATE "energy_plants" SET "plant_type" = 'wind' WHERE "plant_type" = 'other' AND "plant_name" ILIKE '%wind%';
+UPDATE "energy_plants" SET "plant_type" = 'hydro' WHERE "plant_type" = 'other' AND "plant_name" ILIKE '%hydro%';
+UPDATE "energy_plants" SET "plant_type" = 'storage' WHERE "plant_type" = 'other' AND ("plant_name" ILIKE '%storage%' OR "plant_name" ILIKE '%battery%');
+-- create index "idx_energy_plants_plant_type" to table: "energy_plants"
+CREATE INDEX IF NOT EXISTS "idx_energy_plants_plant_type" ON "energy_plants" ("plant_type");
+
+-- +goose Down
+-- reverse: create index "idx_energy_plants_plant_type" to table: "energy_plants"
+DROP INDEX IF EXISTS "idx_energy_plants_plant_type";
+-- reverse: modify "energy_plants" table
+ALTER TABLE "energy_plants" DROP COLUMN IF EXISTS "plant_type";